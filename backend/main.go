@@ -12,23 +12,10 @@
 package main
 
 import (
-	config "backend/src/config"
-	seed "backend/src/data"
-	"log"
+	cli "backend/src/cli"
 	"os"
-
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Panic("Error loading .env file")
-	}
-	config.Config()
-	seed.RunSeed()
-	db := config.SetupDB()
-	db.LogMode(true)
-	r := config.SetupRoutes(db)
-	r.Run("0.0.0.0:" + os.Getenv("APP_PORT"))
+	os.Exit(cli.Run(os.Args[1:]))
 }
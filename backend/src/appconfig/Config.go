@@ -0,0 +1,313 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package appconfig
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+type Config struct {
+	Env             string
+	Port            string
+	ShutdownTimeout time.Duration
+	UploadPath      string
+	FrontendURL     string
+	Database        DatabaseConfig
+	Auth            AuthConfig
+	Mail            MailConfig
+	Payment         PaymentConfig
+	Search          SearchConfig
+}
+
+type DatabaseConfig struct {
+	Connection      string
+	Host            string
+	Port            string
+	Database        string
+	Username        string
+	Password        string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	ConnectRetries  int
+}
+
+type AuthConfig struct {
+	JWTSecret                 string
+	TokenLifespan             time.Duration
+	RefreshTokenLifespan      time.Duration
+	VerificationTokenLifespan time.Duration
+	PasswordHasher            string
+}
+
+type MailConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	LogPath  string
+}
+
+// PaymentConfig reaches the card payment gateway. The gateway is off while
+// GatewayURL is empty; WebhookSecret signs the events it posts back.
+type PaymentConfig struct {
+	GatewayURL    string
+	GatewayKey    string
+	WebhookSecret string
+	Timeout       time.Duration
+}
+
+// SearchConfig tunes the product search index. IndexMaxAge bounds how long
+// products changed outside the admin API take to become searchable.
+type SearchConfig struct {
+	IndexMaxAge time.Duration
+}
+
+// Load reads the configuration from the environment. Variables missing from
+// the environment are taken from file, or from .env when file is empty and
+// a .env exists; anything still missing gets its default. The result is
+// validated before it is returned.
+func Load(file string) (*Config, error) {
+
+	if file != "" {
+		if err := godotenv.Load(file); err != nil {
+			return nil, fmt.Errorf("cannot read config file %s: %v", file, err)
+		}
+	} else if _, err := os.Stat(".env"); err == nil {
+		if err := godotenv.Load(".env"); err != nil {
+			return nil, fmt.Errorf("cannot read config file .env: %v", err)
+		}
+	}
+
+	var errs []error
+	duration := func(key string, fallback int, unit time.Duration) time.Duration {
+		value := os.Getenv(key)
+		if value == "" {
+			return time.Duration(fallback) * unit
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive whole number, got %q", key, value))
+			return time.Duration(fallback) * unit
+		}
+		return time.Duration(n) * unit
+	}
+	count := func(key string, fallback int) int {
+		value := os.Getenv(key)
+		if value == "" {
+			return fallback
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("%s must be a whole number, got %q", key, value))
+			return fallback
+		}
+		return n
+	}
+
+	config := &Config{
+		Env:             strings.ToLower(get("APP_ENV", EnvDevelopment)),
+		Port:            get("APP_PORT", "8000"),
+		ShutdownTimeout: duration("APP_SHUTDOWN_SECOND_TIMEOUT", 15, time.Second),
+		UploadPath:      get("UPLOAD_PATH", "uploads"),
+		FrontendURL:     strings.TrimRight(get("FRONTEND_URL", "http://localhost:4200"), "/"),
+		Database: DatabaseConfig{
+			Connection:      dialect(get("DB_CONNECTION", "mysql")),
+			Host:            get("DB_HOST", "localhost"),
+			Port:            os.Getenv("DB_PORT"),
+			Database:        os.Getenv("DB_DATABASE"),
+			Username:        os.Getenv("DB_USERNAME"),
+			Password:        os.Getenv("DB_PASSWORD"),
+			SSLMode:         get("DB_SSLMODE", "disable"),
+			MaxOpenConns:    count("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    count("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime: duration("DB_CONN_MAX_MINUTE_LIFETIME", 30, time.Minute),
+			ConnMaxIdleTime: duration("DB_CONN_MAX_MINUTE_IDLE_TIME", 5, time.Minute),
+			ConnectRetries:  count("DB_CONNECT_RETRIES", 5),
+		},
+		Auth: AuthConfig{
+			JWTSecret:                 os.Getenv("JWT_SECRET"),
+			TokenLifespan:             duration("TOKEN_HOUR_LIFESPAN", 1, time.Hour),
+			RefreshTokenLifespan:      duration("REFRESH_TOKEN_DAY_LIFESPAN", 30, 24*time.Hour),
+			VerificationTokenLifespan: duration("VERIFICATION_TOKEN_MINUTE_LIFESPAN", 30, time.Minute),
+			PasswordHasher:            strings.ToLower(get("PASSWORD_HASHER", "argon2id")),
+		},
+		Mail: MailConfig{
+			Driver:   strings.ToLower(get("MAIL_DRIVER", "log")),
+			Host:     os.Getenv("MAIL_HOST"),
+			Port:     get("MAIL_PORT", "587"),
+			Username: os.Getenv("MAIL_USERNAME"),
+			Password: os.Getenv("MAIL_PASSWORD"),
+			From:     get("MAIL_FROM", "no-reply@localhost"),
+			LogPath:  get("MAIL_LOG_PATH", "storage/mail.log"),
+		},
+		Payment: PaymentConfig{
+			GatewayURL:    strings.TrimRight(os.Getenv("PAYMENT_GATEWAY_URL"), "/"),
+			GatewayKey:    os.Getenv("PAYMENT_GATEWAY_KEY"),
+			WebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+			Timeout:       duration("PAYMENT_GATEWAY_SECOND_TIMEOUT", 10, time.Second),
+		},
+		Search: SearchConfig{
+			IndexMaxAge: duration("SEARCH_INDEX_SECOND_MAX_AGE", 300, time.Second),
+		},
+	}
+
+	if config.Database.Port == "" {
+		config.Database.Port = defaultPorts[config.Database.Connection]
+	}
+
+	if err := config.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Outside production a missing secret is replaced by a random one, so
+	// tokens stop working when the process restarts.
+	if config.Auth.JWTSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		config.Auth.JWTSecret = hex.EncodeToString(secret)
+		log.Printf("JWT_SECRET is not set, using a random secret for this %s process", config.Env)
+	}
+
+	return config, nil
+}
+
+var defaultPorts = map[string]string{
+	"mysql":    "3306",
+	"postgres": "5432",
+}
+
+// dialect maps DB_CONNECTION to the gorm dialect name.
+func dialect(name string) string {
+	switch strings.ToLower(name) {
+	case "pgsql", "postgresql":
+		return "postgres"
+	case "sqlite":
+		return "sqlite3"
+	}
+	return strings.ToLower(name)
+}
+
+func get(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (config *Config) IsProduction() bool {
+	return config.Env == EnvProduction
+}
+
+// Validate reports every invalid setting at once.
+func (config *Config) Validate() error {
+
+	var errs []error
+
+	switch config.Env {
+	case EnvDevelopment, EnvProduction, EnvTest:
+	default:
+		errs = append(errs, fmt.Errorf("APP_ENV must be one of development, production or test, got %q", config.Env))
+	}
+
+	if port, err := strconv.Atoi(config.Port); err != nil || port <= 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("APP_PORT must be a TCP port number, got %q", config.Port))
+	}
+
+	if config.Database.MaxOpenConns > 0 && config.Database.MaxIdleConns > config.Database.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("DB_MAX_IDLE_CONNS (%d) cannot exceed DB_MAX_OPEN_CONNS (%d)", config.Database.MaxIdleConns, config.Database.MaxOpenConns))
+	}
+
+	switch config.Database.Connection {
+	case "mysql", "postgres":
+		if config.Database.Database == "" {
+			errs = append(errs, errors.New("DB_DATABASE is required"))
+		}
+		if config.Database.Username == "" {
+			errs = append(errs, errors.New("DB_USERNAME is required"))
+		}
+	case "sqlite3":
+		if config.Database.Database == "" {
+			errs = append(errs, errors.New("DB_DATABASE must name the SQLite file, or :memory:"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("DB_CONNECTION must be mysql, postgres or sqlite, got %q", config.Database.Connection))
+	}
+
+	if config.IsProduction() {
+		if config.Auth.JWTSecret == "" || config.Auth.JWTSecret == "secret" {
+			errs = append(errs, errors.New("JWT_SECRET must be set to a strong value in production"))
+		} else if len(config.Auth.JWTSecret) < 32 {
+			errs = append(errs, errors.New("JWT_SECRET must be at least 32 characters in production"))
+		}
+	}
+
+	switch config.Auth.PasswordHasher {
+	case "argon2id", "bcrypt":
+	default:
+		errs = append(errs, fmt.Errorf("PASSWORD_HASHER must be argon2id or bcrypt, got %q", config.Auth.PasswordHasher))
+	}
+
+	switch config.Mail.Driver {
+	case "smtp":
+		if config.Mail.Host == "" {
+			errs = append(errs, errors.New("MAIL_HOST is required when MAIL_DRIVER is smtp"))
+		}
+	case "file":
+		if config.Mail.LogPath == "" {
+			errs = append(errs, errors.New("MAIL_LOG_PATH is required when MAIL_DRIVER is file"))
+		}
+	case "log":
+	default:
+		errs = append(errs, fmt.Errorf("MAIL_DRIVER must be smtp, file or log, got %q", config.Mail.Driver))
+	}
+
+	if !strings.HasPrefix(config.FrontendURL, "http://") && !strings.HasPrefix(config.FrontendURL, "https://") {
+		errs = append(errs, fmt.Errorf("FRONTEND_URL must be an http or https URL, got %q", config.FrontendURL))
+	}
+
+	if gateway := config.Payment.GatewayURL; gateway != "" {
+		if !strings.HasPrefix(gateway, "http://") && !strings.HasPrefix(gateway, "https://") {
+			errs = append(errs, fmt.Errorf("PAYMENT_GATEWAY_URL must be an http or https URL, got %q", gateway))
+		}
+		if config.Payment.WebhookSecret == "" {
+			errs = append(errs, errors.New("PAYMENT_WEBHOOK_SECRET is required when PAYMENT_GATEWAY_URL is set"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,123 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	setup "backend/src/config"
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// createAdmin creates a confirmed user holding the admin role. When the
+// e-mail already belongs to a user, the admin role is added to the roles
+// that user has and the password is only changed if one is given.
+func createAdmin(args []string) error {
+
+	f := newFlags("create-admin")
+	email := f.String("email", "", "e-mail address of the administrator")
+	password := f.envString("password", "ADMIN_PASSWORD", "password, at least 8 characters")
+	name := f.String("name", "Administrator", "first and last name")
+	f.databaseFlags()
+	config, err := f.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(*email) == "" {
+		return errors.New("the --email flag is required")
+	}
+	if *password != "" && len(strings.TrimSpace(*password)) < 8 {
+		return errors.New("the password must have at least 8 characters")
+	}
+
+	db, err := setup.SetupDB(config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var user models.User
+	err = db.Where("email = ?", *email).First(&user).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+	exists := err == nil
+
+	if !exists && *password == "" {
+		return errors.New("the --password flag is required for a new user")
+	}
+
+	tx := db.Begin()
+
+	if *password != "" {
+		hashed, err := services.PasswordHashService(config).Hash(*password)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		user.Password = hashed
+		user.Salt = ""
+	}
+
+	if !exists {
+		names := strings.SplitN(strings.TrimSpace(*name), " ", 2)
+		user.Email = *email
+		user.FirstName = helpers.NewNullString(names[0])
+		if len(names) > 1 {
+			user.LastName = helpers.NewNullString(names[1])
+		}
+	}
+	user.Status = 1
+
+	if err := tx.Save(&user).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	access := services.AccessControl(repositories.NewStore(tx))
+	roles := append(access.UserRoles(user.Id), models.RoleAdmin)
+	if err := access.AssignRoles(user.Id, roles...); err != nil {
+		tx.Rollback()
+		if errors.Is(err, repositories.ErrUnknownRole) {
+			return fmt.Errorf("%v; run migrate up to create the built-in roles", err)
+		}
+		return err
+	}
+
+	if err := tx.Create(&models.Activity{
+		UserId:      int64(user.Id),
+		Subject:     "User Roles",
+		Event:       "Create Administrator",
+		Description: "Granted the admin role from the command line",
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Printf("user %d (%s) now has the admin role\n", user.Id, user.Email)
+	} else {
+		fmt.Printf("created admin user %d (%s)\n", user.Id, user.Email)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	appconfig "backend/src/appconfig"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the REST API (default)", serve},
+	{"migrate", "apply, revert or list schema migrations", migrate},
+	{"seed", "fill empty tables with fixture data", seed},
+	{"create-admin", "create a user with the admin role, or promote an existing one", createAdmin},
+	{"reindex", "recompute product order and rating counters", reindex},
+}
+
+// Run executes the subcommand named in args[0], or serve when none is
+// given, and returns the process exit code.
+func Run(args []string) int {
+
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		usage()
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	return 2
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: backend <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run \"backend <command> -h\" for the flags of a command.")
+}
+
+// flags is a FlagSet whose flags can be bound to environment variables.
+// Settings are resolved in this order: a flag given on the command line,
+// the environment, the file named by --config (or .env), then defaults.
+type flags struct {
+	*flag.FlagSet
+	env        map[string]string
+	ints       map[string]*int
+	configFile *string
+}
+
+func newFlags(name string) *flags {
+	f := &flags{
+		FlagSet: flag.NewFlagSet(name, flag.ContinueOnError),
+		env:     map[string]string{},
+		ints:    map[string]*int{},
+	}
+	f.configFile = f.String("config", "", "env file to read settings from (default .env when it exists)")
+	return f
+}
+
+func (f *flags) envString(name string, key string, usage string) *string {
+	f.env[name] = key
+	return f.String(name, os.Getenv(key), fmt.Sprintf("%s (env %s)", usage, key))
+}
+
+func (f *flags) envInt(name string, key string, value int, usage string) *int {
+	f.env[name] = key
+	p := f.Int(name, value, fmt.Sprintf("%s (env %s)", usage, key))
+	f.ints[name] = p
+	return p
+}
+
+func (f *flags) databaseFlags() {
+	f.envString("db-connection", "DB_CONNECTION", "database driver")
+	f.envString("db-host", "DB_HOST", "database host")
+	f.envString("db-port", "DB_PORT", "database port")
+	f.envString("db-database", "DB_DATABASE", "database name")
+	f.envString("db-username", "DB_USERNAME", "database user")
+	f.envString("db-password", "DB_PASSWORD", "database password")
+}
+
+// parse parses args and loads the configuration with the flags applied.
+func (f *flags) parse(args []string) (*appconfig.Config, error) {
+
+	if err := f.Parse(args); err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{}
+	var err error
+	f.Visit(func(fl *flag.Flag) {
+		visited[fl.Name] = true
+		if key, ok := f.env[fl.Name]; ok && err == nil {
+			err = os.Setenv(key, fl.Value.String())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := appconfig.Load(*f.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, p := range f.ints {
+		if visited[name] {
+			continue
+		}
+		if n, err := strconv.Atoi(os.Getenv(f.env[name])); err == nil {
+			*p = n
+		}
+	}
+
+	return config, nil
+}
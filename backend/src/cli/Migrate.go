@@ -0,0 +1,90 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	setup "backend/src/config"
+	database "backend/src/database"
+	"fmt"
+)
+
+// migrate runs "migrate up", "migrate down" or "migrate status".
+func migrate(args []string) error {
+
+	action := "up"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+
+	f := newFlags("migrate " + action)
+	steps := f.Int("steps", 0, "number of migrations to apply or revert (up: all, down: 1 when 0)")
+	f.databaseFlags()
+	config, err := f.parse(args)
+	if err != nil {
+		return err
+	}
+	if *steps < 0 {
+		return fmt.Errorf("invalid number of steps %d", *steps)
+	}
+
+	db, err := setup.SetupDB(config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.Migrator(db, config.Database.Connection)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		done, err := migrator.Up(*steps)
+		for _, migration := range done {
+			fmt.Printf("migrated  %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to migrate")
+		}
+	case "down":
+		done, err := migrator.Down(*steps)
+		for _, migration := range done {
+			fmt.Printf("reverted  %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to revert")
+		}
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, row := range status {
+			applied := "pending"
+			if row.AppliedAt != nil {
+				applied = row.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", row.Version, row.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown action %q, expected up, down or status", action)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	setup "backend/src/config"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"fmt"
+)
+
+func reindex(args []string) error {
+
+	f := newFlags("reindex")
+	f.databaseFlags()
+	config, err := f.parse(args)
+	if err != nil {
+		return err
+	}
+
+	db, err := setup.SetupDB(config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	total, err := services.ProductIndex(repositories.NewStore(db)).Rebuild()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("reindexed %d products\n", total)
+	return nil
+}
@@ -0,0 +1,52 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	setup "backend/src/config"
+	data "backend/src/data"
+	"fmt"
+)
+
+func seed(args []string) error {
+
+	defaults := data.DefaultSeedOptions()
+
+	f := newFlags("seed")
+	users := f.envInt("users", "SEED_USERS", defaults.Users, "number of customers to create")
+	products := f.envInt("products", "SEED_PRODUCTS", defaults.Products, "number of products to create")
+	randomSeed := f.Int64("random-seed", 0, "seed for the random generator; 0 picks one from the clock")
+	f.databaseFlags()
+	config, err := f.parse(args)
+	if err != nil {
+		return err
+	}
+
+	options := data.SeedOptions{
+		Users:      *users,
+		Products:   *products,
+		RandomSeed: *randomSeed,
+	}
+	if options.RandomSeed == 0 {
+		options.RandomSeed = defaults.RandomSeed
+	}
+
+	db, err := setup.SetupDB(config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	data.RunSeed(db, config, options)
+	fmt.Printf("seeded with --random-seed=%d\n", options.RandomSeed)
+	return nil
+}
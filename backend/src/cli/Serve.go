@@ -0,0 +1,103 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package cli
+
+import (
+	setup "backend/src/config"
+	mailer "backend/src/mailer"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serve runs the API until SIGINT or SIGTERM, then stops accepting
+// connections, lets in-flight requests and background workers finish
+// within the shutdown timeout and closes the database pool.
+func serve(args []string) error {
+
+	f := newFlags("serve")
+	f.envString("port", "APP_PORT", "port to listen on")
+	f.databaseFlags()
+	workers := f.Bool("workers", true, "run the mail outbox and token cleanup workers")
+	logSQL := f.Bool("log-sql", true, "log every SQL statement")
+	config, err := f.parse(args)
+	if err != nil {
+		return err
+	}
+
+	db, err := setup.SetupDB(config.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	db.LogMode(*logSQL)
+
+	if err := services.LoadCurrency(repositories.NewStore(db)); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var background []<-chan struct{}
+	if *workers {
+		background = append(background,
+			mailer.StartOutboxWorker(ctx, db, mailer.NewMailer(config), 30*time.Second),
+			services.StartTokenCleanup(ctx, repositories.NewStore(db), config, time.Hour),
+		)
+	}
+
+	server := &http.Server{
+		Addr:              "0.0.0.0:" + config.Port,
+		Handler:           setup.SetupRoutes(db, config),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		stop()
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-ctx.Done():
+		log.Printf("shutting down, waiting up to %s for open requests", config.ShutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	for _, done := range background {
+		select {
+		case <-done:
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	}
+
+	return nil
+}
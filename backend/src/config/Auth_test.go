@@ -0,0 +1,195 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// mailedToken returns the token at the end of the link in the newest
+// e-mail of the given template sent to recipient.
+func (s *testServer) mailedToken(recipient string, template string) string {
+	s.t.Helper()
+
+	var mail models.MailOutbox
+	if err := s.db.Where("recipient = ? AND template = ?", recipient, template).Order("id desc").First(&mail).Error; err != nil {
+		s.t.Fatalf("no %s e-mail to %s: %v", template, recipient, err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(mail.Payload), &payload); err != nil {
+		s.t.Fatalf("decode e-mail payload: %v", err)
+	}
+
+	link, _ := payload["Link"].(string)
+	return link[strings.LastIndex(link, "/")+1:]
+}
+
+func (s *testServer) login(email string, password string) tokenResponse {
+	s.t.Helper()
+
+	var pair tokenResponse
+	s.expect(s.do(http.MethodPost, "/api/auth/login", "", map[string]string{"email": email, "password": password}), http.StatusOK, &pair)
+	if pair.Token == "" || pair.RefreshToken == "" {
+		s.t.Fatalf("login returned %+v, want both tokens", pair)
+	}
+	return pair
+}
+
+func TestRegisterConfirmAndLogin(t *testing.T) {
+
+	server := newTestServer(t)
+	email := "new@example.com"
+
+	server.expect(server.do(http.MethodPost, "/api/auth/register", "", map[string]string{
+		"name":             "New Person",
+		"email":            email,
+		"password":         fixturePass,
+		"password_confirm": fixturePass,
+	}), http.StatusOK, nil)
+
+	server.expect(server.do(http.MethodPost, "/api/auth/login", "", map[string]string{"email": email, "password": fixturePass}), http.StatusUnauthorized, nil)
+
+	token := server.mailedToken(email, mailer.TemplateConfirm)
+	server.expect(server.do(http.MethodGet, "/api/auth/confirm/"+token, "", nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/auth/confirm/"+token, "", nil), http.StatusBadRequest, nil)
+
+	pair := server.login(email, fixturePass)
+
+	var profile schema.UserProfileSchema
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", pair.Token, nil), http.StatusOK, &profile)
+	if profile.Email != email || profile.FirstName != "New" || profile.LastName != "Person" {
+		t.Errorf("profile = %+v", profile)
+	}
+}
+
+func TestRegisterKeepsNothingWithoutConfirmationMail(t *testing.T) {
+
+	server := newTestServer(t)
+	email := "new@example.com"
+
+	if err := server.db.DropTable(&models.MailOutbox{}).Error; err != nil {
+		t.Fatalf("drop outbox: %v", err)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/auth/register", "", map[string]string{
+		"name":             "New Person",
+		"email":            email,
+		"password":         fixturePass,
+		"password_confirm": fixturePass,
+	}), http.StatusInternalServerError, nil)
+
+	if users := server.count("users", "email = ?", email); users != 0 {
+		t.Errorf("%d users stored, want the account rolled back with its e-mail", users)
+	}
+	if tokens := server.count("authentications", "credential = ?", email); tokens != 0 {
+		t.Errorf("%d confirmation tokens stored, want none", tokens)
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+
+	server := newTestServer(t)
+	password := "Secret456!"
+
+	server.expect(server.do(http.MethodPost, "/api/auth/email/forgot", "", map[string]string{"email": customerEmail}), http.StatusOK, nil)
+
+	token := server.mailedToken(customerEmail, mailer.TemplateReset)
+	server.expect(server.do(http.MethodPost, "/api/auth/email/reset/"+token, "", map[string]string{
+		"email":            customerEmail,
+		"password":         password,
+		"password_confirm": password,
+	}), http.StatusOK, nil)
+
+	server.expect(server.do(http.MethodPost, "/api/auth/login", "", map[string]string{"email": customerEmail, "password": fixturePass}), http.StatusUnauthorized, nil)
+	server.login(customerEmail, password)
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+
+	server := newTestServer(t)
+	first := server.login(customerEmail, fixturePass)
+
+	var second tokenResponse
+	server.expect(server.do(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}), http.StatusOK, &second)
+	if second.RefreshToken == "" || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("refresh returned %+v, want a new refresh token", second)
+	}
+
+	// Presenting a used refresh token revokes the whole session family.
+	server.expect(server.do(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": second.RefreshToken}), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", second.Token, nil), http.StatusUnauthorized, nil)
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+
+	server := newTestServer(t)
+	kept := server.login(customerEmail, fixturePass)
+	pair := server.login(customerEmail, fixturePass)
+
+	server.expect(server.do(http.MethodPost, "/api/auth/logout", pair.Token, nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", pair.Token, nil), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", kept.Token, nil), http.StatusOK, nil)
+
+	server.expect(server.do(http.MethodPost, "/api/auth/logout/all", kept.Token, nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", kept.Token, nil), http.StatusUnauthorized, nil)
+}
+
+func TestSessionCleanup(t *testing.T) {
+
+	server := newTestServer(t)
+	sessions := services.Sessions(server.config, repositories.NewStore(server.db))
+	refresh := "auth_type = ? AND user_id = ?"
+
+	first := server.login(customerEmail, fixturePass)
+	var second tokenResponse
+	server.expect(server.do(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}), http.StatusOK, &second)
+
+	// The used token stays for reuse detection, the live one for the session.
+	if _, err := sessions.Cleanup(); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if rows := server.count("authentications", refresh, models.AuthTypeRefreshToken, customerId); rows != 2 {
+		t.Errorf("%d refresh tokens after cleanup, want the used and the live one", rows)
+	}
+
+	expired := server.login(adminEmail, fixturePass)
+	server.db.Model(&models.Authentication{}).Where(refresh, models.AuthTypeRefreshToken, adminId).UpdateColumn("expired_at", time.Now().Add(-time.Minute))
+
+	server.expect(server.do(http.MethodPost, "/api/auth/logout", second.Token, nil), http.StatusOK, nil)
+	if _, err := sessions.Cleanup(); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if rows := server.count("authentications", "auth_type = ?", models.AuthTypeRefreshToken); rows != 0 {
+		t.Errorf("%d refresh tokens after cleanup, want the revoked and expired ones gone", rows)
+	}
+
+	// Access tokens of the deleted sessions stay rejected.
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", second.Token, nil), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", expired.Token, nil), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": first.RefreshToken}), http.StatusUnauthorized, nil)
+}
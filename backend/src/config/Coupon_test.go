@@ -0,0 +1,118 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCouponCheckout(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 2)
+	server.expect(server.do(http.MethodPost, "/api/order/coupon/apply", token, map[string]string{"code": "nope"}), http.StatusUnprocessableEntity, nil)
+	server.expect(server.do(http.MethodPost, "/api/order/coupon/apply", token, map[string]string{"code": "save10"}), http.StatusOK, nil)
+
+	// 200 subtotal, 5% store discount, then 10% off the remaining 190.
+	var quote struct {
+		Order     models.Order `json:"order"`
+		Breakdown struct {
+			Coupon         string      `json:"coupon"`
+			CouponDiscount money.Money `json:"coupon_discount"`
+			Adjustments    []struct {
+				Kind   string      `json:"kind"`
+				Label  string      `json:"label"`
+				Amount money.Money `json:"amount"`
+			} `json:"adjustments"`
+		} `json:"breakdown"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/checkout/initial", token, nil), http.StatusOK, &quote)
+	if quote.Breakdown.Coupon != "SAVE10" || quote.Breakdown.CouponDiscount != money.FromInt(19) {
+		t.Errorf("coupon %q takes %v off, want SAVE10 taking 19", quote.Breakdown.Coupon, quote.Breakdown.CouponDiscount)
+	}
+	if len(quote.Breakdown.Adjustments) != 4 || quote.Breakdown.Adjustments[1].Kind != "coupon" || quote.Breakdown.Adjustments[1].Amount != money.FromInt(-19) {
+		t.Errorf("adjustments = %+v, want the coupon after the store discount", quote.Breakdown.Adjustments)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": 1,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusOK, nil)
+
+	var redemption models.OrderCoupon
+	if err := server.db.Where("order_id = ?", quote.Order.Id).First(&redemption).Error; err != nil {
+		t.Fatalf("redemption: %v", err)
+	}
+	if redemption.CouponId != 1 || redemption.Amount != money.FromInt(19) {
+		t.Errorf("redemption = %+v, want coupon 1 taking 19", redemption)
+	}
+
+	var detail orderDetailResponse
+	server.expect(server.do(http.MethodGet, fmt.Sprintf("/api/order/detail/%d", quote.Order.Id), token, nil), http.StatusOK, &detail)
+	if detail.Order.TotalDiscount != money.FromInt(29) {
+		t.Errorf("order discount = %v, want 10 + 19", detail.Order.TotalDiscount)
+	}
+
+	// SAVE10 is once per customer.
+	server.addToCart(token, 3, 1)
+	server.expect(server.do(http.MethodPost, "/api/order/coupon/apply", token, map[string]string{"code": "SAVE10"}), http.StatusUnprocessableEntity, nil)
+}
+
+func TestCouponExpiredAtCheckout(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	server.expect(server.do(http.MethodPost, "/api/order/coupon/apply", token, map[string]string{"code": "SAVE10"}), http.StatusOK, nil)
+
+	if err := server.db.Model(&models.Coupon{}).Where("id = ?", 1).Update("ends_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("expire coupon: %v", err)
+	}
+
+	var quote struct {
+		Breakdown struct {
+			CouponError string `json:"coupon_error"`
+		} `json:"breakdown"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/checkout/initial", token, nil), http.StatusOK, &quote)
+	if quote.Breakdown.CouponError != "This coupon has expired." {
+		t.Errorf("coupon error = %q, want the coupon reported expired", quote.Breakdown.CouponError)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": 1,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusConflict, nil)
+	if stock := server.stock(1); stock != 10 {
+		t.Errorf("inventory 1 stock = %d, want 10", stock)
+	}
+
+	server.expect(server.do(http.MethodDelete, "/api/order/coupon/remove", token, nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": 1,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusOK, nil)
+}
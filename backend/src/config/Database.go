@@ -12,47 +12,70 @@
 package config
 
 import (
-	models "backend/src/models"
+	appconfig "backend/src/appconfig"
 	"fmt"
-	"os"
+	"log"
+	"time"
 
-	"github.com/jinzhu/gorm"
-	"github.com/joho/godotenv"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
 )
 
-func SetupDB() *gorm.DB {
-	godotenv.Load(".env")
-	USER := os.Getenv("DB_USERNAME")
-	PASS := os.Getenv("DB_PASSWORD")
-	HOST := os.Getenv("DB_HOST")
-	PORT := os.Getenv("DB_PORT")
-	DBNAME := os.Getenv("DB_DATABASE")
-	URL := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local", USER, PASS, HOST, PORT, DBNAME)
-	db, err := gorm.Open(os.Getenv("DB_CONNECTION"), URL)
-	if err != nil {
-		panic(err.Error())
+// SetupDB opens the connection pool the whole process shares. The server
+// may start before the database is ready, so a failed ping is retried
+// with exponential backoff up to database.ConnectRetries times.
+func SetupDB(database appconfig.DatabaseConfig) (*gorm.DB, error) {
+
+	URL := dataSourceName(database)
+
+	var db *gorm.DB
+	var err error
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		db, err = gorm.Open(database.Connection, URL)
+		if err == nil {
+			break
+		}
+		if attempt >= database.ConnectRetries {
+			return nil, fmt.Errorf("cannot connect to the %s database at %s:%s: %v", database.Connection, database.Host, database.Port, err)
+		}
+		log.Printf("database is not reachable (%v), retrying in %s", err, backoff)
+		time.Sleep(backoff)
+		if backoff < 8*time.Second {
+			backoff *= 2
+		}
 	}
-	return db
+
+	pool := db.DB()
+	if database.Connection == "sqlite3" {
+		// SQLite allows one writer at a time, and closing the only
+		// connection to :memory: would drop the database with it.
+		pool.SetMaxOpenConns(1)
+		pool.SetMaxIdleConns(1)
+		pool.SetConnMaxLifetime(0)
+		pool.SetConnMaxIdleTime(0)
+	} else {
+		pool.SetMaxOpenConns(database.MaxOpenConns)
+		pool.SetMaxIdleConns(database.MaxIdleConns)
+		pool.SetConnMaxLifetime(database.ConnMaxLifetime)
+		pool.SetConnMaxIdleTime(database.ConnMaxIdleTime)
+	}
+
+	return db, nil
 }
 
-func Config() {
-	db := SetupDB()
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Activity{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Authentication{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Brand{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Category{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Colour{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.NewsLetter{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Order{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.OrderBilling{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.OrderDetail{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Payment{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Product{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.ProductImage{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.ProductInventory{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.ProductReview{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Setting{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.Size{})
-	db.Set("gorm:table_options", "ENGINE=InnoDB").AutoMigrate(models.User{})
-}
\ No newline at end of file
+func dataSourceName(database appconfig.DatabaseConfig) string {
+	switch database.Connection {
+	case "postgres":
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", database.Host, database.Port, database.Username, database.Password, database.Database, database.SSLMode)
+	case "sqlite3":
+		if database.Database == ":memory:" {
+			return ":memory:"
+		}
+		return database.Database + "?_busy_timeout=5000&_journal_mode=WAL"
+	default:
+		return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", database.Username, database.Password, database.Host, database.Port, database.Database)
+	}
+}
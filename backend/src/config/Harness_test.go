@@ -0,0 +1,278 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	appconfig "backend/src/appconfig"
+	controllers "backend/src/controllers"
+	database "backend/src/database"
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Fixture ids and credentials shared by the integration tests.
+const (
+	customerId uint64 = 1
+	adminId    uint64 = 2
+	pendingId  uint64 = 3
+
+	customerEmail = "customer@example.com"
+	adminEmail    = "admin@example.com"
+	pendingEmail  = "pending@example.com"
+	fixturePass   = "Secret123!"
+)
+
+var (
+	hashOnce   sync.Once
+	hashedPass string
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// testServer is the router from SetupRoutes on top of a private in-memory
+// SQLite database that holds the migrated schema and the fixtures.
+type testServer struct {
+	t      *testing.T
+	db     *gorm.DB
+	config *appconfig.Config
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	config := &appconfig.Config{
+		Env:         appconfig.EnvTest,
+		Port:        "8000",
+		UploadPath:  t.TempDir(),
+		FrontendURL: "http://shop.test",
+		Database: appconfig.DatabaseConfig{
+			Connection: "sqlite3",
+			Database:   ":memory:",
+		},
+		Auth: appconfig.AuthConfig{
+			JWTSecret:                 "integration-test-secret-integration-test",
+			TokenLifespan:             time.Hour,
+			RefreshTokenLifespan:      24 * time.Hour,
+			VerificationTokenLifespan: 30 * time.Minute,
+			PasswordHasher:            "argon2id",
+		},
+		Mail: appconfig.MailConfig{Driver: "log"},
+	}
+
+	db, err := SetupDB(config.Database)
+	if err != nil {
+		t.Fatalf("SetupDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.Migrator(db, config.Database.Connection)
+	if err != nil {
+		t.Fatalf("Migrator: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	hashOnce.Do(func() {
+		hashedPass, err = services.PasswordHashService(config).Hash(fixturePass)
+	})
+	if hashedPass == "" {
+		t.Fatalf("hash fixture password: %v", err)
+	}
+
+	loadFixtures(t, db)
+
+	return &testServer{t: t, db: db, config: config, router: SetupRoutes(db, config)}
+}
+
+// loadFixtures inserts the same catalogue, users and order on every run.
+// Each table also has one row nothing refers to, for the delete routes.
+func loadFixtures(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := []interface{}{
+		&models.PriceRule{Name: "Store discount", Kind: models.PriceRuleDiscount, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationPercent, Value: money.FromInt(5), Status: 1},
+		&models.PriceRule{Name: "Flat shipment", Kind: models.PriceRuleShipment, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationFixed, Value: money.FromInt(15), Status: 1},
+		&models.TaxRate{Name: "Default tax", Rate: money.MustParseRate("10"), Status: 1},
+		&models.TaxRate{Name: "Indonesia", Country: "Indonesia", Rate: money.MustParseRate("11"), Status: 1},
+
+		&models.Brand{Id: 1, Name: "Acme", Status: 1},
+		&models.Brand{Id: 2, Name: "Unused Brand", Status: 1},
+		&models.Category{Id: 1, Name: "Shirts", Displayed: 1, Status: 1},
+		&models.Category{Id: 2, Name: "Unused Category", Status: 1},
+		&models.Colour{Id: 1, Code: "#ff0000", Name: "Red", Status: 1},
+		&models.Colour{Id: 2, Code: "#0000ff", Name: "Blue", Status: 1},
+		&models.Colour{Id: 3, Code: "#00ff00", Name: "Unused Colour", Status: 1},
+		&models.Size{Id: 1, Name: "M", Status: 1},
+		&models.Size{Id: 2, Name: "L", Status: 1},
+		&models.Size{Id: 3, Name: "Unused Size", Status: 1},
+		&models.Payment{Id: 1, Name: "Direct Bank Transfer", Status: 1},
+		&models.Payment{Id: 2, Name: "Card", Provider: "gateway", Status: 1},
+
+		&models.Product{Id: 1, BrandId: 1, Sku: "SKU-001", Name: "Shirt", Price: money.FromInt(100), TotalRating: 8, RatingAverage: 4, RatingCount: 2, Rating3: 1, Rating5: 1, Status: 1, PublishedAt: &published},
+		&models.Product{Id: 2, BrandId: 1, Sku: "SKU-002", Name: "Shoes", Price: money.FromInt(50), TotalRating: 4, RatingAverage: 4, RatingCount: 1, Rating4: 1, Status: 1, PublishedAt: &published},
+		&models.Product{Id: 3, BrandId: 1, Sku: "SKU-003", Name: "Hat", Price: money.FromInt(25), Status: 1, PublishedAt: &published},
+		&models.Product{Id: 4, BrandId: 1, Sku: "SKU-004", Name: "Draft", Price: money.FromInt(10)},
+		&models.ProductImage{Id: 1, ProductId: 1, Path: "shirt.png", Status: 1},
+		&models.ProductInventory{Id: 1, ProductId: 1, SizeId: 1, ColourId: 1, Stock: 10, Status: 1},
+		&models.ProductInventory{Id: 2, ProductId: 2, SizeId: 1, ColourId: 1, Stock: 1, Status: 1},
+		&models.ProductInventory{Id: 3, ProductId: 3, SizeId: 1, ColourId: 1, Stock: 5, Status: 1},
+		&models.ProductInventory{Id: 4, ProductId: 4, SizeId: 1, ColourId: 1, Stock: 5, Status: 1},
+
+		&models.User{Id: customerId, Email: customerEmail, Password: hashedPass, FirstName: helpers.NewNullString("Ada"), LastName: helpers.NewNullString("Lovelace"), Status: 1},
+		&models.User{Id: adminId, Email: adminEmail, Password: hashedPass, FirstName: helpers.NewNullString("Grace"), Status: 1},
+		&models.User{Id: pendingId, Email: pendingEmail, Password: hashedPass, FirstName: helpers.NewNullString("Alan"), Status: 0},
+
+		&models.Order{Id: 1, UserId: customerId, PaymentId: 1, InvoiceNumber: "INV-0001", TotalItem: 1, Subtotal: money.FromInt(100), TotalPaid: money.FromInt(100), Status: models.OrderStatusPaid},
+		&models.OrderDetail{Id: 1, OrderId: 1, InventoryId: 1, Price: money.FromInt(100), Qty: 1, Total: money.FromInt(100)},
+
+		&models.Coupon{Id: 1, Code: "SAVE10", Name: "Ten off", Calculation: models.PriceCalculationPercent, Value: money.FromInt(10), UsageLimitPerUser: 1, Status: 1},
+		&models.Coupon{Id: 2, Code: "LAUNCH", Name: "Launch", Calculation: models.PriceCalculationFixed, Value: money.FromInt(5), Status: 1},
+		&models.OrderCoupon{Id: 1, OrderId: 1, CouponId: 2, UserId: customerId, Code: "LAUNCH", Amount: money.FromInt(5)},
+	}
+
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("fixture %T: %v", row, err)
+		}
+	}
+
+	statements := []string{
+		"INSERT INTO products_categories(product_id, category_id) VALUES(1, 1)",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("fixture %q: %v", statement, err)
+		}
+	}
+
+	access := services.AccessControl(repositories.NewStore(db))
+	for id, role := range map[uint64]string{customerId: models.RoleCustomer, adminId: models.RoleAdmin, pendingId: models.RoleCustomer} {
+		if err := access.AssignRoles(id, role); err != nil {
+			t.Fatalf("assign %s role: %v", role, err)
+		}
+	}
+}
+
+// token signs in the fixture user without going through the login route.
+func (s *testServer) token(userId uint64) string {
+	s.t.Helper()
+
+	var user models.User
+	if err := s.db.Where("id = ?", userId).First(&user).Error; err != nil {
+		s.t.Fatalf("user %d: %v", userId, err)
+	}
+
+	pair, err := services.Sessions(s.config, repositories.NewStore(s.db)).Issue(user)
+	if err != nil {
+		s.t.Fatalf("issue session for user %d: %v", userId, err)
+	}
+	return pair.AccessToken
+}
+
+// do sends body as JSON, or as is when it is already a reader.
+func (s *testServer) do(method string, path string, token string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.serve(s.request(method, path, token, body))
+}
+
+// doAsGuest is do with the guest cart token header instead of an access token.
+func (s *testServer) doAsGuest(method string, path string, cartToken string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+	req := s.request(method, path, "", body)
+	if cartToken != "" {
+		req.Header.Set(controllers.CartTokenHeader, cartToken)
+	}
+	return s.serve(req)
+}
+
+func (s *testServer) request(method string, path string, token string, body interface{}) *http.Request {
+	s.t.Helper()
+
+	var reader io.Reader
+	switch value := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = value
+	default:
+		payload, err := json.Marshal(value)
+		if err != nil {
+			s.t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func (s *testServer) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// expect fails the test unless the response has the given status, and
+// decodes the body into out when out is not nil.
+func (s *testServer) expect(w *httptest.ResponseRecorder, status int, out interface{}) {
+	s.t.Helper()
+
+	if w.Code != status {
+		s.t.Fatalf("status = %d, want %d; body: %s", w.Code, status, w.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("decode %s: %v", w.Body.String(), err)
+		}
+	}
+}
+
+// count returns the number of rows in table matching where.
+func (s *testServer) count(table string, where string, args ...interface{}) int {
+	s.t.Helper()
+
+	var total int
+	if err := s.db.Table(table).Where(where, args...).Count(&total).Error; err != nil {
+		s.t.Fatalf("count %s: %v", table, err)
+	}
+	return total
+}
@@ -0,0 +1,425 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	controllers "backend/src/controllers"
+	models "backend/src/models"
+	money "backend/src/money"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type orderDetailResponse struct {
+	Order    models.Order `json:"order"`
+	Status   string       `json:"status"`
+	Discount money.Rate   `json:"discount"`
+	Taxes    money.Rate   `json:"taxes"`
+	Carts    []struct {
+		Id  int64
+		Qty uint16
+	} `json:"carts"`
+}
+
+func (s *testServer) addToCart(token string, productId uint64, qty int) {
+	s.t.Helper()
+	path := fmt.Sprintf("/api/order/create/cart/%d", productId)
+	s.expect(s.do(http.MethodPost, path, token, map[string]int{"size_id": 1, "colour_id": 1, "qty": qty}), http.StatusOK, nil)
+}
+
+func (s *testServer) stock(inventoryId uint64) uint16 {
+	s.t.Helper()
+	var inventory models.ProductInventory
+	if err := s.db.Where("id = ?", inventoryId).First(&inventory).Error; err != nil {
+		s.t.Fatalf("inventory %d: %v", inventoryId, err)
+	}
+	return inventory.Stock
+}
+
+func TestCheckoutTotals(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 2)
+	server.addToCart(token, 3, 2)
+
+	// 250 subtotal, 5% discount, 10% taxes on the discounted 237.5 and a
+	// flat 15 shipment.
+	subtotal, discount, taxes, shipment := money.FromInt(250), money.MustParse("12.5"), money.MustParse("23.75"), money.FromInt(15)
+	total := subtotal - discount + taxes + shipment
+
+	var quote struct {
+		Order     models.Order `json:"order"`
+		Discount  money.Rate   `json:"discount"`
+		Taxes     money.Rate   `json:"taxes"`
+		Shipment  money.Money  `json:"shipment"`
+		Breakdown struct {
+			Currency    string                        `json:"currency"`
+			Lines       []struct{ Total money.Money } `json:"lines"`
+			Adjustments []struct {
+				Kind   string      `json:"kind"`
+				Amount money.Money `json:"amount"`
+			} `json:"adjustments"`
+			Total string `json:"total"`
+		} `json:"breakdown"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/checkout/initial", token, nil), http.StatusOK, &quote)
+	if quote.Order.Subtotal != subtotal || quote.Order.TotalPaid != total {
+		t.Errorf("quote subtotal %v and total %v, want %v and %v", quote.Order.Subtotal, quote.Order.TotalPaid, subtotal, total)
+	}
+	if quote.Breakdown.Total != "276.25" || quote.Breakdown.Currency != "USD" {
+		t.Errorf("breakdown total = %q %s, want the string \"276.25\" in USD", quote.Breakdown.Total, quote.Breakdown.Currency)
+	}
+	if quote.Discount != money.MustParseRate("5") || quote.Taxes != money.MustParseRate("10") || quote.Shipment != shipment {
+		t.Errorf("quote rates = %v%%, %v%% and %v, want 5%%, 10%% and %v", quote.Discount, quote.Taxes, quote.Shipment, shipment)
+	}
+	if len(quote.Breakdown.Lines) != 2 || len(quote.Breakdown.Adjustments) != 3 || quote.Breakdown.Adjustments[0].Amount != -discount {
+		t.Errorf("breakdown = %+v", quote.Breakdown)
+	}
+
+	var local struct {
+		Taxes money.Rate `json:"taxes"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/checkout/initial?country=Indonesia", token, nil), http.StatusOK, &local)
+	if local.Taxes != money.MustParseRate("11") {
+		t.Errorf("tax rate for Indonesia = %v%%, want 11%%", local.Taxes)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": 1,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusOK, nil)
+
+	var detail orderDetailResponse
+	path := fmt.Sprintf("/api/order/detail/%d", quote.Order.Id)
+	server.expect(server.do(http.MethodGet, path, token, nil), http.StatusOK, &detail)
+
+	order := detail.Order
+	if order.Subtotal != subtotal || order.TotalDiscount != discount || order.TotalTaxes != taxes || order.TotalShipment != shipment || order.TotalPaid != total {
+		t.Errorf("order totals = %v, %v, %v, %v, %v", order.Subtotal, order.TotalDiscount, order.TotalTaxes, order.TotalShipment, order.TotalPaid)
+	}
+	if detail.Status != "pending_payment" || detail.Discount != money.MustParseRate("5") || detail.Taxes != money.MustParseRate("10") {
+		t.Errorf("detail status %q, discount %v%%, taxes %v%%", detail.Status, detail.Discount, detail.Taxes)
+	}
+	if len(detail.Carts) != 2 {
+		t.Errorf("got %d lines, want 2", len(detail.Carts))
+	}
+
+	if stock := server.stock(1); stock != 8 {
+		t.Errorf("inventory 1 stock = %d, want 8", stock)
+	}
+	if stock := server.stock(3); stock != 3 {
+		t.Errorf("inventory 3 stock = %d, want 3", stock)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{"payment_id": 1, "email": customerEmail}), http.StatusBadRequest, nil)
+}
+
+func TestCheckoutOutOfStock(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	server.addToCart(token, 2, 2)
+
+	var conflict struct {
+		Lines []struct {
+			InventoryId uint64 `json:"inventory_id"`
+			Requested   uint16 `json:"requested"`
+			Available   uint16 `json:"available"`
+		} `json:"lines"`
+	}
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{"payment_id": 1, "email": customerEmail}), http.StatusConflict, &conflict)
+
+	if len(conflict.Lines) != 1 || conflict.Lines[0].InventoryId != 2 || conflict.Lines[0].Requested != 2 || conflict.Lines[0].Available != 1 {
+		t.Errorf("lines = %+v, want inventory 2 short by one", conflict.Lines)
+	}
+	if stock := server.stock(1); stock != 10 {
+		t.Errorf("inventory 1 stock = %d, want it untouched", stock)
+	}
+}
+
+func TestCancelReturnsStock(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", models.OrderStatusPendingPayment).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/order/cancel/1", token, nil), http.StatusOK, nil)
+
+	if stock := server.stock(1); stock != 11 {
+		t.Errorf("inventory 1 stock = %d, want 11", stock)
+	}
+	if n := server.count("order_status_history", "order_id = ? AND to_status = ?", 1, models.OrderStatusCancelled); n != 1 {
+		t.Errorf("got %d cancel history rows, want 1", n)
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/order/cancel/1", token, nil), http.StatusConflict, nil)
+}
+
+func TestCancelRefusesPaidOrders(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	for _, status := range []uint8{models.OrderStatusPaid, models.OrderStatusProcessing} {
+		if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", status).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		server.expect(server.do(http.MethodGet, "/api/order/cancel/1", token, nil), http.StatusConflict, nil)
+
+		if n := server.count("orders", "id = ? AND status = ?", 1, status); n != 1 {
+			t.Errorf("a %s order changed status on a customer cancel", models.OrderStatusNames[status])
+		}
+		if stock := server.stock(1); stock != 10 {
+			t.Errorf("inventory 1 stock = %d, want it untouched", stock)
+		}
+	}
+}
+
+func TestOrderListPagination(t *testing.T) {
+
+	server := newTestServer(t)
+
+	// The fixture order plus eleven more for the customer, and one of
+	// another user that must never show up.
+	for id := uint64(2); id <= 12; id++ {
+		order := models.Order{Id: id, UserId: customerId, PaymentId: 1, InvoiceNumber: fmt.Sprintf("INV-%04d", id), Status: models.OrderStatusDelivered}
+		if err := server.db.Create(&order).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := server.db.Create(&models.Order{Id: 13, UserId: adminId, PaymentId: 1, InvoiceNumber: "INV-0013"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token := server.token(customerId)
+
+	tests := []struct {
+		query    string
+		ids      []uint64
+		filtered int
+	}{
+		{"page=1&limit=5", []uint64{12, 11, 10, 9, 8}, 12},
+		{"page=2&limit=5", []uint64{7, 6, 5, 4, 3}, 12},
+		{"page=3&limit=5", []uint64{2, 1}, 12},
+		{"page=4&limit=5", nil, 12},
+		{"page=1&limit=3&order_dir=asc", []uint64{1, 2, 3}, 12},
+		{"page=1&limit=5&search=INV-0007", []uint64{7}, 1},
+		{"page=1&limit=5&order_by=id;drop", []uint64{12, 11, 10, 9, 8}, 12},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+
+			var page struct {
+				List          []models.Order `json:"list"`
+				TotalAll      int            `json:"totalAll"`
+				TotalFiltered int            `json:"totalFiltered"`
+			}
+			server.expect(server.do(http.MethodGet, "/api/order/list?"+test.query, token, nil), http.StatusOK, &page)
+
+			var ids []uint64
+			for _, order := range page.List {
+				ids = append(ids, order.Id)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.ids) {
+				t.Errorf("ids = %v, want %v", ids, test.ids)
+			}
+			if page.TotalAll != 12 || page.TotalFiltered != test.filtered {
+				t.Errorf("totals = %d and %d, want 12 and %d", page.TotalAll, page.TotalFiltered, test.filtered)
+			}
+		})
+	}
+}
+
+// The wishlist toggle used to run "DELETE ... WHERE product_id = ? AND = ?",
+// which is invalid SQL, so the old row was never removed and the insert
+// that followed failed on the primary key.
+func TestWishlistRegression(t *testing.T) {
+
+	server := newTestServer(t)
+	if err := server.db.Exec("INSERT INTO products_wishlists(product_id, user_id) VALUES(1, ?)", adminId).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token := server.token(customerId)
+	for i := 0; i < 2; i++ {
+		server.expect(server.do(http.MethodGet, "/api/order/wishlist/1", token, nil), http.StatusOK, nil)
+	}
+
+	if n := server.count("products_wishlists", "product_id = 1 AND user_id = ?", customerId); n != 1 {
+		t.Errorf("customer has %d wishlist rows for product 1, want 1", n)
+	}
+	if n := server.count("products_wishlists", "product_id = 1 AND user_id = ?", adminId); n != 1 {
+		t.Errorf("the other user's wishlist row was touched, %d left", n)
+	}
+
+	var session struct {
+		Wishlist []struct{ Id int64 } `json:"whislists"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/session", token, nil), http.StatusOK, &session)
+	if len(session.Wishlist) != 1 || session.Wishlist[0].Id != 1 {
+		t.Errorf("session wishlist = %+v, want product 1", session.Wishlist)
+	}
+}
+
+func TestCartLines(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	server.addToCart(token, 3, 2)
+
+	var session struct {
+		Order models.Order `json:"order"`
+		Carts []struct {
+			Id       int64
+			DetailId uint64
+			Qty      uint16
+		} `json:"carts"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/session", token, nil), http.StatusOK, &session)
+	if len(session.Carts) != 2 || session.Order.Subtotal != money.FromInt(150) {
+		t.Fatalf("session = %+v, want two lines for 150", session)
+	}
+	shirt, hats := session.Carts[0].DetailId, session.Carts[1].DetailId
+	if session.Carts[0].Id != 1 {
+		shirt, hats = hats, shirt
+	}
+
+	server.expect(server.do(http.MethodPatch, fmt.Sprintf("/api/order/cart/line/%d", hats), token, map[string]int{"qty": 4}), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/order/session", token, nil), http.StatusOK, &session)
+	if order := session.Order; order.TotalItem != 5 || order.Subtotal != money.FromInt(200) || order.TotalPaid != money.FromInt(200) {
+		t.Errorf("after the update the cart has %d items, subtotal %v and total %v, want 5, 200 and 200", order.TotalItem, order.Subtotal, order.TotalPaid)
+	}
+
+	other := server.token(adminId)
+	server.expect(server.do(http.MethodDelete, fmt.Sprintf("/api/order/cart/line/%d", shirt), other, nil), http.StatusNotFound, nil)
+
+	server.expect(server.do(http.MethodDelete, fmt.Sprintf("/api/order/cart/line/%d", shirt), token, nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/order/session", token, nil), http.StatusOK, &session)
+	if len(session.Carts) != 1 || session.Order.Subtotal != money.FromInt(100) {
+		t.Errorf("after removing the shirt the session = %+v, want four hats for 100", session)
+	}
+	if n := server.count("orders_carts", "order_id = ? AND product_id = 1", session.Order.Id); n != 0 {
+		t.Errorf("the removed shirt is still attached to the cart order")
+	}
+
+	server.expect(server.do(http.MethodDelete, "/api/order/cart/clear", token, nil), http.StatusOK, nil)
+	server.expect(server.do(http.MethodGet, "/api/order/session", token, nil), http.StatusOK, &session)
+	if len(session.Carts) != 0 || session.Order.TotalItem != 0 || session.Order.TotalPaid != 0 {
+		t.Errorf("after clearing the session = %+v, want an empty cart", session)
+	}
+	server.expect(server.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": 1,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusBadRequest, nil)
+}
+
+func TestGuestCartMergedOnLogin(t *testing.T) {
+
+	server := newTestServer(t)
+
+	var added struct {
+		CartToken string `json:"cart_token"`
+	}
+	server.expect(server.doAsGuest(http.MethodPost, "/api/order/create/cart/1", "", map[string]int{"size_id": 1, "colour_id": 1, "qty": 2}), http.StatusOK, &added)
+	if added.CartToken == "" {
+		t.Fatal("adding to a guest cart returned no cart token")
+	}
+	guestToken := added.CartToken
+	server.expect(server.doAsGuest(http.MethodPost, "/api/order/create/cart/3", guestToken, map[string]int{"size_id": 1, "colour_id": 1, "qty": 9}), http.StatusOK, &added)
+	if added.CartToken != guestToken {
+		t.Errorf("second add returned cart token %q, want the first one", added.CartToken)
+	}
+
+	var session struct {
+		Order models.Order `json:"order"`
+		Carts []struct {
+			Id       int64
+			DetailId uint64
+			Qty      uint16
+		} `json:"carts"`
+	}
+	server.expect(server.doAsGuest(http.MethodGet, "/api/order/session", guestToken, nil), http.StatusOK, &session)
+	if len(session.Carts) != 2 || session.Order.TotalItem != 11 {
+		t.Fatalf("guest session = %+v, want two lines of 11 items", session)
+	}
+	server.expect(server.doAsGuest(http.MethodGet, "/api/order/session", "", nil), http.StatusOK, &session)
+	if len(session.Carts) != 0 {
+		t.Errorf("a visitor without the token sees %d cart lines", len(session.Carts))
+	}
+
+	// The customer already has a shirt in their cart; the hats are capped
+	// at the five in stock.
+	customer := server.token(customerId)
+	server.addToCart(customer, 1, 1)
+
+	req := server.request(http.MethodPost, "/api/auth/login", "", map[string]string{"email": customerEmail, "password": fixturePass})
+	req.Header.Set(controllers.CartTokenHeader, guestToken)
+	server.expect(server.serve(req), http.StatusOK, nil)
+
+	server.expect(server.do(http.MethodGet, "/api/order/session", customer, nil), http.StatusOK, &session)
+	quantities := map[int64]uint16{}
+	for _, line := range session.Carts {
+		quantities[line.Id] = line.Qty
+	}
+	if len(quantities) != 2 || quantities[1] != 3 || quantities[3] != 5 {
+		t.Errorf("merged cart quantities = %v, want 3 shirts and 5 hats", quantities)
+	}
+	if session.Order.TotalItem != 8 || session.Order.Subtotal != money.FromInt(425) {
+		t.Errorf("merged cart has %d items for %v, want 8 for 425", session.Order.TotalItem, session.Order.Subtotal)
+	}
+	if n := server.count("orders", "status = ?", models.OrderStatusCart); n != 1 {
+		t.Errorf("%d carts are left, want the guest cart merged away", n)
+	}
+
+	server.expect(server.doAsGuest(http.MethodGet, "/api/order/session", guestToken, nil), http.StatusOK, &session)
+	if len(session.Carts) != 0 {
+		t.Errorf("the cart token still opens %d lines after the merge", len(session.Carts))
+	}
+}
+
+func TestReviewRequiresDelivery(t *testing.T) {
+
+	server := newTestServer(t)
+	customer := server.token(customerId)
+	review := map[string]interface{}{"rating": 4, "review": "Fits well"}
+
+	// The fixture order holds the shirt but has only been paid for.
+	server.expect(server.do(http.MethodPost, "/api/order/review/1", customer, review), http.StatusForbidden, nil)
+
+	if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", models.OrderStatusDelivered).Error; err != nil {
+		t.Fatalf("deliver order: %v", err)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/order/review/1", customer, review), http.StatusOK, nil)
+	server.expect(server.do(http.MethodPost, "/api/order/review/2", customer, review), http.StatusForbidden, nil)
+	server.expect(server.do(http.MethodPost, "/api/order/review/1", server.token(adminId), review), http.StatusForbidden, nil)
+
+	if reviews := server.count("products_reviews", "product_id = ?", 1); reviews != 1 {
+		t.Errorf("%d reviews stored, want 1", reviews)
+	}
+}
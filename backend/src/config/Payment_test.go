@@ -0,0 +1,202 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	payment "backend/src/payment"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const webhookSecret = "whsec_integration"
+
+// useMockGateway points the gateway provider at a local server that
+// creates intents pi_1, pi_2 and so on.
+func (s *testServer) useMockGateway() {
+	s.t.Helper()
+
+	var intents int64
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payment_intents" || r.Header.Get("Authorization") != "Bearer sk_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id := atomic.AddInt64(&intents, 1)
+		fmt.Fprintf(w, `{"id":"pi_%d","status":"requires_action","redirect_url":"https://pay.test/pi_%d"}`, id, id)
+	}))
+	s.t.Cleanup(gateway.Close)
+
+	s.config.Payment = appconfig.PaymentConfig{
+		GatewayURL:    gateway.URL,
+		GatewayKey:    "sk_test",
+		WebhookSecret: webhookSecret,
+		Timeout:       time.Second,
+	}
+}
+
+// webhook posts a gateway event signed with secret.
+func (s *testServer) webhook(secret string, id string, kind string, reference string, amount string) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"id":   id,
+		"type": kind,
+		"data": map[string]string{"id": reference, "amount": amount, "currency": "usd", "failure_message": "card declined"},
+	})
+	req := s.request(http.MethodPost, "/api/payment/webhook/gateway", "", bytes.NewReader(payload))
+	req.Header.Set(payment.SignatureHeader, payment.Sign(secret, payload, time.Now()))
+	return s.serve(req)
+}
+
+func (s *testServer) checkoutWith(token string, paymentId uint64) (models.Order, *models.PaymentTransaction) {
+	s.t.Helper()
+
+	var placed struct {
+		Order   models.Order               `json:"order"`
+		Payment *models.PaymentTransaction `json:"payment"`
+	}
+	s.expect(s.do(http.MethodPost, "/api/order/checkout/submit", token, map[string]interface{}{
+		"payment_id": paymentId,
+		"email":      customerEmail,
+		"first_name": "Ada",
+		"address":    "1 Main Street",
+	}), http.StatusOK, &placed)
+
+	return placed.Order, placed.Payment
+}
+
+func (s *testServer) orderStatus(id uint64) string {
+	s.t.Helper()
+
+	var order models.Order
+	if err := s.db.Where("id = ?", id).First(&order).Error; err != nil {
+		s.t.Fatalf("order %d: %v", id, err)
+	}
+	return order.StatusName()
+}
+
+func TestPaymentGatewayCheckout(t *testing.T) {
+
+	server := newTestServer(t)
+	server.useMockGateway()
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	order, started := server.checkoutWith(token, 2)
+	if started == nil || started.Reference != "pi_1" || started.RedirectURL.String != "https://pay.test/pi_1" {
+		t.Fatalf("payment = %+v, want intent pi_1 with its payment page", started)
+	}
+	if status := server.orderStatus(order.Id); status != "pending_payment" {
+		t.Fatalf("status after checkout = %s, want pending_payment", status)
+	}
+
+	// Forged and unknown-provider deliveries change nothing.
+	server.expect(server.webhook("wrong-secret", "evt_1", "payment_intent.succeeded", "pi_1", order.TotalPaid.String()), http.StatusUnauthorized, nil)
+	server.expect(server.do(http.MethodPost, "/api/payment/webhook/manual", "", strings.NewReader("{}")), http.StatusNotFound, nil)
+	server.expect(server.do(http.MethodPost, "/api/payment/webhook/nope", "", strings.NewReader("{}")), http.StatusNotFound, nil)
+
+	for i := 0; i < 2; i++ {
+		server.expect(server.webhook(webhookSecret, "evt_1", "payment_intent.succeeded", "pi_1", order.TotalPaid.String()), http.StatusOK, nil)
+	}
+
+	if status := server.orderStatus(order.Id); status != "paid" {
+		t.Errorf("status after the webhook = %s, want paid", status)
+	}
+	if events := server.count("payments_events", "event_id = ?", "evt_1"); events != 1 {
+		t.Errorf("%d events recorded, want 1", events)
+	}
+	if paid := server.count("order_status_history", "order_id = ? AND to_status = ?", order.Id, models.OrderStatusPaid); paid != 1 {
+		t.Errorf("%d paid histories, want 1", paid)
+	}
+
+	var detail struct {
+		Transactions []models.PaymentTransaction `json:"transactions"`
+	}
+	server.expect(server.do(http.MethodGet, fmt.Sprintf("/api/order/detail/%d", order.Id), token, nil), http.StatusOK, &detail)
+	if len(detail.Transactions) != 1 || detail.Transactions[0].Status != payment.StatusSucceeded {
+		t.Errorf("transactions = %+v, want one succeeded", detail.Transactions)
+	}
+
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/order/payment/%d", order.Id), token, nil), http.StatusConflict, nil)
+}
+
+func TestPaymentGatewayFailureAndRetry(t *testing.T) {
+
+	server := newTestServer(t)
+	server.useMockGateway()
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	order, _ := server.checkoutWith(token, 2)
+
+	server.expect(server.webhook(webhookSecret, "evt_1", "payment_intent.payment_failed", "pi_1", order.TotalPaid.String()), http.StatusOK, nil)
+	if status := server.orderStatus(order.Id); status != "payment_failed" {
+		t.Fatalf("status after a failed payment = %s, want payment_failed", status)
+	}
+
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/order/payment/%d", order.Id), server.token(adminId), nil), http.StatusNotFound, nil)
+
+	var retry struct {
+		Payment models.PaymentTransaction `json:"payment"`
+	}
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/order/payment/%d", order.Id), token, nil), http.StatusOK, &retry)
+	if retry.Payment.Reference != "pi_2" || server.orderStatus(order.Id) != "pending_payment" {
+		t.Errorf("retry = %s with the order %s, want pi_2 and pending_payment", retry.Payment.Reference, server.orderStatus(order.Id))
+	}
+
+	server.expect(server.webhook(webhookSecret, "evt_2", "payment_intent.succeeded", "pi_2", order.TotalPaid.String()), http.StatusOK, nil)
+	if status := server.orderStatus(order.Id); status != "paid" {
+		t.Errorf("status after the retry was paid = %s, want paid", status)
+	}
+}
+
+func TestPaymentManualCheckout(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.addToCart(token, 1, 1)
+	order, started := server.checkoutWith(token, 1)
+	if started == nil || started.Provider != payment.ProviderManual || started.Status != payment.StatusPending || started.RedirectURL.Valid {
+		t.Errorf("payment = %+v, want a pending manual payment", started)
+	}
+	if status := server.orderStatus(order.Id); status != "pending_payment" {
+		t.Errorf("status = %s, want pending_payment until staff confirm it", status)
+	}
+
+	// Confirming the transfer settles the attempt, so a later refund is
+	// booked against it.
+	admin := server.token(adminId)
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/admin/order/status/%d", order.Id), admin, map[string]string{"status": "paid"}), http.StatusOK, nil)
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/admin/order/status/%d", order.Id), admin, map[string]string{"status": "cancelled"}), http.StatusOK, nil)
+
+	var transaction models.PaymentTransaction
+	server.db.Where("id = ?", started.Id).First(&transaction)
+	if transaction.Status != payment.StatusSucceeded || transaction.Refunded != order.TotalPaid {
+		t.Errorf("payment = %+v, want it succeeded and refunded %v", transaction, order.TotalPaid)
+	}
+
+	// Without a configured gateway the order is still placed.
+	server.addToCart(token, 3, 1)
+	if _, started := server.checkoutWith(token, 2); started != nil {
+		t.Errorf("payment = %+v, want none while the gateway is off", started)
+	}
+}
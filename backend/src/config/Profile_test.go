@@ -0,0 +1,102 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	schema "backend/src/schema"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func (s *testServer) upload(token string, name string, content []byte) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/profile/upload", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestProfileUpload(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	var first, second struct {
+		Data string `json:"data"`
+	}
+	server.expect(server.upload(token, "avatar.png", []byte("first image")), http.StatusOK, &first)
+
+	if filepath.Ext(first.Data) != ".png" {
+		t.Errorf("stored path %q lost the extension", first.Data)
+	}
+	content, err := os.ReadFile(filepath.Join(server.config.UploadPath, first.Data))
+	if err != nil || string(content) != "first image" {
+		t.Fatalf("uploaded file = %q, %v", content, err)
+	}
+
+	var profile schema.UserProfileSchema
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", token, nil), http.StatusOK, &profile)
+	if profile.Image.String != first.Data {
+		t.Errorf("profile image = %q, want %q", profile.Image.String, first.Data)
+	}
+
+	server.expect(server.do(http.MethodGet, "/uploads/"+first.Data, "", nil), http.StatusOK, nil)
+
+	// A new picture replaces the file of the previous one.
+	server.expect(server.upload(token, "avatar.jpg", []byte("second image")), http.StatusOK, &second)
+	if _, err := os.Stat(filepath.Join(server.config.UploadPath, first.Data)); !os.IsNotExist(err) {
+		t.Errorf("previous image still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(server.config.UploadPath, second.Data)); err != nil {
+		t.Errorf("new image is missing: %v", err)
+	}
+}
+
+func TestProfileUpdate(t *testing.T) {
+
+	server := newTestServer(t)
+	token := server.token(customerId)
+
+	server.expect(server.do(http.MethodPost, "/api/profile/update", token, map[string]string{
+		"email":      "ada@example.com",
+		"first_name": "Augusta",
+		"city":       "London",
+	}), http.StatusOK, nil)
+
+	var profile schema.UserProfileSchema
+	server.expect(server.do(http.MethodGet, "/api/profile/detail", token, nil), http.StatusOK, &profile)
+	if profile.Email != "ada@example.com" || profile.FirstName != "Augusta" || profile.City != "London" {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	var activities []models.Activity
+	server.expect(server.do(http.MethodGet, "/api/profile/activity", token, nil), http.StatusOK, &activities)
+}
@@ -0,0 +1,160 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	payment "backend/src/payment"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReturnWorkflow(t *testing.T) {
+
+	server := newTestServer(t)
+	customer := server.token(customerId)
+	admin := server.token(adminId)
+	empty := map[string]string{}
+
+	if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", models.OrderStatusDelivered).Error; err != nil {
+		t.Fatalf("deliver order: %v", err)
+	}
+
+	request := map[string]interface{}{
+		"reason": "Wrong size",
+		"lines":  []map[string]interface{}{{"detail_id": 1, "qty": 1}},
+	}
+
+	var requested struct {
+		Return models.OrderReturn `json:"return"`
+	}
+	server.expect(server.do(http.MethodPost, "/api/order/return/1", server.token(pendingId), request), http.StatusNotFound, nil)
+	server.expect(server.do(http.MethodPost, "/api/order/return/1", customer, request), http.StatusOK, &requested)
+	server.expect(server.do(http.MethodPost, "/api/order/return/1", customer, request), http.StatusUnprocessableEntity, nil)
+
+	id := requested.Return.Id
+	step := func(name string, body interface{}, status int) {
+		t.Helper()
+		server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/admin/return/%s/%d", name, id), admin, body), status, nil)
+	}
+
+	step("receive", empty, http.StatusConflict)
+	step("approve", map[string]string{"note": "Please use the prepaid label"}, http.StatusOK)
+	step("receive", empty, http.StatusOK)
+	if stock := server.stock(1); stock != 11 {
+		t.Errorf("stock after receipt = %d, want 10 + 1", stock)
+	}
+	step("refund", map[string]string{"amount": "100.01"}, http.StatusUnprocessableEntity)
+	step("refund", empty, http.StatusOK)
+
+	if status := server.orderStatus(1); status != "refunded" {
+		t.Errorf("order status = %s, want refunded once all of it was paid back", status)
+	}
+	var order models.Order
+	server.db.Where("id = ?", 1).First(&order)
+	if order.TotalRefunded != money.FromInt(100) {
+		t.Errorf("order refunded %v, want 100", order.TotalRefunded)
+	}
+	if steps := server.count("activities", "user_id = ? AND subject = ?", customerId, "Return Order"); steps != 4 {
+		t.Errorf("%d return activities for the customer, want one per step", steps)
+	}
+	if steps := server.count("orders_returns_history", "return_id = ? AND actor_id = ? AND actor_type = ?", id, adminId, "staff"); steps != 3 {
+		t.Errorf("%d return steps recorded for the admin, want approve, receive and refund", steps)
+	}
+
+	var detail struct {
+		Return models.OrderReturn `json:"return"`
+	}
+	server.expect(server.do(http.MethodGet, fmt.Sprintf("/api/admin/return/detail/%d", id), admin, nil), http.StatusOK, &detail)
+	if n := len(detail.Return.Histories); n != 4 {
+		t.Errorf("return detail lists %d steps, want 4", n)
+	}
+
+	var list struct {
+		Returns []models.OrderReturn `json:"returns"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/returns/1", customer, nil), http.StatusOK, &list)
+	if len(list.Returns) != 1 || list.Returns[0].Status != models.ReturnStatusRefunded || list.Returns[0].RefundAmount != money.FromInt(100) || len(list.Returns[0].Lines) != 1 {
+		t.Errorf("returns = %+v, want the refunded return with its line", list.Returns)
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/returns/1", admin, nil), http.StatusNotFound, nil)
+}
+
+func TestReturnNotDelivered(t *testing.T) {
+
+	server := newTestServer(t)
+
+	server.expect(server.do(http.MethodPost, "/api/order/return/1", server.token(customerId), map[string]interface{}{
+		"reason": "Changed my mind",
+		"lines":  []map[string]interface{}{{"detail_id": 1, "qty": 1}},
+	}), http.StatusConflict, nil)
+}
+
+func TestStaffCancelRefundsPaidOrder(t *testing.T) {
+
+	server := newTestServer(t)
+	admin := server.token(adminId)
+
+	transaction := models.PaymentTransaction{OrderId: 1, PaymentId: 1, Provider: "manual", Reference: "manual-1", Amount: money.FromInt(100), Currency: "USD", Status: payment.StatusSucceeded}
+	if err := server.db.Create(&transaction).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "cancelled", "reason": "Out of stock at the warehouse"}), http.StatusOK, nil)
+
+	if status := server.orderStatus(1); status != "cancelled" {
+		t.Errorf("order status = %s, want cancelled", status)
+	}
+	var order models.Order
+	server.db.Where("id = ?", 1).First(&order)
+	if order.TotalRefunded != order.TotalPaid {
+		t.Errorf("order refunded %v of %v, want all of it", order.TotalRefunded, order.TotalPaid)
+	}
+	server.db.Where("id = ?", transaction.Id).First(&transaction)
+	if transaction.Refunded != money.FromInt(100) {
+		t.Errorf("transaction refunded %v, want 100", transaction.Refunded)
+	}
+	if stock := server.stock(1); stock != 11 {
+		t.Errorf("inventory 1 stock = %d, want 10 + 1", stock)
+	}
+}
+
+func TestOrderRefundedOnlyThroughReturns(t *testing.T) {
+
+	server := newTestServer(t)
+	admin := server.token(adminId)
+
+	if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", models.OrderStatusDelivered).Error; err != nil {
+		t.Fatalf("deliver order: %v", err)
+	}
+
+	server.expect(server.do(http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "refunded"}), http.StatusUnprocessableEntity, nil)
+
+	if status := server.orderStatus(1); status != "delivered" {
+		t.Errorf("order status = %s, want it left delivered", status)
+	}
+	if n := server.count("order_status_history", "order_id = ? AND to_status = ?", 1, models.OrderStatusRefunded); n != 0 {
+		t.Errorf("got %d refund history rows, want none", n)
+	}
+
+	var detail struct {
+		Transitions []uint8 `json:"transitions"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/admin/order/detail/1", admin, nil), http.StatusOK, &detail)
+	for _, to := range detail.Transitions {
+		if to == models.OrderStatusRefunded {
+			t.Errorf("transitions = %v, want refunded left to the return path", detail.Transitions)
+		}
+	}
+}
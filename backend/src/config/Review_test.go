@@ -0,0 +1,129 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	services "backend/src/services"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestReviewModeration(t *testing.T) {
+
+	server := newTestServer(t)
+	customer := server.token(customerId)
+	admin := server.token(adminId)
+
+	if err := server.db.Model(&models.Order{}).Where("id = ?", 1).Update("status", models.OrderStatusDelivered).Error; err != nil {
+		t.Fatalf("deliver order: %v", err)
+	}
+
+	var submitted struct {
+		Review models.ProductReview `json:"review"`
+	}
+	server.expect(server.do(http.MethodPost, "/api/order/review/1", customer, map[string]interface{}{"rating": 2, "review": "Runs small"}), http.StatusOK, &submitted)
+	id := submitted.Review.Id
+	if !submitted.Review.Verified || submitted.Review.Status != models.ReviewStatusPending {
+		t.Errorf("review = %+v, want a verified review waiting for moderation", submitted.Review)
+	}
+
+	var public []services.ReviewCard
+	server.expect(server.do(http.MethodGet, "/api/order/review/1", customer, nil), http.StatusOK, &public)
+	if len(public) != 0 {
+		t.Errorf("listed %d reviews before moderation, want none", len(public))
+	}
+
+	var queue struct {
+		List []models.ProductReview `json:"list"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/admin/review/list?status=pending", admin, nil), http.StatusOK, &queue)
+	if len(queue.List) != 1 || queue.List[0].Id != id {
+		t.Errorf("queue = %+v, want the new review", queue.List)
+	}
+
+	// Writing again edits the one review the customer has of the product.
+	server.expect(server.do(http.MethodPost, "/api/order/review/1", customer, map[string]interface{}{"rating": 5, "review": "Fits after all"}), http.StatusOK, nil)
+	if reviews := server.count("products_reviews", "product_id = ? AND user_id = ?", 1, customerId); reviews != 1 {
+		t.Errorf("%d reviews stored, want 1", reviews)
+	}
+
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/admin/review/approve/%d", id), admin, map[string]string{"note": "Thanks"}), http.StatusOK, nil)
+
+	var product models.Product
+	server.db.Where("id = ?", 1).First(&product)
+	if product.RatingCount != 1 || product.RatingAverage != 5 || product.TotalRating != 5 || product.Stars() != [5]uint32{0, 0, 0, 0, 1} {
+		t.Errorf("product = %+v, want the fixture aggregates replaced by one five-star rating", product)
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/order/review/1", customer, nil), http.StatusOK, &public)
+	if len(public) != 1 || public[0].Rating != 5 || !public[0].Verified {
+		t.Errorf("reviews = %+v, want the approved five-star review", public)
+	}
+
+	helpful := fmt.Sprintf("/api/order/review/helpful/%d", id)
+	server.expect(server.do(http.MethodPost, helpful, customer, nil), http.StatusConflict, nil)
+
+	var vote struct {
+		Helpful uint32 `json:"helpful"`
+	}
+	server.expect(server.do(http.MethodPost, helpful, admin, nil), http.StatusOK, &vote)
+	server.expect(server.do(http.MethodPost, helpful, admin, nil), http.StatusOK, &vote)
+	if vote.Helpful != 1 {
+		t.Errorf("helpful = %d after voting twice, want 1", vote.Helpful)
+	}
+	server.expect(server.do(http.MethodDelete, helpful, admin, nil), http.StatusOK, &vote)
+	if vote.Helpful != 0 {
+		t.Errorf("helpful = %d after taking the vote back, want 0", vote.Helpful)
+	}
+
+	server.expect(server.do(http.MethodPost, fmt.Sprintf("/api/admin/review/reject/%d", id), admin, map[string]string{}), http.StatusOK, nil)
+	server.db.Where("id = ?", 1).First(&product)
+	if product.RatingCount != 0 || product.RatingAverage != 0 {
+		t.Errorf("product = %+v, want no ratings once the review is rejected", product)
+	}
+
+	var mine struct {
+		Status string `json:"status"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/order/review/mine/1", customer, nil), http.StatusOK, &mine)
+	if mine.Status != "rejected" {
+		t.Errorf("own review status = %q, want rejected", mine.Status)
+	}
+	if events := server.count("activities", "user_id = ? AND subject = ?", customerId, "Review Moderation"); events != 2 {
+		t.Errorf("%d moderation activities, want one per decision", events)
+	}
+}
+
+func TestShopSortsByRating(t *testing.T) {
+
+	server := newTestServer(t)
+
+	// The shirt and the shoes average four stars; more customers rated
+	// the shirt.
+	var page struct {
+		List []services.ProductCard `json:"list"`
+	}
+	server.expect(server.do(http.MethodGet, "/api/shop/list?order_by=rating", "", nil), http.StatusOK, &page)
+
+	var ids []int64
+	for _, card := range page.List {
+		ids = append(ids, card.Id)
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("ids = %v, want the shirt, the shoes and then the unrated hat", ids)
+	}
+	if len(page.List) > 0 && (page.List[0].TotalRating != 4 || page.List[0].RatingCount != 2) {
+		t.Errorf("shirt = %+v, want 4 stars from 2 ratings", page.List[0])
+	}
+}
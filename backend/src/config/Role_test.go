@@ -0,0 +1,47 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// TestBuiltInRoles checks that the migrations alone create the roles and
+// permissions of services.RolePermissions; the fixtures do not seed them.
+func TestBuiltInRoles(t *testing.T) {
+
+	server := newTestServer(t)
+	access := services.AccessControl(repositories.NewStore(server.db))
+
+	for role, want := range services.RolePermissions {
+		got := access.Permissions([]string{role})
+		want = slices.Sorted(slices.Values(want))
+		slices.Sort(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s permissions = %v, want %v", role, got, want)
+		}
+	}
+
+	err := access.AssignRoles(customerId, models.RoleSupport, "nobody")
+	if !errors.Is(err, repositories.ErrUnknownRole) {
+		t.Errorf("AssignRoles with an unknown role = %v, want ErrUnknownRole", err)
+	}
+	if roles := access.UserRoles(customerId); fmt.Sprint(roles) != "[customer]" {
+		t.Errorf("roles = %v after the failed assignment, want them unchanged", roles)
+	}
+}
@@ -12,9 +12,13 @@
 package config
 
 import (
+	appconfig "backend/src/appconfig"
 	controllers "backend/src/controllers"
 	"backend/src/middleware"
-	"os"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	search "backend/src/search"
+	services "backend/src/services"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -29,21 +33,26 @@ type RouteSource struct {
 	Result func(c *gin.Context)
 }
 
-func SetupRoutes(db *gorm.DB) *gin.Engine {
+func SetupRoutes(db *gorm.DB, config *appconfig.Config) *gin.Engine {
 
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "Cache-Control"},
+		AllowHeaders:     []string{"Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "Cache-Control", controllers.CartTokenHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	store := repositories.NewStore(db)
+	engine := search.NewEngine(services.SearchIndexLoader(store), config.Search.IndexMaxAge)
+
 	r.Use(func(c *gin.Context) {
-		c.Set("db", db)
+		c.Set("store", store)
+		c.Set("config", config)
+		c.Set("search", engine)
 	})
 
 	r.GET("api/home/ping", controllers.HomePing)
@@ -54,8 +63,12 @@ func SetupRoutes(db *gorm.DB) *gin.Engine {
 	r.POST("api/auth/login", controllers.AuthLogin)
 	r.POST("api/auth/register", controllers.AuthRegister)
 	r.GET("api/auth/confirm/:token", controllers.AuthConfirm)
+	r.POST("api/auth/confirm/resend", controllers.AuthConfirmResend)
 	r.POST("api/auth/email/forgot", controllers.AuthEmailForgot)
 	r.POST("api/auth/email/reset/:token", controllers.AuthEmailReset)
+	r.POST("api/auth/refresh", controllers.AuthRefresh)
+	r.POST("api/auth/logout", middleware.AuthorizeJWT(), controllers.AuthLogout)
+	r.POST("api/auth/logout/all", middleware.AuthorizeJWT(), controllers.AuthLogoutAll)
 
 	r.GET("api/profile/detail", middleware.AuthorizeJWT(), controllers.ProfileDetail)
 	r.GET("api/profile/activity", middleware.AuthorizeJWT(), controllers.ProfileActivity)
@@ -66,20 +79,110 @@ func SetupRoutes(db *gorm.DB) *gin.Engine {
 
 	r.GET("api/shop/filter", controllers.ShopFilter)
 	r.GET("api/shop/list", controllers.ShopList)
+	r.GET("api/shop/suggest", controllers.ShopSuggest)
 
 	r.GET("api/order/list", middleware.AuthorizeJWT(), controllers.OrderList)
 	r.GET("api/order/detail/:id", middleware.AuthorizeJWT(), controllers.OrderDetail)
 	r.GET("api/order/cancel/:id", middleware.AuthorizeJWT(), controllers.OrderCancel)
 	r.GET("api/order/wishlist/:id", middleware.AuthorizeJWT(), controllers.OrderWishlist)
-	r.GET("api/order/session", middleware.AuthorizeJWT(), controllers.OrderGetSession)
-	r.GET("api/order/cart/:id", middleware.AuthorizeJWT(), controllers.OrderCart)
+	r.GET("api/order/session", middleware.OptionalJWT(), controllers.OrderGetSession)
+	r.GET("api/order/cart/:id", middleware.OptionalJWT(), controllers.OrderCart)
 	r.GET("api/order/review/:id", middleware.AuthorizeJWT(), controllers.OrderListReview)
 	r.POST("api/order/review/:id", middleware.AuthorizeJWT(), controllers.OrderCreateReview)
-	r.POST("api/order/create/cart/:id", middleware.AuthorizeJWT(), controllers.OrderCreateCart)
+	r.GET("api/order/review/mine/:id", middleware.AuthorizeJWT(), controllers.OrderMyReview)
+	r.POST("api/order/review/helpful/:id", middleware.AuthorizeJWT(), controllers.OrderReviewVote)
+	r.DELETE("api/order/review/helpful/:id", middleware.AuthorizeJWT(), controllers.OrderReviewUnvote)
+	r.POST("api/order/create/cart/:id", middleware.OptionalJWT(), controllers.OrderCreateCart)
+	r.PATCH("api/order/cart/line/:id", middleware.OptionalJWT(), controllers.OrderCartUpdate)
+	r.DELETE("api/order/cart/line/:id", middleware.OptionalJWT(), controllers.OrderCartRemove)
+	r.DELETE("api/order/cart/clear", middleware.OptionalJWT(), controllers.OrderCartClear)
 	r.GET("api/order/checkout/initial", middleware.AuthorizeJWT(), controllers.OrderCheckoutInitial)
 	r.POST("api/order/checkout/submit", middleware.AuthorizeJWT(), controllers.OrderCheckout)
+	r.POST("api/order/coupon/apply", middleware.AuthorizeJWT(), controllers.OrderCouponApply)
+	r.DELETE("api/order/coupon/remove", middleware.AuthorizeJWT(), controllers.OrderCouponRemove)
+	r.POST("api/order/payment/:id", middleware.AuthorizeJWT(), controllers.OrderPayment)
+	r.GET("api/order/returns/:id", middleware.AuthorizeJWT(), controllers.OrderReturnList)
+	r.POST("api/order/return/:id", middleware.AuthorizeJWT(), controllers.OrderReturnRequest)
+	r.POST("api/payment/webhook/:provider", controllers.PaymentWebhook)
+
+	admin := r.Group("api/admin", middleware.AuthorizeJWT())
+
+	catalog := admin.Group("", middleware.RequirePermission(models.PermissionCatalogManage), middleware.InvalidateSearch())
+	{
+		catalog.GET("brand/list", controllers.AdminBrandList)
+		catalog.GET("brand/detail/:id", controllers.AdminBrandDetail)
+		catalog.POST("brand/create", controllers.AdminBrandCreate)
+		catalog.PUT("brand/update/:id", controllers.AdminBrandUpdate)
+		catalog.DELETE("brand/delete/:id", controllers.AdminBrandDelete)
+
+		catalog.GET("category/list", controllers.AdminCategoryList)
+		catalog.GET("category/detail/:id", controllers.AdminCategoryDetail)
+		catalog.POST("category/create", controllers.AdminCategoryCreate)
+		catalog.PUT("category/update/:id", controllers.AdminCategoryUpdate)
+		catalog.DELETE("category/delete/:id", controllers.AdminCategoryDelete)
+
+		catalog.GET("colour/list", controllers.AdminColourList)
+		catalog.GET("colour/detail/:id", controllers.AdminColourDetail)
+		catalog.POST("colour/create", controllers.AdminColourCreate)
+		catalog.PUT("colour/update/:id", controllers.AdminColourUpdate)
+		catalog.DELETE("colour/delete/:id", controllers.AdminColourDelete)
+
+		catalog.GET("size/list", controllers.AdminSizeList)
+		catalog.GET("size/detail/:id", controllers.AdminSizeDetail)
+		catalog.POST("size/create", controllers.AdminSizeCreate)
+		catalog.PUT("size/update/:id", controllers.AdminSizeUpdate)
+		catalog.DELETE("size/delete/:id", controllers.AdminSizeDelete)
+
+		catalog.GET("product/list", controllers.AdminProductList)
+		catalog.GET("product/detail/:id", controllers.AdminProductDetail)
+		catalog.POST("product/create", controllers.AdminProductCreate)
+		catalog.PUT("product/update/:id", controllers.AdminProductUpdate)
+		catalog.DELETE("product/delete/:id", controllers.AdminProductDelete)
+		catalog.POST("product/publish/:id", controllers.AdminProductPublish)
+		catalog.POST("product/unpublish/:id", controllers.AdminProductUnpublish)
+		catalog.PUT("product/categories/:id", controllers.AdminProductCategorySync)
+		catalog.POST("product/categories/:id/:category_id", controllers.AdminProductCategoryAttach)
+		catalog.DELETE("product/categories/:id/:category_id", controllers.AdminProductCategoryDetach)
+
+		catalog.GET("product/image/list/:id", controllers.AdminProductImageList)
+		catalog.POST("product/image/create/:id", controllers.AdminProductImageCreate)
+		catalog.PUT("product/image/update/:id", controllers.AdminProductImageUpdate)
+		catalog.DELETE("product/image/delete/:id", controllers.AdminProductImageDelete)
+
+		catalog.GET("product/inventory/list/:id", controllers.AdminProductInventoryList)
+		catalog.POST("product/inventory/create/:id", controllers.AdminProductInventoryCreate)
+		catalog.PUT("product/inventory/update/:id", controllers.AdminProductInventoryUpdate)
+		catalog.DELETE("product/inventory/delete/:id", controllers.AdminProductInventoryDelete)
+	}
+
+	coupons := admin.Group("", middleware.RequirePermission(models.PermissionCouponsManage))
+	{
+		coupons.GET("coupon/list", controllers.AdminCouponList)
+		coupons.GET("coupon/detail/:id", controllers.AdminCouponDetail)
+		coupons.POST("coupon/create", controllers.AdminCouponCreate)
+		coupons.PUT("coupon/update/:id", controllers.AdminCouponUpdate)
+		coupons.DELETE("coupon/delete/:id", controllers.AdminCouponDelete)
+	}
+
+	admin.GET("order/list", middleware.RequirePermission(models.PermissionOrdersViewAny), controllers.AdminOrderList)
+	admin.GET("order/detail/:id", middleware.RequirePermission(models.PermissionOrdersViewAny), controllers.AdminOrderDetail)
+	admin.POST("order/status/:id", middleware.RequirePermission(models.PermissionOrdersManage), controllers.AdminOrderStatus)
+	admin.GET("return/list", middleware.RequirePermission(models.PermissionOrdersViewAny), controllers.AdminReturnList)
+	admin.GET("return/detail/:id", middleware.RequirePermission(models.PermissionOrdersViewAny), controllers.AdminReturnDetail)
+	admin.POST("return/approve/:id", middleware.RequirePermission(models.PermissionOrdersManage), controllers.AdminReturnApprove)
+	admin.POST("return/reject/:id", middleware.RequirePermission(models.PermissionOrdersManage), controllers.AdminReturnReject)
+	admin.POST("return/receive/:id", middleware.RequirePermission(models.PermissionOrdersManage), controllers.AdminReturnReceive)
+	admin.POST("return/refund/:id", middleware.RequirePermission(models.PermissionOrdersRefund), controllers.AdminReturnRefund)
+
+	admin.GET("review/list", middleware.RequirePermission(models.PermissionReviewsManage), controllers.AdminReviewList)
+	admin.GET("review/detail/:id", middleware.RequirePermission(models.PermissionReviewsManage), controllers.AdminReviewDetail)
+	admin.POST("review/approve/:id", middleware.RequirePermission(models.PermissionReviewsManage), controllers.AdminReviewApprove)
+	admin.POST("review/reject/:id", middleware.RequirePermission(models.PermissionReviewsManage), controllers.AdminReviewReject)
+
+	admin.GET("role/list", middleware.RequirePermission(models.PermissionRolesManage), controllers.AdminRoleList)
+	admin.PUT("user/roles/:id", middleware.RequirePermission(models.PermissionRolesManage), controllers.AdminUserRoles)
 
 	r.MaxMultipartMemory = 8 << 20
-	r.Static("uploads", os.Getenv("UPLOAD_PATH"))
+	r.Static("uploads", config.UploadPath)
 	return r
 }
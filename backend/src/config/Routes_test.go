@@ -0,0 +1,249 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestRoutes sends one request to every route in SetupRoutes, each against
+// a fresh database, and checks the status code. Behaviour beyond the
+// status is covered by the flow tests next to this file.
+func TestRoutes(t *testing.T) {
+
+	const (
+		anonymous = iota
+		customer
+		admin
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		as     int
+		body   interface{}
+		want   int
+	}{
+		{"home ping", http.MethodGet, "/api/home/ping", anonymous, nil, http.StatusOK},
+		{"home component", http.MethodGet, "/api/home/component", anonymous, nil, http.StatusOK},
+		{"home page", http.MethodGet, "/api/home/page", anonymous, nil, http.StatusOK},
+		{"newsletter", http.MethodPost, "/api/home/newsletter", anonymous, map[string]string{"email": "reader@example.com"}, http.StatusOK},
+		{"newsletter without email", http.MethodPost, "/api/home/newsletter", anonymous, map[string]string{}, http.StatusBadRequest},
+
+		{"login", http.MethodPost, "/api/auth/login", anonymous, map[string]string{"email": customerEmail, "password": fixturePass}, http.StatusOK},
+		{"login with wrong password", http.MethodPost, "/api/auth/login", anonymous, map[string]string{"email": customerEmail, "password": "wrong-password"}, http.StatusUnauthorized},
+		{"login unknown email", http.MethodPost, "/api/auth/login", anonymous, map[string]string{"email": "nobody@example.com", "password": fixturePass}, http.StatusUnauthorized},
+		{"login unconfirmed", http.MethodPost, "/api/auth/login", anonymous, map[string]string{"email": pendingEmail, "password": fixturePass}, http.StatusUnauthorized},
+		{"register", http.MethodPost, "/api/auth/register", anonymous, map[string]string{"name": "New Person", "email": "new@example.com", "password": fixturePass, "password_confirm": fixturePass}, http.StatusOK},
+		{"register taken email", http.MethodPost, "/api/auth/register", anonymous, map[string]string{"name": "Ada", "email": customerEmail, "password": fixturePass, "password_confirm": fixturePass}, http.StatusBadRequest},
+		{"register short password", http.MethodPost, "/api/auth/register", anonymous, map[string]string{"name": "New Person", "email": "new@example.com", "password": "short", "password_confirm": "short"}, http.StatusBadRequest},
+		{"confirm unknown token", http.MethodGet, "/api/auth/confirm/unknown-token", anonymous, nil, http.StatusBadRequest},
+		{"resend confirmation", http.MethodPost, "/api/auth/confirm/resend", anonymous, map[string]string{"email": pendingEmail}, http.StatusOK},
+		{"resend to confirmed user", http.MethodPost, "/api/auth/confirm/resend", anonymous, map[string]string{"email": customerEmail}, http.StatusBadRequest},
+		{"forgot password", http.MethodPost, "/api/auth/email/forgot", anonymous, map[string]string{"email": customerEmail}, http.StatusOK},
+		{"forgot password unknown email", http.MethodPost, "/api/auth/email/forgot", anonymous, map[string]string{"email": "nobody@example.com"}, http.StatusBadRequest},
+		{"reset unknown token", http.MethodPost, "/api/auth/email/reset/unknown-token", anonymous, map[string]string{"email": customerEmail, "password": fixturePass, "password_confirm": fixturePass}, http.StatusBadRequest},
+		{"refresh unknown token", http.MethodPost, "/api/auth/refresh", anonymous, map[string]string{"refresh_token": "unknown-token"}, http.StatusUnauthorized},
+		{"logout", http.MethodPost, "/api/auth/logout", customer, nil, http.StatusOK},
+		{"logout anonymous", http.MethodPost, "/api/auth/logout", anonymous, nil, http.StatusUnauthorized},
+		{"logout everywhere", http.MethodPost, "/api/auth/logout/all", customer, nil, http.StatusOK},
+
+		{"profile detail", http.MethodGet, "/api/profile/detail", customer, nil, http.StatusOK},
+		{"profile detail anonymous", http.MethodGet, "/api/profile/detail", anonymous, nil, http.StatusUnauthorized},
+		{"profile activity", http.MethodGet, "/api/profile/activity", customer, nil, http.StatusOK},
+		{"profile refresh", http.MethodGet, "/api/profile/refresh", customer, nil, http.StatusOK},
+		{"profile update", http.MethodPost, "/api/profile/update", customer, map[string]string{"email": customerEmail, "first_name": "Augusta"}, http.StatusOK},
+		{"profile update taken email", http.MethodPost, "/api/profile/update", customer, map[string]string{"email": adminEmail}, http.StatusBadRequest},
+		{"profile password", http.MethodPost, "/api/profile/password", customer, map[string]string{"old_password": fixturePass, "password": "Secret456!", "password_confirm": "Secret456!"}, http.StatusOK},
+		{"profile password wrong", http.MethodPost, "/api/profile/password", customer, map[string]string{"old_password": "wrong-password", "password": "Secret456!", "password_confirm": "Secret456!"}, http.StatusBadRequest},
+		{"profile upload without file", http.MethodPost, "/api/profile/upload", customer, nil, http.StatusBadRequest},
+
+		{"shop filter", http.MethodGet, "/api/shop/filter", anonymous, nil, http.StatusOK},
+		{"shop list", http.MethodGet, "/api/shop/list?page=1&limit=2", anonymous, nil, http.StatusOK},
+		{"shop list by rating", http.MethodGet, "/api/shop/list?order_by=rating", anonymous, nil, http.StatusOK},
+		{"shop search", http.MethodGet, "/api/shop/list?search=shirt", anonymous, nil, http.StatusOK},
+		{"shop suggest", http.MethodGet, "/api/shop/suggest?q=sh", anonymous, nil, http.StatusOK},
+		{"shop suggest nothing", http.MethodGet, "/api/shop/suggest", anonymous, nil, http.StatusOK},
+
+		{"order list", http.MethodGet, "/api/order/list", customer, nil, http.StatusOK},
+		{"order list anonymous", http.MethodGet, "/api/order/list", anonymous, nil, http.StatusUnauthorized},
+		{"order detail", http.MethodGet, "/api/order/detail/1", customer, nil, http.StatusOK},
+		{"order detail missing", http.MethodGet, "/api/order/detail/999", customer, nil, http.StatusNotFound},
+		{"order detail bad id", http.MethodGet, "/api/order/detail/abc", customer, nil, http.StatusNotFound},
+		{"order detail of another customer", http.MethodGet, "/api/order/detail/1", admin, nil, http.StatusNotFound},
+		{"order cancel of another customer", http.MethodGet, "/api/order/cancel/1", admin, nil, http.StatusNotFound},
+		{"order payment of another customer", http.MethodPost, "/api/order/payment/1", admin, nil, http.StatusNotFound},
+		{"order returns of another customer", http.MethodGet, "/api/order/returns/1", admin, nil, http.StatusNotFound},
+		{"order return of another customer", http.MethodPost, "/api/order/return/1", admin, map[string]interface{}{"reason": "Broken", "lines": []map[string]int{{"detail_id": 1, "qty": 1}}}, http.StatusNotFound},
+		{"order cancel once paid", http.MethodGet, "/api/order/cancel/1", customer, nil, http.StatusConflict},
+		{"order wishlist", http.MethodGet, "/api/order/wishlist/1", customer, nil, http.StatusOK},
+		{"order session", http.MethodGet, "/api/order/session", customer, nil, http.StatusOK},
+		{"order session guest", http.MethodGet, "/api/order/session", anonymous, nil, http.StatusOK},
+		{"order cart product", http.MethodGet, "/api/order/cart/1", customer, nil, http.StatusOK},
+		{"order cart draft product", http.MethodGet, "/api/order/cart/4", customer, nil, http.StatusNotFound},
+		{"order cart product guest", http.MethodGet, "/api/order/cart/1", anonymous, nil, http.StatusOK},
+		{"order reviews", http.MethodGet, "/api/order/review/1", customer, nil, http.StatusOK},
+		{"order create review without a delivery", http.MethodPost, "/api/order/review/1", customer, map[string]interface{}{"rating": 4, "review": "Fits well"}, http.StatusForbidden},
+		{"order create review rated six", http.MethodPost, "/api/order/review/1", customer, map[string]interface{}{"rating": 6, "review": "Off the scale"}, http.StatusBadRequest},
+		{"order create review without a rating", http.MethodPost, "/api/order/review/1", customer, map[string]interface{}{"review": "No stars"}, http.StatusBadRequest},
+		{"order my review none yet", http.MethodGet, "/api/order/review/mine/1", customer, nil, http.StatusNotFound},
+		{"order review helpful missing", http.MethodPost, "/api/order/review/helpful/999", customer, nil, http.StatusNotFound},
+		{"order review helpful anonymous", http.MethodPost, "/api/order/review/helpful/1", anonymous, nil, http.StatusUnauthorized},
+		{"order review unvote missing", http.MethodDelete, "/api/order/review/helpful/999", customer, nil, http.StatusNotFound},
+		{"order add to cart", http.MethodPost, "/api/order/create/cart/1", customer, map[string]int{"size_id": 1, "colour_id": 1, "qty": 1}, http.StatusOK},
+		{"order add to cart guest", http.MethodPost, "/api/order/create/cart/1", anonymous, map[string]int{"size_id": 1, "colour_id": 1, "qty": 1}, http.StatusOK},
+		{"order wishlist guest", http.MethodGet, "/api/order/wishlist/1", anonymous, nil, http.StatusUnauthorized},
+		{"order checkout guest", http.MethodGet, "/api/order/checkout/initial", anonymous, nil, http.StatusUnauthorized},
+		{"order add unknown inventory", http.MethodPost, "/api/order/create/cart/1", customer, map[string]int{"size_id": 3, "colour_id": 1, "qty": 1}, http.StatusNotFound},
+		{"order add without qty", http.MethodPost, "/api/order/create/cart/1", customer, map[string]int{"size_id": 1, "colour_id": 1}, http.StatusBadRequest},
+		{"order cart update line of a placed order", http.MethodPatch, "/api/order/cart/line/1", customer, map[string]int{"qty": 2}, http.StatusNotFound},
+		{"order cart update zero qty", http.MethodPatch, "/api/order/cart/line/1", customer, map[string]int{"qty": 0}, http.StatusBadRequest},
+		{"order cart update guest without a cart", http.MethodPatch, "/api/order/cart/line/1", anonymous, map[string]int{"qty": 2}, http.StatusNotFound},
+		{"order cart remove line of a placed order", http.MethodDelete, "/api/order/cart/line/1", customer, nil, http.StatusNotFound},
+		{"order cart remove bad id", http.MethodDelete, "/api/order/cart/line/abc", customer, nil, http.StatusNotFound},
+		{"order cart clear without a cart", http.MethodDelete, "/api/order/cart/clear", customer, nil, http.StatusOK},
+		{"order checkout initial", http.MethodGet, "/api/order/checkout/initial", customer, nil, http.StatusOK},
+		{"order checkout empty cart", http.MethodPost, "/api/order/checkout/submit", customer, map[string]interface{}{"payment_id": 1, "email": customerEmail}, http.StatusBadRequest},
+		{"order coupon apply empty cart", http.MethodPost, "/api/order/coupon/apply", customer, map[string]string{"code": "SAVE10"}, http.StatusBadRequest},
+		{"order coupon apply without code", http.MethodPost, "/api/order/coupon/apply", customer, map[string]string{}, http.StatusBadRequest},
+		{"order coupon apply anonymous", http.MethodPost, "/api/order/coupon/apply", anonymous, map[string]string{"code": "SAVE10"}, http.StatusUnauthorized},
+		{"order coupon remove empty cart", http.MethodDelete, "/api/order/coupon/remove", customer, nil, http.StatusBadRequest},
+		{"order payment not awaiting payment", http.MethodPost, "/api/order/payment/1", customer, nil, http.StatusConflict},
+		{"order return without lines", http.MethodPost, "/api/order/return/1", customer, map[string]interface{}{"reason": "Broken"}, http.StatusBadRequest},
+		{"order returns", http.MethodGet, "/api/order/returns/1", customer, nil, http.StatusOK},
+		{"order payment anonymous", http.MethodPost, "/api/order/payment/1", anonymous, nil, http.StatusUnauthorized},
+		{"order payment missing order", http.MethodPost, "/api/order/payment/999", customer, nil, http.StatusNotFound},
+		{"payment webhook unknown provider", http.MethodPost, "/api/payment/webhook/nope", anonymous, map[string]string{}, http.StatusNotFound},
+		{"payment webhook gateway off", http.MethodPost, "/api/payment/webhook/gateway", anonymous, map[string]string{}, http.StatusNotFound},
+
+		{"admin as customer", http.MethodGet, "/api/admin/brand/list", customer, nil, http.StatusForbidden},
+		{"admin anonymous", http.MethodGet, "/api/admin/brand/list", anonymous, nil, http.StatusUnauthorized},
+
+		{"brand list", http.MethodGet, "/api/admin/brand/list", admin, nil, http.StatusOK},
+		{"brand detail", http.MethodGet, "/api/admin/brand/detail/1", admin, nil, http.StatusOK},
+		{"brand detail missing", http.MethodGet, "/api/admin/brand/detail/999", admin, nil, http.StatusNotFound},
+		{"brand create", http.MethodPost, "/api/admin/brand/create", admin, map[string]interface{}{"name": "Globex", "status": 1}, http.StatusCreated},
+		{"brand create invalid", http.MethodPost, "/api/admin/brand/create", admin, map[string]interface{}{"status": 1}, http.StatusUnprocessableEntity},
+		{"brand update", http.MethodPut, "/api/admin/brand/update/1", admin, map[string]interface{}{"name": "Acme Corp", "status": 1}, http.StatusOK},
+		{"brand delete", http.MethodDelete, "/api/admin/brand/delete/2", admin, nil, http.StatusOK},
+		{"brand delete in use", http.MethodDelete, "/api/admin/brand/delete/1", admin, nil, http.StatusConflict},
+
+		{"category list", http.MethodGet, "/api/admin/category/list", admin, nil, http.StatusOK},
+		{"category detail", http.MethodGet, "/api/admin/category/detail/1", admin, nil, http.StatusOK},
+		{"category create", http.MethodPost, "/api/admin/category/create", admin, map[string]interface{}{"name": "Hats", "displayed": 1, "status": 1}, http.StatusCreated},
+		{"category update", http.MethodPut, "/api/admin/category/update/1", admin, map[string]interface{}{"name": "Tops", "displayed": 1, "status": 1}, http.StatusOK},
+		{"category delete", http.MethodDelete, "/api/admin/category/delete/2", admin, nil, http.StatusOK},
+
+		{"colour list", http.MethodGet, "/api/admin/colour/list", admin, nil, http.StatusOK},
+		{"colour detail", http.MethodGet, "/api/admin/colour/detail/1", admin, nil, http.StatusOK},
+		{"colour create", http.MethodPost, "/api/admin/colour/create", admin, map[string]interface{}{"code": "#000000", "name": "Black", "status": 1}, http.StatusCreated},
+		{"colour update", http.MethodPut, "/api/admin/colour/update/1", admin, map[string]interface{}{"code": "#ee0000", "name": "Crimson", "status": 1}, http.StatusOK},
+		{"colour delete", http.MethodDelete, "/api/admin/colour/delete/3", admin, nil, http.StatusOK},
+
+		{"size list", http.MethodGet, "/api/admin/size/list", admin, nil, http.StatusOK},
+		{"size detail", http.MethodGet, "/api/admin/size/detail/1", admin, nil, http.StatusOK},
+		{"size create", http.MethodPost, "/api/admin/size/create", admin, map[string]interface{}{"name": "XL", "status": 1}, http.StatusCreated},
+		{"size update", http.MethodPut, "/api/admin/size/update/1", admin, map[string]interface{}{"name": "Medium", "status": 1}, http.StatusOK},
+		{"size delete", http.MethodDelete, "/api/admin/size/delete/3", admin, nil, http.StatusOK},
+
+		{"product list", http.MethodGet, "/api/admin/product/list", admin, nil, http.StatusOK},
+		{"product detail", http.MethodGet, "/api/admin/product/detail/1", admin, nil, http.StatusOK},
+		{"product create", http.MethodPost, "/api/admin/product/create", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-005", "name": "Scarf", "price": 15, "category_ids": []int{1}}, http.StatusCreated},
+		{"product create with a decimal price", http.MethodPost, "/api/admin/product/create", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-005", "name": "Scarf", "price": "15.50"}, http.StatusCreated},
+		{"product create negative price", http.MethodPost, "/api/admin/product/create", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-005", "name": "Scarf", "price": "-1"}, http.StatusUnprocessableEntity},
+		{"product create malformed price", http.MethodPost, "/api/admin/product/create", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-005", "name": "Scarf", "price": "15,50"}, http.StatusUnprocessableEntity},
+		{"product create duplicate sku", http.MethodPost, "/api/admin/product/create", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-001", "name": "Scarf", "price": 15}, http.StatusUnprocessableEntity},
+		{"product update", http.MethodPut, "/api/admin/product/update/1", admin, map[string]interface{}{"brand_id": 1, "sku": "SKU-001", "name": "Oxford Shirt", "price": 110}, http.StatusOK},
+		{"product delete", http.MethodDelete, "/api/admin/product/delete/4", admin, nil, http.StatusOK},
+		{"product delete ordered", http.MethodDelete, "/api/admin/product/delete/1", admin, nil, http.StatusConflict},
+		{"product publish", http.MethodPost, "/api/admin/product/publish/4", admin, map[string]interface{}{}, http.StatusOK},
+		{"product unpublish", http.MethodPost, "/api/admin/product/unpublish/1", admin, nil, http.StatusOK},
+		{"product categories sync", http.MethodPut, "/api/admin/product/categories/2", admin, map[string]interface{}{"category_ids": []int{1, 2}}, http.StatusOK},
+		{"product category attach", http.MethodPost, "/api/admin/product/categories/2/1", admin, nil, http.StatusOK},
+		{"product category detach", http.MethodDelete, "/api/admin/product/categories/1/1", admin, nil, http.StatusOK},
+		{"product category detach bad id", http.MethodDelete, "/api/admin/product/categories/1/shirts", admin, nil, http.StatusNotFound},
+
+		{"product image list", http.MethodGet, "/api/admin/product/image/list/1", admin, nil, http.StatusOK},
+		{"product image create", http.MethodPost, "/api/admin/product/image/create/1", admin, map[string]interface{}{"path": "shirt-back.png", "sort": 2, "status": 1}, http.StatusCreated},
+		{"product image update", http.MethodPut, "/api/admin/product/image/update/1", admin, map[string]interface{}{"path": "shirt-front.png", "sort": 1, "status": 1}, http.StatusOK},
+		{"product image delete", http.MethodDelete, "/api/admin/product/image/delete/1", admin, nil, http.StatusOK},
+
+		{"product inventory list", http.MethodGet, "/api/admin/product/inventory/list/1", admin, nil, http.StatusOK},
+		{"product inventory create", http.MethodPost, "/api/admin/product/inventory/create/1", admin, map[string]interface{}{"size_id": 2, "colour_id": 2, "stock": 3, "status": 1}, http.StatusCreated},
+		{"product inventory create duplicate", http.MethodPost, "/api/admin/product/inventory/create/1", admin, map[string]interface{}{"size_id": 1, "colour_id": 1, "stock": 3, "status": 1}, http.StatusUnprocessableEntity},
+		{"product inventory update", http.MethodPut, "/api/admin/product/inventory/update/4", admin, map[string]interface{}{"size_id": 1, "colour_id": 1, "stock": 8, "status": 1}, http.StatusOK},
+		{"product inventory delete", http.MethodDelete, "/api/admin/product/inventory/delete/4", admin, nil, http.StatusOK},
+		{"product inventory delete ordered", http.MethodDelete, "/api/admin/product/inventory/delete/1", admin, nil, http.StatusConflict},
+
+		{"admin order list", http.MethodGet, "/api/admin/order/list", admin, nil, http.StatusOK},
+		{"admin order list by status", http.MethodGet, "/api/admin/order/list?status=paid&search=INV", admin, nil, http.StatusOK},
+		{"admin order list bad status", http.MethodGet, "/api/admin/order/list?status=lost", admin, nil, http.StatusUnprocessableEntity},
+		{"admin order detail missing", http.MethodGet, "/api/admin/order/detail/999", admin, nil, http.StatusNotFound},
+		{"admin order detail", http.MethodGet, "/api/admin/order/detail/1", admin, nil, http.StatusOK},
+		{"admin order status", http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "processing"}, http.StatusOK},
+		{"admin order invalid transition", http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "delivered"}, http.StatusConflict},
+		{"admin order unknown status", http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "lost"}, http.StatusUnprocessableEntity},
+		{"admin order refunded by status", http.MethodPost, "/api/admin/order/status/1", admin, map[string]string{"status": "refunded"}, http.StatusUnprocessableEntity},
+
+		{"return list", http.MethodGet, "/api/admin/return/list", admin, nil, http.StatusOK},
+		{"return list bad status", http.MethodGet, "/api/admin/return/list?status=lost", admin, nil, http.StatusUnprocessableEntity},
+		{"return list as customer", http.MethodGet, "/api/admin/return/list", customer, nil, http.StatusForbidden},
+		{"return detail missing", http.MethodGet, "/api/admin/return/detail/999", admin, nil, http.StatusNotFound},
+		{"return approve missing", http.MethodPost, "/api/admin/return/approve/999", admin, map[string]string{}, http.StatusNotFound},
+		{"return refund as customer", http.MethodPost, "/api/admin/return/refund/1", customer, map[string]string{}, http.StatusForbidden},
+		{"review list", http.MethodGet, "/api/admin/review/list?status=pending", admin, nil, http.StatusOK},
+		{"review list bad status", http.MethodGet, "/api/admin/review/list?status=lost", admin, nil, http.StatusUnprocessableEntity},
+		{"review list bad product", http.MethodGet, "/api/admin/review/list?product_id=shirt", admin, nil, http.StatusUnprocessableEntity},
+		{"review list as customer", http.MethodGet, "/api/admin/review/list", customer, nil, http.StatusForbidden},
+		{"review detail missing", http.MethodGet, "/api/admin/review/detail/999", admin, nil, http.StatusNotFound},
+		{"review approve missing", http.MethodPost, "/api/admin/review/approve/999", admin, map[string]string{}, http.StatusNotFound},
+		{"review reject as customer", http.MethodPost, "/api/admin/review/reject/1", customer, map[string]string{}, http.StatusForbidden},
+		{"coupon list", http.MethodGet, "/api/admin/coupon/list", admin, nil, http.StatusOK},
+		{"coupon list as customer", http.MethodGet, "/api/admin/coupon/list", customer, nil, http.StatusForbidden},
+		{"coupon detail", http.MethodGet, "/api/admin/coupon/detail/1", admin, nil, http.StatusOK},
+		{"coupon detail missing", http.MethodGet, "/api/admin/coupon/detail/999", admin, nil, http.StatusNotFound},
+		{"coupon create", http.MethodPost, "/api/admin/coupon/create", admin, map[string]interface{}{"code": "summer", "name": "Summer", "calculation": "fixed", "value": "7.50", "status": 1, "targets": []map[string]interface{}{{"scope": "category", "target_id": 1}}}, http.StatusCreated},
+		{"coupon create duplicate code", http.MethodPost, "/api/admin/coupon/create", admin, map[string]interface{}{"code": "save10", "name": "Again", "calculation": "percent", "value": 10, "status": 1}, http.StatusUnprocessableEntity},
+		{"coupon create percent over 100", http.MethodPost, "/api/admin/coupon/create", admin, map[string]interface{}{"code": "HUGE", "name": "Huge", "calculation": "percent", "value": 150, "status": 1}, http.StatusUnprocessableEntity},
+		{"coupon create unknown target", http.MethodPost, "/api/admin/coupon/create", admin, map[string]interface{}{"code": "LOST", "name": "Lost", "calculation": "percent", "value": 10, "status": 1, "targets": []map[string]interface{}{{"scope": "brand", "target_id": 999}}}, http.StatusUnprocessableEntity},
+		{"coupon create ends before start", http.MethodPost, "/api/admin/coupon/create", admin, map[string]interface{}{"code": "BACKWARDS", "name": "Backwards", "calculation": "percent", "value": 10, "status": 1, "starts_at": "2025-02-01T00:00:00Z", "ends_at": "2025-01-01T00:00:00Z"}, http.StatusUnprocessableEntity},
+		{"coupon update", http.MethodPut, "/api/admin/coupon/update/1", admin, map[string]interface{}{"code": "SAVE15", "name": "Fifteen off", "calculation": "percent", "value": 15, "status": 1, "targets": []map[string]interface{}{{"scope": "product", "target_id": 1}}}, http.StatusOK},
+		{"coupon delete", http.MethodDelete, "/api/admin/coupon/delete/1", admin, nil, http.StatusOK},
+		{"coupon delete redeemed", http.MethodDelete, "/api/admin/coupon/delete/2", admin, nil, http.StatusConflict},
+
+		{"role list", http.MethodGet, "/api/admin/role/list", admin, nil, http.StatusOK},
+		{"user roles", http.MethodPut, "/api/admin/user/roles/1", admin, map[string][]string{"roles": {"customer", "support"}}, http.StatusOK},
+		{"user roles unknown role", http.MethodPut, "/api/admin/user/roles/1", admin, map[string][]string{"roles": {"wizard"}}, http.StatusUnprocessableEntity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			server := newTestServer(t)
+
+			var token string
+			switch test.as {
+			case customer:
+				token = server.token(customerId)
+			case admin:
+				token = server.token(adminId)
+			}
+
+			w := server.do(test.method, test.path, token, test.body)
+			if w.Code != test.want {
+				t.Errorf("%s %s = %d, want %d; body: %s", test.method, test.path, w.Code, test.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package config
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type shopPage struct {
+	List          []services.ProductCard `json:"list"`
+	TotalFiltered int64                  `json:"totalFiltered"`
+	Facets        struct {
+		Categories []repositories.NameCount `json:"categories"`
+		Brands     []repositories.NameCount `json:"brands"`
+	} `json:"facets"`
+}
+
+func (page shopPage) ids() string {
+	var ids []int64
+	for _, card := range page.List {
+		ids = append(ids, card.Id)
+	}
+	return fmt.Sprint(ids)
+}
+
+func counts(facet []repositories.NameCount) string {
+	var out []string
+	for _, count := range facet {
+		out = append(out, fmt.Sprintf("%s:%d", count.Name, count.Total))
+	}
+	return fmt.Sprint(out)
+}
+
+func TestShopSearch(t *testing.T) {
+
+	server := newTestServer(t)
+
+	// The draft is scheduled for tomorrow: indexed already, not yet shown.
+	tomorrow := time.Now().Add(24 * time.Hour)
+	server.db.Model(&models.Product{}).Where("id = ?", 2).Update("description", "Goes with any shirt.")
+	server.db.Model(&models.Product{}).Where("id = ?", 4).Updates(map[string]interface{}{"name": "Shirt Preview", "status": 1, "published_at": &tomorrow})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// A shirt by name outranks one only described so.
+		{"search=shirt", "[1 2]"},
+		{"search=shrit", "[1 2]"},
+		{"search=SHI", "[1 2]"},
+		{"search=acme+hat", "[3]"},
+		{"search=sku-003", "[3]"},
+		{"search=sku003", "[3]"},
+		{"search=shirt&order_by=price&order_dir=asc", "[2 1]"},
+		{"search=shirt&limit=1&page=2", "[2]"},
+		// The LIKE clause used to escape the status filter.
+		{"search=sku-004", "[]"},
+		{"search=preview", "[]"},
+		{"search=socks", "[]"},
+	}
+
+	for _, test := range tests {
+		var page shopPage
+		server.expect(server.do(http.MethodGet, "/api/shop/list?"+test.query, "", nil), http.StatusOK, &page)
+		if got := page.ids(); got != test.want {
+			t.Errorf("%s: ids = %s, want %s", test.query, got, test.want)
+		}
+	}
+
+	var page shopPage
+	server.expect(server.do(http.MethodGet, "/api/shop/list?search=shirt&limit=1", "", nil), http.StatusOK, &page)
+	if page.TotalFiltered != 2 {
+		t.Errorf("totalFiltered = %d, want both matches counted", page.TotalFiltered)
+	}
+}
+
+func TestShopSearchSeesCatalogChanges(t *testing.T) {
+
+	server := newTestServer(t)
+	server.config.Search.IndexMaxAge = time.Hour
+	server.router = SetupRoutes(server.db, server.config)
+
+	var page shopPage
+	server.expect(server.do(http.MethodGet, "/api/shop/list?search=cap", "", nil), http.StatusOK, &page)
+	if page.ids() != "[]" {
+		t.Fatalf("ids = %s before the rename, want none", page.ids())
+	}
+
+	server.expect(server.do(http.MethodPut, "/api/admin/product/update/3", server.token(adminId), map[string]interface{}{"brand_id": 1, "sku": "SKU-003", "name": "Cap", "price": 25}), http.StatusOK, nil)
+
+	server.expect(server.do(http.MethodGet, "/api/shop/list?search=cap", "", nil), http.StatusOK, &page)
+	if page.ids() != "[3]" {
+		t.Errorf("ids = %s after the rename, want the cap", page.ids())
+	}
+}
+
+func TestShopFacets(t *testing.T) {
+
+	server := newTestServer(t)
+	server.db.Model(&models.Product{}).Where("id = ?", 3).Update("brand_id", 2)
+
+	tests := []struct {
+		query      string
+		ids        string
+		categories string
+		brands     string
+	}{
+		{"", "[3 2 1]", "[Shirts:1]", "[Acme:2 Unused Brand:1]"},
+		// Each facet keeps counting the choices the customer did not make.
+		{"brand=1", "[2 1]", "[Shirts:1]", "[Acme:2 Unused Brand:1]"},
+		{"category=1", "[1]", "[Shirts:1]", "[Acme:1]"},
+		{"search=hat", "[3]", "[]", "[Unused Brand:1]"},
+		{"search=nothing", "[]", "[]", "[]"},
+	}
+
+	for _, test := range tests {
+		var page shopPage
+		server.expect(server.do(http.MethodGet, "/api/shop/list?"+test.query, "", nil), http.StatusOK, &page)
+		if got := page.ids(); got != test.ids {
+			t.Errorf("%q: ids = %s, want %s", test.query, got, test.ids)
+		}
+		if got := counts(page.Facets.Categories); got != test.categories {
+			t.Errorf("%q: categories = %s, want %s", test.query, got, test.categories)
+		}
+		if got := counts(page.Facets.Brands); got != test.brands {
+			t.Errorf("%q: brands = %s, want %s", test.query, got, test.brands)
+		}
+	}
+}
+
+func TestShopSuggest(t *testing.T) {
+
+	server := newTestServer(t)
+
+	var suggestions struct {
+		Terms    []string               `json:"terms"`
+		Products []services.ProductCard `json:"products"`
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/shop/suggest?q=sho", "", nil), http.StatusOK, &suggestions)
+	if fmt.Sprint(suggestions.Terms) != "[shoes]" || len(suggestions.Products) != 1 || suggestions.Products[0].Id != 2 {
+		t.Errorf("suggestions = %+v, want the shoes", suggestions)
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/shop/suggest?q=acme+h&limit=1", "", nil), http.StatusOK, &suggestions)
+	if fmt.Sprint(suggestions.Terms) != "[acme hat]" || len(suggestions.Products) != 1 || suggestions.Products[0].Id != 3 {
+		t.Errorf("suggestions = %+v, want the hat", suggestions)
+	}
+
+	server.expect(server.do(http.MethodGet, "/api/shop/suggest?q=+", "", nil), http.StatusOK, &suggestions)
+	if len(suggestions.Terms) != 0 || len(suggestions.Products) != 0 {
+		t.Errorf("suggestions = %+v for a blank query, want none", suggestions)
+	}
+}
@@ -0,0 +1,364 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminConflicts are the messages of the deletes the catalogue refuses.
+var adminConflicts = map[error]string{
+	services.ErrBrandInUse:       "This brand still has products assigned to it.",
+	services.ErrColourInUse:      "This colour is still used by product inventories.",
+	services.ErrSizeInUse:        "This size is still used by product inventories.",
+	services.ErrProductOrdered:   "This product has been ordered and can only be unpublished.",
+	services.ErrInventoryOrdered: "This inventory has been ordered and can only be disabled.",
+	services.ErrCouponRedeemed:   "This coupon has been redeemed and can only be disabled.",
+}
+
+// adminList answers one page of the brands, categories, colours or sizes
+// list points to.
+func adminList(c *gin.Context, list interface{}) {
+	store := c.MustGet("store").(repositories.Store)
+	page, err := services.CatalogAdmin(store).List(list, listParams(c, 10))
+	adminPage(c, page, err)
+}
+
+func adminPage(c *gin.Context, page services.RecordPage, err error) {
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
+	})
+}
+
+func adminBind(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		adminInvalid(c, helpers.ValidationErrors(err)...)
+		return false
+	}
+	return true
+}
+
+func adminInvalid(c *gin.Context, errs ...helpers.ValidationError) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The given data was invalid.", "errors": errs})
+}
+
+// adminFind reads the record the path parameter name points to into
+// record, answering 404 when there is none.
+func adminFind(c *gin.Context, record interface{}, name string) bool {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	id, ok := paramId(c, name)
+	if !ok {
+		return false
+	}
+
+	if err := services.CatalogAdmin(store).Find(record, id); err != nil {
+		storeError(c, err, "Failed to load the record")
+		return false
+	}
+	return true
+}
+
+// adminSaveError answers a failed create or update: 422 for input the
+// service refused and 500 with message otherwise.
+func adminSaveError(c *gin.Context, err error, message string) {
+	var invalid *services.InvalidInputError
+	if errors.As(err, &invalid) {
+		adminInvalid(c, invalid.Errors...)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// adminDelete deletes record, answering 409 while it is still in use.
+func adminDelete(c *gin.Context, record interface{}, message string) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	if err := services.CatalogAdmin(store).Delete(record); err != nil {
+		adminDeleteError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
+
+func adminDeleteError(c *gin.Context, err error, message string) {
+	if conflict, ok := adminConflicts[err]; ok {
+		c.JSON(http.StatusConflict, gin.H{"error": conflict})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// brands
+
+func AdminBrandList(c *gin.Context) {
+	var data []models.Brand
+	adminList(c, &data)
+}
+
+func AdminBrandDetail(c *gin.Context) {
+	var brand models.Brand
+	if adminFind(c, &brand, "id") {
+		c.JSON(http.StatusOK, brand)
+	}
+}
+
+func AdminBrandCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.BrandSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	brand, err := services.CatalogAdmin(store).CreateBrand(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create brand"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, brand)
+}
+
+func AdminBrandUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var brand models.Brand
+	if !adminFind(c, &brand, "id") {
+		return
+	}
+
+	var input schema.BrandSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateBrand(&brand, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update brand"})
+		return
+	}
+
+	c.JSON(http.StatusOK, brand)
+}
+
+func AdminBrandDelete(c *gin.Context) {
+	var brand models.Brand
+	if adminFind(c, &brand, "id") {
+		adminDelete(c, &brand, "Failed to delete brand")
+	}
+}
+
+// categories
+
+func AdminCategoryList(c *gin.Context) {
+	var data []models.Category
+	adminList(c, &data)
+}
+
+func AdminCategoryDetail(c *gin.Context) {
+	var category models.Category
+	if adminFind(c, &category, "id") {
+		c.JSON(http.StatusOK, category)
+	}
+}
+
+func AdminCategoryCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.CategorySchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	category, err := services.CatalogAdmin(store).CreateCategory(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+func AdminCategoryUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var category models.Category
+	if !adminFind(c, &category, "id") {
+		return
+	}
+
+	var input schema.CategorySchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateCategory(&category, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func AdminCategoryDelete(c *gin.Context) {
+	var category models.Category
+	if adminFind(c, &category, "id") {
+		adminDelete(c, &category, "Failed to delete category")
+	}
+}
+
+// colours
+
+func AdminColourList(c *gin.Context) {
+	var data []models.Colour
+	adminList(c, &data)
+}
+
+func AdminColourDetail(c *gin.Context) {
+	var colour models.Colour
+	if adminFind(c, &colour, "id") {
+		c.JSON(http.StatusOK, colour)
+	}
+}
+
+func AdminColourCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.ColourSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	colour, err := services.CatalogAdmin(store).CreateColour(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create colour"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, colour)
+}
+
+func AdminColourUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var colour models.Colour
+	if !adminFind(c, &colour, "id") {
+		return
+	}
+
+	var input schema.ColourSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateColour(&colour, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update colour"})
+		return
+	}
+
+	c.JSON(http.StatusOK, colour)
+}
+
+func AdminColourDelete(c *gin.Context) {
+	var colour models.Colour
+	if adminFind(c, &colour, "id") {
+		adminDelete(c, &colour, "Failed to delete colour")
+	}
+}
+
+// sizes
+
+func AdminSizeList(c *gin.Context) {
+	var data []models.Size
+	adminList(c, &data)
+}
+
+func AdminSizeDetail(c *gin.Context) {
+	var size models.Size
+	if adminFind(c, &size, "id") {
+		c.JSON(http.StatusOK, size)
+	}
+}
+
+func AdminSizeCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.SizeSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	size, err := services.CatalogAdmin(store).CreateSize(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create size"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, size)
+}
+
+func AdminSizeUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var size models.Size
+	if !adminFind(c, &size, "id") {
+		return
+	}
+
+	var input schema.SizeSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateSize(&size, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update size"})
+		return
+	}
+
+	c.JSON(http.StatusOK, size)
+}
+
+func AdminSizeDelete(c *gin.Context) {
+	var size models.Size
+	if adminFind(c, &size, "id") {
+		adminDelete(c, &size, "Failed to delete size")
+	}
+}
@@ -0,0 +1,129 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminCouponList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	page, err := services.Coupons(store).AdminList(listParams(c, 10))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
+	})
+}
+
+func AdminCouponDetail(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	coupon, err := services.Coupons(store).AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the coupon")
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+func AdminCouponCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.CouponSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	coupon, err := services.Coupons(store).Create(input)
+	if err != nil {
+		adminSaveError(c, err, "Failed to create coupon")
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+func AdminCouponUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	coupons := services.Coupons(store)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	coupon, err := coupons.AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the coupon")
+		return
+	}
+
+	var input schema.CouponSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := coupons.Update(&coupon, input); err != nil {
+		adminSaveError(c, err, "Failed to update coupon")
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+func AdminCouponDelete(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	coupons := services.Coupons(store)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	coupon, err := coupons.AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the coupon")
+		return
+	}
+
+	if err := coupons.Delete(coupon); err != nil {
+		adminDeleteError(c, err, "Failed to delete coupon")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
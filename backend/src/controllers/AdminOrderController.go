@@ -0,0 +1,253 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	appconfig "backend/src/appconfig"
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func orderStatusFromName(name string) (uint8, bool) {
+	for status, statusName := range models.OrderStatusNames {
+		if statusName == name {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+func AdminOrderList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	var status *uint8
+	if name := c.Query("status"); len(name) > 0 {
+		found, ok := orderStatusFromName(name)
+		if !ok {
+			adminInvalid(c, helpers.ValidationError{Field: "status", Rule: "oneof", Message: "The selected status is invalid."})
+			return
+		}
+		status = &found
+	}
+
+	page, err := services.Orders(config, store).AdminList(status, listParams(c, 10))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
+	})
+}
+
+func AdminOrderDetail(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := services.Orders(config, store).AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the order")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order":       order,
+		"status":      order.StatusName(),
+		"transitions": staffTransitions(order.Status),
+	})
+}
+
+// staffTransitions lists the statuses staff may move an order to from
+// AdminOrderStatus. An order only becomes refunded through AdminReturnRefund.
+func staffTransitions(from uint8) []uint8 {
+	transitions := []uint8{}
+	for _, to := range models.OrderTransitions[from] {
+		if to != models.OrderStatusRefunded {
+			transitions = append(transitions, to)
+		}
+	}
+	return transitions
+}
+
+func AdminOrderStatus(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	var input schema.OrderStatusSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	to, ok := orderStatusFromName(input.Status)
+	if !ok {
+		adminInvalid(c, helpers.ValidationError{Field: "status", Rule: "oneof", Message: "The selected status is invalid."})
+		return
+	}
+
+	// Refunds move money and are booked against a return, so they only
+	// happen through AdminReturnRefund.
+	if to == models.OrderStatusRefunded {
+		adminInvalid(c, helpers.ValidationError{Field: "status", Rule: "not_in", Message: "Orders are refunded through their returns, not by setting the status."})
+		return
+	}
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := services.Orders(config, store).UpdateStatus(id, to, claimId(c), services.ActorStaff, input.Reason)
+	if err != nil {
+		var invalid *services.InvalidTransitionError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		storeError(c, err, "Failed to update order status")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"order": order, "status": order.StatusName()})
+}
+
+// returns
+
+func AdminReturnList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	status := c.Query("status")
+	if _, ok := models.ReturnTransitions[status]; len(status) > 0 && !ok {
+		adminInvalid(c, helpers.ValidationError{Field: "status", Rule: "oneof", Message: "The selected status is invalid."})
+		return
+	}
+
+	page, err := services.Returns(config, store).AdminList(status, listParams(c, 10))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
+	})
+}
+
+func AdminReturnDetail(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	orderReturn, err := services.Returns(config, store).AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the return")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"return":      orderReturn,
+		"total":       orderReturn.Total(),
+		"transitions": models.ReturnTransitions[orderReturn.Status],
+	})
+}
+
+func AdminReturnApprove(c *gin.Context) {
+	adminReturnStep(c, services.ReturnService.Approve)
+}
+
+func AdminReturnReject(c *gin.Context) {
+	adminReturnStep(c, services.ReturnService.Reject)
+}
+
+func AdminReturnReceive(c *gin.Context) {
+	adminReturnStep(c, services.ReturnService.Receive)
+}
+
+func AdminReturnRefund(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	var input schema.ReturnRefundSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	orderReturn, err := services.Returns(config, store).Refund(id, claimId(c), input.Amount, input.Note)
+	if err != nil {
+		returnError(c, err, "Failed to refund the return")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"return": orderReturn})
+}
+
+// adminReturnStep runs one of the return steps that only take a note.
+func adminReturnStep(c *gin.Context, step func(service services.ReturnService, id uint64, staffId uint64, note string) (models.OrderReturn, error)) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	var input schema.ReturnDecisionSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	orderReturn, err := step(services.Returns(config, store), id, claimId(c), input.Note)
+	if err != nil {
+		returnError(c, err, "Failed to update the return")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"return": orderReturn})
+}
@@ -0,0 +1,345 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminProductList(c *gin.Context) {
+	store := c.MustGet("store").(repositories.Store)
+	page, err := services.CatalogAdmin(store).Products(listParams(c, 10))
+	adminPage(c, page, err)
+}
+
+func AdminProductDetail(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	product, err := services.CatalogAdmin(store).ProductDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the product")
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func AdminProductCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.ProductSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	product, err := services.CatalogAdmin(store).CreateProduct(input)
+	if err != nil {
+		adminSaveError(c, err, "Failed to create product")
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+func AdminProductUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var input schema.ProductSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateProduct(&product, input); err != nil {
+		adminSaveError(c, err, "Failed to update product")
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func AdminProductDelete(c *gin.Context) {
+	var product models.Product
+	if adminFind(c, &product, "id") {
+		adminDelete(c, &product, "Failed to delete product")
+	}
+}
+
+func AdminProductPublish(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var input schema.ProductPublishSchema
+	if c.Request.ContentLength > 0 && !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).Publish(&product, input.PublishedAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish product"})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func AdminProductUnpublish(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).Unpublish(&product); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unpublish product"})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// product categories (products_categories)
+
+func AdminProductCategorySync(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var input schema.ProductCategoriesSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	categories, err := services.CatalogAdmin(store).SyncCategories(&product, input.CategoryIds)
+	if err != nil {
+		adminSaveError(c, err, "Failed to update product categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+func AdminProductCategoryAttach(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var category models.Category
+	if !adminFind(c, &category, "category_id") {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).AttachCategory(&product, category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to attach category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
+
+func AdminProductCategoryDetach(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	categoryId, ok := paramId(c, "category_id")
+	if !ok {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).DetachCategory(product.Id, categoryId); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detach category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
+
+// product images
+
+func AdminProductImageList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	productId, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	images, err := services.CatalogAdmin(store).Images(productId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load images"})
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+func AdminProductImageCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var input schema.ProductImageSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	image, err := services.CatalogAdmin(store).CreateImage(product.Id, input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create image"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+func AdminProductImageUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var image models.ProductImage
+	if !adminFind(c, &image, "id") {
+		return
+	}
+
+	var input schema.ProductImageSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateImage(&image, input); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update image"})
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+func AdminProductImageDelete(c *gin.Context) {
+	var image models.ProductImage
+	if adminFind(c, &image, "id") {
+		adminDelete(c, &image, "Failed to delete image")
+	}
+}
+
+// product inventories
+
+func AdminProductInventoryList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	productId, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	inventories, err := services.CatalogAdmin(store).Inventories(productId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load inventories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, inventories)
+}
+
+func AdminProductInventoryCreate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var product models.Product
+	if !adminFind(c, &product, "id") {
+		return
+	}
+
+	var input schema.ProductInventorySchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	inventory, err := services.CatalogAdmin(store).CreateInventory(product.Id, input)
+	if err != nil {
+		adminSaveError(c, err, "Failed to create inventory")
+		return
+	}
+
+	c.JSON(http.StatusCreated, inventory)
+}
+
+func AdminProductInventoryUpdate(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var inventory models.ProductInventory
+	if !adminFind(c, &inventory, "id") {
+		return
+	}
+
+	var input schema.ProductInventorySchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	if err := services.CatalogAdmin(store).UpdateInventory(&inventory, input); err != nil {
+		adminSaveError(c, err, "Failed to update inventory")
+		return
+	}
+
+	c.JSON(http.StatusOK, inventory)
+}
+
+func AdminProductInventoryDelete(c *gin.Context) {
+	var inventory models.ProductInventory
+	if adminFind(c, &inventory, "id") {
+		adminDelete(c, &inventory, "Failed to delete inventory")
+	}
+}
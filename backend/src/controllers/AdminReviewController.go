@@ -0,0 +1,124 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func reviewStatusFromName(name string) (uint8, bool) {
+	for status, statusName := range models.ReviewStatusNames {
+		if statusName == name {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// AdminReviewList is the moderation queue: reviews filtered by status
+// name and, optionally, by product.
+func AdminReviewList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var status *uint8
+	if name := c.Query("status"); len(name) > 0 {
+		found, ok := reviewStatusFromName(name)
+		if !ok {
+			adminInvalid(c, helpers.ValidationError{Field: "status", Rule: "oneof", Message: "The selected status is invalid."})
+			return
+		}
+		status = &found
+	}
+
+	var productId uint64
+	if value := c.Query("product_id"); len(value) > 0 {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			adminInvalid(c, helpers.ValidationError{Field: "product_id", Rule: "numeric", Message: "The product_id must be a number."})
+			return
+		}
+		productId = id
+	}
+
+	page, err := services.Reviews(store).AdminList(status, productId, listParams(c, 10))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
+	})
+}
+
+func AdminReviewDetail(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	review, err := services.Reviews(store).AdminDetail(id)
+	if err != nil {
+		storeError(c, err, "Failed to load the review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review, "status": review.StatusName()})
+}
+
+func AdminReviewApprove(c *gin.Context) {
+	adminReviewStep(c, services.ReviewService.Approve)
+}
+
+func AdminReviewReject(c *gin.Context) {
+	adminReviewStep(c, services.ReviewService.Reject)
+}
+
+// adminReviewStep approves or rejects a review with an optional note.
+func adminReviewStep(c *gin.Context, step func(service services.ReviewService, id uint64, note string) (models.ProductReview, error)) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.ReviewModerationSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	review, err := step(services.Reviews(store), id, input.Note)
+	if err != nil {
+		storeError(c, err, "Failed to moderate the review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review, "status": review.StatusName()})
+}
@@ -0,0 +1,71 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	appconfig "backend/src/appconfig"
+	helpers "backend/src/helpers"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminRoleList(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	roles, err := services.AccessControl(store).Roles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+func AdminUserRoles(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	user, err := services.Users(config, store).Find(id)
+	if err != nil {
+		storeError(c, err, "Failed to load user")
+		return
+	}
+
+	var input schema.UserRolesSchema
+	if !adminBind(c, &input) {
+		return
+	}
+
+	access := services.AccessControl(store)
+	if err := access.AssignRoles(user.Id, input.Roles...); err != nil {
+		if errors.Is(err, repositories.ErrUnknownRole) {
+			adminInvalid(c, helpers.ValidationError{Field: "roles", Rule: "exists", Message: "The selected roles are invalid."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": access.UserRoles(user.Id)})
+}
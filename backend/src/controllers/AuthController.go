@@ -12,26 +12,23 @@
 package controllers
 
 import (
-	helpers "backend/src/helpers"
-	models "backend/src/models"
+	appconfig "backend/src/appconfig"
+	repositories "backend/src/repositories"
 	schema "backend/src/schema"
 	services "backend/src/services"
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
 )
 
 func AuthLogin(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-
-	var user models.User
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserLoginSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -49,39 +46,94 @@ func AuthLogin(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	user, pair, err := services.Auth(config, store).Login(input.Email, input.Password)
+	switch {
+	case errors.Is(err, services.ErrUnknownEmail):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user with e-mail address " + input.Email + " not found!"})
 		return
+	case errors.Is(err, services.ErrAccountNotConfirmed):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "You need to confirm your account. We have sent you an activation code, please check your email.!"})
+		return
+	case errors.Is(err, services.ErrIncorrectPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "incorrect password!"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
 	}
 
-	decrypt := helpers.Decrypt(user.Password, user.Salt)
+	// A guest cart that cannot be merged is left for the next sign in
+	// rather than failing this one.
+	if token := strings.TrimSpace(c.GetHeader(CartTokenHeader)); token != "" {
+		if err := services.Cart(store).Merge(user.Id, token); err != nil {
+			log.Printf("merge guest cart into user %d: %v", user.Id, err)
+		}
+	}
 
-	if user.Status == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You need to confirm your account. We have sent you an activation code, please check your email.!"})
+	c.JSON(http.StatusOK, pair)
+}
+
+func AuthRefresh(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	var input schema.RefreshTokenSchema
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if input.Password != decrypt {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "incorrect password!"})
+	if len(strings.TrimSpace(input.RefreshToken)) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The refresh_token field is required.!"})
 		return
 	}
 
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "User Login",
-		Event:       "Sign In",
-		Description: "Sign in to application",
+	pair, err := services.Sessions(config, store).Rotate(input.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
 	}
-	db.Create(&Activity)
 
-	c.JSON(http.StatusOK, gin.H{"token": services.JWTAuthService().GenerateToken(int(user.Id), user.Email, true)})
+	c.JSON(http.StatusOK, pair)
 }
 
-func AuthRegister(c *gin.Context) {
+func AuthLogout(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+	auth := c.MustGet("claims").(jwt.MapClaims)
+
+	sid, _ := auth["sid"].(string)
+	if err := services.Auth(config, store).Logout(claimId(c), sid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "You have been signed out."})
+}
 
-	var user models.User
+func AuthLogoutAll(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	if err := services.Auth(config, store).LogoutAll(claimId(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "You have been signed out of all sessions."})
+}
+
+func AuthRegister(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserRegisterSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -119,117 +171,75 @@ func AuthRegister(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("email = ?", input.Email).First(&user).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "The email already exists"})
+	if _, err := services.Auth(config, store).Register(input.Name, input.Email, input.Password); err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The email already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create your account"})
 		return
 	}
 
-	bytes := make([]byte, 32) //generate a random 32 byte key for AES-256
-	if _, err := rand.Read(bytes); err != nil {
-		panic(err.Error())
-	}
-
-	key := hex.EncodeToString(bytes) //encode key in bytes to string and keep as secret, put in a vault
-	encrypted := helpers.Encrypt(input.Password, key)
-	token := (uuid.New()).String()
-
-	FirstName := ""
-	LastName := ""
-	Names := strings.Split(input.Name, " ")
-
-	if len(Names) > 0 {
-		FirstName = Names[0]
-		LastName = strings.Join(Names[1:], " ")
-	} else {
-		FirstName = Names[0]
-	}
-
-	User := models.User{
-		FirstName: helpers.NewNullString(FirstName),
-		LastName:  helpers.NewNullString(LastName),
-		Email:     input.Email,
-		Password:  encrypted,
-		Status:    0,
-		Salt:      key,
-	}
-	db.Create(&User)
-
-	Verification := models.Authentication{
-		UserId:     int64(User.Id),
-		AuthType:   "email-confirm",
-		Credential: input.Email,
-		Token:      token,
-		Status:     0,
-		ExpiredAt: func(t time.Time) *time.Time {
-			t = t.Add(30 * time.Minute)
-			return &t
-		}(time.Now()),
-	}
-	db.Create(&Verification)
-
-	Activity := models.Activity{
-		UserId:      int64(User.Id),
-		Subject:     "User Register",
-		Event:       "Sign Up",
-		Description: "Register new user account",
-	}
-	db.Create(&Activity)
-
-	c.JSON(http.StatusOK, gin.H{"message": "Your account has been created. Please check your email for the confirmation message we just sent you.", "token": token})
+	c.JSON(http.StatusOK, gin.H{"message": "Your account has been created. Please check your email for the confirmation message we just sent you."})
 }
 
 func AuthConfirm(c *gin.Context) {
 
-	var verification models.Authentication
-	var user models.User
-
-	db := c.MustGet("db").(*gorm.DB)
-
-	if err := db.Where("token = ? AND status = ? ", c.Param("token"), 0).First(&verification).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "We can't find a user with that  token is invalid.!"})
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	if err := services.Auth(config, store).Confirm(c.Param("token")); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User not found!"})
+		case isVerificationError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		}
 		return
 	}
 
-	if err := db.Where("email = ?", verification.Credential).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found!"})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{"message": "Your registration is complete. Now you can login."})
+}
 
-	updateVerification := models.Authentication{
-		ExpiredAt: func(t time.Time) *time.Time { return &t }(time.Now()),
-		Status:    2,
-	}
+func AuthConfirmResend(c *gin.Context) {
 
-	updateUser := models.User{
-		Status: 1,
-	}
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	if err := db.Model(&verification).Updates(updateVerification).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update verification"})
+	var input schema.ConfirmResendSchema
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := db.Model(&user).Updates(updateUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+	if len(strings.TrimSpace(input.Email)) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The email field is required.!"})
 		return
 	}
 
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "User Verification",
-		Event:       "Email Confirmation",
-		Description: "Confirm new member registration account",
+	if err := services.Auth(config, store).ResendConfirmation(input.Email); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEmail):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "We can't find a user with that e-mail address."})
+		case errors.Is(err, services.ErrAccountConfirmed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Your account has already been confirmed. Now you can login."})
+		case errors.Is(err, services.ErrVerificationThrottled):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send confirmation e-mail"})
+		}
+		return
 	}
-	db.Create(&Activity)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Your registration is complete. Now you can login."})
+	c.JSON(http.StatusOK, gin.H{"message": "We have sent you a new confirmation link, please check your email."})
 }
 
 func AuthEmailForgot(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-
-	var user models.User
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserForgotSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -242,43 +252,25 @@ func AuthEmailForgot(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "We can't find a user with that e-mail address."})
+	if err := services.Auth(config, store).Forgot(input.Email); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEmail):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "We can't find a user with that e-mail address."})
+		case errors.Is(err, services.ErrVerificationThrottled):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send password reset e-mail"})
+		}
 		return
 	}
 
-	token := (uuid.New()).String()
-
-	ResetPassword := models.Authentication{
-		UserId:     int64(user.Id),
-		AuthType:   "reset-password",
-		Credential: input.Email,
-		Token:      token,
-		Status:     0,
-		ExpiredAt: func(t time.Time) *time.Time {
-			t = t.Add(30 * time.Minute)
-			return &t
-		}(time.Now()),
-	}
-	db.Create(&ResetPassword)
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "Request Forgot Password",
-		Event:       "Forgot Password",
-		Description: "Request reset password link",
-	}
-	db.Create(&Activity)
-
-	c.JSON(http.StatusOK, gin.H{"message": "We have e-mailed your password reset link!", "token": token})
+	c.JSON(http.StatusOK, gin.H{"message": "We have e-mailed your password reset link!"})
 }
 
 func AuthEmailReset(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-
-	var user models.User
-	var resetPassword models.Authentication
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserResetSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -311,52 +303,23 @@ func AuthEmailReset(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("credential = ? AND token = ? AND status = 0", input.Email, c.Param("token")).First(&resetPassword).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This password and email reset token is invalid."})
+	if err := services.Auth(config, store).Reset(c.Param("token"), input.Email, input.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEmail):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user with e-mail address " + input.Email + " not found!"})
+		case isVerificationError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		}
 		return
 	}
 
-	if err := db.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user with e-mail address " + input.Email + " not found!"})
-		return
-	}
-
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		panic(err.Error())
-	}
-
-	key := hex.EncodeToString(bytes)
-	encrypted := helpers.Encrypt(input.Password, key)
-
-	updateUser := models.User{
-		Status:   1,
-		Password: encrypted,
-		Salt:     key,
-	}
-
-	updatePasswordReset := models.Authentication{
-		ExpiredAt: func(t time.Time) *time.Time { return &t }(time.Now()),
-		Status:    2,
-	}
-
-	if err := db.Model(&resetPassword).Updates(updatePasswordReset).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password reset"})
-		return
-	}
-
-	if err := db.Model(&user).Updates(updateUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
-		return
-	}
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "User Recovery",
-		Event:       "Reset Password",
-		Description: "Reset account password",
-	}
-	db.Create(&Activity)
-
 	c.JSON(http.StatusOK, gin.H{"message": "Your password has been reset!"})
 }
+
+// isVerificationError reports whether err is the caller's fault: a token
+// that does not exist, was already used or has expired.
+func isVerificationError(err error) bool {
+	return errors.Is(err, services.ErrVerificationTokenInvalid) || errors.Is(err, services.ErrVerificationTokenExpired)
+}
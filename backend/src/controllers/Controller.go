@@ -0,0 +1,91 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+// claimId returns the id of the user the request's access token was issued to.
+func claimId(c *gin.Context) uint64 {
+	auth := c.MustGet("claims").(jwt.MapClaims)
+	id, _ := auth["id"].(float64)
+	return uint64(id)
+}
+
+// CartTokenHeader carries a guest's cart token on the cart routes and on
+// login, where the guest cart is merged into the user's.
+const CartTokenHeader = "X-Cart-Token"
+
+// cartOwner is the signed-in user when the request has an access token,
+// and otherwise the guest the cart token header names.
+func cartOwner(c *gin.Context) services.CartOwner {
+	if _, ok := c.Get("claims"); ok {
+		return services.CartOwner{UserId: claimId(c)}
+	}
+	return services.CartOwner{Token: strings.TrimSpace(c.GetHeader(CartTokenHeader))}
+}
+
+var adminOrderColumn = regexp.MustCompile(`^[a-z_]+$`)
+
+// paramId parses a numeric path parameter, answering 404 when it is not one.
+func paramId(c *gin.Context, name string) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return 0, false
+	}
+	return id, true
+}
+
+// listParams reads the paging, sorting and search parameters of a list.
+func listParams(c *gin.Context, limit int) repositories.ListQuery {
+
+	query := repositories.ListQuery{Page: 1, Limit: limit, OrderBy: "id", OrderDir: "desc"}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit <= 100 {
+		query.Limit = limit
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		query.Page = page
+	}
+
+	if adminOrderColumn.MatchString(c.Query("order_by")) {
+		query.OrderBy = c.Query("order_by")
+	}
+
+	if dir := strings.ToLower(c.Query("order_dir")); dir == "asc" || dir == "desc" {
+		query.OrderDir = dir
+	}
+
+	query.Search = strings.TrimSpace(c.Query("search"))
+	return query
+}
+
+// storeError answers 404 for a missing record and 500 with message otherwise.
+func storeError(c *gin.Context, err error, message string) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -12,115 +12,49 @@
 package controllers
 
 import (
-	helpers "backend/src/helpers"
-	"backend/src/models"
-	"backend/src/schema"
-	"database/sql"
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	services "backend/src/services"
 	"errors"
-	"fmt"
-	"math"
+	"log"
 	"net/http"
-	"strconv"
-	"strings"
-	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/jinzhu/gorm"
 )
 
-type ProductRequest struct {
-	Id    int64
-	Name  string
-	Image sql.NullString
-	Price float64
-}
-
-type ProductCartRequest struct {
-	Id    int64
-	Name  string
-	Image sql.NullString
-	Price float64
-	Qty   uint16
-	Total float64
-}
-
-type ProductReviewRequest struct {
-	Id          int64
-	Name        string
-	Description string
-	Rating      float64
-	Percentage  float64
-	CreatedAt   time.Time
-}
-
 func OrderWishlist(c *gin.Context) {
 
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	product_id := c.Param("id")
-	db := c.MustGet("db").(*gorm.DB)
+	store := c.MustGet("store").(repositories.Store)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+	productId, ok := paramId(c, "id")
+	if !ok {
 		return
 	}
 
-	db.Exec("DELETE FROM products_wishlists WHERE product_id = ? AND = ?", product_id, auth["id"])
-	db.Exec("INSERT INTO products_wishlists(product_id, user_id) VALUES(?,?) ", product_id, auth["id"])
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "Add Wishlist",
-		Event:       "Add Product To Wishlist",
-		Description: "Your has been added product to your wishlist.",
+	if err := services.Cart(store).AddToWishlist(claimId(c), productId); err != nil {
+		storeError(c, err, "Failed to update wishlist")
+		return
 	}
-	db.Create(&Activity)
 
 	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
 }
 
 func OrderGetSession(c *gin.Context) {
 
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-
-	var order models.Order
-	db.Where("status = 0 AND user_id = ?", auth["id"]).Order("id desc").First(&order)
-
-	var carts []ProductCartRequest
-	var whislists []ProductRequest
-
-	db.Raw(`
-		SELECT 
-			products.image,
-			products.name,
-			products.price
-		FROM
-			products
-		INNER JOIN products_wishlists ON products_wishlists.product_id = products.id
-		WHERE products_wishlists.user_id = ?
-	`, auth["id"]).Scan(&whislists)
-
-	db.Raw(`
-		SELECT 
-			products.id,
-			products.image,
-			products.name,
-			products.price,
-			orders_details.qty,
-			orders_details.total
-		FROM orders_details
-		INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
-		INNER JOIN orders ON orders.id = orders_details.order_id
-		INNER JOIN products ON products.id = products_inventories.product_id
-		WHERE orders.status = 0 AND orders.user_id = ?
-	`, auth["id"]).Scan(&carts)
+	store := c.MustGet("store").(repositories.Store)
+
+	session, err := services.Cart(store).Session(cartOwner(c))
+	if err != nil {
+		storeError(c, err, "Failed to load your cart")
+		return
+	}
 
 	var payload = gin.H{
-		"carts":     carts,
-		"order":     order,
-		"whislists": whislists,
+		"carts":     session.Lines,
+		"order":     session.Order,
+		"whislists": session.Wishlist,
 	}
 
 	c.JSON(http.StatusOK, payload)
@@ -128,92 +62,38 @@ func OrderGetSession(c *gin.Context) {
 
 func OrderCart(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	product_id := c.Param("id")
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+	productId, ok := paramId(c, "id")
+	if !ok {
 		return
 	}
 
-	var topProduct models.Product
-	db.Where("status = 1 AND published_at <= NOW()").Order("total_rating desc").First(&topProduct)
-
-	var getProducts []models.Product
-	db.Preload("Categories").Limit(4).Where("status = 1 AND published_at <= NOW() AND id = ?", product_id).Order("id desc").Find(&getProducts)
-
-	var getTopSellings []models.Product
-	db.Preload("Categories").Limit(3).Where("status = 1 AND published_at <= NOW() AND id != ? ", product_id).Order("total_order desc").Find(&getTopSellings)
-
-	var images []models.ProductImage
-	db.Where("product_id = ? ", product_id).Order("id desc").Find(&images)
-
-	var sizes []models.Size
-	db.Where("status = 1").Order("name asc").Find(&sizes)
-
-	var colours []models.Size
-	db.Where("status = 1").Order("name asc").Find(&colours)
-
-	var inventories []models.ProductInventory
-	db.Where("product_id = ?", product_id).Find(&inventories)
-
-	var products []ProductResponse
-	for _, p := range getProducts {
-
-		var categoryNames []string
-		for _, cat := range p.Categories {
-			categoryNames = append(categoryNames, cat.Name)
-		}
-
-		numRandom := uint16(helpers.RandomInt(0, 1))
-		products = append(products, ProductResponse{
-			Id:           int64(p.Id),
-			Name:         p.Name,
-			Image:        p.Image,
-			Description:  p.Description,
-			Details:      p.Details,
-			Price:        p.Price,
-			PriceOld:     p.Price + (p.Price * 0.05),
-			CategoryName: strings.Join(categoryNames, ", "),
-			IsNewest:     numRandom == 1,
-			IsDiscount:   numRandom == 0,
-			TotalRating:  math.Floor((((float64(p.TotalRating) / float64(topProduct.TotalRating)) * 100) / 20)),
-		})
-	}
-
-	var topSellings []ProductResponse
-	for _, p3 := range getTopSellings {
-
-		var categoryNames []string
-		for _, cat := range p3.Categories {
-			categoryNames = append(categoryNames, cat.Name)
+	// Guests shop without an account, so there is no user to show them.
+	var user *models.User
+	if owner := cartOwner(c); !owner.IsGuest() {
+		found, err := services.Users(config, store).Find(owner.UserId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+			return
 		}
+		user = &found
+	}
 
-		numRandom := uint16(helpers.RandomInt(0, 1))
-		topSellings = append(topSellings, ProductResponse{
-			Id:           int64(p3.Id),
-			Name:         p3.Name,
-			Image:        p3.Image,
-			Description:  p3.Description,
-			Details:      p3.Details,
-			Price:        p3.Price,
-			PriceOld:     p3.Price + (p3.Price * 0.05),
-			CategoryName: strings.Join(categoryNames, ", "),
-			IsNewest:     numRandom == 1,
-			IsDiscount:   numRandom == 0,
-			TotalRating:  math.Floor((((float64(p3.TotalRating) / float64(topProduct.TotalRating)) * 100) / 20)),
-		})
+	view, err := services.Catalog(store).Product(productId)
+	if err != nil {
+		storeError(c, err, "Failed to load the product")
+		return
 	}
 
 	var payload = gin.H{
-		"images":         images,
-		"product":        products[0],
-		"productRelated": topSellings,
-		"sizes":          sizes,
-		"colours":        colours,
-		"inventories":    inventories,
+		"images":         view.Images,
+		"product":        view.Product,
+		"productRelated": view.Related,
+		"sizes":          view.Sizes,
+		"colours":        view.Colours,
+		"inventories":    view.Inventories,
 		"user":           user,
 	}
 
@@ -222,39 +102,48 @@ func OrderCart(c *gin.Context) {
 
 func OrderListReview(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	product_id := c.Param("id")
+	store := c.MustGet("store").(repositories.Store)
+
+	productId, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
 
-	var topRating models.ProductReview
-	db.Where("product_id = ?", product_id).Order("rating desc").First(&topRating)
+	reviews, err := services.Reviews(store).List(productId)
+	if err != nil {
+		storeError(c, err, "Failed to load the reviews")
+		return
+	}
 
-	var reviews []models.ProductReview
-	db.Preload("User").Where("product_id = ?", product_id).Order("id desc").Find(&reviews)
+	c.JSON(http.StatusOK, reviews)
+}
 
-	var payload []ProductReviewRequest
-	for _, p := range reviews {
-		payload = append(payload, ProductReviewRequest{
-			Id:          int64(p.Id),
-			Name:        p.User.FirstName.String + " " + p.User.LastName.String,
-			Description: p.Review,
-			Rating:      math.Floor((((float64(p.Rating) / float64(topRating.Rating)) * 100) / 20)),
-			Percentage:  math.Floor(((float64(p.Rating) / float64(topRating.Rating)) * 100)),
-			CreatedAt:   p.CreatedAt,
-		})
+// OrderMyReview returns the customer's own review of a product, so it can
+// be edited, together with its moderation status.
+func OrderMyReview(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	productId, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
 
-	c.JSON(http.StatusOK, payload)
+	review, err := services.Reviews(store).Mine(claimId(c), productId)
+	if err != nil {
+		storeError(c, err, "Failed to load the review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"review": review, "status": review.StatusName()})
 }
 
 func OrderCreateReview(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	product_id := c.Param("id")
-	auth := c.MustGet("claims").(jwt.MapClaims)
+	store := c.MustGet("store").(repositories.Store)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+	productId, ok := paramId(c, "id")
+	if !ok {
 		return
 	}
 
@@ -264,34 +153,50 @@ func OrderCreateReview(c *gin.Context) {
 		return
 	}
 
-	var Product models.Product
-	db.Where("id = ? ", product_id).Order("total_rating desc").First(&Product)
+	review, err := services.Reviews(store).Submit(claimId(c), productId, input.Rating, input.Review)
+	if err != nil {
+		reviewError(c, err, "Failed to save review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "Your review has been sent and will appear once it is approved.", "review": review})
+}
+
+func OrderReviewVote(c *gin.Context) {
+	orderReviewVote(c, services.ReviewService.Vote)
+}
+
+func OrderReviewUnvote(c *gin.Context) {
+	orderReviewVote(c, services.ReviewService.Unvote)
+}
+
+// orderReviewVote adds or takes back the customer's helpful vote.
+func orderReviewVote(c *gin.Context, vote func(service services.ReviewService, userId uint64, id uint64) (models.ProductReview, error)) {
+
+	store := c.MustGet("store").(repositories.Store)
 
-	ModelReview := models.ProductReview{
-		User:    user,
-		Product: Product,
-		Rating:  uint16(input.Rating),
-		Status:  1,
-		Review:  input.Review,
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
-	db.Create(&ModelReview)
 
-	Activity := models.Activity{
-		User:        user,
-		Subject:     "Create new review",
-		Event:       "Add review to " + Product.Name,
-		Description: "Your has been added new review to " + Product.Name,
+	review, err := vote(services.Reviews(store), claimId(c), id)
+	if err != nil {
+		reviewError(c, err, "Failed to save the vote")
+		return
 	}
-	db.Create(&Activity)
 
-	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+	c.JSON(http.StatusOK, gin.H{"id": review.Id, "helpful": review.HelpfulCount})
 }
 
 func OrderCreateCart(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	product_id := c.Param("id")
-	auth := c.MustGet("claims").(jwt.MapClaims)
+	store := c.MustGet("store").(repositories.Store)
+
+	productId, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
 
 	var input schema.CreateCartSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -299,166 +204,155 @@ func OrderCreateCart(c *gin.Context) {
 		return
 	}
 
-	var Product models.Product
-	db.Where("id = ? ", product_id).Order("total_rating desc").First(&Product)
-
-	var User models.User
-	db.Where("id = ? ", auth["id"]).First(&User)
+	item := services.CartItem{
+		ProductId: productId,
+		SizeId:    input.SizeId,
+		ColourId:  input.ColourId,
+		Qty:       uint16(input.Qty),
+	}
 
-	var Payment models.Payment
-	db.Where("status = 1").First(&Payment)
+	token, err := services.Cart(store).Add(cartOwner(c), item)
+	if err != nil {
+		cartError(c, err, "Failed to update Order")
+		return
+	}
 
-	Order := &models.Order{}
-	resultOrder := db.Where("status = 0 AND user_id = ?", auth["id"]).Order("id desc").First(Order)
+	var payload = gin.H{"status": true, "message": "ok"}
+	if token != "" {
+		payload["cart_token"] = token
+	}
 
-	Inventory := &models.ProductInventory{}
-	resultInventory := db.Where("product_id = ? AND size_id = ? AND colour_id = ?", product_id, input.SizeId, input.ColourId).Order("id desc").First(Inventory)
+	c.JSON(http.StatusOK, payload)
+}
 
-	Total := Product.Price * float64(input.Qty)
+func OrderCartUpdate(c *gin.Context) {
 
-	if errors.Is(resultOrder.Error, gorm.ErrRecordNotFound) {
-		// Not found
-		NewOrder := models.Order{
-			User:          User,
-			Payment:       Payment,
-			InvoiceNumber: strconv.FormatInt(helpers.NowTicks(), 10),
-			TotalItem:     uint16(input.Qty),
-			Status:        0,
-			Subtotal:      Total,
-			TotalPaid:     Total,
-		}
-		db.Create(&NewOrder)
-		Order.Id = NewOrder.Id
-	} else {
-		// Item Exists
-		Order.TotalItem = Order.TotalItem + uint16(input.Qty)
-		Order.Subtotal = Order.Subtotal + Total
-		Order.TotalPaid = Order.Subtotal + Total
-		if err := db.Save(&Order).Error; err != nil {
-			c.JSON(500, gin.H{"error": "Failed to update Order"})
-			return
-		}
-	}
+	store := c.MustGet("store").(repositories.Store)
 
-	if !errors.Is(resultInventory.Error, gorm.ErrRecordNotFound) {
-
-		DetailOrder := &models.OrderDetail{}
-		resultDetailOrder := db.Where("inventory_id = ? AND order_id = ?", Inventory.Id, Order.Id).First(DetailOrder)
-
-		if !errors.Is(resultDetailOrder.Error, gorm.ErrRecordNotFound) {
-			DetailOrder.Qty = DetailOrder.Qty + uint16(input.Qty)
-			DetailOrder.Total = DetailOrder.Total + Total
-			if err := db.Save(&DetailOrder).Error; err != nil {
-				c.JSON(500, gin.H{"error": "Failed to update Detail Order"})
-				return
-			}
-		} else {
-			NewDetailOrder := models.OrderDetail{
-				OrderId:     Order.Id,
-				InventoryId: Inventory.Id,
-				Price:       Product.Price,
-				Status:      1,
-				Qty:         uint16(input.Qty),
-				Total:       Total,
-			}
-			db.Create(&NewDetailOrder)
-		}
+	detailId, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
 
-	if Order != nil {
-		db.Exec("DELETE FROM orders_carts WHERE order_id = ? AND product_id = ?", Order.Id, product_id)
-		db.Exec("INSERT INTO orders_carts(order_id, product_id) VALUES (?,?)", Order.Id, product_id)
+	var input schema.UpdateCartLineSchema
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	Activity := models.Activity{
-		User:        User,
-		Subject:     "Add Cart",
-		Event:       "Add product to cart with name " + Product.Name,
-		Description: "Your has been added product to cart. with name " + Product.Name,
+	if err := services.Cart(store).UpdateLine(cartOwner(c), detailId, uint16(input.Qty)); err != nil {
+		storeError(c, err, "Failed to update your cart")
+		return
 	}
-	db.Create(&Activity)
 
 	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
 }
 
-func OrderCheckoutInitial(c *gin.Context) {
+func OrderCartRemove(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	auth := c.MustGet("claims").(jwt.MapClaims)
+	store := c.MustGet("store").(repositories.Store)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+	detailId, ok := paramId(c, "id")
+	if !ok {
 		return
 	}
 
-	var payments []models.Payment
-	db.Where("status = 1").Order("name asc").Find(&payments)
+	if err := services.Cart(store).RemoveLine(cartOwner(c), detailId); err != nil {
+		storeError(c, err, "Failed to update your cart")
+		return
+	}
 
-	var order models.Order
-	db.Where("status = 0 AND user_id = ?", auth["id"]).Order("id desc").First(&order)
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
 
-	var carts []ProductCartRequest
-	db.Raw(`
-		SELECT 
-			products.id,
-			products.image,
-			products.name,
-			products.price,
-			orders_details.qty,
-			orders_details.total
-		FROM orders_details
-		INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
-		INNER JOIN orders ON orders.id = orders_details.order_id
-		INNER JOIN products ON products.id = products_inventories.product_id
-		WHERE orders.status = 0 AND orders.user_id = ?
-	`, auth["id"]).Scan(&carts)
+func OrderCartClear(c *gin.Context) {
 
-	var discount models.Setting
-	db.Where("key_name = ?", "discount_value").Order("id desc").First(&discount)
+	store := c.MustGet("store").(repositories.Store)
 
-	var taxes models.Setting
-	db.Where("key_name = ?", "taxes_value").Order("id desc").First(&taxes)
+	if err := services.Cart(store).Clear(cartOwner(c)); err != nil {
+		storeError(c, err, "Failed to empty your cart")
+		return
+	}
 
-	var shipment models.Setting
-	db.Where("key_name = ?", "total_shipment").Order("id desc").First(&shipment)
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
 
-	subtotal := order.Subtotal
+func OrderCouponApply(c *gin.Context) {
 
-	iDiscount, err := strconv.ParseFloat(discount.KeyValue, 64)
-	if err != nil {
-		fmt.Println("Error:", err)
+	store := c.MustGet("store").(repositories.Store)
+
+	var input schema.ApplyCouponSchema
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	iTaxes, err := strconv.ParseFloat(taxes.KeyValue, 64)
+	coupon, err := services.Coupons(store).Apply(claimId(c), input.Code)
 	if err != nil {
-		fmt.Println("Error:", err)
+		var invalid *services.CouponError
+		switch {
+		case errors.Is(err, services.ErrCartEmpty):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Your cart is empty."})
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": invalid.Reason})
+		default:
+			storeError(c, err, "Failed to apply the coupon")
+		}
 		return
 	}
 
-	totalShipment, err := strconv.ParseFloat(shipment.KeyValue, 64)
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok", "coupon": coupon})
+}
+
+func OrderCouponRemove(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+
+	if err := services.Coupons(store).Remove(claimId(c)); err != nil {
+		if errors.Is(err, services.ErrCartEmpty) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Your cart is empty."})
+			return
+		}
+		storeError(c, err, "Failed to remove the coupon")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
+
+func OrderCheckoutInitial(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	user, err := services.Users(config, store).Find(claimId(c))
 	if err != nil {
-		fmt.Println("Error:", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
 		return
 	}
 
-	totalDiscount := subtotal * (iDiscount / 100)
-	totalTaxes := subtotal * (iTaxes / 100)
+	// Tax depends on the billing address, which the checkout form sends
+	// while it is being filled in; until then the profile address is used.
+	address := services.PriceAddress{
+		Country: c.DefaultQuery("country", user.Country.String),
+		ZipCode: c.DefaultQuery("zip_code", user.ZipCode.String),
+	}
 
-	order.TotalDiscount = totalDiscount
-	order.TotalTaxes = totalTaxes
-	order.TotalShipment = totalShipment
-	order.TotalPaid = (subtotal + totalTaxes + totalShipment) - totalDiscount
+	quote, err := services.Orders(config, store).Quote(user.Id, address)
+	if err != nil {
+		storeError(c, err, "Failed to price your cart")
+		return
+	}
 
 	var payload = gin.H{
-		"order":    order,
-		"carts":    carts,
-		"user":     user,
-		"payments": payments,
-		"discount": iDiscount,
-		"taxes":    iTaxes,
-		"shipment": totalShipment,
+		"order":     quote.Order,
+		"carts":     quote.Lines,
+		"user":      user,
+		"payments":  quote.Payments,
+		"discount":  quote.Breakdown.DiscountRate(),
+		"taxes":     quote.Breakdown.TaxRate,
+		"shipment":  quote.Breakdown.Shipment,
+		"breakdown": quote.Breakdown,
 	}
 
 	c.JSON(http.StatusOK, payload)
@@ -466,8 +360,8 @@ func OrderCheckoutInitial(c *gin.Context) {
 
 func OrderCheckout(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	auth := c.MustGet("claims").(jwt.MapClaims)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.CheckoutSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -475,281 +369,205 @@ func OrderCheckout(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+	order, breakdown, err := services.Orders(config, store).Checkout(claimId(c), input)
+	if err != nil {
+		var outOfStock *services.OutOfStockError
+		var invalid *services.InvalidTransitionError
+		var coupon *services.CouponError
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
+		case errors.Is(err, services.ErrCartEmpty):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Your cart is empty."})
+		case errors.As(err, &outOfStock):
+			c.JSON(http.StatusConflict, gin.H{"error": "Some items in your cart are no longer in stock.", "lines": outOfStock.Lines})
+		case errors.As(err, &coupon):
+			c.JSON(http.StatusConflict, gin.H{"error": coupon.Reason, "coupon": coupon.Code})
+		case errors.As(err, &invalid):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete checkout"})
+		}
 		return
 	}
 
-	var order models.Order
-	db.Where("status = 0 AND user_id = ?", auth["id"]).Order("id desc").First(&order)
+	// The order is placed either way; when the provider cannot be reached
+	// the customer starts the payment again from the order page.
+	var transaction *models.PaymentTransaction
+	if started, err := services.Payments(config, store).Start(order.UserId, order.Id); err == nil {
+		transaction = &started
+	} else {
+		log.Printf("start payment for order %d: %v", order.Id, err)
+	}
 
-	var details []models.OrderDetail
-	db.Where("order_id = ? ", order.Id).Find(&details)
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok", "order": order, "breakdown": breakdown, "payment": transaction})
+}
 
-	for _, detail := range details {
+func OrderList(c *gin.Context) {
 
-		inventory := detail.Inventory
-		inventory.Stock = inventory.Stock - detail.Qty
-		if err := db.Save(&inventory).Error; err != nil {
-			c.JSON(500, gin.H{"error": "Failed to update inventory"})
-			return
-		}
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-		product := detail.Inventory.Product
-		product.TotalOrder = product.TotalOrder + detail.Qty
-		if err := db.Save(&inventory).Error; err != nil {
-			c.JSON(500, gin.H{"error": "Failed to update product"})
-			return
-		}
-
-		db.Exec("DELETE FROM products_wishlists WHERE product_id = ? AND = ?", product.Id, auth["id"])
+	page, err := services.Orders(config, store).List(claimId(c), listParams(c, 10))
+	if err != nil {
+		storeError(c, err, "Failed to load your orders")
+		return
+	}
 
+	var payload = gin.H{
+		"list":          page.List,
+		"totalAll":      page.TotalAll,
+		"totalFiltered": page.TotalFiltered,
+		"limit":         page.Limit,
+		"page":          page.Page,
 	}
 
-	var discount models.Setting
-	db.Where("key_name = ?", "discount_value").Order("id desc").First(&discount)
+	c.JSON(http.StatusOK, payload)
+}
 
-	var taxes models.Setting
-	db.Where("key_name = ?", "taxes_value").Order("id desc").First(&taxes)
+func OrderDetail(c *gin.Context) {
 
-	var shipment models.Setting
-	db.Where("key_name = ?", "total_shipment").Order("id desc").First(&shipment)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	subtotal := order.Subtotal
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
 
-	iDiscount, err := strconv.ParseFloat(discount.KeyValue, 64)
+	view, err := services.Orders(config, store).Detail(claimId(c), id)
 	if err != nil {
-		fmt.Println("Error:", err)
+		storeError(c, err, "Failed to load the order")
 		return
 	}
 
-	iTaxes, err := strconv.ParseFloat(taxes.KeyValue, 64)
+	transactions, err := services.Payments(config, store).Transactions(id)
 	if err != nil {
-		fmt.Println("Error:", err)
+		storeError(c, err, "Failed to load the order")
 		return
 	}
 
-	totalShipment, err := strconv.ParseFloat(shipment.KeyValue, 64)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return
-	}
-
-	totalDiscount := subtotal * (iDiscount / 100)
-	totalTaxes := subtotal * (iTaxes / 100)
-
-	order.Status = 1
-	order.PaymentId = input.PaymentId
-	order.TotalTaxes = totalTaxes
-	order.TotalDiscount = totalDiscount
-	order.TotalShipment = totalShipment
-	order.TotalPaid = (subtotal + totalTaxes + totalShipment) - totalDiscount
-	if err := db.Save(&order).Error; err != nil {
-		c.JSON(500, gin.H{"error": "Failed to update order"})
-		return
-	}
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "email",
-		Description: input.Email,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "phone",
-		Description: input.Phone,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "first_name",
-		Description: input.FirstName,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "last_name",
-		Description: input.LastName,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "country",
-		Description: input.Country,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "city",
-		Description: input.City,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "zip_code",
-		Description: input.ZipCode,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "address",
-		Description: input.Address,
-		Status:      1,
-	})
-
-	db.Create(&models.OrderBilling{
-		OrderId:     order.Id,
-		Name:        "notes",
-		Description: input.Notes,
-		Status:      1,
-	})
-
-	db.Exec("DELETE FROM orders_carts WHERE order_id = ? ", order.Id)
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "Checkout Order",
-		Event:       "Completed Checkout Current Order",
-		Description: "Your order has been finished.",
-	}
-	db.Create(&Activity)
+	var payload = gin.H{
+		"discount":     view.Discount,
+		"taxes":        view.Taxes,
+		"shipment":     view.Order.TotalShipment,
+		"carts":        view.Lines,
+		"order":        view.Order,
+		"status":       view.Order.StatusName(),
+		"histories":    view.Histories,
+		"billings":     view.Billings,
+		"payment":      view.Payment,
+		"transactions": transactions,
+	}
 
-	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+	c.JSON(http.StatusOK, payload)
 }
 
-func OrderList(c *gin.Context) {
+func OrderCancel(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	auth := c.MustGet("claims").(jwt.MapClaims)
-
-	page := 1
-	limit := 10
-	order_by := "id"
-	order_dir := "desc"
-	offset := ((page - 1) * limit)
-	var data []models.Order
-	var total_filtered int64
-	var total_all int64
-
-	db.Model(&models.Order{}).Where("user_id = ? ", auth["id"]).Count(&total_all)
-	total_filtered = total_all
-
-	if len(strings.TrimSpace(c.Query("limit"))) > 0 {
-		limit_, err := strconv.ParseInt(c.Query("limit"), 0, 32)
-		if err == nil {
-			limit = int(limit_)
-		}
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
 
-	if len(strings.TrimSpace(c.Query("page"))) > 0 {
-		page_, err := strconv.ParseInt(c.Query("page"), 0, 32)
-		if err == nil {
-			page = int(page_)
+	if err := services.Orders(config, store).Cancel(claimId(c), id); err != nil {
+		var invalid *services.InvalidTransitionError
+		if errors.As(err, &invalid) || errors.Is(err, services.ErrOrderNotCancellable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		}
+		storeError(c, err, "Failed to cancel order")
+		return
 	}
 
-	if len(strings.TrimSpace(c.Query("order_by"))) > 0 {
-		order_by = c.Query("order_by")
-	}
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
 
-	if len(strings.TrimSpace(c.Query("order_dir"))) > 0 {
-		order_dir = c.Query("order_dir")
-	}
+func OrderReturnRequest(c *gin.Context) {
 
-	if len(strings.TrimSpace(c.Query("search"))) > 0 {
-		db = db.Where("invoice_number LIKE ? ", c.Query("search"))
-		db.Model(&models.Order{}).Where("invoice_number LIKE ? ", c.Query("search")).Where("user_id = ? ", auth["id"]).Count(&total_filtered)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
 
-	db = db.Limit(limit).Offset(offset).Order(order_by + " " + order_dir).Find(&data)
+	var input schema.ReturnRequestSchema
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	var payload = gin.H{
-		"list":          data,
-		"totalAll":      total_all,
-		"totalFiltered": total_filtered,
-		"limit":         limit,
-		"page":          page,
+	orderReturn, err := services.Returns(config, store).Request(claimId(c), id, input)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotReturnable) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only delivered orders can be returned."})
+			return
+		}
+		returnError(c, err, "Failed to request the return")
+		return
 	}
 
-	c.JSON(http.StatusOK, payload)
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok", "return": orderReturn})
 }
 
-func OrderDetail(c *gin.Context) {
+func OrderReturnList(c *gin.Context) {
 
-	db := c.MustGet("db").(*gorm.DB)
-	id := c.Param("id")
-
-	var order models.Order
-	db.Where("id = ?", id).Order("id desc").First(&order)
-
-	var billings []models.OrderBilling
-	db.Where("order_id = ? ", id).Order("name asc").Find(&billings)
-
-	var payment models.Payment
-	db.Where("id = ?", order.PaymentId).Order("id desc").First(&payment)
-
-	var carts []ProductCartRequest
-	db.Raw(`
-		SELECT 
-			products.id,
-			products.image,
-			products.name,
-			products.price,
-			orders_details.qty,
-			orders_details.total
-		FROM orders_details
-		INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
-		INNER JOIN orders ON orders.id = orders_details.order_id
-		INNER JOIN products ON products.id = products_inventories.product_id
-		WHERE orders_details.order_id = ?
-	`, id).Scan(&carts)
-
-	discount := (order.TotalDiscount / order.Subtotal) * 100
-	taxes := (order.TotalTaxes / order.Subtotal) * 100
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	var payload = gin.H{
-		"discount": discount,
-		"taxes":    taxes,
-		"shipment": order.TotalShipment,
-		"carts":    carts,
-		"order":    order,
-		"billings": billings,
-		"payment":  payment,
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
 	}
 
-	c.JSON(http.StatusOK, payload)
-}
-
-func OrderCancel(c *gin.Context) {
-
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-	id := c.Param("id")
+	returns, err := services.Returns(config, store).List(claimId(c), id)
+	if err != nil {
+		storeError(c, err, "Failed to load the returns")
+		return
+	}
 
-	var User models.User
-	db.Where("id = ? ", auth["id"]).First(&User)
+	c.JSON(http.StatusOK, gin.H{"returns": returns})
+}
 
-	db.Exec("DELETE FROM orders_details WHERE order_id = ?", id)
-	db.Exec("DELETE FROM orders_carts WHERE order_id = ?", id)
-	db.Exec("DELETE FROM orders_billings WHERE order_id = ?", id)
-	db.Exec("DELETE FROM orders WHERE id = ?", id)
+// cartError answers 422 when a cart line would hold too many items.
+func cartError(c *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrCartQuantity) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	storeError(c, err, message)
+}
 
-	Activity := models.Activity{
-		UserId:      int64(User.Id),
-		Subject:     "Cancel Order",
-		Event:       "Canceling Current Order",
-		Description: "Your has been canceling current order.",
+// reviewError answers for the errors writing a review or voting on one can
+// fail with.
+func reviewError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrReviewNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only customers who received this product can review it."})
+	case errors.Is(err, services.ErrInvalidRating):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOwnReviewVote):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		storeError(c, err, message)
 	}
-	db.Create(&Activity)
+}
 
-	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+// returnError answers for the errors every step of a return can fail with.
+func returnError(c *gin.Context, err error, message string) {
+	var refused *services.ReturnError
+	var invalid *services.InvalidReturnTransitionError
+	switch {
+	case errors.As(err, &refused):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": refused.Reason})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		storeError(c, err, message)
+	}
 }
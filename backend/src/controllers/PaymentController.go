@@ -0,0 +1,86 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package controllers
+
+import (
+	appconfig "backend/src/appconfig"
+	payment "backend/src/payment"
+	repositories "backend/src/repositories"
+	services "backend/src/services"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBytes bounds the body a payment provider may post.
+const maxWebhookBytes = 1 << 20
+
+func OrderPayment(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	id, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	transaction, err := services.Payments(config, store).Start(claimId(c), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotPayable):
+			c.JSON(http.StatusConflict, gin.H{"error": "This order is not awaiting payment."})
+		case errors.Is(err, repositories.ErrNotFound):
+			storeError(c, err, "")
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "The payment could not be started, please try again."})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok", "payment": transaction})
+}
+
+// PaymentWebhook receives the events a payment provider posts about the
+// payments it collects. The signature covers the exact bytes sent, so the
+// body is read raw rather than bound.
+func PaymentWebhook(c *gin.Context) {
+
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Payload too large"})
+		return
+	}
+
+	err = services.Payments(config, store).HandleWebhook(c.Param("provider"), payload, c.GetHeader(payment.SignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, payment.ErrUnknownProvider), errors.Is(err, payment.ErrNotConfigured), errors.Is(err, payment.ErrWebhookUnsupported):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		case errors.Is(err, payment.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		case errors.Is(err, payment.ErrInvalidPayload):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+		default:
+			// Anything else is on our side; a 5xx makes the provider retry.
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process the event"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "ok"})
+}
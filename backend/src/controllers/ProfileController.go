@@ -12,94 +12,60 @@
 package controllers
 
 import (
-	helpers "backend/src/helpers"
-	models "backend/src/models"
+	appconfig "backend/src/appconfig"
+	repositories "backend/src/repositories"
 	schema "backend/src/schema"
 	services "backend/src/services"
-	"crypto/rand"
-	"database/sql"
-	"encoding/hex"
 	"errors"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
-	"github.com/joho/godotenv"
 )
 
 func ProfileActivity(c *gin.Context) {
 
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-	page := 1
-	limit := 10
-	order_by := "id"
-	order_dir := "desc"
-	offset := ((page - 1) * limit)
-	var data []models.Activity
-
-	if len(strings.TrimSpace(c.Query("limit"))) > 0 {
-		limit_, err := strconv.ParseInt(c.Query("limit"), 0, 32)
-		if err == nil {
-			limit = int(limit_)
-		}
-	}
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	if len(strings.TrimSpace(c.Query("page"))) > 0 {
-		page_, err := strconv.ParseInt(c.Query("page"), 0, 32)
-		if err == nil {
-			page = int(page_)
-		}
-	}
-
-	if len(strings.TrimSpace(c.Query("order_by"))) > 0 {
-		order_by = c.Query("order_by")
-	}
-
-	if len(strings.TrimSpace(c.Query("order_dir"))) > 0 {
-		order_dir = c.Query("order_dir")
-	}
-
-	db = db.Select("id, event, subject, description, created_at").Where("user_id = ?", auth["id"])
-
-	if len(strings.TrimSpace(c.Query("search"))) > 0 {
-		db = db.Where("event LIKE ? OR description LIKE ? OR subject LIKE ?", c.Query("search"), c.Query("search"), c.Query("search"))
+	data, err := services.Users(config, store).Activities(claimId(c), listParams(c, 10))
+	if err != nil {
+		storeError(c, err, "Failed to load your activity")
+		return
 	}
 
-	db = db.Limit(limit).Offset(offset).Order(order_by + " " + order_dir).Find(&data)
-
 	c.JSON(http.StatusOK, data)
 }
 
 func ProfileRefresh(c *gin.Context) {
 
 	auth := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
+	user, err := services.Users(config, store).Find(claimId(c))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": services.JWTAuthService().GenerateToken(int(user.Id), user.Email, true)})
+	sid, _ := auth["sid"].(string)
+	c.JSON(http.StatusOK, services.Sessions(config, store).AccessToken(user, sid))
 }
 
 func ProfileDetail(c *gin.Context) {
 
-	auth := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
-	var user models.User
-	if err := db.Where("id = ?", auth["id"]).First(&user).Error; err != nil {
+	user, err := services.Users(config, store).Find(claimId(c))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found"})
 		return
 	}
@@ -126,10 +92,8 @@ func ProfileDetail(c *gin.Context) {
 
 func ProfileUpdate(c *gin.Context) {
 
-	authUser := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-
-	var user models.User
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserProfileSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -142,53 +106,28 @@ func ProfileUpdate(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("email = ? AND id != ?", input.Email, authUser["id"]).First(&user).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email address already exists !!"})
-		return
-	}
-
-	if len(strings.TrimSpace(input.Phone)) > 0 {
-		if err := db.Where("phone = ? AND id != ?", input.Phone, authUser["id"]).First(&user).Error; err == nil {
+	if err := services.Users(config, store).UpdateProfile(claimId(c), input); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailTaken):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email address already exists !!"})
+		case errors.Is(err, services.ErrPhoneTaken):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Phone number already exists !!"})
-			return
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found!"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		}
-	}
-
-	if err := db.Where("id = ?", authUser["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found!"})
 		return
 	}
 
-	var _user models.User
-	_user.Email = input.Email
-	_user.Phone = input.Phone
-	_user.FirstName = sql.NullString{String: input.FirstName, Valid: true}
-	_user.LastName = sql.NullString{String: input.LastName, Valid: true}
-	_user.Gender = sql.NullString{String: input.Gender, Valid: true}
-	_user.Country = sql.NullString{String: input.Country, Valid: true}
-	_user.City = sql.NullString{String: input.City, Valid: true}
-	_user.ZipCode = sql.NullString{String: input.ZipCode, Valid: true}
-	_user.Address = sql.NullString{String: input.Address, Valid: true}
-	db.Model(&user).Updates(_user)
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "Update Current Profile",
-		Event:       "Update Profile",
-		Description: "Edit user profile account",
-	}
-	db.Create(&Activity)
-
 	c.JSON(http.StatusOK, gin.H{"message": "Your profile has been changed!", "status": true})
 
 }
 
 func ProfilePassword(c *gin.Context) {
 
-	authUser := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-
-	var user models.User
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
 
 	var input schema.UserPasswordSchema
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -221,54 +160,34 @@ func ProfilePassword(c *gin.Context) {
 		return
 	}
 
-	if err := db.Where("id = ?", authUser["id"]).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found!"})
-		return
-	}
-
-	decrypt := helpers.Decrypt(user.Password, user.Salt)
-
-	if input.OldPassword != decrypt {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "incorrect current password!"})
+	if err := services.Users(config, store).ChangePassword(claimId(c), input.OldPassword, input.Password); err != nil {
+		switch {
+		case errors.Is(err, services.ErrIncorrectPassword):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "incorrect current password!"})
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found!"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		}
 		return
 	}
 
-	bytes := make([]byte, 32) //generate a random 32 byte key for AES-256
-	if _, err := rand.Read(bytes); err != nil {
-		panic(err.Error())
-	}
-
-	key := hex.EncodeToString(bytes) //encode key in bytes to string and keep as secret, put in a vault
-	encrypted := helpers.Encrypt(input.Password, key)
-
-	var _user models.User
-	_user.Password = encrypted
-	_user.Salt = key
-	db.Model(&user).Updates(_user)
-
-	Activity := models.Activity{
-		UserId:      int64(user.Id),
-		Subject:     "User Profile Password",
-		Event:       "Change Password",
-		Description: "Change new password account",
-	}
-	db.Create(&Activity)
-
 	c.JSON(http.StatusOK, gin.H{"message": "Your password has been changed!"})
 
 }
 
 func ProfileUpload(c *gin.Context) {
 
-	authUser := c.MustGet("claims").(jwt.MapClaims)
-	db := c.MustGet("db").(*gorm.DB)
-	var user models.User
-	if err := db.Where("id = ?", authUser["id"]).First(&user).Error; err != nil {
+	store := c.MustGet("store").(repositories.Store)
+	config := c.MustGet("config").(*appconfig.Config)
+	users := services.Users(config, store)
+
+	user, err := users.Find(claimId(c))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Record not found!"})
 		return
 	}
 
-	godotenv.Load(".env")
 	file, err := c.FormFile("file")
 	currentTime := time.Now()
 	datePath := currentTime.Format("2006-01-02")
@@ -281,7 +200,7 @@ func ProfileUpload(c *gin.Context) {
 		return
 	}
 
-	path := os.Getenv("UPLOAD_PATH")
+	path := config.UploadPath
 	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
 		err := os.Mkdir(path, os.ModePerm)
 		if err != nil {
@@ -312,28 +231,16 @@ func ProfileUpload(c *gin.Context) {
 
 	result := datePath + "/" + newFileName
 
-	if result != "" {
-
-		if user.Image.Valid {
-			image := sql.NullString{user.Image.String, true}
-			e := os.Remove(path + "/" + image.String)
-			if e != nil {
-				log.Fatal(e)
-			}
-		}
-
-		var _user models.User
-		_user.Image = sql.NullString{result, true}
-		db.Model(&user).Updates(_user)
+	previous, err := users.ChangeImage(user.Id, result)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile image"})
+		return
+	}
 
-		Activity := models.Activity{
-			UserId:      int64(user.Id),
-			Subject:     "User Upload Image",
-			Event:       "Upload Profile Image",
-			Description: "Upload new user profile image",
+	if len(previous) > 0 {
+		if err := os.Remove(path + "/" + previous); err != nil {
+			log.Println(err)
 		}
-		db.Create(&Activity)
-
 	}
 
 	// File saved successfully. Return proper result
@@ -12,57 +12,104 @@
 package data
 
 import (
-	_db "backend/src/config"
-	"backend/src/helpers"
+	appconfig "backend/src/appconfig"
 	"backend/src/models"
-	"crypto/rand"
+	"backend/src/money"
+	"backend/src/repositories"
+	"backend/src/services"
 	"database/sql"
-	"encoding/hex"
 	"fmt"
 	math "math/rand"
+	"sort"
 	"time"
 
 	"github.com/Pallinder/go-randomdata"
 	"github.com/bxcodec/faker/v4"
-	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
 )
 
-func RunSeed() {
-	CreateUser()
-	CreateSetting()
-	CreateCategories()
-	CreateBrands()
-	CreateColours()
-	CreatePayment()
-	CreateSize()
-	CreateProduct()
+type SeedOptions struct {
+	Users      int
+	Products   int
+	RandomSeed int64
 }
 
-func CreateSetting() {
+func DefaultSeedOptions() SeedOptions {
+	return SeedOptions{
+		Users:      10,
+		Products:   9,
+		RandomSeed: time.Now().UnixNano(),
+	}
+}
+
+var (
+	config  *appconfig.Config
+	options = DefaultSeedOptions()
+	random  = math.New(math.NewSource(options.RandomSeed))
+)
+
+// RunSeed fills empty tables with fixtures. Every random choice is drawn
+// from opts.RandomSeed, so the same seed against an empty database
+// produces the same data.
+func RunSeed(db *gorm.DB, cfg *appconfig.Config, opts SeedOptions) {
+	config = cfg
+	options = opts
+	random = math.New(math.NewSource(opts.RandomSeed))
+	randomdata.CustomRand(math.New(math.NewSource(opts.RandomSeed)))
+	faker.SetRandomSource(math.NewSource(opts.RandomSeed))
+
+	CreateRoles(db)
+	CreateUser(db)
+	CreateSetting(db)
+	CreatePricing(db)
+	CreateCoupons(db)
+	CreateCategories(db)
+	CreateBrands(db)
+	CreateColours(db)
+	CreatePayment(db)
+	CreateSize(db)
+	CreateProduct(db)
+}
+
+func CreateRoles(db *gorm.DB) {
+
+	for _, roleName := range sortedKeys(services.RolePermissions) {
+
+		permissionNames := services.RolePermissions[roleName]
+
+		var permissions []models.Permission
+		for _, permissionName := range permissionNames {
+			var permission models.Permission
+			db.Where(models.Permission{Name: permissionName}).FirstOrCreate(&permission)
+			permissions = append(permissions, permission)
+		}
+
+		var role models.Role
+		db.Where(models.Role{Name: roleName}).Attrs(models.Role{Status: 1}).FirstOrCreate(&role)
+		db.Model(&role).Association("Permissions").Replace(permissions)
+	}
+
+}
+
+func CreateSetting(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Setting{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
 
 		settings := map[string]string{
-			"about_section":   "Lorem ipsum dolor sit amet, consectetur adipisicing elit, sed do eiusmod tempor incididunt ut.",
-			"com_location":    "West Java, Indonesia",
-			"com_phone":       "+62-898-921-8470",
-			"com_email":       "sandy.andryanto.official@gmail.com",
-			"com_currency":    "USD",
-			"installed":       "1",
-			"discount_active": "1",
-			"discount_value":  "5",
-			"discount_start":  time.Now().Format("2006-01-02 15:04:05"),
-			"discount_end":    time.Now().Add(7 * 24 * time.Hour).Format("2006-01-02 15:04:05"),
-			"taxes_value":     "10",
-			"total_shipment":  "50",
+			"about_section": "Lorem ipsum dolor sit amet, consectetur adipisicing elit, sed do eiusmod tempor incididunt ut.",
+			"com_location":  "West Java, Indonesia",
+			"com_phone":     "+62-898-921-8470",
+			"com_email":     "sandy.andryanto.official@gmail.com",
+			"com_currency":  "USD",
+			"installed":     "1",
 		}
 
-		for key, value := range settings {
+		for _, key := range sortedKeys(settings) {
+			value := settings[key]
 			setting := models.Setting{
 				KeyName:  key,
 				KeyValue: value,
@@ -75,11 +122,64 @@ func CreateSetting() {
 
 }
 
-func CreateCategories() {
+func CreatePricing(db *gorm.DB) {
+
+	var totalRow int64
+
+	db.Model(&models.PriceRule{}).Where("id <> 0").Count(&totalRow)
+
+	if totalRow == 0 {
+
+		start := time.Now()
+		end := start.Add(7 * 24 * time.Hour)
+
+		rules := []models.PriceRule{
+			{Name: "Store discount", Kind: models.PriceRuleDiscount, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationPercent, Value: money.FromInt(5), StartsAt: &start, EndsAt: &end, Status: 1},
+			{Name: "Flat shipment", Kind: models.PriceRuleShipment, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationFixed, Value: money.FromInt(50), Status: 1},
+		}
+
+		for _, rule := range rules {
+			db.Create(&rule)
+		}
+	}
+
+	db.Model(&models.TaxRate{}).Where("id <> 0").Count(&totalRow)
+
+	if totalRow == 0 {
+		tax := models.TaxRate{
+			Name:   "Default tax",
+			Rate:   money.MustParseRate("10"),
+			Status: 1,
+		}
+		db.Create(&tax)
+	}
+
+}
+
+func CreateCoupons(db *gorm.DB) {
+
+	var totalRow int64
+
+	db.Model(&models.Coupon{}).Where("id <> 0").Count(&totalRow)
+
+	if totalRow == 0 {
+		coupon := models.Coupon{
+			Code:              "WELCOME10",
+			Name:              "10% off your first order",
+			Calculation:       models.PriceCalculationPercent,
+			Value:             money.FromInt(10),
+			UsageLimitPerUser: 1,
+			Status:            1,
+		}
+		db.Create(&coupon)
+	}
+
+}
+
+func CreateCategories(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Category{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
@@ -93,7 +193,9 @@ func CreateCategories() {
 		}
 
 		i := 1
-		for name, image := range items {
+		for _, name := range sortedKeys(items) {
+
+			image := items[name]
 
 			var displayed int16
 
@@ -117,11 +219,10 @@ func CreateCategories() {
 
 }
 
-func CreateBrands() {
+func CreateBrands(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Brand{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
@@ -140,11 +241,10 @@ func CreateBrands() {
 
 }
 
-func CreateColours() {
+func CreateColours(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Colour{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
@@ -159,7 +259,8 @@ func CreateColours() {
 			"#AAA":    "Light Gray",
 		}
 
-		for name, code := range colors {
+		for _, name := range sortedKeys(colors) {
+			code := colors[name]
 			colour := models.Colour{
 				Code:        code,
 				Name:        name,
@@ -173,21 +274,30 @@ func CreateColours() {
 
 }
 
-func CreatePayment() {
+func CreatePayment(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Payment{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
 
-		items := []string{"Direct Bank Transfer", "Cheque Payment", "Paypal System"}
+		// Transfers and cheques are confirmed by staff; the online method
+		// goes through the card gateway.
+		items := []struct {
+			Name     string
+			Provider string
+		}{
+			{"Direct Bank Transfer", "manual"},
+			{"Cheque Payment", "manual"},
+			{"Paypal System", "gateway"},
+		}
 
 		for _, item := range items {
 			payment := models.Payment{
-				Name:        item,
+				Name:        item.Name,
 				Description: randomdata.Paragraph(),
+				Provider:    item.Provider,
 				Status:      1,
 			}
 			db.Create(&payment)
@@ -196,11 +306,10 @@ func CreatePayment() {
 
 }
 
-func CreateSize() {
+func CreateSize(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Size{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
@@ -224,18 +333,17 @@ func CreateSize() {
 
 }
 
-func CreateProduct() {
+func CreateProduct(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.Product{}).Where("id <> 0").Count(&totalRow)
 
 	var sizes []models.Size
-	db.Find(&sizes)
+	db.Order("id asc").Find(&sizes)
 
 	var colours []models.Colour
-	db.Find(&colours)
+	db.Order("id asc").Find(&colours)
 
 	if totalRow == 0 {
 
@@ -251,28 +359,47 @@ func CreateProduct() {
 			"https://5an9y4lf0n50.github.io/demo-images/demo-commerce/product09.png",
 		}
 
-		for i := 1; i <= 9; i++ {
+		var allCategories []models.Category
+		db.Order("id asc").Find(&allCategories)
+
+		var allUsers []models.User
+		db.Order("id asc").Find(&allUsers)
+
+		var brands []models.Brand
+		db.Order("id asc").Find(&brands)
 
-			math.Seed(time.Now().UnixNano())
-			image := images[math.Intn(len(images))]
+		for i := 1; i <= options.Products; i++ {
+
+			image := images[random.Intn(len(images))]
 
 			var categories []models.Category
-			db.Order("RAND()").Limit(3).Find(&categories)
+			for _, k := range random.Perm(len(allCategories)) {
+				if len(categories) == 3 {
+					break
+				}
+				categories = append(categories, allCategories[k])
+			}
 
 			var reviewers []models.User
-			db.Order("RAND()").Limit(5).Find(&reviewers)
+			for _, k := range random.Perm(len(allUsers)) {
+				if len(reviewers) == 5 {
+					break
+				}
+				reviewers = append(reviewers, allUsers[k])
+			}
 
 			var brand models.Brand
-			db.Order("RAND()").Limit(1).First(&brand)
+			if len(brands) > 0 {
+				brand = brands[random.Intn(len(brands))]
+			}
 
 			product := models.Product{
 				Image:       sql.NullString{String: image, Valid: true},
 				BrandId:     brand.Id,
 				Sku:         fmt.Sprintf("P%03d", i),
 				Name:        fmt.Sprintf("Product %03d", i),
-				Price:       float64(helpers.RandomInt(100, 999)),
-				TotalOrder:  uint16(helpers.RandomInt(100, 1000)),
-				TotalRating: uint16(helpers.RandomInt(100, 1000)),
+				Price:       money.FromInt(int64(randomInt(100, 999))),
+				TotalOrder:  uint16(randomInt(100, 1000)),
 				Description: randomdata.Paragraph(),
 				Details:     randomdata.Paragraph(),
 				PublishedAt: func(t time.Time) *time.Time { return &t }(time.Now()),
@@ -281,20 +408,23 @@ func CreateProduct() {
 			}
 			db.Create(&product)
 
+			var stars [5]uint32
 			for _, reviewer := range reviewers {
 				rr := models.ProductReview{
 					ProductId: product.Id,
 					UserId:    reviewer.Id,
-					Rating:    uint16(helpers.RandomInt(0, 100)),
+					Rating:    uint16(randomInt(models.ReviewRatingMin, models.ReviewRatingMax)),
 					Review:    randomdata.Paragraph(),
-					Status:    1,
+					Status:    models.ReviewStatusApproved,
 				}
 				db.Create(&rr)
+				stars[rr.Rating-1]++
 			}
+			product.SetStars(stars)
+			repositories.NewStore(db).Catalog().SaveRatings(product)
 
 			for j := 1; j <= 3; j++ {
-				math.Seed(time.Now().UnixNano())
-				imageOther := images[math.Intn(len(images))]
+				imageOther := images[random.Intn(len(images))]
 				pi := models.ProductImage{
 					ProductId: product.Id,
 					Path:      imageOther,
@@ -310,7 +440,7 @@ func CreateProduct() {
 						ProductId: product.Id,
 						SizeId:    size.Id,
 						ColourId:  colour.Id,
-						Stock:     uint16(helpers.RandomInt(1, 50)),
+						Stock:     uint16(randomInt(1, 50)),
 						Status:    1,
 					}
 					db.Create(&inv)
@@ -322,27 +452,22 @@ func CreateProduct() {
 
 }
 
-func CreateUser() {
+func CreateUser(db *gorm.DB) {
 
 	var totalRow int64
 
-	db := _db.SetupDB()
 	db.Model(&models.User{}).Where("id <> 0").Count(&totalRow)
 
 	if totalRow == 0 {
-		for i := 1; i <= 10; i++ {
 
-			bytes := make([]byte, 32)
-			if _, err := rand.Read(bytes); err != nil {
-				panic(err.Error())
-			}
+		hashed, err := services.PasswordHashService(config).Hash("Qwerty123!")
+		if err != nil {
+			panic(err.Error())
+		}
 
-			key := hex.EncodeToString(bytes)
-			encrypted := helpers.Encrypt("Qwerty123!", key)
+		for i := 1; i <= options.Users; i++ {
 
-			min := 1
-			max := 2
-			gender := math.Intn(max-min+1) + min
+			gender := randomInt(1, 2)
 			firstName := ""
 			genderChar := ""
 
@@ -363,23 +488,28 @@ func CreateUser() {
 				Country:   sql.NullString{String: randomdata.Country(randomdata.FullCountry), Valid: true},
 				City:      sql.NullString{String: randomdata.City(), Valid: true},
 				Address:   sql.NullString{String: randomdata.Address(), Valid: true},
-				Salt:      key,
-				Password:  encrypted,
+				Password:  hashed,
 				Status:    1,
 			}
 			db.Create(&user)
+			services.AccessControl(repositories.NewStore(db)).AssignRoles(user.Id, models.RoleCustomer)
+		}
+	}
 
-			token := uuid.New().String()
-			auth := models.Authentication{
-				UserId:     int64(user.Id),
-				AuthType:   "email-confirm",
-				Credential: user.Email,
-				Token:      token,
-				Status:     1,
-			}
-			db.Create(&auth)
+}
 
-		}
+func randomInt(min, max int) int {
+	if min > max {
+		min, max = max, min
 	}
+	return random.Intn(max-min+1) + min
+}
 
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
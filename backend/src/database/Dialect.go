@@ -0,0 +1,26 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package database
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// ForUpdate locks the rows read by the next query on tx until tx ends.
+// SQLite has no row locks and rejects FOR UPDATE; it already serialises
+// writers on the whole database, so the query is left unchanged there.
+func ForUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialect().GetName() == "sqlite3" {
+		return tx
+	}
+	return tx.Set("gorm:query_option", "FOR UPDATE")
+}
@@ -0,0 +1,294 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+const migrationsTable = "schema_migrations"
+
+// Migration is one numbered schema change, read from a pair of files named
+// NNNN_description.up.sql and NNNN_description.down.sql under
+// migrations/<dialect>.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   uint64     `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+type appliedMigration struct {
+	Version   uint64
+	Name      string
+	AppliedAt time.Time
+}
+
+// migrator
+type MigrationService interface {
+	Up(steps int) ([]Migration, error)
+	Down(steps int) ([]Migration, error)
+	Status() ([]MigrationStatus, error)
+}
+
+type migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func Migrator(db *gorm.DB, dialect string) (MigrationService, error) {
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations returns the embedded migrations for dialect ordered by version.
+func LoadMigrations(dialect string) ([]Migration, error) {
+
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("migration %s: file name must look like 0001_description.%s.sql", name, direction)
+		}
+		version, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version %q", name, parts[0])
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		} else if migration.Name != parts[1] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, parts[1])
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *migrator) ensureTable() error {
+	return m.db.Exec("CREATE TABLE IF NOT EXISTS " + migrationsTable + " (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)").Error
+}
+
+func (m *migrator) applied() (map[uint64]appliedMigration, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	var rows []appliedMigration
+	if err := m.db.Table(migrationsTable).Order("version asc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := map[uint64]appliedMigration{}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Up applies pending migrations in version order. steps <= 0 applies all of them.
+func (m *migrator) Up(steps int) ([]Migration, error) {
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if err := m.run(migration, migration.Up, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO "+migrationsTable+" (version, name, applied_at) VALUES (?, ?, ?)", migration.Version, migration.Name, time.Now()).Error
+		}); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts applied migrations, newest first. steps <= 0 reverts one.
+func (m *migrator) Down(steps int) ([]Migration, error) {
+
+	if steps <= 0 {
+		steps = 1
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %04d_%s cannot be reverted: it has no down file", migration.Version, migration.Name)
+		}
+		if err := m.run(migration, migration.Down, func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM "+migrationsTable+" WHERE version = ?", migration.Version).Error
+		}); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *migrator) Status() ([]MigrationStatus, error) {
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		row := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			appliedAt := a.AppliedAt
+			row.AppliedAt = &appliedAt
+		}
+		status = append(status, row)
+	}
+
+	return status, nil
+}
+
+// run executes every statement of script and then record inside one
+// transaction. Databases with transactional DDL roll the whole migration
+// back on failure; MySQL commits each DDL statement implicitly, so a
+// failed migration there may need to be cleaned up by hand.
+func (m *migrator) run(migration Migration, script string, record func(tx *gorm.DB) error) error {
+
+	tx := m.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+	}
+
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// splitStatements breaks a script into statements on semicolons that end a
+// line, dropping "--" comment lines. Migrations must not put a semicolon at
+// the end of a line inside a string literal.
+func splitStatements(script string) []string {
+
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(strings.ReplaceAll(script, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, statement)
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
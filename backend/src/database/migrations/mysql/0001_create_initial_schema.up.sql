@@ -0,0 +1,358 @@
+-- Tables that used to be created by AutoMigrate on boot, including the
+-- many2many join tables gorm only created implicitly.
+
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `email` VARCHAR(191) NOT NULL,
+  `phone` VARCHAR(191) NULL DEFAULT NULL,
+  `password` VARCHAR(255) NOT NULL,
+  `salt` VARCHAR(255) NULL DEFAULT NULL,
+  `image` VARCHAR(191) NULL DEFAULT NULL,
+  `first_name` VARCHAR(191) NULL DEFAULT NULL,
+  `last_name` VARCHAR(191) NULL DEFAULT NULL,
+  `gender` VARCHAR(2) NULL DEFAULT NULL,
+  `country` VARCHAR(191) NULL DEFAULT NULL,
+  `city` VARCHAR(191) NULL DEFAULT NULL,
+  `zip_code` VARCHAR(64) NULL DEFAULT NULL,
+  `address` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_users_email` (`email`),
+  KEY `idx_users_phone` (`phone`),
+  KEY `idx_users_password` (`password`),
+  KEY `idx_users_salt` (`salt`),
+  KEY `idx_users_image` (`image`),
+  KEY `idx_users_first_name` (`first_name`),
+  KEY `idx_users_last_name` (`last_name`),
+  KEY `idx_users_gender` (`gender`),
+  KEY `idx_users_country` (`country`),
+  KEY `idx_users_city` (`city`),
+  KEY `idx_users_zip_code` (`zip_code`),
+  KEY `idx_users_status` (`status`),
+  KEY `idx_users_created_at` (`created_at`),
+  KEY `idx_users_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `authentications` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `auth_type` VARCHAR(100) NOT NULL,
+  `credential` VARCHAR(180) NOT NULL,
+  `token` VARCHAR(100) NOT NULL,
+  `family_id` VARCHAR(100) NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `expired_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_authentications_user_id` (`user_id`),
+  KEY `idx_authentications_auth_type` (`auth_type`),
+  KEY `idx_authentications_credential` (`credential`),
+  KEY `idx_authentications_token` (`token`),
+  KEY `idx_authentications_family_id` (`family_id`),
+  KEY `idx_authentications_status` (`status`),
+  KEY `idx_authentications_expired_at` (`expired_at`),
+  KEY `idx_authentications_created_at` (`created_at`),
+  KEY `idx_authentications_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `activities` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `subject` VARCHAR(255) NOT NULL,
+  `event` VARCHAR(255) NOT NULL,
+  `description` TEXT NOT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 1,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_activities_user_id` (`user_id`),
+  KEY `idx_activities_subject` (`subject`),
+  KEY `idx_activities_event` (`event`),
+  KEY `idx_activities_status` (`status`),
+  KEY `idx_activities_created_at` (`created_at`),
+  KEY `idx_activities_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `brands` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `image` VARCHAR(191) NULL DEFAULT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_brands_image` (`image`),
+  KEY `idx_brands_name` (`name`),
+  KEY `idx_brands_status` (`status`),
+  KEY `idx_brands_created_at` (`created_at`),
+  KEY `idx_brands_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `categories` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `image` VARCHAR(191) NULL DEFAULT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `displayed` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_categories_image` (`image`),
+  KEY `idx_categories_name` (`name`),
+  KEY `idx_categories_displayed` (`displayed`),
+  KEY `idx_categories_status` (`status`),
+  KEY `idx_categories_created_at` (`created_at`),
+  KEY `idx_categories_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `colours` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(100) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_colours_code` (`code`),
+  KEY `idx_colours_name` (`name`),
+  KEY `idx_colours_status` (`status`),
+  KEY `idx_colours_created_at` (`created_at`),
+  KEY `idx_colours_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `sizes` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sizes_name` (`name`),
+  KEY `idx_sizes_status` (`status`),
+  KEY `idx_sizes_created_at` (`created_at`),
+  KEY `idx_sizes_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `payments` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `image` VARCHAR(191) NULL DEFAULT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `displayed` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_payments_image` (`image`),
+  KEY `idx_payments_name` (`name`),
+  KEY `idx_payments_displayed` (`displayed`),
+  KEY `idx_payments_status` (`status`),
+  KEY `idx_payments_created_at` (`created_at`),
+  KEY `idx_payments_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `brand_id` BIGINT UNSIGNED NOT NULL,
+  `image` VARCHAR(191) NULL DEFAULT NULL,
+  `sku` VARCHAR(100) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `price` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `total_order` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `total_rating` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `description` TEXT NULL DEFAULT NULL,
+  `details` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `published_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_products_brand_id` (`brand_id`),
+  KEY `idx_products_image` (`image`),
+  KEY `idx_products_sku` (`sku`),
+  KEY `idx_products_name` (`name`),
+  KEY `idx_products_price` (`price`),
+  KEY `idx_products_total_order` (`total_order`),
+  KEY `idx_products_total_rating` (`total_rating`),
+  KEY `idx_products_status` (`status`),
+  KEY `idx_products_published_at` (`published_at`),
+  KEY `idx_products_created_at` (`created_at`),
+  KEY `idx_products_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products_images` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  `path` VARCHAR(255) NOT NULL,
+  `sort` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_products_images_product_id` (`product_id`),
+  KEY `idx_products_images_path` (`path`),
+  KEY `idx_products_images_sort` (`sort`),
+  KEY `idx_products_images_status` (`status`),
+  KEY `idx_products_images_created_at` (`created_at`),
+  KEY `idx_products_images_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products_inventories` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  `size_id` BIGINT UNSIGNED NOT NULL,
+  `colour_id` BIGINT UNSIGNED NOT NULL,
+  `stock` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_products_inventories_product_id` (`product_id`),
+  KEY `idx_products_inventories_size_id` (`size_id`),
+  KEY `idx_products_inventories_colour_id` (`colour_id`),
+  KEY `idx_products_inventories_stock` (`stock`),
+  KEY `idx_products_inventories_status` (`status`),
+  KEY `idx_products_inventories_created_at` (`created_at`),
+  KEY `idx_products_inventories_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products_reviews` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  `rating` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `review` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_products_reviews_product_id` (`product_id`),
+  KEY `idx_products_reviews_user_id` (`user_id`),
+  KEY `idx_products_reviews_rating` (`rating`),
+  KEY `idx_products_reviews_status` (`status`),
+  KEY `idx_products_reviews_created_at` (`created_at`),
+  KEY `idx_products_reviews_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  `payment_id` BIGINT UNSIGNED NOT NULL,
+  `invoice_number` VARCHAR(255) NOT NULL,
+  `total_item` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `subtotal` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `total_discount` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `total_taxes` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `total_shipment` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `total_paid` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_user_id` (`user_id`),
+  KEY `idx_orders_payment_id` (`payment_id`),
+  KEY `idx_orders_invoice_number` (`invoice_number`),
+  KEY `idx_orders_total_item` (`total_item`),
+  KEY `idx_orders_subtotal` (`subtotal`),
+  KEY `idx_orders_total_discount` (`total_discount`),
+  KEY `idx_orders_total_taxes` (`total_taxes`),
+  KEY `idx_orders_total_shipment` (`total_shipment`),
+  KEY `idx_orders_total_paid` (`total_paid`),
+  KEY `idx_orders_status` (`status`),
+  KEY `idx_orders_created_at` (`created_at`),
+  KEY `idx_orders_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders_billings` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `description` TEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_billings_order_id` (`order_id`),
+  KEY `idx_orders_billings_name` (`name`),
+  KEY `idx_orders_billings_status` (`status`),
+  KEY `idx_orders_billings_created_at` (`created_at`),
+  KEY `idx_orders_billings_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders_details` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `inventory_id` BIGINT UNSIGNED NOT NULL,
+  `price` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `qty` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `total` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_details_order_id` (`order_id`),
+  KEY `idx_orders_details_inventory_id` (`inventory_id`),
+  KEY `idx_orders_details_price` (`price`),
+  KEY `idx_orders_details_qty` (`qty`),
+  KEY `idx_orders_details_total` (`total`),
+  KEY `idx_orders_details_status` (`status`),
+  KEY `idx_orders_details_created_at` (`created_at`),
+  KEY `idx_orders_details_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `newsLetters` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `ip_address` VARCHAR(45) NOT NULL,
+  `email` VARCHAR(180) NOT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_newsLetters_ip_address` (`ip_address`),
+  KEY `idx_newsLetters_email` (`email`),
+  KEY `idx_newsLetters_status` (`status`),
+  KEY `idx_newsLetters_created_at` (`created_at`),
+  KEY `idx_newsLetters_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `settings` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `key_name` VARCHAR(255) NOT NULL,
+  `key_value` LONGTEXT NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_settings_key_name` (`key_name`),
+  KEY `idx_settings_status` (`status`),
+  KEY `idx_settings_created_at` (`created_at`),
+  KEY `idx_settings_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products_categories` (
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  `category_id` BIGINT UNSIGNED NOT NULL,
+  PRIMARY KEY (`product_id`, `category_id`),
+  KEY `idx_products_categories_category_id` (`category_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `products_wishlists` (
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  PRIMARY KEY (`product_id`, `user_id`),
+  KEY `idx_products_wishlists_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders_carts` (
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `product_id` BIGINT UNSIGNED NOT NULL,
+  PRIMARY KEY (`order_id`, `product_id`),
+  KEY `idx_orders_carts_product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
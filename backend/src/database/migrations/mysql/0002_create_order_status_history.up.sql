@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS `order_status_history` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `from_status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `to_status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `actor_id` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `actor_type` VARCHAR(50) NOT NULL,
+  `reason` TEXT NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_order_status_history_order_id` (`order_id`),
+  KEY `idx_order_status_history_from_status` (`from_status`),
+  KEY `idx_order_status_history_to_status` (`to_status`),
+  KEY `idx_order_status_history_actor_id` (`actor_id`),
+  KEY `idx_order_status_history_actor_type` (`actor_type`),
+  KEY `idx_order_status_history_created_at` (`created_at`),
+  KEY `idx_order_status_history_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
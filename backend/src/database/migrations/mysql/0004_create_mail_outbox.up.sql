@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS `mail_outbox` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `recipient` VARCHAR(191) NOT NULL,
+  `template` VARCHAR(100) NOT NULL,
+  `payload` TEXT NOT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `attempts` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `last_error` TEXT NULL DEFAULT NULL,
+  `next_attempt_at` DATETIME NULL DEFAULT NULL,
+  `sent_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_mail_outbox_recipient` (`recipient`),
+  KEY `idx_mail_outbox_template` (`template`),
+  KEY `idx_mail_outbox_status` (`status`),
+  KEY `idx_mail_outbox_next_attempt_at` (`next_attempt_at`),
+  KEY `idx_mail_outbox_sent_at` (`sent_at`),
+  KEY `idx_mail_outbox_created_at` (`created_at`),
+  KEY `idx_mail_outbox_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS `price_rules` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `kind` VARCHAR(20) NOT NULL,
+  `scope` VARCHAR(20) NOT NULL DEFAULT 'order',
+  `target_id` BIGINT UNSIGNED NULL DEFAULT NULL,
+  `calculation` VARCHAR(20) NOT NULL DEFAULT 'percent',
+  `value` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `min_subtotal` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `priority` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `starts_at` DATETIME NULL DEFAULT NULL,
+  `ends_at` DATETIME NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_price_rules_kind` (`kind`),
+  KEY `idx_price_rules_scope_target_id` (`scope`, `target_id`),
+  KEY `idx_price_rules_priority` (`priority`),
+  KEY `idx_price_rules_starts_at` (`starts_at`),
+  KEY `idx_price_rules_ends_at` (`ends_at`),
+  KEY `idx_price_rules_status` (`status`),
+  KEY `idx_price_rules_created_at` (`created_at`),
+  KEY `idx_price_rules_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `tax_rates` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `country` VARCHAR(191) NOT NULL DEFAULT '',
+  `zip_prefix` VARCHAR(64) NOT NULL DEFAULT '',
+  `rate` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_tax_rates_country` (`country`),
+  KEY `idx_tax_rates_zip_prefix` (`zip_prefix`),
+  KEY `idx_tax_rates_status` (`status`),
+  KEY `idx_tax_rates_created_at` (`created_at`),
+  KEY `idx_tax_rates_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Carry the old pricing settings over. The settings rows are left in place
+-- so a rollback finds them again.
+INSERT INTO `price_rules` (`name`, `kind`, `scope`, `calculation`, `value`, `priority`, `starts_at`, `ends_at`, `status`)
+SELECT 'Store discount', 'discount', 'order', 'percent', CAST(`key_value` AS DECIMAL(18,4)), 0,
+  CAST(NULLIF((SELECT `key_value` FROM `settings` WHERE `key_name` = 'discount_start' ORDER BY `id` DESC LIMIT 1), '') AS DATETIME),
+  CAST(NULLIF((SELECT `key_value` FROM `settings` WHERE `key_name` = 'discount_end' ORDER BY `id` DESC LIMIT 1), '') AS DATETIME),
+  COALESCE((SELECT CAST(`key_value` AS UNSIGNED) FROM `settings` WHERE `key_name` = 'discount_active' ORDER BY `id` DESC LIMIT 1), 1)
+FROM `settings` WHERE `key_name` = 'discount_value' ORDER BY `id` DESC LIMIT 1;
+
+INSERT INTO `price_rules` (`name`, `kind`, `scope`, `calculation`, `value`, `priority`, `starts_at`, `ends_at`, `status`)
+SELECT 'Flat shipment', 'shipment', 'order', 'fixed', CAST(`key_value` AS DECIMAL(18,4)), 0, NULL, NULL, 1
+FROM `settings` WHERE `key_name` = 'total_shipment' ORDER BY `id` DESC LIMIT 1;
+
+INSERT INTO `tax_rates` (`name`, `country`, `zip_prefix`, `rate`, `status`)
+SELECT 'Default tax', '', '', CAST(`key_value` AS DECIMAL(18,4)), 1
+FROM `settings` WHERE `key_name` = 'taxes_value' ORDER BY `id` DESC LIMIT 1;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS `coupons` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `code` VARCHAR(64) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `calculation` VARCHAR(20) NOT NULL DEFAULT 'percent',
+  `value` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `min_subtotal` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `usage_limit` INT UNSIGNED NOT NULL DEFAULT 0,
+  `usage_limit_per_user` INT UNSIGNED NOT NULL DEFAULT 0,
+  `starts_at` DATETIME NULL DEFAULT NULL,
+  `ends_at` DATETIME NULL DEFAULT NULL,
+  `status` TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_coupons_code` (`code`),
+  KEY `idx_coupons_starts_at` (`starts_at`),
+  KEY `idx_coupons_ends_at` (`ends_at`),
+  KEY `idx_coupons_status` (`status`),
+  KEY `idx_coupons_created_at` (`created_at`),
+  KEY `idx_coupons_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `coupons_targets` (
+  `coupon_id` BIGINT UNSIGNED NOT NULL,
+  `scope` VARCHAR(20) NOT NULL,
+  `target_id` BIGINT UNSIGNED NOT NULL,
+  PRIMARY KEY (`coupon_id`, `scope`, `target_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders_coupons` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `coupon_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  `code` VARCHAR(64) NOT NULL,
+  `amount` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_orders_coupons_order_id` (`order_id`),
+  KEY `idx_orders_coupons_coupon_id_user_id` (`coupon_id`, `user_id`),
+  KEY `idx_orders_coupons_created_at` (`created_at`),
+  KEY `idx_orders_coupons_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TABLE `orders` DROP INDEX `uix_orders_cart_token`;
+ALTER TABLE `orders` DROP COLUMN `cart_token`;
@@ -0,0 +1,2 @@
+ALTER TABLE `orders` ADD COLUMN `cart_token` CHAR(64) NULL DEFAULT NULL AFTER `invoice_number`;
+ALTER TABLE `orders` ADD UNIQUE KEY `uix_orders_cart_token` (`cart_token`);
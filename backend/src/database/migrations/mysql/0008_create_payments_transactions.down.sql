@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `payments_events`;
+DROP TABLE IF EXISTS `payments_transactions`;
+ALTER TABLE `payments` DROP COLUMN `provider`;
@@ -0,0 +1,34 @@
+ALTER TABLE `payments` ADD COLUMN `provider` VARCHAR(50) NOT NULL DEFAULT 'manual' AFTER `description`;
+
+CREATE TABLE IF NOT EXISTS `payments_transactions` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `payment_id` BIGINT UNSIGNED NOT NULL,
+  `provider` VARCHAR(50) NOT NULL,
+  `reference` VARCHAR(191) NOT NULL,
+  `amount` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `currency` CHAR(3) NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+  `redirect_url` VARCHAR(2048) NULL DEFAULT NULL,
+  `error` VARCHAR(255) NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_payments_transactions_provider_reference` (`provider`, `reference`),
+  KEY `idx_payments_transactions_order_id` (`order_id`),
+  KEY `idx_payments_transactions_status` (`status`),
+  KEY `idx_payments_transactions_created_at` (`created_at`),
+  KEY `idx_payments_transactions_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `payments_events` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `provider` VARCHAR(50) NOT NULL,
+  `event_id` VARCHAR(191) NOT NULL,
+  `type` VARCHAR(100) NOT NULL,
+  `reference` VARCHAR(191) NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_payments_events_provider_event_id` (`provider`, `event_id`),
+  KEY `idx_payments_events_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,35 @@
+ALTER TABLE `orders` ADD COLUMN `total_refunded` DECIMAL(18,4) NOT NULL DEFAULT 0 AFTER `total_paid`;
+ALTER TABLE `payments_transactions` ADD COLUMN `refunded` DECIMAL(18,4) NOT NULL DEFAULT 0 AFTER `amount`;
+
+CREATE TABLE IF NOT EXISTS `orders_returns` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'requested',
+  `reason` TEXT NOT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `refund_amount` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  `approved_at` DATETIME NULL DEFAULT NULL,
+  `received_at` DATETIME NULL DEFAULT NULL,
+  `refunded_at` DATETIME NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_returns_order_id` (`order_id`),
+  KEY `idx_orders_returns_user_id` (`user_id`),
+  KEY `idx_orders_returns_status` (`status`),
+  KEY `idx_orders_returns_created_at` (`created_at`),
+  KEY `idx_orders_returns_updated_at` (`updated_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders_returns_lines` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `return_id` BIGINT UNSIGNED NOT NULL,
+  `detail_id` BIGINT UNSIGNED NOT NULL,
+  `inventory_id` BIGINT UNSIGNED NOT NULL,
+  `qty` SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  `price` DECIMAL(18,4) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_returns_lines_return_id` (`return_id`),
+  KEY `idx_orders_returns_lines_detail_id` (`detail_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
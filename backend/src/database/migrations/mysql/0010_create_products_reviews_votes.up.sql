@@ -0,0 +1,51 @@
+-- Older versions let a customer review a product more than once and stored
+-- ratings out of 100. Only the newest review per customer and product is
+-- kept and ratings are scaled to 1-5 below, so the rows that change and the
+-- product totals computed from them are copied aside first for the down
+-- migration to put back.
+CREATE TABLE IF NOT EXISTS `products_reviews_0010_backup` AS
+SELECT * FROM `products_reviews` WHERE `rating` > 5 OR `rating` < 1 OR `id` NOT IN (
+  SELECT `id` FROM (SELECT MAX(`id`) AS `id` FROM `products_reviews` GROUP BY `product_id`, `user_id`) AS `newest`
+);
+CREATE TABLE IF NOT EXISTS `products_0010_backup` AS SELECT `id`, `total_rating` FROM `products`;
+
+ALTER TABLE `products` ADD COLUMN `rating_average` DECIMAL(3,2) NOT NULL DEFAULT 0 AFTER `total_rating`;
+ALTER TABLE `products` ADD COLUMN `rating_count` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_average`;
+ALTER TABLE `products` ADD COLUMN `rating_1` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_count`;
+ALTER TABLE `products` ADD COLUMN `rating_2` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_1`;
+ALTER TABLE `products` ADD COLUMN `rating_3` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_2`;
+ALTER TABLE `products` ADD COLUMN `rating_4` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_3`;
+ALTER TABLE `products` ADD COLUMN `rating_5` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `rating_4`;
+CREATE INDEX `idx_products_rating_average` ON `products` (`rating_average`);
+
+ALTER TABLE `products_reviews` ADD COLUMN `verified` TINYINT(1) NOT NULL DEFAULT 0 AFTER `review`;
+ALTER TABLE `products_reviews` ADD COLUMN `helpful_count` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `verified`;
+ALTER TABLE `products_reviews` ADD COLUMN `moderation_note` TEXT NULL DEFAULT NULL AFTER `status`;
+ALTER TABLE `products_reviews` ADD COLUMN `moderated_at` DATETIME NULL DEFAULT NULL AFTER `moderation_note`;
+
+DELETE FROM `products_reviews` WHERE `id` NOT IN (
+  SELECT `id` FROM (SELECT MAX(`id`) AS `id` FROM `products_reviews` GROUP BY `product_id`, `user_id`) AS `newest`
+);
+UPDATE `products_reviews` SET `rating` = (`rating` + 19) DIV 20 WHERE `rating` > 5;
+UPDATE `products_reviews` SET `rating` = 1 WHERE `rating` < 1;
+CREATE UNIQUE INDEX `uix_products_reviews_product_id_user_id` ON `products_reviews` (`product_id`, `user_id`);
+
+CREATE TABLE IF NOT EXISTS `products_reviews_votes` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `review_id` BIGINT UNSIGNED NOT NULL,
+  `user_id` BIGINT UNSIGNED NOT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_products_reviews_votes_review_id_user_id` (`review_id`, `user_id`),
+  KEY `idx_products_reviews_votes_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+UPDATE `products` SET
+  `total_rating` = (SELECT COALESCE(SUM(`rating`), 0) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1),
+  `rating_count` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1),
+  `rating_average` = (SELECT COALESCE(ROUND(AVG(`rating`), 2), 0) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1),
+  `rating_1` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1 AND `products_reviews`.`rating` = 1),
+  `rating_2` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1 AND `products_reviews`.`rating` = 2),
+  `rating_3` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1 AND `products_reviews`.`rating` = 3),
+  `rating_4` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1 AND `products_reviews`.`rating` = 4),
+  `rating_5` = (SELECT COUNT(*) FROM `products_reviews` WHERE `products_reviews`.`product_id` = `products`.`id` AND `products_reviews`.`status` = 1 AND `products_reviews`.`rating` = 5);
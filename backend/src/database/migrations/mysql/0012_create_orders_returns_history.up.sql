@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `orders_returns_history` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `return_id` BIGINT UNSIGNED NOT NULL,
+  `from_status` VARCHAR(20) NOT NULL DEFAULT '',
+  `to_status` VARCHAR(20) NOT NULL,
+  `actor_id` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  `actor_type` VARCHAR(50) NOT NULL,
+  `note` TEXT NULL DEFAULT NULL,
+  `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_returns_history_return_id` (`return_id`),
+  KEY `idx_orders_returns_history_actor_id` (`actor_id`),
+  KEY `idx_orders_returns_history_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "from_status" SMALLINT NOT NULL DEFAULT 0,
+  "to_status" SMALLINT NOT NULL DEFAULT 0,
+  "actor_id" BIGINT NOT NULL DEFAULT 0,
+  "actor_type" VARCHAR(50) NOT NULL,
+  "reason" TEXT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_order_id" ON "order_status_history" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_from_status" ON "order_status_history" ("from_status");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_to_status" ON "order_status_history" ("to_status");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_actor_id" ON "order_status_history" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_actor_type" ON "order_status_history" ("actor_type");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_created_at" ON "order_status_history" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_updated_at" ON "order_status_history" ("updated_at");
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "users_roles";
+DROP TABLE IF EXISTS "roles_permissions";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "roles";
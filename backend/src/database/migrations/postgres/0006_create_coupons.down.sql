@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "orders_coupons";
+DROP TABLE IF EXISTS "coupons_targets";
+DROP TABLE IF EXISTS "coupons";
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "uix_orders_cart_token";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "cart_token";
@@ -0,0 +1,32 @@
+ALTER TABLE "payments" ADD COLUMN IF NOT EXISTS "provider" VARCHAR(50) NOT NULL DEFAULT 'manual';
+
+CREATE TABLE IF NOT EXISTS "payments_transactions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "payment_id" BIGINT NOT NULL,
+  "provider" VARCHAR(50) NOT NULL,
+  "reference" VARCHAR(191) NOT NULL,
+  "amount" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "currency" CHAR(3) NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "redirect_url" VARCHAR(2048) NULL,
+  "error" VARCHAR(255) NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_payments_transactions_provider_reference" ON "payments_transactions" ("provider", "reference");
+CREATE INDEX IF NOT EXISTS "idx_payments_transactions_order_id" ON "payments_transactions" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_payments_transactions_status" ON "payments_transactions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payments_transactions_created_at" ON "payments_transactions" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_payments_transactions_updated_at" ON "payments_transactions" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "payments_events" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "provider" VARCHAR(50) NOT NULL,
+  "event_id" VARCHAR(191) NOT NULL,
+  "type" VARCHAR(100) NOT NULL,
+  "reference" VARCHAR(191) NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_payments_events_provider_event_id" ON "payments_events" ("provider", "event_id");
+CREATE INDEX IF NOT EXISTS "idx_payments_events_created_at" ON "payments_events" ("created_at");
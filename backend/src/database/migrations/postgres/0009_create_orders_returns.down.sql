@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "orders_returns_lines";
+DROP TABLE IF EXISTS "orders_returns";
+ALTER TABLE "payments_transactions" DROP COLUMN IF EXISTS "refunded";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "total_refunded";
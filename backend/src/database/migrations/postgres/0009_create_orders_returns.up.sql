@@ -0,0 +1,33 @@
+ALTER TABLE "orders" ADD COLUMN IF NOT EXISTS "total_refunded" DECIMAL(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE "payments_transactions" ADD COLUMN IF NOT EXISTS "refunded" DECIMAL(18,4) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "orders_returns" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" BIGINT NOT NULL,
+  "user_id" BIGINT NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'requested',
+  "reason" TEXT NOT NULL,
+  "note" TEXT NULL,
+  "refund_amount" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "approved_at" TIMESTAMP NULL,
+  "received_at" TIMESTAMP NULL,
+  "refunded_at" TIMESTAMP NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_order_id" ON "orders_returns" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_user_id" ON "orders_returns" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_status" ON "orders_returns" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_created_at" ON "orders_returns" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_updated_at" ON "orders_returns" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "orders_returns_lines" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "return_id" BIGINT NOT NULL,
+  "detail_id" BIGINT NOT NULL,
+  "inventory_id" BIGINT NOT NULL,
+  "qty" INTEGER NOT NULL DEFAULT 0,
+  "price" DECIMAL(18,4) NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_lines_return_id" ON "orders_returns_lines" ("return_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_lines_detail_id" ON "orders_returns_lines" ("detail_id");
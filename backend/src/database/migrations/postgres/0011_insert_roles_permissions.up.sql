@@ -0,0 +1,55 @@
+-- The built-in roles and what they may do, as services.RolePermissions
+-- defines them. Rows the seeder already created are left alone.
+
+INSERT INTO "roles" ("name", "status")
+SELECT 'admin', 1 WHERE NOT EXISTS (SELECT 1 FROM "roles" WHERE "name" = 'admin');
+INSERT INTO "roles" ("name", "status")
+SELECT 'catalog-manager', 1 WHERE NOT EXISTS (SELECT 1 FROM "roles" WHERE "name" = 'catalog-manager');
+INSERT INTO "roles" ("name", "status")
+SELECT 'customer', 1 WHERE NOT EXISTS (SELECT 1 FROM "roles" WHERE "name" = 'customer');
+INSERT INTO "roles" ("name", "status")
+SELECT 'support', 1 WHERE NOT EXISTS (SELECT 1 FROM "roles" WHERE "name" = 'support');
+
+INSERT INTO "permissions" ("name")
+SELECT 'catalog.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'catalog.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'coupons.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'coupons.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'orders.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'orders.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'orders.refund' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'orders.refund');
+INSERT INTO "permissions" ("name")
+SELECT 'orders.view_any' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'orders.view_any');
+INSERT INTO "permissions" ("name")
+SELECT 'reviews.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'reviews.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'roles.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'roles.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'users.manage' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'users.manage');
+INSERT INTO "permissions" ("name")
+SELECT 'users.view' WHERE NOT EXISTS (SELECT 1 FROM "permissions" WHERE "name" = 'users.view');
+
+INSERT INTO "roles_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id"
+FROM (
+  SELECT 'admin' AS "role_name", 'catalog.manage' AS "permission_name"
+  UNION ALL SELECT 'admin', 'orders.view_any'
+  UNION ALL SELECT 'admin', 'orders.manage'
+  UNION ALL SELECT 'admin', 'orders.refund'
+  UNION ALL SELECT 'admin', 'users.view'
+  UNION ALL SELECT 'admin', 'users.manage'
+  UNION ALL SELECT 'admin', 'roles.manage'
+  UNION ALL SELECT 'admin', 'coupons.manage'
+  UNION ALL SELECT 'admin', 'reviews.manage'
+  UNION ALL SELECT 'catalog-manager', 'catalog.manage'
+  UNION ALL SELECT 'support', 'orders.view_any'
+  UNION ALL SELECT 'support', 'orders.manage'
+  UNION ALL SELECT 'support', 'users.view'
+  UNION ALL SELECT 'support', 'reviews.manage'
+) "grants"
+INNER JOIN "roles" ON "roles"."name" = "grants"."role_name"
+INNER JOIN "permissions" ON "permissions"."name" = "grants"."permission_name"
+WHERE NOT EXISTS (
+  SELECT 1 FROM "roles_permissions"
+  WHERE "roles_permissions"."role_id" = "roles"."id" AND "roles_permissions"."permission_id" = "permissions"."id"
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "orders_returns_history" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "return_id" BIGINT NOT NULL,
+  "from_status" VARCHAR(20) NOT NULL DEFAULT '',
+  "to_status" VARCHAR(20) NOT NULL,
+  "actor_id" BIGINT NOT NULL DEFAULT 0,
+  "actor_type" VARCHAR(50) NOT NULL,
+  "note" TEXT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_history_return_id" ON "orders_returns_history" ("return_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_history_actor_id" ON "orders_returns_history" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_history_created_at" ON "orders_returns_history" ("created_at");
@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS "orders_carts";
+DROP TABLE IF EXISTS "products_wishlists";
+DROP TABLE IF EXISTS "products_categories";
+DROP TABLE IF EXISTS "settings";
+DROP TABLE IF EXISTS "newsLetters";
+DROP TABLE IF EXISTS "orders_details";
+DROP TABLE IF EXISTS "orders_billings";
+DROP TABLE IF EXISTS "orders";
+DROP TABLE IF EXISTS "products_reviews";
+DROP TABLE IF EXISTS "products_inventories";
+DROP TABLE IF EXISTS "products_images";
+DROP TABLE IF EXISTS "products";
+DROP TABLE IF EXISTS "payments";
+DROP TABLE IF EXISTS "sizes";
+DROP TABLE IF EXISTS "colours";
+DROP TABLE IF EXISTS "categories";
+DROP TABLE IF EXISTS "brands";
+DROP TABLE IF EXISTS "activities";
+DROP TABLE IF EXISTS "authentications";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,341 @@
+-- Tables that used to be created by AutoMigrate on boot, including the
+-- many2many join tables gorm only created implicitly.
+
+CREATE TABLE IF NOT EXISTS "users" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "email" VARCHAR(191) NOT NULL,
+  "phone" VARCHAR(191) NULL,
+  "password" VARCHAR(255) NOT NULL,
+  "salt" VARCHAR(255) NULL,
+  "image" VARCHAR(191) NULL,
+  "first_name" VARCHAR(191) NULL,
+  "last_name" VARCHAR(191) NULL,
+  "gender" VARCHAR(2) NULL,
+  "country" VARCHAR(191) NULL,
+  "city" VARCHAR(191) NULL,
+  "zip_code" VARCHAR(64) NULL,
+  "address" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone");
+CREATE INDEX IF NOT EXISTS "idx_users_password" ON "users" ("password");
+CREATE INDEX IF NOT EXISTS "idx_users_salt" ON "users" ("salt");
+CREATE INDEX IF NOT EXISTS "idx_users_image" ON "users" ("image");
+CREATE INDEX IF NOT EXISTS "idx_users_first_name" ON "users" ("first_name");
+CREATE INDEX IF NOT EXISTS "idx_users_last_name" ON "users" ("last_name");
+CREATE INDEX IF NOT EXISTS "idx_users_gender" ON "users" ("gender");
+CREATE INDEX IF NOT EXISTS "idx_users_country" ON "users" ("country");
+CREATE INDEX IF NOT EXISTS "idx_users_city" ON "users" ("city");
+CREATE INDEX IF NOT EXISTS "idx_users_zip_code" ON "users" ("zip_code");
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
+CREATE INDEX IF NOT EXISTS "idx_users_created_at" ON "users" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_users_updated_at" ON "users" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "authentications" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER NOT NULL,
+  "auth_type" VARCHAR(100) NOT NULL,
+  "credential" VARCHAR(180) NOT NULL,
+  "token" VARCHAR(100) NOT NULL,
+  "family_id" VARCHAR(100) NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "expired_at" DATETIME NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_authentications_user_id" ON "authentications" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_authentications_auth_type" ON "authentications" ("auth_type");
+CREATE INDEX IF NOT EXISTS "idx_authentications_credential" ON "authentications" ("credential");
+CREATE INDEX IF NOT EXISTS "idx_authentications_token" ON "authentications" ("token");
+CREATE INDEX IF NOT EXISTS "idx_authentications_family_id" ON "authentications" ("family_id");
+CREATE INDEX IF NOT EXISTS "idx_authentications_status" ON "authentications" ("status");
+CREATE INDEX IF NOT EXISTS "idx_authentications_expired_at" ON "authentications" ("expired_at");
+CREATE INDEX IF NOT EXISTS "idx_authentications_created_at" ON "authentications" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_authentications_updated_at" ON "authentications" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "activities" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER NOT NULL,
+  "subject" VARCHAR(255) NOT NULL,
+  "event" VARCHAR(255) NOT NULL,
+  "description" TEXT NOT NULL,
+  "status" INTEGER NOT NULL DEFAULT 1,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_activities_user_id" ON "activities" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_activities_subject" ON "activities" ("subject");
+CREATE INDEX IF NOT EXISTS "idx_activities_event" ON "activities" ("event");
+CREATE INDEX IF NOT EXISTS "idx_activities_status" ON "activities" ("status");
+CREATE INDEX IF NOT EXISTS "idx_activities_created_at" ON "activities" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_activities_updated_at" ON "activities" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "brands" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "image" VARCHAR(191) NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_brands_image" ON "brands" ("image");
+CREATE INDEX IF NOT EXISTS "idx_brands_name" ON "brands" ("name");
+CREATE INDEX IF NOT EXISTS "idx_brands_status" ON "brands" ("status");
+CREATE INDEX IF NOT EXISTS "idx_brands_created_at" ON "brands" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_brands_updated_at" ON "brands" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "categories" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "image" VARCHAR(191) NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "displayed" INTEGER NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_categories_image" ON "categories" ("image");
+CREATE INDEX IF NOT EXISTS "idx_categories_name" ON "categories" ("name");
+CREATE INDEX IF NOT EXISTS "idx_categories_displayed" ON "categories" ("displayed");
+CREATE INDEX IF NOT EXISTS "idx_categories_status" ON "categories" ("status");
+CREATE INDEX IF NOT EXISTS "idx_categories_created_at" ON "categories" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_categories_updated_at" ON "categories" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "colours" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "code" VARCHAR(100) NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_colours_code" ON "colours" ("code");
+CREATE INDEX IF NOT EXISTS "idx_colours_name" ON "colours" ("name");
+CREATE INDEX IF NOT EXISTS "idx_colours_status" ON "colours" ("status");
+CREATE INDEX IF NOT EXISTS "idx_colours_created_at" ON "colours" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_colours_updated_at" ON "colours" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "sizes" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_sizes_name" ON "sizes" ("name");
+CREATE INDEX IF NOT EXISTS "idx_sizes_status" ON "sizes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_sizes_created_at" ON "sizes" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_sizes_updated_at" ON "sizes" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "payments" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "image" VARCHAR(191) NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "displayed" INTEGER NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_payments_image" ON "payments" ("image");
+CREATE INDEX IF NOT EXISTS "idx_payments_name" ON "payments" ("name");
+CREATE INDEX IF NOT EXISTS "idx_payments_displayed" ON "payments" ("displayed");
+CREATE INDEX IF NOT EXISTS "idx_payments_status" ON "payments" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payments_created_at" ON "payments" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_payments_updated_at" ON "payments" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "products" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "brand_id" INTEGER NOT NULL,
+  "image" VARCHAR(191) NULL,
+  "sku" VARCHAR(100) NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "price" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "total_order" INTEGER NOT NULL DEFAULT 0,
+  "total_rating" INTEGER NOT NULL DEFAULT 0,
+  "description" TEXT NULL,
+  "details" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "published_at" DATETIME NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_products_brand_id" ON "products" ("brand_id");
+CREATE INDEX IF NOT EXISTS "idx_products_image" ON "products" ("image");
+CREATE INDEX IF NOT EXISTS "idx_products_sku" ON "products" ("sku");
+CREATE INDEX IF NOT EXISTS "idx_products_name" ON "products" ("name");
+CREATE INDEX IF NOT EXISTS "idx_products_price" ON "products" ("price");
+CREATE INDEX IF NOT EXISTS "idx_products_total_order" ON "products" ("total_order");
+CREATE INDEX IF NOT EXISTS "idx_products_total_rating" ON "products" ("total_rating");
+CREATE INDEX IF NOT EXISTS "idx_products_status" ON "products" ("status");
+CREATE INDEX IF NOT EXISTS "idx_products_published_at" ON "products" ("published_at");
+CREATE INDEX IF NOT EXISTS "idx_products_created_at" ON "products" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_products_updated_at" ON "products" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "products_images" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "product_id" INTEGER NOT NULL,
+  "path" VARCHAR(255) NOT NULL,
+  "sort" INTEGER NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_products_images_product_id" ON "products_images" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_products_images_path" ON "products_images" ("path");
+CREATE INDEX IF NOT EXISTS "idx_products_images_sort" ON "products_images" ("sort");
+CREATE INDEX IF NOT EXISTS "idx_products_images_status" ON "products_images" ("status");
+CREATE INDEX IF NOT EXISTS "idx_products_images_created_at" ON "products_images" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_products_images_updated_at" ON "products_images" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "products_inventories" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "product_id" INTEGER NOT NULL,
+  "size_id" INTEGER NOT NULL,
+  "colour_id" INTEGER NOT NULL,
+  "stock" INTEGER NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_product_id" ON "products_inventories" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_size_id" ON "products_inventories" ("size_id");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_colour_id" ON "products_inventories" ("colour_id");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_stock" ON "products_inventories" ("stock");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_status" ON "products_inventories" ("status");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_created_at" ON "products_inventories" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_products_inventories_updated_at" ON "products_inventories" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "products_reviews" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "product_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "rating" INTEGER NOT NULL DEFAULT 0,
+  "review" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_product_id" ON "products_reviews" ("product_id");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_user_id" ON "products_reviews" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_rating" ON "products_reviews" ("rating");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_status" ON "products_reviews" ("status");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_created_at" ON "products_reviews" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_updated_at" ON "products_reviews" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "orders" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER NOT NULL,
+  "payment_id" INTEGER NOT NULL,
+  "invoice_number" VARCHAR(255) NOT NULL,
+  "total_item" INTEGER NOT NULL DEFAULT 0,
+  "subtotal" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "total_discount" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "total_taxes" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "total_shipment" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "total_paid" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_user_id" ON "orders" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_payment_id" ON "orders" ("payment_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_invoice_number" ON "orders" ("invoice_number");
+CREATE INDEX IF NOT EXISTS "idx_orders_total_item" ON "orders" ("total_item");
+CREATE INDEX IF NOT EXISTS "idx_orders_subtotal" ON "orders" ("subtotal");
+CREATE INDEX IF NOT EXISTS "idx_orders_total_discount" ON "orders" ("total_discount");
+CREATE INDEX IF NOT EXISTS "idx_orders_total_taxes" ON "orders" ("total_taxes");
+CREATE INDEX IF NOT EXISTS "idx_orders_total_shipment" ON "orders" ("total_shipment");
+CREATE INDEX IF NOT EXISTS "idx_orders_total_paid" ON "orders" ("total_paid");
+CREATE INDEX IF NOT EXISTS "idx_orders_status" ON "orders" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_created_at" ON "orders" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_updated_at" ON "orders" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "orders_billings" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "order_id" INTEGER NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "description" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_billings_order_id" ON "orders_billings" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_billings_name" ON "orders_billings" ("name");
+CREATE INDEX IF NOT EXISTS "idx_orders_billings_status" ON "orders_billings" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_billings_created_at" ON "orders_billings" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_billings_updated_at" ON "orders_billings" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "orders_details" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "order_id" INTEGER NOT NULL,
+  "inventory_id" INTEGER NOT NULL,
+  "price" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "qty" INTEGER NOT NULL DEFAULT 0,
+  "total" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_details_order_id" ON "orders_details" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_inventory_id" ON "orders_details" ("inventory_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_price" ON "orders_details" ("price");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_qty" ON "orders_details" ("qty");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_total" ON "orders_details" ("total");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_status" ON "orders_details" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_created_at" ON "orders_details" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_details_updated_at" ON "orders_details" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "newsLetters" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "ip_address" VARCHAR(45) NOT NULL,
+  "email" VARCHAR(180) NOT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_newsLetters_ip_address" ON "newsLetters" ("ip_address");
+CREATE INDEX IF NOT EXISTS "idx_newsLetters_email" ON "newsLetters" ("email");
+CREATE INDEX IF NOT EXISTS "idx_newsLetters_status" ON "newsLetters" ("status");
+CREATE INDEX IF NOT EXISTS "idx_newsLetters_created_at" ON "newsLetters" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_newsLetters_updated_at" ON "newsLetters" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "settings" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "key_name" VARCHAR(255) NOT NULL,
+  "key_value" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_settings_key_name" ON "settings" ("key_name");
+CREATE INDEX IF NOT EXISTS "idx_settings_status" ON "settings" ("status");
+CREATE INDEX IF NOT EXISTS "idx_settings_created_at" ON "settings" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_settings_updated_at" ON "settings" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "products_categories" (
+  "product_id" INTEGER NOT NULL,
+  "category_id" INTEGER NOT NULL,
+  PRIMARY KEY ("product_id", "category_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_products_categories_category_id" ON "products_categories" ("category_id");
+
+CREATE TABLE IF NOT EXISTS "products_wishlists" (
+  "product_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  PRIMARY KEY ("product_id", "user_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_products_wishlists_user_id" ON "products_wishlists" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "orders_carts" (
+  "order_id" INTEGER NOT NULL,
+  "product_id" INTEGER NOT NULL,
+  PRIMARY KEY ("order_id", "product_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_carts_product_id" ON "orders_carts" ("product_id");
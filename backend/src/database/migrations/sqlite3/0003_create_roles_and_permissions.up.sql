@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS "roles" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(100) NOT NULL,
+  "description" TEXT NULL,
+  "status" INTEGER NOT NULL DEFAULT 1,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_roles_name" ON "roles" ("name");
+CREATE INDEX IF NOT EXISTS "idx_roles_status" ON "roles" ("status");
+CREATE INDEX IF NOT EXISTS "idx_roles_created_at" ON "roles" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_roles_updated_at" ON "roles" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(100) NOT NULL,
+  "description" TEXT NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_permissions_name" ON "permissions" ("name");
+CREATE INDEX IF NOT EXISTS "idx_permissions_created_at" ON "permissions" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_permissions_updated_at" ON "permissions" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "roles_permissions" (
+  "role_id" INTEGER NOT NULL,
+  "permission_id" INTEGER NOT NULL,
+  PRIMARY KEY ("role_id", "permission_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_roles_permissions_permission_id" ON "roles_permissions" ("permission_id");
+
+CREATE TABLE IF NOT EXISTS "users_roles" (
+  "user_id" INTEGER NOT NULL,
+  "role_id" INTEGER NOT NULL,
+  PRIMARY KEY ("user_id", "role_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_roles_role_id" ON "users_roles" ("role_id");
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "mail_outbox" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "recipient" VARCHAR(191) NOT NULL,
+  "template" VARCHAR(100) NOT NULL,
+  "payload" TEXT NOT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "last_error" TEXT NULL,
+  "next_attempt_at" DATETIME NULL,
+  "sent_at" DATETIME NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_recipient" ON "mail_outbox" ("recipient");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_template" ON "mail_outbox" ("template");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_status" ON "mail_outbox" ("status");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_next_attempt_at" ON "mail_outbox" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_sent_at" ON "mail_outbox" ("sent_at");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_created_at" ON "mail_outbox" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_mail_outbox_updated_at" ON "mail_outbox" ("updated_at");
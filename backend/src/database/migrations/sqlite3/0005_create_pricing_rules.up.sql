@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS "price_rules" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(255) NOT NULL,
+  "kind" VARCHAR(20) NOT NULL,
+  "scope" VARCHAR(20) NOT NULL DEFAULT 'order',
+  "target_id" INTEGER NULL,
+  "calculation" VARCHAR(20) NOT NULL DEFAULT 'percent',
+  "value" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "min_subtotal" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "priority" INTEGER NOT NULL DEFAULT 0,
+  "starts_at" DATETIME NULL,
+  "ends_at" DATETIME NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_price_rules_kind" ON "price_rules" ("kind");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_scope_target_id" ON "price_rules" ("scope", "target_id");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_priority" ON "price_rules" ("priority");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_starts_at" ON "price_rules" ("starts_at");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_ends_at" ON "price_rules" ("ends_at");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_status" ON "price_rules" ("status");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_created_at" ON "price_rules" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_price_rules_updated_at" ON "price_rules" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "tax_rates" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(255) NOT NULL,
+  "country" VARCHAR(191) NOT NULL DEFAULT '',
+  "zip_prefix" VARCHAR(64) NOT NULL DEFAULT '',
+  "rate" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_country" ON "tax_rates" ("country");
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_zip_prefix" ON "tax_rates" ("zip_prefix");
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_status" ON "tax_rates" ("status");
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_created_at" ON "tax_rates" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_updated_at" ON "tax_rates" ("updated_at");
+
+-- Carry the old pricing settings over. The settings rows are left in place
+-- so a rollback finds them again.
+INSERT INTO "price_rules" ("name", "kind", "scope", "calculation", "value", "priority", "starts_at", "ends_at", "status")
+SELECT 'Store discount', 'discount', 'order', 'percent', CAST("key_value" AS REAL), 0,
+  NULLIF((SELECT "key_value" FROM "settings" WHERE "key_name" = 'discount_start' ORDER BY "id" DESC LIMIT 1), ''),
+  NULLIF((SELECT "key_value" FROM "settings" WHERE "key_name" = 'discount_end' ORDER BY "id" DESC LIMIT 1), ''),
+  COALESCE((SELECT CAST("key_value" AS INTEGER) FROM "settings" WHERE "key_name" = 'discount_active' ORDER BY "id" DESC LIMIT 1), 1)
+FROM "settings" WHERE "key_name" = 'discount_value' ORDER BY "id" DESC LIMIT 1;
+
+INSERT INTO "price_rules" ("name", "kind", "scope", "calculation", "value", "priority", "starts_at", "ends_at", "status")
+SELECT 'Flat shipment', 'shipment', 'order', 'fixed', CAST("key_value" AS REAL), 0, NULL, NULL, 1
+FROM "settings" WHERE "key_name" = 'total_shipment' ORDER BY "id" DESC LIMIT 1;
+
+INSERT INTO "tax_rates" ("name", "country", "zip_prefix", "rate", "status")
+SELECT 'Default tax', '', '', CAST("key_value" AS REAL), 1
+FROM "settings" WHERE "key_name" = 'taxes_value' ORDER BY "id" DESC LIMIT 1;
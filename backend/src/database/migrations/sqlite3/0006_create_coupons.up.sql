@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS "coupons" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "code" VARCHAR(64) NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "calculation" VARCHAR(20) NOT NULL DEFAULT 'percent',
+  "value" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "min_subtotal" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "usage_limit" INTEGER NOT NULL DEFAULT 0,
+  "usage_limit_per_user" INTEGER NOT NULL DEFAULT 0,
+  "starts_at" DATETIME NULL,
+  "ends_at" DATETIME NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_coupons_code" ON "coupons" ("code");
+CREATE INDEX IF NOT EXISTS "idx_coupons_starts_at" ON "coupons" ("starts_at");
+CREATE INDEX IF NOT EXISTS "idx_coupons_ends_at" ON "coupons" ("ends_at");
+CREATE INDEX IF NOT EXISTS "idx_coupons_status" ON "coupons" ("status");
+CREATE INDEX IF NOT EXISTS "idx_coupons_created_at" ON "coupons" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_coupons_updated_at" ON "coupons" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "coupons_targets" (
+  "coupon_id" INTEGER NOT NULL,
+  "scope" VARCHAR(20) NOT NULL,
+  "target_id" INTEGER NOT NULL,
+  PRIMARY KEY ("coupon_id", "scope", "target_id")
+);
+
+CREATE TABLE IF NOT EXISTS "orders_coupons" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "order_id" INTEGER NOT NULL,
+  "coupon_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "code" VARCHAR(64) NOT NULL,
+  "amount" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_orders_coupons_order_id" ON "orders_coupons" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_coupons_coupon_id_user_id" ON "orders_coupons" ("coupon_id", "user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_coupons_created_at" ON "orders_coupons" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_coupons_updated_at" ON "orders_coupons" ("updated_at");
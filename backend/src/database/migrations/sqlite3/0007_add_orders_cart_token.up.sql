@@ -0,0 +1,2 @@
+ALTER TABLE "orders" ADD COLUMN "cart_token" CHAR(64) NULL DEFAULT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_orders_cart_token" ON "orders" ("cart_token");
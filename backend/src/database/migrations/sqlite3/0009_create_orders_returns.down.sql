@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "orders_returns_lines";
+DROP TABLE IF EXISTS "orders_returns";
+ALTER TABLE "payments_transactions" DROP COLUMN "refunded";
+ALTER TABLE "orders" DROP COLUMN "total_refunded";
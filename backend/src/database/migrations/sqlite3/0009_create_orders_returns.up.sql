@@ -0,0 +1,33 @@
+ALTER TABLE "orders" ADD COLUMN "total_refunded" DECIMAL(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE "payments_transactions" ADD COLUMN "refunded" DECIMAL(18,4) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "orders_returns" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "order_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'requested',
+  "reason" TEXT NOT NULL,
+  "note" TEXT NULL,
+  "refund_amount" DECIMAL(18,4) NOT NULL DEFAULT 0,
+  "approved_at" DATETIME NULL,
+  "received_at" DATETIME NULL,
+  "refunded_at" DATETIME NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_order_id" ON "orders_returns" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_user_id" ON "orders_returns" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_status" ON "orders_returns" ("status");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_created_at" ON "orders_returns" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_updated_at" ON "orders_returns" ("updated_at");
+
+CREATE TABLE IF NOT EXISTS "orders_returns_lines" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "return_id" INTEGER NOT NULL,
+  "detail_id" INTEGER NOT NULL,
+  "inventory_id" INTEGER NOT NULL,
+  "qty" INTEGER NOT NULL DEFAULT 0,
+  "price" DECIMAL(18,4) NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_lines_return_id" ON "orders_returns_lines" ("return_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_returns_lines_detail_id" ON "orders_returns_lines" ("detail_id");
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS "products_reviews_votes";
+DROP INDEX IF EXISTS "uix_products_reviews_product_id_user_id";
+ALTER TABLE "products_reviews" DROP COLUMN "moderated_at";
+ALTER TABLE "products_reviews" DROP COLUMN "moderation_note";
+ALTER TABLE "products_reviews" DROP COLUMN "helpful_count";
+ALTER TABLE "products_reviews" DROP COLUMN "verified";
+DROP INDEX IF EXISTS "idx_products_rating_average";
+ALTER TABLE "products" DROP COLUMN "rating_5";
+ALTER TABLE "products" DROP COLUMN "rating_4";
+ALTER TABLE "products" DROP COLUMN "rating_3";
+ALTER TABLE "products" DROP COLUMN "rating_2";
+ALTER TABLE "products" DROP COLUMN "rating_1";
+ALTER TABLE "products" DROP COLUMN "rating_count";
+ALTER TABLE "products" DROP COLUMN "rating_average";
+
+-- Put back the duplicate and out-of-range reviews and the product totals
+-- the up migration replaced.
+DELETE FROM "products_reviews" WHERE "id" IN (SELECT "id" FROM "products_reviews_0010_backup");
+INSERT INTO "products_reviews" SELECT * FROM "products_reviews_0010_backup";
+UPDATE "products" SET "total_rating" = COALESCE((SELECT "total_rating" FROM "products_0010_backup" WHERE "products_0010_backup"."id" = "products"."id"), "total_rating");
+DROP TABLE IF EXISTS "products_reviews_0010_backup";
+DROP TABLE IF EXISTS "products_0010_backup";
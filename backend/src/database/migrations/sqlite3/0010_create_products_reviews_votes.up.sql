@@ -0,0 +1,50 @@
+-- Older versions let a customer review a product more than once and stored
+-- ratings out of 100. Only the newest review per customer and product is
+-- kept and ratings are scaled to 1-5 below, so the rows that change and the
+-- product totals computed from them are copied aside first for the down
+-- migration to put back.
+CREATE TABLE IF NOT EXISTS "products_reviews_0010_backup" AS
+SELECT * FROM "products_reviews" WHERE "rating" > 5 OR "rating" < 1 OR "id" NOT IN (
+  SELECT "id" FROM (SELECT MAX("id") AS "id" FROM "products_reviews" GROUP BY "product_id", "user_id") AS "newest"
+);
+CREATE TABLE IF NOT EXISTS "products_0010_backup" AS SELECT "id", "total_rating" FROM "products";
+
+ALTER TABLE "products" ADD COLUMN "rating_average" DECIMAL(3,2) NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_count" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_1" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_2" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_3" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_4" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN "rating_5" INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_products_rating_average" ON "products" ("rating_average");
+
+ALTER TABLE "products_reviews" ADD COLUMN "verified" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products_reviews" ADD COLUMN "helpful_count" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "products_reviews" ADD COLUMN "moderation_note" TEXT NULL;
+ALTER TABLE "products_reviews" ADD COLUMN "moderated_at" DATETIME NULL;
+
+DELETE FROM "products_reviews" WHERE "id" NOT IN (
+  SELECT "id" FROM (SELECT MAX("id") AS "id" FROM "products_reviews" GROUP BY "product_id", "user_id") AS "newest"
+);
+UPDATE "products_reviews" SET "rating" = ("rating" + 19) / 20 WHERE "rating" > 5;
+UPDATE "products_reviews" SET "rating" = 1 WHERE "rating" < 1;
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_products_reviews_product_id_user_id" ON "products_reviews" ("product_id", "user_id");
+
+CREATE TABLE IF NOT EXISTS "products_reviews_votes" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "review_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "uix_products_reviews_votes_review_id_user_id" ON "products_reviews_votes" ("review_id", "user_id");
+CREATE INDEX IF NOT EXISTS "idx_products_reviews_votes_user_id" ON "products_reviews_votes" ("user_id");
+
+UPDATE "products" SET
+  "total_rating" = (SELECT COALESCE(SUM("rating"), 0) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1),
+  "rating_count" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1),
+  "rating_average" = (SELECT COALESCE(ROUND(AVG("rating"), 2), 0) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1),
+  "rating_1" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1 AND "products_reviews"."rating" = 1),
+  "rating_2" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1 AND "products_reviews"."rating" = 2),
+  "rating_3" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1 AND "products_reviews"."rating" = 3),
+  "rating_4" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1 AND "products_reviews"."rating" = 4),
+  "rating_5" = (SELECT COUNT(*) FROM "products_reviews" WHERE "products_reviews"."product_id" = "products"."id" AND "products_reviews"."status" = 1 AND "products_reviews"."rating" = 5);
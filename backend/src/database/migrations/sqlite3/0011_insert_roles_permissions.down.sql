@@ -0,0 +1,5 @@
+DELETE FROM "roles_permissions" WHERE "role_id" IN (SELECT "id" FROM "roles" WHERE "name" IN ('admin', 'catalog-manager', 'customer', 'support'));
+DELETE FROM "roles_permissions" WHERE "permission_id" IN (SELECT "id" FROM "permissions" WHERE "name" IN ('catalog.manage', 'coupons.manage', 'orders.manage', 'orders.refund', 'orders.view_any', 'reviews.manage', 'roles.manage', 'users.manage', 'users.view'));
+DELETE FROM "users_roles" WHERE "role_id" IN (SELECT "id" FROM "roles" WHERE "name" IN ('admin', 'catalog-manager', 'customer', 'support'));
+DELETE FROM "permissions" WHERE "name" IN ('catalog.manage', 'coupons.manage', 'orders.manage', 'orders.refund', 'orders.view_any', 'reviews.manage', 'roles.manage', 'users.manage', 'users.view');
+DELETE FROM "roles" WHERE "name" IN ('admin', 'catalog-manager', 'customer', 'support');
@@ -0,0 +1,90 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+type ValidationError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// ValidationErrors turns a binding error into one entry per offending field
+// so clients can show messages next to the matching inputs.
+func ValidationErrors(err error) []ValidationError {
+
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		result := make([]ValidationError, 0, len(fieldErrors))
+		for _, fe := range fieldErrors {
+			result = append(result, ValidationError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: validationMessage(fe),
+			})
+		}
+		return result
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []ValidationError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("The %s field must be of type %s.", typeError.Field, typeError.Type.String()),
+		}}
+	}
+
+	return []ValidationError{{Field: "", Rule: "body", Message: err.Error()}}
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("The %s field is required.", fe.Field())
+	case "max":
+		return fmt.Sprintf("The %s field may not be greater than %s.", fe.Field(), fe.Param())
+	case "min":
+		return fmt.Sprintf("The %s field must be at least %s.", fe.Field(), fe.Param())
+	case "gte":
+		return fmt.Sprintf("The %s field must be greater than or equal to %s.", fe.Field(), fe.Param())
+	case "gt":
+		return fmt.Sprintf("The %s field must be greater than %s.", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("The %s field must be one of [%s].", fe.Field(), fe.Param())
+	case "email":
+		return fmt.Sprintf("The %s field must be a valid email address.", fe.Field())
+	}
+	return fmt.Sprintf("The %s field is invalid.", fe.Field())
+}
@@ -0,0 +1,72 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type logMailer struct {
+	from string
+}
+
+// LogMailer writes the text part of every message to the application log.
+func LogMailer(from string) Mailer {
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(message Message) error {
+	log.Printf("mail from=%s to=%s subject=%q\n%s", m.from, message.To, message.Subject, message.Text)
+	return nil
+}
+
+type fileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+// FileMailer appends every message, in full MIME form, to a single file.
+func FileMailer(path string, from string) Mailer {
+	if path == "" {
+		path = filepath.Join(os.TempDir(), "mail.log")
+	}
+	return &fileMailer{path: path, from: from}
+}
+
+func (m *fileMailer) Send(message Message) error {
+
+	body, err := buildMIME(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "==== %s\r\n", time.Now().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	_, err = file.Write(body)
+	return err
+}
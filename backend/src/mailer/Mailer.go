@@ -0,0 +1,50 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	appconfig "backend/src/appconfig"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer delivers a fully rendered message. Implementations must be safe
+// for concurrent use.
+type Mailer interface {
+	Send(message Message) error
+}
+
+// NewMailer picks the transport named in the MAIL_DRIVER setting: "smtp"
+// talks to MAIL_HOST:MAIL_PORT, "file" appends to MAIL_LOG_PATH and "log"
+// writes messages to the application log.
+func NewMailer(config *appconfig.Config) Mailer {
+	mail := config.Mail
+	switch mail.Driver {
+	case "smtp":
+		return SMTPMailer(mail.Host, mail.Port, mail.Username, mail.Password, mail.From)
+	case "file":
+		return FileMailer(mail.LogPath, mail.From)
+	default:
+		return LogMailer(mail.From)
+	}
+}
+
+// Link returns an absolute URL on the storefront for path.
+func Link(config *appconfig.Config, path string) string {
+	return config.FrontendURL + "/" + strings.TrimLeft(path, "/")
+}
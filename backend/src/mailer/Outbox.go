@@ -0,0 +1,138 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	models "backend/src/models"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	MaxAttempts = 8
+	batchSize   = 20
+	leaseTime   = 5 * time.Minute
+)
+
+// Enqueue stores a message in the outbox. Pass a transaction to make the
+// e-mail part of the same unit of work as the change that triggers it.
+func Enqueue(db *gorm.DB, to string, template string, data map[string]interface{}) error {
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return db.Create(&models.MailOutbox{
+		Recipient:     to,
+		Template:      template,
+		Payload:       string(payload),
+		Status:        models.MailStatusPending,
+		NextAttemptAt: &now,
+	}).Error
+}
+
+// StartOutboxWorker drains the outbox every interval until ctx is done.
+// The returned channel is closed once the worker has stopped.
+func StartOutboxWorker(ctx context.Context, db *gorm.DB, mailer Mailer, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			DrainOutbox(db, mailer)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
+
+// DrainOutbox sends every message that is due and returns how many were
+// delivered. Each row is leased before sending so several workers can
+// share one outbox without delivering a message twice.
+func DrainOutbox(db *gorm.DB, mailer Mailer) int {
+
+	var rows []models.MailOutbox
+	db.Where("status = ? AND next_attempt_at <= ?", models.MailStatusPending, time.Now()).
+		Order("id asc").
+		Limit(batchSize).
+		Find(&rows)
+
+	sent := 0
+	for _, row := range rows {
+
+		lease := time.Now().Add(leaseTime)
+		claim := db.Model(&models.MailOutbox{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", row.Id, models.MailStatusPending, time.Now()).
+			UpdateColumn("next_attempt_at", lease)
+		if claim.Error != nil || claim.RowsAffected != 1 {
+			continue
+		}
+
+		if err := deliver(row, mailer); err != nil {
+			fail(db, row, err)
+			continue
+		}
+
+		now := time.Now()
+		db.Model(&row).UpdateColumns(map[string]interface{}{
+			"status":     models.MailStatusSent,
+			"attempts":   row.Attempts + 1,
+			"sent_at":    now,
+			"last_error": "",
+		})
+		sent++
+	}
+
+	return sent
+}
+
+func deliver(row models.MailOutbox, mailer Mailer) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(row.Payload), &data); err != nil {
+		return err
+	}
+	message, err := Render(row.Template, row.Recipient, data)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(message)
+}
+
+func fail(db *gorm.DB, row models.MailOutbox, cause error) {
+
+	attempts := row.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": cause.Error(),
+	}
+
+	if attempts >= MaxAttempts {
+		updates["status"] = models.MailStatusFailed
+		log.Printf("mail %d to %s failed permanently: %v", row.Id, row.Recipient, cause)
+	} else {
+		// 1m, 2m, 4m, ... between attempts
+		updates["next_attempt_at"] = time.Now().Add(time.Minute << (attempts - 1))
+	}
+
+	db.Model(&row).UpdateColumns(updates)
+}
@@ -0,0 +1,201 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	models "backend/src/models"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// recordingMailer keeps every message it is asked to send and fails them
+// all while err is set.
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []Message
+	err  error
+	// during runs inside Send, before the message counts as sent.
+	during func()
+}
+
+func (m *recordingMailer) Send(message Message) error {
+	if m.during != nil {
+		during := m.during
+		m.during = nil
+		during()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+func outboxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db.DB().SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.AutoMigrate(&models.MailOutbox{}).Error; err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func enqueueConfirm(t *testing.T, db *gorm.DB, to string) models.MailOutbox {
+	t.Helper()
+
+	if err := Enqueue(db, to, TemplateConfirm, map[string]interface{}{"Name": "Ada", "Link": "http://shop.test/confirm", "ExpiresIn": "30 minutes"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	var row models.MailOutbox
+	db.Where("recipient = ?", to).Last(&row)
+	return row
+}
+
+func reload(t *testing.T, db *gorm.DB, id uint64) models.MailOutbox {
+	t.Helper()
+
+	var row models.MailOutbox
+	if err := db.Where("id = ?", id).First(&row).Error; err != nil {
+		t.Fatalf("reload %d: %v", id, err)
+	}
+	return row
+}
+
+// makeDue moves a row's next attempt into the past, as if its backoff had run out.
+func makeDue(db *gorm.DB, id uint64) {
+	past := time.Now().Add(-time.Second)
+	db.Model(&models.MailOutbox{}).Where("id = ?", id).UpdateColumn("next_attempt_at", past)
+}
+
+func TestDrainOutboxSends(t *testing.T) {
+
+	db := outboxDB(t)
+	mailer := &recordingMailer{}
+	row := enqueueConfirm(t, db, "ada@example.com")
+
+	if sent := DrainOutbox(db, mailer); sent != 1 {
+		t.Fatalf("sent %d, want 1", sent)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "ada@example.com" || mailer.sent[0].Subject != "Confirm your account" {
+		t.Errorf("sent %+v, want the confirmation to ada@example.com", mailer.sent)
+	}
+
+	row = reload(t, db, row.Id)
+	if row.Status != models.MailStatusSent || row.Attempts != 1 || row.SentAt == nil {
+		t.Errorf("row = %+v, want it sent on the first attempt", row)
+	}
+
+	if sent := DrainOutbox(db, mailer); sent != 0 || len(mailer.sent) != 1 {
+		t.Errorf("a second drain sent the message again")
+	}
+}
+
+func TestDrainOutboxBacksOff(t *testing.T) {
+
+	db := outboxDB(t)
+	mailer := &recordingMailer{err: errors.New("connection refused")}
+	row := enqueueConfirm(t, db, "ada@example.com")
+
+	var previous time.Duration
+	for attempt := uint16(1); attempt <= 3; attempt++ {
+		makeDue(db, row.Id)
+		started := time.Now()
+
+		if sent := DrainOutbox(db, mailer); sent != 0 {
+			t.Fatalf("attempt %d: sent %d, want 0", attempt, sent)
+		}
+
+		row = reload(t, db, row.Id)
+		if row.Status != models.MailStatusPending || row.Attempts != attempt || row.LastError != "connection refused" {
+			t.Fatalf("attempt %d: row = %+v, want it pending with the error recorded", attempt, row)
+		}
+		wait := row.NextAttemptAt.Sub(started)
+		if wait <= previous {
+			t.Errorf("attempt %d: next attempt in %v, want longer than the %v before it", attempt, wait, previous)
+		}
+		previous = wait
+
+		// Not due yet, so a drain straight away leaves it alone.
+		DrainOutbox(db, mailer)
+		if again := reload(t, db, row.Id); again.Attempts != attempt {
+			t.Errorf("attempt %d: retried before its backoff ran out", attempt)
+		}
+	}
+}
+
+func TestDrainOutboxGivesUp(t *testing.T) {
+
+	db := outboxDB(t)
+	mailer := &recordingMailer{err: errors.New("mailbox unavailable")}
+	row := enqueueConfirm(t, db, "ada@example.com")
+	db.Model(&models.MailOutbox{}).Where("id = ?", row.Id).UpdateColumn("attempts", MaxAttempts-1)
+
+	DrainOutbox(db, mailer)
+
+	row = reload(t, db, row.Id)
+	if row.Status != models.MailStatusFailed || row.Attempts != MaxAttempts {
+		t.Fatalf("row = %+v, want it failed after %d attempts", row, MaxAttempts)
+	}
+
+	makeDue(db, row.Id)
+	mailer.err = nil
+	if sent := DrainOutbox(db, mailer); sent != 0 || len(mailer.sent) != 0 {
+		t.Error("a failed message was sent again")
+	}
+}
+
+func TestDrainOutboxHonoursLease(t *testing.T) {
+
+	db := outboxDB(t)
+	mailer := &recordingMailer{}
+	first := enqueueConfirm(t, db, "ada@example.com")
+	second := enqueueConfirm(t, db, "grace@example.com")
+
+	// A second worker drains the outbox while the first is still
+	// delivering: the row being sent is leased and must be skipped.
+	var nested int
+	mailer.during = func() { nested = DrainOutbox(db, mailer) }
+
+	if sent := DrainOutbox(db, mailer); sent != 1 {
+		t.Errorf("first worker sent %d, want 1", sent)
+	}
+	if nested != 1 {
+		t.Errorf("second worker sent %d, want only the row not leased", nested)
+	}
+
+	counts := map[string]int{}
+	for _, message := range mailer.sent {
+		counts[message.To]++
+	}
+	if counts["ada@example.com"] != 1 || counts["grace@example.com"] != 1 {
+		t.Errorf("deliveries = %v, want each message exactly once", counts)
+	}
+	for _, id := range []uint64{first.Id, second.Id} {
+		if row := reload(t, db, id); row.Status != models.MailStatusSent || row.Attempts != 1 {
+			t.Errorf("row %d = %+v, want it sent once", id, row)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// envelope is what the fake SMTP server received for one message.
+type envelope struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP accepts one connection on a local port, speaks just enough SMTP
+// for net/smtp and hands back what it was sent.
+func fakeSMTP(t *testing.T) (string, string, <-chan envelope) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan envelope, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		var message envelope
+		reply("220 localhost fake SMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				message.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				message.to = append(message.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(line, "."))
+				}
+				message.data = data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- message
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+
+	host, port, received := fakeSMTP(t)
+
+	message, err := Render(TemplateConfirm, "ada@example.com", map[string]interface{}{
+		"Name":      "Ada",
+		"Link":      "http://shop.test/auth/confirm/abc",
+		"ExpiresIn": "30 minutes",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if err := SMTPMailer(host, port, "", "", "shop@example.com").Send(message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := <-received
+	if got.from != "shop@example.com" {
+		t.Errorf("MAIL FROM = %q, want shop@example.com", got.from)
+	}
+	if len(got.to) != 1 || got.to[0] != "ada@example.com" {
+		t.Errorf("RCPT TO = %v, want ada@example.com", got.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	if subject := parsed.Header.Get("Subject"); subject != "Confirm your account" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", parsed.Header.Get("Content-Type"))
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(part)
+		parts[contentType] = string(body)
+	}
+
+	if text := parts["text/plain"]; !strings.Contains(text, "Hello Ada,") || !strings.Contains(text, "http://shop.test/auth/confirm/abc") {
+		t.Errorf("text part = %q, want the greeting and the link", text)
+	}
+	if html := parts["text/html"]; !strings.Contains(html, `href="http://shop.test/auth/confirm/abc"`) {
+		t.Errorf("html part = %q, want the link", html)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	if _, err := Render("nope", "ada@example.com", nil); err == nil {
+		t.Error("Render of an unknown template succeeded")
+	}
+}
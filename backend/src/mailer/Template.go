@@ -0,0 +1,74 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+const (
+	TemplateConfirm      = "confirm"
+	TemplateReset        = "reset"
+	TemplateOrderPlaced  = "order-placed"
+	TemplateOrderShipped = "order-shipped"
+)
+
+var subjects = map[string]string{
+	TemplateConfirm:      "Confirm your account",
+	TemplateReset:        "Reset your password",
+	TemplateOrderPlaced:  "We have received your order {{.InvoiceNumber}}",
+	TemplateOrderShipped: "Your order {{.InvoiceNumber}} is on its way",
+}
+
+//go:embed templates/*.html templates/*.txt
+var templateFiles embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/*.txt"))
+)
+
+// Render builds a message for one of the Template* names from its subject,
+// html and text templates.
+func Render(name string, to string, data map[string]interface{}) (Message, error) {
+
+	subjectSource, ok := subjects[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	var subject bytes.Buffer
+	if err := texttemplate.Must(texttemplate.New("subject").Parse(subjectSource)).Execute(&subject, data); err != nil {
+		return Message{}, err
+	}
+
+	var html bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, err
+	}
+
+	var text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: subject.String(),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
@@ -12,8 +12,9 @@
 package middleware
 
 import (
+	appconfig "backend/src/appconfig"
+	repositories "backend/src/repositories"
 	service "backend/src/services"
-	"fmt"
 	"net/http"
 	"strings"
 
@@ -21,24 +22,44 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// OptionalJWT authenticates requests that carry a bearer token exactly like
+// AuthorizeJWT, and lets requests without one through anonymously, for the
+// routes guests may use too.
+func OptionalJWT() gin.HandlerFunc {
+	authorize := AuthorizeJWT()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			return
+		}
+		authorize(c)
+	}
+}
+
 func AuthorizeJWT() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		const BEARER_SCHEMA = "Bearer "
 		authHeader := c.GetHeader("Authorization")
-		if len(strings.TrimSpace(authHeader)) == 0 {
+		if !strings.HasPrefix(authHeader, BEARER_SCHEMA) {
 			c.AbortWithStatus(http.StatusUnauthorized)
-		} else {
-			tokenString := authHeader[len(BEARER_SCHEMA):]
-			token, err := service.JWTAuthService().ValidateToken(tokenString)
-			if token.Valid {
-				claims := token.Claims.(jwt.MapClaims)
-				c.Set("claims", claims)
-				fmt.Println(claims)
-			} else {
-				fmt.Println(err)
-				c.AbortWithStatus(http.StatusUnauthorized)
-			}
+			return
+		}
+
+		config := c.MustGet("config").(*appconfig.Config)
+		tokenString := strings.TrimSpace(authHeader[len(BEARER_SCHEMA):])
+		token, err := service.JWTAuthService(config).ValidateToken(tokenString)
+		if err != nil || token == nil || !token.Valid {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		claims := token.Claims.(jwt.MapClaims)
+		sid, _ := claims["sid"].(string)
+		store := c.MustGet("store").(repositories.Store)
+		if service.Sessions(config, store).IsRevoked(sid) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "This session has been revoked."})
+			return
 		}
 
+		c.Set("claims", claims)
 	}
 }
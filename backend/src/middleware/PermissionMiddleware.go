@@ -0,0 +1,50 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package middleware
+
+import (
+	repositories "backend/src/repositories"
+	service "backend/src/services"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission must run after AuthorizeJWT. Roles come from the token,
+// permissions are resolved from the database on every request so changes to
+// a role apply without reissuing tokens.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Get("claims")
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		store := c.MustGet("store").(repositories.Store)
+		roles := service.ClaimRoles(claims.(jwt.MapClaims))
+		granted := make(map[string]bool)
+		for _, permission := range service.AccessControl(store).Permissions(roles) {
+			granted[permission] = true
+		}
+
+		for _, permission := range permissions {
+			if !granted[permission] {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to access this resource."})
+				return
+			}
+		}
+
+		c.Set("roles", roles)
+	}
+}
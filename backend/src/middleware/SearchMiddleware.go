@@ -0,0 +1,35 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package middleware
+
+import (
+	search "backend/src/search"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateSearch drops the search index after a request that changed the
+// catalogue, so the storefront finds the change on its next search.
+func InvalidateSearch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method == http.MethodGet || c.Writer.Status() >= http.StatusMultipleChoices {
+			return
+		}
+
+		if engine, ok := c.Get("search"); ok {
+			engine.(*search.Engine).Invalidate()
+		}
+	}
+}
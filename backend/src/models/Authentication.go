@@ -12,9 +12,28 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
+const (
+	AuthTypeEmailConfirm  = "email-confirm"
+	AuthTypeResetPassword = "reset-password"
+	AuthTypeRefreshToken  = "refresh-token"
+)
+
+var AuthTypes = []string{
+	AuthTypeEmailConfirm,
+	AuthTypeResetPassword,
+	AuthTypeRefreshToken,
+}
+
+const (
+	AuthStatusPending uint8 = 0
+	AuthStatusUsed    uint8 = 2
+	AuthStatusRevoked uint8 = 3
+)
+
 type Authentication struct {
 	Id         uint64     `json:"id" gorm:"primary_key"`
 	UserId     int64      `json:"user_id" gorm:"index;not null"`
@@ -22,6 +41,7 @@ type Authentication struct {
 	AuthType   string     `json:"type" gorm:"index;size:100;not null"`
 	Credential string     `json:"credential" gorm:"index;size:180;not null"`
 	Token      string     `json:"token" gorm:"index;size:100;not null"`
+	FamilyId   string     `json:"-" gorm:"index;size:100"`
 	Status     uint8      `json:"status" gorm:"index;default:0"`
 	ExpiredAt  *time.Time `json:"expired_at" gorm:"index"`
 	CreatedAt  time.Time  `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
@@ -31,3 +51,13 @@ type Authentication struct {
 func (Authentication) TableName() string {
 	return "authentications"
 }
+
+// BeforeCreate rejects rows whose auth_type is not one of AuthTypes.
+func (auth *Authentication) BeforeCreate() error {
+	for _, authType := range AuthTypes {
+		if auth.AuthType == authType {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown authentication type %q", auth.AuthType)
+}
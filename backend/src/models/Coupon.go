@@ -0,0 +1,54 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"time"
+)
+
+// Coupon is a promo code a customer applies to their cart. Without
+// targets it discounts the whole order; with targets only the cart lines
+// of those products, categories or brands. A UsageLimit or
+// UsageLimitPerUser of zero means no limit.
+type Coupon struct {
+	Id                uint64         `json:"id" gorm:"primary_key"`
+	Code              string         `json:"code" gorm:"unique_index;size:64;not null"`
+	Name              string         `json:"name" gorm:"size:255;not null"`
+	Calculation       string         `json:"calculation" gorm:"size:20;not null;default:'percent'"`
+	Value             money.Money    `json:"value" gorm:"type:decimal(18,4);default:0"`
+	MinSubtotal       money.Money    `json:"min_subtotal" gorm:"type:decimal(18,4);default:0"`
+	UsageLimit        uint32         `json:"usage_limit" gorm:"default:0"`
+	UsageLimitPerUser uint32         `json:"usage_limit_per_user" gorm:"default:0"`
+	StartsAt          *time.Time     `json:"starts_at" gorm:"index"`
+	EndsAt            *time.Time     `json:"ends_at" gorm:"index"`
+	Status            uint8          `json:"status" gorm:"index;default:0"`
+	CreatedAt         time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Targets           []CouponTarget `json:"targets" gorm:"foreignkey:CouponId"`
+}
+
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponTarget limits a coupon to the products, categories or brands it
+// names. Scope is one of the PriceScope constants other than order.
+type CouponTarget struct {
+	CouponId uint64 `json:"-" gorm:"primary_key"`
+	Scope    string `json:"scope" gorm:"primary_key;size:20"`
+	TargetId uint64 `json:"target_id" gorm:"primary_key"`
+}
+
+func (CouponTarget) TableName() string {
+	return "coupons_targets"
+}
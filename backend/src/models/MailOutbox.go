@@ -0,0 +1,40 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	MailStatusPending uint8 = 0
+	MailStatusSent    uint8 = 1
+	MailStatusFailed  uint8 = 2
+)
+
+type MailOutbox struct {
+	Id            uint64     `json:"id" gorm:"primary_key"`
+	Recipient     string     `json:"recipient" gorm:"index;size:191;not null"`
+	Template      string     `json:"template" gorm:"index;size:100;not null"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"`
+	Status        uint8      `json:"status" gorm:"index;default:0"`
+	Attempts      uint16     `json:"attempts" gorm:"default:0"`
+	LastError     string     `json:"last_error" gorm:"type:text;default null"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" gorm:"index"`
+	SentAt        *time.Time `json:"sent_at" gorm:"index"`
+	CreatedAt     time.Time  `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (MailOutbox) TableName() string {
+	return "mail_outbox"
+}
@@ -12,30 +12,99 @@
 package models
 
 import (
+	money "backend/src/money"
+	"database/sql"
 	"time"
 )
 
+const (
+	OrderStatusCart           uint8 = 0
+	OrderStatusPendingPayment uint8 = 1
+	OrderStatusPaid           uint8 = 2
+	OrderStatusProcessing     uint8 = 3
+	OrderStatusShipped        uint8 = 4
+	OrderStatusDelivered      uint8 = 5
+	OrderStatusCancelled      uint8 = 6
+	OrderStatusRefunded       uint8 = 7
+	OrderStatusPaymentFailed  uint8 = 8
+)
+
+var OrderStatusNames = map[uint8]string{
+	OrderStatusCart:           "cart",
+	OrderStatusPendingPayment: "pending_payment",
+	OrderStatusPaid:           "paid",
+	OrderStatusProcessing:     "processing",
+	OrderStatusShipped:        "shipped",
+	OrderStatusDelivered:      "delivered",
+	OrderStatusCancelled:      "cancelled",
+	OrderStatusRefunded:       "refunded",
+	OrderStatusPaymentFailed:  "payment_failed",
+}
+
+// OrderTransitions lists, for every status, the statuses an order may move to next.
+// Only staff may cancel an order that has been paid for, which refunds it in
+// full; see CustomerCancellable.
+var OrderTransitions = map[uint8][]uint8{
+	OrderStatusCart:           {OrderStatusPendingPayment, OrderStatusCancelled},
+	OrderStatusPendingPayment: {OrderStatusPaid, OrderStatusPaymentFailed, OrderStatusCancelled},
+	OrderStatusPaymentFailed:  {OrderStatusPendingPayment, OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:           {OrderStatusProcessing, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusProcessing:     {OrderStatusShipped, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusShipped:        {OrderStatusDelivered},
+	OrderStatusDelivered:      {OrderStatusRefunded},
+	OrderStatusCancelled:      {},
+	OrderStatusRefunded:       {},
+}
+
+// CustomerCancellable lists the statuses a customer may cancel their own
+// order from. Nothing has been charged yet in any of them.
+var CustomerCancellable = map[uint8]bool{
+	OrderStatusCart:           true,
+	OrderStatusPendingPayment: true,
+	OrderStatusPaymentFailed:  true,
+}
+
+// Order is a cart while its status is OrderStatusCart. A guest's cart has
+// no user yet and is found by the hash of its cart token instead.
 type Order struct {
-	Id            uint64    `json:"id" gorm:"primary_key"`
-	UserId        uint64    `json:"user_id" gorm:"index;not null"`
-	User          User      `json:"-" gorm:"foreignKey:user_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	PaymentId     uint64    `json:"payment_id" gorm:"index;not null"`
-	Payment       Payment   `json:"-" gorm:"foreignKey:payment_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	InvoiceNumber string    `json:"invoice_number" gorm:"index;size:255;not null"`
-	TotalItem     uint16    `json:"total_item" gorm:"index;default:0"`
-	Subtotal      float64   `json:"subtotal" gorm:"type:decimal(18,4);default:0;index"`
-	TotalDiscount float64   `json:"total_discount" gorm:"type:decimal(18,4);default:0;index"`
-	TotalTaxes    float64   `json:"total_taxes" gorm:"type:decimal(18,4);default:0;index"`
-	TotalShipment float64   `json:"total_shipment" gorm:"type:decimal(18,4);default:0;index"`
-	TotalPaid     float64   `json:"total_paid" gorm:"type:decimal(18,4);default:0;index"`
-	Status        uint8     `json:"status" gorm:"index;default:0"`
-	CreatedAt     time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	Products      []Product `gorm:"many2many:orders_carts"`
+	Id            uint64         `json:"id" gorm:"primary_key"`
+	UserId        uint64         `json:"user_id" gorm:"index;not null"`
+	User          User           `json:"-" gorm:"foreignKey:user_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	PaymentId     uint64         `json:"payment_id" gorm:"index;not null"`
+	Payment       Payment        `json:"-" gorm:"foreignKey:payment_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	InvoiceNumber string         `json:"invoice_number" gorm:"index;size:255;not null"`
+	CartToken     sql.NullString `json:"-" gorm:"unique_index;size:64"`
+	TotalItem     uint16         `json:"total_item" gorm:"index;default:0"`
+	Subtotal      money.Money    `json:"subtotal" gorm:"type:decimal(18,4);default:0;index"`
+	TotalDiscount money.Money    `json:"total_discount" gorm:"type:decimal(18,4);default:0;index"`
+	TotalTaxes    money.Money    `json:"total_taxes" gorm:"type:decimal(18,4);default:0;index"`
+	TotalShipment money.Money    `json:"total_shipment" gorm:"type:decimal(18,4);default:0;index"`
+	TotalPaid     money.Money    `json:"total_paid" gorm:"type:decimal(18,4);default:0;index"`
+	TotalRefunded money.Money    `json:"total_refunded" gorm:"type:decimal(18,4);default:0"`
+	Status        uint8          `json:"status" gorm:"index;default:0"`
+	CreatedAt     time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Products      []Product      `gorm:"many2many:orders_carts"`
 	Billings      []OrderBilling
 	Details       []OrderDetail
+	Histories     []OrderStatusHistory
+	Transactions  []PaymentTransaction
+	Returns       []OrderReturn
 }
 
 func (Order) TableName() string {
 	return "orders"
 }
+
+func (order Order) StatusName() string {
+	return OrderStatusNames[order.Status]
+}
+
+// HoldsStock reports whether the order's lines have been taken out of inventory.
+func (order Order) HoldsStock() bool {
+	switch order.Status {
+	case OrderStatusPendingPayment, OrderStatusPaymentFailed, OrderStatusPaid, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered:
+		return true
+	}
+	return false
+}
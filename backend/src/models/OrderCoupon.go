@@ -0,0 +1,35 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"time"
+)
+
+// OrderCoupon is the coupon applied to an order. While the order is a
+// cart it can still be removed; once the order is checked out the row is
+// the redemption, with Amount set to the discount the coupon gave.
+type OrderCoupon struct {
+	Id        uint64      `json:"id" gorm:"primary_key"`
+	OrderId   uint64      `json:"order_id" gorm:"unique_index;not null"`
+	CouponId  uint64      `json:"coupon_id" gorm:"index;not null"`
+	UserId    uint64      `json:"user_id" gorm:"index;not null"`
+	Code      string      `json:"code" gorm:"size:64;not null"`
+	Amount    money.Money `json:"amount" gorm:"type:decimal(18,4);default:0"`
+	CreatedAt time.Time   `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time   `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (OrderCoupon) TableName() string {
+	return "orders_coupons"
+}
@@ -12,6 +12,7 @@
 package models
 
 import (
+	money "backend/src/money"
 	"time"
 )
 
@@ -21,9 +22,9 @@ type OrderDetail struct {
 	Order       Order            `gorm:"foreignKey:order_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
 	InventoryId uint64           `json:"inventory_id" gorm:"index;not null"`
 	Inventory   ProductInventory `gorm:"foreignKey:inventory_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	Price       float64          `json:"price" gorm:"type:decimal(18,4);default:0;index"`
+	Price       money.Money      `json:"price" gorm:"type:decimal(18,4);default:0;index"`
 	Qty         uint16           `json:"qty" gorm:"index;default:0"`
-	Total       float64          `json:"total" gorm:"type:decimal(18,4);default:0;index"`
+	Total       money.Money      `json:"total" gorm:"type:decimal(18,4);default:0;index"`
 	Status      uint8            `json:"status" gorm:"index;default:0"`
 	CreatedAt   time.Time        `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt   time.Time        `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
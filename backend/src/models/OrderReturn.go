@@ -0,0 +1,68 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"database/sql"
+	"time"
+)
+
+const (
+	ReturnStatusRequested = "requested"
+	ReturnStatusApproved  = "approved"
+	ReturnStatusRejected  = "rejected"
+	ReturnStatusReceived  = "received"
+	ReturnStatusRefunded  = "refunded"
+)
+
+// ReturnTransitions lists, for every return status, the statuses a return
+// may move to next.
+var ReturnTransitions = map[string][]string{
+	ReturnStatusRequested: {ReturnStatusApproved, ReturnStatusRejected},
+	ReturnStatusApproved:  {ReturnStatusReceived, ReturnStatusRejected},
+	ReturnStatusReceived:  {ReturnStatusRefunded},
+	ReturnStatusRejected:  {},
+	ReturnStatusRefunded:  {},
+}
+
+// OrderReturn is a customer's request to send lines of a delivered order
+// back. Staff approve it, receive the goods and refund RefundAmount.
+type OrderReturn struct {
+	Id           uint64               `json:"id" gorm:"primary_key"`
+	OrderId      uint64               `json:"order_id" gorm:"index;not null"`
+	UserId       uint64               `json:"user_id" gorm:"index;not null"`
+	Status       string               `json:"status" gorm:"index;size:20;not null"`
+	Reason       string               `json:"reason" gorm:"type:text;not null"`
+	Note         sql.NullString       `json:"note" gorm:"type:text"`
+	RefundAmount money.Money          `json:"refund_amount" gorm:"type:decimal(18,4);default:0"`
+	ApprovedAt   *time.Time           `json:"approved_at"`
+	ReceivedAt   *time.Time           `json:"received_at"`
+	RefundedAt   *time.Time           `json:"refunded_at"`
+	CreatedAt    time.Time            `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time            `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Lines        []OrderReturnLine    `json:"lines" gorm:"foreignkey:ReturnId"`
+	Histories    []OrderReturnHistory `json:"histories,omitempty" gorm:"foreignkey:ReturnId"`
+}
+
+func (OrderReturn) TableName() string {
+	return "orders_returns"
+}
+
+// Total is the value of the returned lines at the price they were sold at.
+func (orderReturn OrderReturn) Total() money.Money {
+	var total money.Money
+	for _, line := range orderReturn.Lines {
+		total += line.Price.Times(int64(line.Qty))
+	}
+	return total
+}
@@ -0,0 +1,34 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+// OrderReturnHistory records one step of a return and who took it.
+// FromStatus is empty for the customer's request that opened the return.
+type OrderReturnHistory struct {
+	Id         uint64    `json:"id" gorm:"primary_key"`
+	ReturnId   uint64    `json:"return_id" gorm:"index;not null"`
+	FromStatus string    `json:"from_status" gorm:"size:20;not null"`
+	ToStatus   string    `json:"to_status" gorm:"size:20;not null"`
+	ActorId    uint64    `json:"actor_id" gorm:"index;default:0"`
+	ActorType  string    `json:"actor_type" gorm:"size:50;not null"`
+	Note       string    `json:"note" gorm:"type:text;default null"`
+	CreatedAt  time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (OrderReturnHistory) TableName() string {
+	return "orders_returns_history"
+}
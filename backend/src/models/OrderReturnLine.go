@@ -0,0 +1,31 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+)
+
+// OrderReturnLine is a quantity of one order line sent back. Price is the
+// unit price the line was sold at.
+type OrderReturnLine struct {
+	Id          uint64      `json:"id" gorm:"primary_key"`
+	ReturnId    uint64      `json:"return_id" gorm:"index;not null"`
+	DetailId    uint64      `json:"detail_id" gorm:"index;not null"`
+	InventoryId uint64      `json:"inventory_id" gorm:"not null"`
+	Qty         uint16      `json:"qty" gorm:"default:0"`
+	Price       money.Money `json:"price" gorm:"type:decimal(18,4);default:0"`
+}
+
+func (OrderReturnLine) TableName() string {
+	return "orders_returns_lines"
+}
@@ -0,0 +1,33 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+type OrderStatusHistory struct {
+	Id         uint64    `json:"id" gorm:"primary_key"`
+	OrderId    uint64    `json:"order_id" gorm:"index;not null"`
+	Order      Order     `json:"-" gorm:"foreignKey:order_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	FromStatus uint8     `json:"from_status" gorm:"index;default:0"`
+	ToStatus   uint8     `json:"to_status" gorm:"index;default:0"`
+	ActorId    uint64    `json:"actor_id" gorm:"index;default:0"`
+	ActorType  string    `json:"actor_type" gorm:"index;size:50;not null"`
+	Reason     string    `json:"reason"  gorm:"type:text;default null"`
+	CreatedAt  time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
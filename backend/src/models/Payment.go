@@ -21,6 +21,7 @@ type Payment struct {
 	Image       sql.NullString `json:"image" gorm:"index;size:191;default:null;"`
 	Name        string         `json:"name" gorm:"index;size:255;not null"`
 	Description string         `json:"description"  gorm:"type:text;default null"`
+	Provider    string         `json:"provider" gorm:"size:50;not null;default:'manual'"`
 	Displayed   uint8          `json:"displayed" gorm:"index;default:0"`
 	Status      uint8          `json:"status" gorm:"index;default:0"`
 	CreatedAt   time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
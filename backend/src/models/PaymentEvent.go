@@ -0,0 +1,32 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PaymentEvent records a webhook event that has been processed, so a
+// provider delivering it again changes nothing.
+type PaymentEvent struct {
+	Id        uint64         `json:"id" gorm:"primary_key"`
+	Provider  string         `json:"provider" gorm:"size:50;not null"`
+	EventId   string         `json:"event_id" gorm:"size:191;not null"`
+	Type      string         `json:"type" gorm:"size:100;not null"`
+	Reference sql.NullString `json:"reference" gorm:"size:191"`
+	CreatedAt time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (PaymentEvent) TableName() string {
+	return "payments_events"
+}
@@ -0,0 +1,40 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"database/sql"
+	"time"
+)
+
+// PaymentTransaction is one attempt at collecting an order's total through
+// a payment provider. Reference is the provider's own id for the attempt.
+type PaymentTransaction struct {
+	Id          uint64         `json:"id" gorm:"primary_key"`
+	OrderId     uint64         `json:"order_id" gorm:"index;not null"`
+	PaymentId   uint64         `json:"payment_id" gorm:"not null"`
+	Provider    string         `json:"provider" gorm:"size:50;not null"`
+	Reference   string         `json:"reference" gorm:"size:191;not null"`
+	Amount      money.Money    `json:"amount" gorm:"type:decimal(18,4);default:0"`
+	Refunded    money.Money    `json:"refunded" gorm:"type:decimal(18,4);default:0"`
+	Currency    string         `json:"currency" gorm:"size:3;not null"`
+	Status      string         `json:"status" gorm:"index;size:20;not null"`
+	RedirectURL sql.NullString `json:"redirect_url" gorm:"size:2048"`
+	Error       sql.NullString `json:"error" gorm:"size:255"`
+	CreatedAt   time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (PaymentTransaction) TableName() string {
+	return "payments_transactions"
+}
@@ -0,0 +1,41 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	PermissionCatalogManage = "catalog.manage"
+	PermissionOrdersViewAny = "orders.view_any"
+	PermissionOrdersManage  = "orders.manage"
+	PermissionOrdersRefund  = "orders.refund"
+	PermissionUsersView     = "users.view"
+	PermissionUsersManage   = "users.manage"
+	PermissionRolesManage   = "roles.manage"
+	PermissionCouponsManage = "coupons.manage"
+	PermissionReviewsManage = "reviews.manage"
+)
+
+type Permission struct {
+	Id          uint64    `json:"id" gorm:"primary_key"`
+	Name        string    `json:"name" gorm:"unique_index;size:100;not null"`
+	Description string    `json:"description"  gorm:"type:text;default null"`
+	CreatedAt   time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Roles       []Role    `json:"-" gorm:"many2many:roles_permissions"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
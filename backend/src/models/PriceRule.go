@@ -0,0 +1,69 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"time"
+)
+
+const (
+	PriceRuleDiscount = "discount"
+	PriceRuleShipment = "shipment"
+
+	PriceScopeOrder    = "order"
+	PriceScopeProduct  = "product"
+	PriceScopeCategory = "category"
+	PriceScopeBrand    = "brand"
+
+	PriceCalculationPercent = "percent"
+	PriceCalculationFixed   = "fixed"
+)
+
+// PriceRule is a discount or a shipment charge. Discounts scoped to a
+// product, category or brand adjust the matching cart lines; order scoped
+// rules adjust the whole order. A rule only applies between StartsAt and
+// EndsAt, and once the order subtotal reaches MinSubtotal.
+type PriceRule struct {
+	Id          uint64      `json:"id" gorm:"primary_key"`
+	Name        string      `json:"name" gorm:"size:255;not null"`
+	Kind        string      `json:"kind" gorm:"index;size:20;not null"`
+	Scope       string      `json:"scope" gorm:"size:20;not null;default:'order'"`
+	TargetId    *uint64     `json:"target_id"`
+	Calculation string      `json:"calculation" gorm:"size:20;not null;default:'percent'"`
+	Value       money.Money `json:"value" gorm:"type:decimal(18,4);default:0"`
+	MinSubtotal money.Money `json:"min_subtotal" gorm:"type:decimal(18,4);default:0"`
+	Priority    uint16      `json:"priority" gorm:"index;default:0"`
+	StartsAt    *time.Time  `json:"starts_at" gorm:"index"`
+	EndsAt      *time.Time  `json:"ends_at" gorm:"index"`
+	Status      uint8       `json:"status" gorm:"index;default:0"`
+	CreatedAt   time.Time   `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (PriceRule) TableName() string {
+	return "price_rules"
+}
+
+// ActiveAt reports whether the rule is enabled and inside its time window.
+func (rule PriceRule) ActiveAt(at time.Time) bool {
+	if rule.Status != 1 {
+		return false
+	}
+	if rule.StartsAt != nil && at.Before(*rule.StartsAt) {
+		return false
+	}
+	if rule.EndsAt != nil && !at.Before(*rule.EndsAt) {
+		return false
+	}
+	return true
+}
@@ -12,34 +12,68 @@
 package models
 
 import (
+	money "backend/src/money"
 	"database/sql"
+	"math"
 	"time"
 )
 
 type Product struct {
-	Id          uint64         `json:"id" gorm:"primary_key"`
-	BrandId     uint64         `json:"brand_id" gorm:"index;not null"`
-	Brand       Brand          `json:"-" gorm:"foreignKey:brand_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	Image       sql.NullString `json:"image" gorm:"index;size:191;default:null;"`
-	Sku         string         `json:"sku" gorm:"index;size:100;not null"`
-	Name        string         `json:"name" gorm:"index;size:255;not null"`
-	Price       float64        `json:"price" gorm:"type:decimal(18,4);default:0;index"`
-	TotalOrder  uint16         `json:"total_order" gorm:"index;default:0"`
-	TotalRating uint16         `json:"total_rating" gorm:"index;default:0"`
-	Description string         `json:"description"  gorm:"type:text;default null"`
-	Details     string         `json:"details"  gorm:"type:text;default null"`
-	Status      uint8          `json:"status" gorm:"index;default:0"`
-	PublishedAt *time.Time     `json:"published_at" gorm:"index"`
-	CreatedAt   time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	Categories  []Category     `gorm:"many2many:products_categories"`
-	Orders      []Order        `gorm:"many2many:orders_carts"`
-	Users       []User         `gorm:"many2many:products_wishlists"`
-	Images      []ProductImage
-	Inventories []ProductInventory
-	Reviews     []ProductReview
+	Id            uint64         `json:"id" gorm:"primary_key"`
+	BrandId       uint64         `json:"brand_id" gorm:"index;not null"`
+	Brand         Brand          `json:"-" gorm:"foreignKey:brand_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Image         sql.NullString `json:"image" gorm:"index;size:191;default:null;"`
+	Sku           string         `json:"sku" gorm:"index;size:100;not null"`
+	Name          string         `json:"name" gorm:"index;size:255;not null"`
+	Price         money.Money    `json:"price" gorm:"type:decimal(18,4);default:0;index"`
+	TotalOrder    uint16         `json:"total_order" gorm:"index;default:0"`
+	TotalRating   uint16         `json:"total_rating" gorm:"index;default:0"`
+	RatingAverage float64        `json:"rating_average" gorm:"type:decimal(3,2);index;default:0"`
+	RatingCount   uint32         `json:"rating_count" gorm:"default:0"`
+	Rating1       uint32         `json:"rating_1" gorm:"column:rating_1;default:0"`
+	Rating2       uint32         `json:"rating_2" gorm:"column:rating_2;default:0"`
+	Rating3       uint32         `json:"rating_3" gorm:"column:rating_3;default:0"`
+	Rating4       uint32         `json:"rating_4" gorm:"column:rating_4;default:0"`
+	Rating5       uint32         `json:"rating_5" gorm:"column:rating_5;default:0"`
+	Description   string         `json:"description"  gorm:"type:text;default null"`
+	Details       string         `json:"details"  gorm:"type:text;default null"`
+	Status        uint8          `json:"status" gorm:"index;default:0"`
+	PublishedAt   *time.Time     `json:"published_at" gorm:"index"`
+	CreatedAt     time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Categories    []Category     `gorm:"many2many:products_categories"`
+	Orders        []Order        `gorm:"many2many:orders_carts"`
+	Users         []User         `gorm:"many2many:products_wishlists"`
+	Images        []ProductImage
+	Inventories   []ProductInventory
+	Reviews       []ProductReview
 }
 
 func (Product) TableName() string {
 	return "products"
 }
+
+// Stars is the number of approved reviews giving the product each number
+// of stars, one star first.
+func (product Product) Stars() [5]uint32 {
+	return [5]uint32{product.Rating1, product.Rating2, product.Rating3, product.Rating4, product.Rating5}
+}
+
+// SetStars replaces the product's rating aggregates with those of the
+// given star counts: the number of ratings, their sum and their average.
+func (product *Product) SetStars(stars [5]uint32) {
+
+	var count, sum uint64
+	for i, total := range stars {
+		count += uint64(total)
+		sum += uint64(total) * uint64(i+1)
+	}
+
+	product.Rating1, product.Rating2, product.Rating3, product.Rating4, product.Rating5 = stars[0], stars[1], stars[2], stars[3], stars[4]
+	product.RatingCount = uint32(count)
+	product.TotalRating = uint16(min(sum, math.MaxUint16))
+	product.RatingAverage = 0
+	if count > 0 {
+		product.RatingAverage = math.Round(float64(sum)/float64(count)*100) / 100
+	}
+}
@@ -12,22 +12,53 @@
 package models
 
 import (
+	"database/sql"
 	"time"
 )
 
+const (
+	ReviewStatusPending  uint8 = 0
+	ReviewStatusApproved uint8 = 1
+	ReviewStatusRejected uint8 = 2
+)
+
+// ReviewStatusNames maps every review status to the name the moderation
+// queue is filtered by.
+var ReviewStatusNames = map[uint8]string{
+	ReviewStatusPending:  "pending",
+	ReviewStatusApproved: "approved",
+	ReviewStatusRejected: "rejected",
+}
+
+const (
+	ReviewRatingMin = 1
+	ReviewRatingMax = 5
+)
+
+// ProductReview is a customer's rating of a product. A customer has one
+// review per product; editing it sends it back to moderation, and only
+// approved reviews are listed and counted in the product's rating.
 type ProductReview struct {
-	Id        uint64    `json:"id" gorm:"primary_key"`
-	ProductId uint64    `json:"product_id" gorm:"index;not null"`
-	Product   Product   `gorm:"foreignKey:product_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	UserId    uint64    `json:"user_id" gorm:"index;not null"`
-	User      User      `gorm:"foreignKey:user_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
-	Rating    uint16    `json:"rating" gorm:"index;default:0"`
-	Review    string    `json:"review"  gorm:"type:text;default null"`
-	Status    uint8     `json:"status" gorm:"index;default:0"`
-	CreatedAt time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Id             uint64         `json:"id" gorm:"primary_key"`
+	ProductId      uint64         `json:"product_id" gorm:"index;not null"`
+	Product        Product        `gorm:"foreignKey:product_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	UserId         uint64         `json:"user_id" gorm:"index;not null"`
+	User           User           `gorm:"foreignKey:user_id;references:id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Rating         uint16         `json:"rating" gorm:"index;default:0"`
+	Review         string         `json:"review"  gorm:"type:text;default null"`
+	Verified       bool           `json:"verified" gorm:"default:false"`
+	HelpfulCount   uint32         `json:"helpful_count" gorm:"default:0"`
+	Status         uint8          `json:"status" gorm:"index;default:0"`
+	ModerationNote sql.NullString `json:"moderation_note" gorm:"type:text"`
+	ModeratedAt    *time.Time     `json:"moderated_at"`
+	CreatedAt      time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 func (ProductReview) TableName() string {
 	return "products_reviews"
 }
+
+func (review ProductReview) StatusName() string {
+	return ReviewStatusNames[review.Status]
+}
@@ -0,0 +1,28 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+// ProductReviewVote marks a review as helpful to one customer.
+type ProductReviewVote struct {
+	Id        uint64    `json:"id" gorm:"primary_key"`
+	ReviewId  uint64    `json:"review_id" gorm:"not null"`
+	UserId    uint64    `json:"user_id" gorm:"index;not null"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (ProductReviewVote) TableName() string {
+	return "products_reviews_votes"
+}
@@ -0,0 +1,38 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	"time"
+)
+
+const (
+	RoleCustomer       = "customer"
+	RoleSupport        = "support"
+	RoleCatalogManager = "catalog-manager"
+	RoleAdmin          = "admin"
+)
+
+type Role struct {
+	Id          uint64       `json:"id" gorm:"primary_key"`
+	Name        string       `json:"name" gorm:"unique_index;size:100;not null"`
+	Description string       `json:"description"  gorm:"type:text;default null"`
+	Status      uint8        `json:"status" gorm:"index;default:1"`
+	CreatedAt   time.Time    `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:roles_permissions"`
+	Users       []User       `json:"-" gorm:"many2many:users_roles"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
@@ -0,0 +1,34 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package models
+
+import (
+	money "backend/src/money"
+	"time"
+)
+
+// TaxRate is a percentage charged on orders billed to Country, narrowed to
+// zip codes starting with ZipPrefix. Empty fields match every address.
+type TaxRate struct {
+	Id        uint64     `json:"id" gorm:"primary_key"`
+	Name      string     `json:"name" gorm:"size:255;not null"`
+	Country   string     `json:"country" gorm:"index;size:191;not null;default:''"`
+	ZipPrefix string     `json:"zip_prefix" gorm:"index;size:64;not null;default:''"`
+	Rate      money.Rate `json:"rate" gorm:"type:decimal(18,4);default:0"`
+	Status    uint8      `json:"status" gorm:"index;default:0"`
+	CreatedAt time.Time  `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (TaxRate) TableName() string {
+	return "tax_rates"
+}
@@ -20,8 +20,8 @@ type User struct {
 	Id              uint64         `json:"id" gorm:"primary_key"`
 	Email           string         `json:"email" gorm:"index;size:191;not null"`
 	Phone           string         `json:"phone" gorm:"index;size:191;default:null"`
-	Password        string         `json:"password" gorm:"index;size:255;not null"`
-	Salt            string         `json:"salt" gorm:"index;size:255;"`
+	Password        string         `json:"-" gorm:"index;size:255;not null"`
+	Salt            string         `json:"-" gorm:"index;size:255;"`
 	Image           sql.NullString `json:"image" gorm:"index;size:191;default:null;"`
 	FirstName       sql.NullString `json:"first_name" gorm:"index;size:191;default:null;"`
 	LastName        sql.NullString `json:"last_name" gorm:"index;size:191;default:null;"`
@@ -34,6 +34,7 @@ type User struct {
 	CreatedAt       time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt       time.Time      `gorm:"index;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	Products        []Product      `gorm:"many2many:products_wishlists"`
+	Roles           []Role         `json:"-" gorm:"many2many:users_roles"`
 	Activities      []Activity
 	Authentications []Authentication
 	Reviews         []ProductReview
@@ -0,0 +1,66 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package money
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// DefaultCurrency is used until SetDefault is called.
+const DefaultCurrency Currency = "USD"
+
+// minorDigits lists the currencies whose minor unit is not a cent.
+var minorDigits = map[Currency]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+var current atomic.Value
+
+// Digits is the number of decimals of the currency's minor unit.
+func (c Currency) Digits() int {
+	if digits, ok := minorDigits[c]; ok {
+		return digits
+	}
+	return 2
+}
+
+// Default is the store currency every amount is rounded and formatted in.
+func Default() Currency {
+	if c, ok := current.Load().(Currency); ok {
+		return c
+	}
+	return DefaultCurrency
+}
+
+// SetDefault changes the store currency. code must be three letters.
+func SetDefault(code string) error {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if len(c) != 3 || strings.Trim(string(c), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("money: invalid currency code %q", code)
+	}
+	current.Store(c)
+	return nil
+}
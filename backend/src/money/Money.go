@@ -0,0 +1,220 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+// Package money holds amounts as fixed-point integers so that prices,
+// discounts and totals add up exactly.
+//
+// Every amount is kept to Scale decimal places, the precision of the
+// decimal(18,4) columns it is stored in. Adding and multiplying by a
+// quantity are exact. Taking a percentage rounds half away from zero to
+// Scale places, and charges derived from percentages are then rounded to
+// the minor unit of the store currency with Round.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places amounts are held to.
+const Scale = 4
+
+const unit = 10000
+
+// Money is an amount of the store currency in ten-thousandths.
+type Money int64
+
+// FromInt returns n whole units of the currency.
+func FromInt(n int64) Money {
+	return Money(n * unit)
+}
+
+// Parse reads a decimal such as "12", "-0.5" or "19.99". Digits beyond
+// Scale are rounded half away from zero.
+func Parse(s string) (Money, error) {
+	v, err := parseFixed(s)
+	return Money(v), err
+}
+
+// MustParse is Parse for constants; it panics on malformed input.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Times multiplies m by a quantity.
+func (m Money) Times(qty int64) Money {
+	return m * Money(qty)
+}
+
+// Percent returns rate percent of m, rounded half away from zero to Scale
+// places.
+func (m Money) Percent(rate Rate) Money {
+	return Money(mulDiv(int64(m), int64(rate), 100*unit))
+}
+
+// Round rounds m half away from zero to the minor unit of currency.
+func (m Money) Round(currency Currency) Money {
+	return Money(roundTo(int64(m), step(currency.Digits())))
+}
+
+// String formats m with the minor digits of the default currency.
+func (m Money) String() string {
+	currency := Default()
+	return formatFixed(int64(m.Round(currency)), currency.Digits())
+}
+
+// MarshalJSON writes m as a string so clients never see it as a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts an amount as a string or as a plain number.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Value stores m at full precision.
+func (m Money) Value() (driver.Value, error) {
+	return formatFixed(int64(m), Scale), nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	v, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
+
+func parseFixed(s string) (int64, error) {
+
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimLeft(text, "+-")
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<63-1)/unit-1 {
+		return 0, fmt.Errorf("money: amount %q is out of range", s)
+	}
+
+	roundUp := len(fraction) > Scale && fraction[Scale] >= '5'
+	fraction = (fraction + strings.Repeat("0", Scale))[:Scale]
+	parts, _ := strconv.ParseInt(fraction, 10, 64)
+
+	v := units*unit + parts
+	if roundUp {
+		v++
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatFixed prints v, held to Scale places, with the given number of
+// decimals. Extra places are cut, so v should be rounded first.
+func formatFixed(v int64, digits int) string {
+
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	text := sign + strconv.FormatInt(v/unit, 10)
+	if digits > 0 {
+		text += "." + fmt.Sprintf("%04d", v%unit)[:digits]
+	}
+	return text
+}
+
+func unmarshalFixed(data []byte) (int64, error) {
+	text := string(data)
+	if text == "null" {
+		return 0, nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	return parseFixed(text)
+}
+
+func scanFixed(src interface{}) (int64, error) {
+	switch value := src.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return value * unit, nil
+	case float64:
+		return parseFixed(strconv.FormatFloat(value, 'f', -1, 64))
+	case []byte:
+		return parseFixed(string(value))
+	case string:
+		return parseFixed(value)
+	}
+	return 0, fmt.Errorf("money: cannot scan %T", src)
+}
+
+// step is the size of one minor unit at the given number of decimals.
+func step(digits int) int64 {
+	s := int64(1)
+	for i := digits; i < Scale; i++ {
+		s *= 10
+	}
+	return s
+}
+
+// roundTo rounds v half away from zero to a multiple of step.
+func roundTo(v int64, step int64) int64 {
+	rest := v % step
+	v -= rest
+	if rest < 0 && -rest*2 >= step {
+		v -= step
+	} else if rest > 0 && rest*2 >= step {
+		v += step
+	}
+	return v
+}
+
+// mulDiv returns a*b/d rounded half away from zero, without overflowing
+// on the intermediate product.
+func mulDiv(a int64, b int64, d int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	divisor := big.NewInt(d)
+	quotient, rest := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	twice := new(big.Int).Lsh(rest.Abs(rest), 1)
+	if twice.CmpAbs(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign()*divisor.Sign())))
+	}
+	return quotient.Int64()
+}
@@ -0,0 +1,157 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"12", 120000},
+		{"0.1", 1000},
+		{".5", 5000},
+		{"-19.99", -199900},
+		{" 1.00005 ", 10001},
+		{"1.00004", 10000},
+		{"-1.00005", -10001},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.in)
+		if err != nil || got != test.want {
+			t.Errorf("Parse(%q) = %d, %v, want %d", test.in, got, err, test.want)
+		}
+	}
+
+	for _, in := range []string{"", "-", ".", "1.2.3", "1e3", "abc", "99999999999999999999"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+// Ten cents added ten times is exactly a dollar, which it is not in float64.
+func TestAddIsExact(t *testing.T) {
+	var sum Money
+	for i := 0; i < 10; i++ {
+		sum += MustParse("0.1")
+	}
+	if sum != FromInt(1) {
+		t.Errorf("sum = %v, want 1", sum)
+	}
+}
+
+func TestPercentAndRound(t *testing.T) {
+
+	tests := []struct {
+		amount string
+		rate   string
+		want   string
+	}{
+		{"150", "8.55", "12.83"},
+		{"237.5", "10", "23.75"},
+		{"0.05", "50", "0.03"},
+		{"-0.05", "50", "-0.03"},
+		{"150", "3.333", "5.00"},
+		{"99999999999", "100", "99999999999.00"},
+	}
+
+	for _, test := range tests {
+		got := MustParse(test.amount).Percent(MustParseRate(test.rate)).Round("USD")
+		if got.String() != test.want {
+			t.Errorf("%s%% of %s = %s, want %s", test.rate, test.amount, got, test.want)
+		}
+	}
+
+	if got := MustParse("1234.5").Round("JPY"); got != FromInt(1235) {
+		t.Errorf("1234.5 in JPY = %v, want 1235", got)
+	}
+	if got := MustParse("1.2345").Round("KWD"); got != MustParse("1.235") {
+		t.Errorf("1.2345 in KWD = %v, want 1.235", got)
+	}
+}
+
+func TestRateOf(t *testing.T) {
+	if got := RateOf(MustParse("12.5"), FromInt(250)); got != MustParseRate("5") {
+		t.Errorf("RateOf(12.5, 250) = %v, want 5", got)
+	}
+	if got := RateOf(FromInt(1), FromInt(3)); got.String() != "33.33" {
+		t.Errorf("RateOf(1, 3) = %v, want 33.33", got)
+	}
+	if got := RateOf(FromInt(1), 0); got != 0 {
+		t.Errorf("RateOf(1, 0) = %v, want 0", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+
+	data, err := json.Marshal(struct {
+		Price Money `json:"price"`
+		Rate  Rate  `json:"rate"`
+	}{MustParse("19.9"), MustParseRate("7.25")})
+	if err != nil || string(data) != `{"price":"19.90","rate":7.25}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+
+	var in struct {
+		A Money `json:"a"`
+		B Money `json:"b"`
+		C Money `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"19.90","b":15,"c":null}`), &in); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if in.A != MustParse("19.9") || in.B != FromInt(15) || in.C != 0 {
+		t.Errorf("Unmarshal = %+v", in)
+	}
+}
+
+func TestScanAndValue(t *testing.T) {
+
+	for _, src := range []interface{}{[]byte("969.0000"), "969", int64(969), float64(969)} {
+		var m Money
+		if err := m.Scan(src); err != nil || m != FromInt(969) {
+			t.Errorf("Scan(%#v) = %v, %v, want 969", src, m, err)
+		}
+	}
+
+	var m Money
+	if err := m.Scan(float64(0.1) + float64(0.2)); err != nil || m != MustParse("0.3") {
+		t.Errorf("Scan(0.1 + 0.2) = %v, %v, want 0.3", m, err)
+	}
+
+	value, err := MustParse("-12.3456").Value()
+	if err != nil || value != "-12.3456" {
+		t.Errorf("Value = %v, %v, want the full precision", value, err)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+
+	defer SetDefault(string(Default()))
+
+	if err := SetDefault(" jpy "); err != nil || Default() != "JPY" {
+		t.Fatalf("SetDefault(jpy) = %v, default %s", err, Default())
+	}
+	if got := MustParse("1234.5").String(); got != "1235" {
+		t.Errorf("1234.5 in JPY = %s, want 1235", got)
+	}
+	if err := SetDefault("dollars"); err == nil || Default() != "JPY" {
+		t.Errorf("SetDefault(dollars) = %v, default %s, want an error and no change", err, Default())
+	}
+}
@@ -0,0 +1,80 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package money
+
+import (
+	"database/sql/driver"
+	"strings"
+)
+
+// Rate is a percentage held to Scale decimal places, so 10% is
+// Rate(100000). It shares the representation of Money: a price rule
+// value read as Money can be converted to a Rate when it is a percentage.
+type Rate int64
+
+// ParseRate reads a percentage such as "10" or "7.25".
+func ParseRate(s string) (Rate, error) {
+	v, err := parseFixed(s)
+	return Rate(v), err
+}
+
+// MustParseRate is ParseRate for constants; it panics on malformed input.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// RateOf returns part as a percentage of whole, to two decimals. A zero
+// whole gives a zero rate.
+func RateOf(part Money, whole Money) Rate {
+	if whole == 0 {
+		return 0
+	}
+	return Rate(roundTo(mulDiv(int64(part), 100*unit, int64(whole)), step(2)))
+}
+
+// String formats r without trailing zeros.
+func (r Rate) String() string {
+	text := formatFixed(int64(r), Scale)
+	text = strings.TrimRight(text, "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+// MarshalJSON writes r as a plain number; rates are not amounts.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*r = Rate(v)
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return formatFixed(int64(r), Scale), nil
+}
+
+func (r *Rate) Scan(src interface{}) error {
+	v, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*r = Rate(v)
+	return nil
+}
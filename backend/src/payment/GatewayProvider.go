@@ -0,0 +1,243 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package payment
+
+import (
+	money "backend/src/money"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the gateway's webhook signature.
+const SignatureHeader = "Payment-Signature"
+
+// SignatureTolerance is how old a signed webhook may be before it is
+// refused as a replay.
+const SignatureTolerance = 5 * time.Minute
+
+type gatewayProvider struct {
+	baseURL string
+	key     string
+	secret  string
+	client  *http.Client
+}
+
+// Gateway talks to a card gateway that hands out a hosted payment page
+// for every intent and reports the outcome with signed webhooks.
+func Gateway(baseURL string, key string, secret string, timeout time.Duration) Provider {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &gatewayProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		key:     key,
+		secret:  secret,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *gatewayProvider) Name() string {
+	return ProviderGateway
+}
+
+type gatewayIntentRequest struct {
+	Amount        money.Money    `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	Reference     string         `json:"reference"`
+	CustomerEmail string         `json:"customer_email"`
+	ReturnURL     string         `json:"return_url"`
+}
+
+type gatewayIntent struct {
+	Id          string `json:"id"`
+	Status      string `json:"status"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+type gatewayError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *gatewayProvider) CreateIntent(request Request) (Intent, error) {
+
+	body, err := json.Marshal(gatewayIntentRequest{
+		Amount:        request.Amount,
+		Currency:      request.Currency,
+		Reference:     request.InvoiceNumber,
+		CustomerEmail: request.Email,
+		ReturnURL:     request.ReturnURL,
+	})
+	if err != nil {
+		return Intent{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.baseURL+"/v1/payment_intents", bytes.NewReader(body))
+	if err != nil {
+		return Intent{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.key)
+	if request.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", request.IdempotencyKey)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return Intent{}, fmt.Errorf("payment gateway: %w", err)
+	}
+	defer res.Body.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return Intent{}, fmt.Errorf("payment gateway: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var failure gatewayError
+		if json.Unmarshal(payload, &failure) == nil && failure.Error.Message != "" {
+			return Intent{}, fmt.Errorf("payment gateway: %s", failure.Error.Message)
+		}
+		return Intent{}, fmt.Errorf("payment gateway: unexpected status %d", res.StatusCode)
+	}
+
+	var intent gatewayIntent
+	if err := json.Unmarshal(payload, &intent); err != nil {
+		return Intent{}, fmt.Errorf("payment gateway: %w", err)
+	}
+	if intent.Id == "" {
+		return Intent{}, errors.New("payment gateway: intent without an id")
+	}
+
+	status := StatusPending
+	switch intent.Status {
+	case "succeeded":
+		status = StatusSucceeded
+	case "failed", "canceled":
+		status = StatusFailed
+	}
+
+	return Intent{Reference: intent.Id, Status: status, RedirectURL: intent.RedirectURL}, nil
+}
+
+type gatewayEvent struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Id             string         `json:"id"`
+		Amount         money.Money    `json:"amount"`
+		Currency       money.Currency `json:"currency"`
+		FailureMessage string         `json:"failure_message"`
+	} `json:"data"`
+}
+
+// ParseWebhook verifies the signature and decodes the event. Event types
+// other than success and failure keep the gateway's own name so callers
+// can ignore them.
+func (p *gatewayProvider) ParseWebhook(payload []byte, signature string) (Event, error) {
+
+	if err := Verify(p.secret, payload, signature, time.Now()); err != nil {
+		return Event{}, err
+	}
+
+	var raw gatewayEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if raw.Id == "" || raw.Data.Id == "" {
+		return Event{}, fmt.Errorf("%w: event without an id", ErrInvalidPayload)
+	}
+
+	event := Event{
+		Id:        raw.Id,
+		Type:      raw.Type,
+		Reference: raw.Data.Id,
+		Amount:    raw.Data.Amount,
+		Currency:  money.Currency(strings.ToUpper(string(raw.Data.Currency))),
+		Reason:    raw.Data.FailureMessage,
+	}
+	switch raw.Type {
+	case "payment_intent.succeeded":
+		event.Type = EventSucceeded
+	case "payment_intent.payment_failed":
+		event.Type = EventFailed
+	}
+
+	return event, nil
+}
+
+// Sign returns the signature header value for payload sent at the given
+// time: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<payload>">".
+func Sign(secret string, payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + digest(secret, timestamp, payload)
+}
+
+// Verify checks a signature made by Sign and refuses it when it is older
+// than SignatureTolerance.
+func Verify(secret string, payload []byte, signature string, now time.Time) error {
+
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := digest(secret, timestamp, payload)
+	for _, candidate := range signatures {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func digest(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
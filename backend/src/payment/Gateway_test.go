@@ -0,0 +1,165 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package payment
+
+import (
+	money "backend/src/money"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+// mockGateway answers intent requests the way the card gateway does and
+// remembers the body and headers of the last request it was sent.
+func mockGateway(t *testing.T, status int, response string) (*httptest.Server, *map[string]interface{}, *http.Header) {
+	t.Helper()
+
+	var received map[string]interface{}
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/payment_intents" {
+			t.Errorf("request %s %s, want POST /v1/payment_intents", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk_test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		headers = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &received, &headers
+}
+
+func TestGatewayCreateIntent(t *testing.T) {
+
+	server, received, headers := mockGateway(t, http.StatusOK, `{"id":"pi_123","status":"requires_action","redirect_url":"https://pay.test/pi_123"}`)
+	provider := Gateway(server.URL+"/", "sk_test", testSecret, time.Second)
+
+	intent, err := provider.CreateIntent(Request{
+		OrderId:        7,
+		InvoiceNumber:  "INV-0007",
+		Amount:         money.MustParse("19.5"),
+		Currency:       "USD",
+		Email:          "ada@example.com",
+		ReturnURL:      "http://shop.test/order/detail/7",
+		IdempotencyKey: "order-7-attempt-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateIntent: %v", err)
+	}
+
+	want := Intent{Reference: "pi_123", Status: StatusPending, RedirectURL: "https://pay.test/pi_123"}
+	if intent != want {
+		t.Errorf("intent = %+v, want %+v", intent, want)
+	}
+	if (*received)["amount"] != "19.50" || (*received)["reference"] != "INV-0007" || (*received)["currency"] != "USD" {
+		t.Errorf("request = %v", *received)
+	}
+	if got := headers.Get("Idempotency-Key"); got != "order-7-attempt-1" {
+		t.Errorf("Idempotency-Key = %q, want order-7-attempt-1", got)
+	}
+}
+
+func TestGatewayCreateIntentError(t *testing.T) {
+
+	server, _, _ := mockGateway(t, http.StatusPaymentRequired, `{"error":{"message":"card declined"}}`)
+	provider := Gateway(server.URL, "sk_test", testSecret, time.Second)
+
+	_, err := provider.CreateIntent(Request{InvoiceNumber: "INV-0008", Amount: money.FromInt(1), Currency: "USD"})
+	if err == nil || err.Error() != "payment gateway: card declined" {
+		t.Errorf("err = %v, want the gateway's message", err)
+	}
+}
+
+func TestGatewayParseWebhook(t *testing.T) {
+
+	provider := Gateway("http://gateway.test", "sk_test", testSecret, time.Second)
+
+	tests := []struct {
+		name string
+		body string
+		want Event
+	}{
+		{
+			name: "succeeded",
+			body: `{"id":"evt_1","type":"payment_intent.succeeded","data":{"id":"pi_1","amount":"19.50","currency":"usd"}}`,
+			want: Event{Id: "evt_1", Type: EventSucceeded, Reference: "pi_1", Amount: money.MustParse("19.5"), Currency: "USD"},
+		},
+		{
+			name: "failed",
+			body: `{"id":"evt_2","type":"payment_intent.payment_failed","data":{"id":"pi_1","amount":19.5,"currency":"USD","failure_message":"card declined"}}`,
+			want: Event{Id: "evt_2", Type: EventFailed, Reference: "pi_1", Amount: money.MustParse("19.5"), Currency: "USD", Reason: "card declined"},
+		},
+		{
+			name: "other types keep their name",
+			body: `{"id":"evt_3","type":"payment_intent.created","data":{"id":"pi_1","amount":"0"}}`,
+			want: Event{Id: "evt_3", Type: "payment_intent.created", Reference: "pi_1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			payload := []byte(test.body)
+			event, err := provider.ParseWebhook(payload, Sign(testSecret, payload, time.Now()))
+			if err != nil {
+				t.Fatalf("ParseWebhook: %v", err)
+			}
+			if event != test.want {
+				t.Errorf("event = %+v, want %+v", event, test.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1760000000, 0)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		ok        bool
+	}{
+		{"valid", testSecret, Sign(testSecret, payload, now), true},
+		{"within tolerance", testSecret, Sign(testSecret, payload, now.Add(-4*time.Minute)), true},
+		{"rotated secret listed second", testSecret, Sign("old", payload, now) + ",v1=" + digest(testSecret, "1760000000", payload), true},
+		{"other secret", testSecret, Sign("other", payload, now), false},
+		{"too old", testSecret, Sign(testSecret, payload, now.Add(-6*time.Minute)), false},
+		{"tampered payload", testSecret, Sign(testSecret, []byte(`{"id":"evt_2"}`), now), false},
+		{"missing timestamp", testSecret, "v1=" + digest(testSecret, "1760000000", payload), false},
+		{"empty", testSecret, "", false},
+		{"no secret configured", "", Sign("", payload, now), false},
+	}
+
+	for _, test := range tests {
+		err := Verify(test.secret, payload, test.signature, now)
+		if test.ok && err != nil {
+			t.Errorf("%s: Verify = %v, want nil", test.name, err)
+		}
+		if !test.ok && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidSignature", test.name, err)
+		}
+	}
+}
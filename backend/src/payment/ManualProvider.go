@@ -0,0 +1,32 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package payment
+
+type manualProvider struct{}
+
+// Manual is for offline payments such as bank transfers and cheques. The
+// intent stays pending until staff mark the order paid.
+func Manual() Provider {
+	return manualProvider{}
+}
+
+func (manualProvider) Name() string {
+	return ProviderManual
+}
+
+func (manualProvider) CreateIntent(request Request) (Intent, error) {
+	return Intent{Reference: "manual-" + request.InvoiceNumber, Status: StatusPending}, nil
+}
+
+func (manualProvider) ParseWebhook(payload []byte, signature string) (Event, error) {
+	return Event{}, ErrWebhookUnsupported
+}
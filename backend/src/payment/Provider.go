@@ -0,0 +1,103 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package payment
+
+import (
+	appconfig "backend/src/appconfig"
+	money "backend/src/money"
+	"errors"
+)
+
+// Provider names stored on payments.provider.
+const (
+	ProviderManual  = "manual"
+	ProviderGateway = "gateway"
+)
+
+// Intent and transaction statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Webhook event types.
+const (
+	EventSucceeded = "payment.succeeded"
+	EventFailed    = "payment.failed"
+)
+
+var (
+	ErrUnknownProvider    = errors.New("unknown payment provider")
+	ErrNotConfigured      = errors.New("payment provider is not configured")
+	ErrInvalidSignature   = errors.New("invalid webhook signature")
+	ErrInvalidPayload     = errors.New("invalid webhook payload")
+	ErrWebhookUnsupported = errors.New("payment provider does not send webhooks")
+)
+
+// Request asks a provider to collect the total of one order.
+// IdempotencyKey names the payment attempt; providers that support it
+// answer a repeated key with the intent they already created for it.
+type Request struct {
+	OrderId        uint64
+	InvoiceNumber  string
+	Amount         money.Money
+	Currency       money.Currency
+	Email          string
+	ReturnURL      string
+	IdempotencyKey string
+}
+
+// Intent is the provider's handle on a payment it was asked to collect.
+// RedirectURL is empty when the customer has nothing to visit, as with a
+// bank transfer.
+type Intent struct {
+	Reference   string
+	Status      string
+	RedirectURL string
+}
+
+// Event is a verified webhook notification about an intent.
+type Event struct {
+	Id        string
+	Type      string
+	Reference string
+	Amount    money.Money
+	Currency  money.Currency
+	Reason    string
+}
+
+// Provider collects payments. Implementations must be safe for concurrent
+// use.
+type Provider interface {
+	Name() string
+	CreateIntent(request Request) (Intent, error)
+	ParseWebhook(payload []byte, signature string) (Event, error)
+}
+
+// NewProvider returns the provider a payment row is set up with: "manual"
+// waits for staff to confirm an offline payment and "gateway" talks to the
+// card gateway at PAYMENT_GATEWAY_URL.
+func NewProvider(config *appconfig.Config, name string) (Provider, error) {
+	switch name {
+	case ProviderManual:
+		return Manual(), nil
+	case ProviderGateway:
+		settings := config.Payment
+		if settings.GatewayURL == "" {
+			return nil, ErrNotConfigured
+		}
+		return Gateway(settings.GatewayURL, settings.GatewayKey, settings.WebhookSecret, settings.Timeout), nil
+	default:
+		return nil, ErrUnknownProvider
+	}
+}
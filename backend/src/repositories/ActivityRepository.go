@@ -0,0 +1,45 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// activity repository
+type ActivityRepository interface {
+	Create(activity *models.Activity) error
+	List(userId uint64, query ListQuery) ([]models.Activity, error)
+}
+
+type activityRepository struct {
+	db *gorm.DB
+}
+
+func (r *activityRepository) Create(activity *models.Activity) error {
+	return r.db.Create(activity).Error
+}
+
+func (r *activityRepository) List(userId uint64, query ListQuery) ([]models.Activity, error) {
+
+	db := r.db.Select("id, event, subject, description, created_at").Where("user_id = ?", userId)
+
+	if len(query.Search) > 0 {
+		db = db.Where("(event LIKE ? OR description LIKE ? OR subject LIKE ?)", query.Search, query.Search, query.Search)
+	}
+
+	var data []models.Activity
+	err := db.Limit(query.Limit).Offset(query.Offset()).Order(query.Sort()).Find(&data).Error
+	return data, err
+}
@@ -0,0 +1,132 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// authentication repository
+type AuthenticationRepository interface {
+	Latest(userId uint64, authType string) (models.Authentication, error)
+	CountSince(userId uint64, authType string, since time.Time) (int64, error)
+	RevokePending(userId uint64, authType string) error
+	Create(row *models.Authentication) error
+	Lock(authType string, token string) (models.Authentication, error)
+	FindPending(authType string, token string, credential string) (models.Authentication, error)
+	Use(id uint64, expiredAt *time.Time) (bool, error)
+	RevokeFamily(familyId string) error
+	RevokeAll(userId uint64, authType string) error
+	FamilyLive(familyId string, at time.Time) (bool, error)
+	DeleteRefresh(at time.Time) (int64, error)
+	DeleteExpired(authTypes []string, before time.Time) (int64, error)
+}
+
+type authenticationRepository struct {
+	db *gorm.DB
+}
+
+func (r *authenticationRepository) Latest(userId uint64, authType string) (models.Authentication, error) {
+	var row models.Authentication
+	err := r.db.Where("user_id = ? AND auth_type = ?", userId, authType).Order("id desc").First(&row).Error
+	return row, notFound(err)
+}
+
+func (r *authenticationRepository) CountSince(userId uint64, authType string, since time.Time) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Authentication{}).Where("user_id = ? AND auth_type = ? AND created_at > ?", userId, authType, since).Count(&total).Error
+	return total, err
+}
+
+func (r *authenticationRepository) RevokePending(userId uint64, authType string) error {
+	return r.db.Model(&models.Authentication{}).
+		Where("user_id = ? AND auth_type = ? AND status = ?", userId, authType, models.AuthStatusPending).
+		UpdateColumn("status", models.AuthStatusRevoked).Error
+}
+
+func (r *authenticationRepository) Create(row *models.Authentication) error {
+	return r.db.Create(row).Error
+}
+
+// Lock holds the row of a token hash until the transaction ends, so two
+// requests presenting the same token are handled one after the other.
+func (r *authenticationRepository) Lock(authType string, token string) (models.Authentication, error) {
+	var row models.Authentication
+	err := database.ForUpdate(r.db).Where("auth_type = ? AND token = ?", authType, token).First(&row).Error
+	return row, notFound(err)
+}
+
+// FindPending returns the pending row of a token hash. When credential is
+// not empty it must match the address the token was issued to.
+func (r *authenticationRepository) FindPending(authType string, token string, credential string) (models.Authentication, error) {
+	var row models.Authentication
+	query := r.db.Where("auth_type = ? AND token = ? AND status = ?", authType, token, models.AuthStatusPending)
+	if credential != "" {
+		query = query.Where("credential = ?", credential)
+	}
+	err := query.First(&row).Error
+	return row, notFound(err)
+}
+
+// Use marks a pending row as used, ending its life at expiredAt when that
+// is set. It reports false when the row was no longer pending.
+func (r *authenticationRepository) Use(id uint64, expiredAt *time.Time) (bool, error) {
+	changes := map[string]interface{}{"status": models.AuthStatusUsed}
+	if expiredAt != nil {
+		changes["expired_at"] = *expiredAt
+	}
+	result := r.db.Model(&models.Authentication{}).
+		Where("id = ? AND status = ?", id, models.AuthStatusPending).
+		UpdateColumns(changes)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *authenticationRepository) RevokeFamily(familyId string) error {
+	return r.db.Model(&models.Authentication{}).
+		Where("auth_type = ? AND family_id = ?", models.AuthTypeRefreshToken, familyId).
+		UpdateColumn("status", models.AuthStatusRevoked).Error
+}
+
+func (r *authenticationRepository) RevokeAll(userId uint64, authType string) error {
+	return r.db.Model(&models.Authentication{}).
+		Where("auth_type = ? AND user_id = ?", authType, userId).
+		UpdateColumn("status", models.AuthStatusRevoked).Error
+}
+
+// FamilyLive reports whether the family still has a refresh token that is
+// pending or used and has not expired at the given time.
+func (r *authenticationRepository) FamilyLive(familyId string, at time.Time) (bool, error) {
+	var total int
+	err := r.db.Model(&models.Authentication{}).
+		Where("auth_type = ? AND family_id = ? AND status IN (?, ?) AND expired_at > ?", models.AuthTypeRefreshToken, familyId, models.AuthStatusPending, models.AuthStatusUsed, at).
+		Count(&total).Error
+	return total > 0, err
+}
+
+// DeleteRefresh deletes the refresh tokens that were revoked or had expired at the given time.
+func (r *authenticationRepository) DeleteRefresh(at time.Time) (int64, error) {
+	result := r.db.
+		Where("auth_type = ? AND (status = ? OR expired_at < ?)", models.AuthTypeRefreshToken, models.AuthStatusRevoked, at).
+		Delete(&models.Authentication{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *authenticationRepository) DeleteExpired(authTypes []string, before time.Time) (int64, error) {
+	result := r.db.
+		Where("auth_type IN (?) AND expired_at < ?", authTypes, before).
+		Delete(&models.Authentication{})
+	return result.RowsAffected, result.Error
+}
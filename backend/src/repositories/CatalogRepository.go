@@ -0,0 +1,510 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+	money "backend/src/money"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// NameCount is the number of published products under a category or brand.
+type NameCount struct {
+	Id    uint
+	Name  string
+	Total int64
+}
+
+// ProductQuery selects published products for the storefront.
+type ProductQuery struct {
+	OrderBy   string
+	Limit     int
+	ExcludeId uint64
+}
+
+// ProductFilter narrows the shop listing. Ids, when not nil, limits it to
+// those products; the search service fills it from the search index.
+type ProductFilter struct {
+	ListQuery
+	Categories []string
+	Brands     []string
+	Ids        []uint64
+}
+
+// Facets counts the products matching a filter under each category and
+// brand. Each count ignores the filter's own choice of that dimension, so
+// picking a brand still shows how many products the other brands have.
+type Facets struct {
+	Categories []NameCount
+	Brands     []NameCount
+}
+
+// catalog repository
+type CatalogRepository interface {
+	Product(id uint64) (models.Product, error)
+	PublishedProduct(id uint64) (models.Product, error)
+	Published(query ProductQuery) ([]models.Product, error)
+	Search(filter ProductFilter) ([]models.Product, int64, error)
+	SearchIds(filter ProductFilter) ([]uint64, error)
+	PublishedIn(ids []uint64) ([]models.Product, error)
+	Facets(filter ProductFilter) (Facets, error)
+	Indexable() ([]models.Product, error)
+	CountPublished() (int64, error)
+	PriceRange() (money.Money, money.Money, error)
+	Categories(displayedOnly bool, limit int) ([]models.Category, error)
+	CategoryCounts() ([]NameCount, error)
+	BrandCounts() ([]NameCount, error)
+	Images(productId uint64) ([]models.ProductImage, error)
+	Sizes() ([]models.Size, error)
+	Colours() ([]models.Colour, error)
+	Inventories(productId uint64) ([]models.ProductInventory, error)
+	FindInventory(productId uint64, sizeId uint64, colourId uint64) (models.ProductInventory, error)
+	LockInventory(id uint64) (models.ProductInventory, error)
+	TakeStock(inventoryId uint64, qty uint16) (bool, error)
+	ReturnStock(inventoryId uint64, qty uint16) error
+	AddOrdered(productId uint64, qty uint16) error
+	RemoveOrdered(productId uint64, qty uint16) error
+	SaveRatings(product models.Product) error
+	ProductIds() ([]uint64, error)
+	OrderedTotals(statuses []uint8) (map[uint64]uint64, error)
+	SetOrdered(productId uint64, total uint16) error
+	ProductPage(query ListQuery) ([]models.Product, int64, int64, error)
+	ProductComplete(id uint64) (models.Product, error)
+	SortedImages(productId uint64) ([]models.ProductImage, error)
+	SkuTaken(sku string, exceptId uint64) (bool, error)
+	CategoriesIn(ids []uint64) ([]models.Category, error)
+	ReplaceCategories(product *models.Product, categories []models.Category) error
+	AttachCategory(product *models.Product, category models.Category) error
+	DetachCategory(productId uint64, categoryId uint64) error
+	InventoryTaken(productId uint64, sizeId uint64, colourId uint64, exceptId uint64) (bool, error)
+	BrandInUse(id uint64) (bool, error)
+	ColourInUse(id uint64) (bool, error)
+	SizeInUse(id uint64) (bool, error)
+	ProductOrdered(productId uint64) (bool, error)
+	InventoryOrdered(inventoryId uint64) (bool, error)
+	DeleteCategory(id uint64) error
+	DeleteProduct(id uint64) error
+}
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func (r *catalogRepository) published() *gorm.DB {
+	return r.db.Where("status = 1 AND published_at <= ?", time.Now())
+}
+
+func (r *catalogRepository) Product(id uint64) (models.Product, error) {
+	var product models.Product
+	err := r.db.Where("id = ?", id).First(&product).Error
+	return product, notFound(err)
+}
+
+func (r *catalogRepository) PublishedProduct(id uint64) (models.Product, error) {
+	var product models.Product
+	err := r.published().Preload("Categories").Where("id = ?", id).First(&product).Error
+	return product, notFound(err)
+}
+
+func (r *catalogRepository) Published(query ProductQuery) ([]models.Product, error) {
+	db := r.published().Preload("Categories")
+	if query.ExcludeId > 0 {
+		db = db.Where("id != ?", query.ExcludeId)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	var products []models.Product
+	err := db.Order(query.OrderBy).Find(&products).Error
+	return products, err
+}
+
+// matching selects the published products that filter lets through,
+// leaving out its "categories" or "brands" condition when except names it.
+func (r *catalogRepository) matching(filter ProductFilter, except string) *gorm.DB {
+
+	db := r.published().Model(&models.Product{})
+
+	if len(filter.Categories) > 0 && except != "categories" {
+		db = db.Where("products.id IN (?)", r.db.Table("products_categories").Select("product_id").Where("category_id IN (?)", filter.Categories).SubQuery())
+	}
+
+	if len(filter.Brands) > 0 && except != "brands" {
+		db = db.Where("products.brand_id IN (?)", filter.Brands)
+	}
+
+	if filter.Ids != nil {
+		if len(filter.Ids) == 0 {
+			return db.Where("1 = 0")
+		}
+		db = db.Where("products.id IN (?)", filter.Ids)
+	}
+
+	return db
+}
+
+// Search returns one page of published products matching filter together
+// with the number of products that match it across all pages.
+func (r *catalogRepository) Search(filter ProductFilter) ([]models.Product, int64, error) {
+
+	db := r.matching(filter, "").Preload("Categories")
+
+	var total int64
+	if err := db.Model(&models.Product{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sort := filter.Sort()
+	if filter.OrderBy == "rating_average" {
+		// Of two products with the same average, the one more customers
+		// rated comes first.
+		sort += ", rating_count " + filter.OrderDir
+	}
+
+	var products []models.Product
+	err := db.Limit(filter.Limit).Offset(filter.Offset()).Order(sort).Find(&products).Error
+	return products, total, err
+}
+
+// SearchIds returns the ids of every published product matching filter.
+func (r *catalogRepository) SearchIds(filter ProductFilter) ([]uint64, error) {
+	var ids []uint64
+	err := r.matching(filter, "").Pluck("products.id", &ids).Error
+	return ids, err
+}
+
+// PublishedIn returns those of the given products that are published, in
+// no particular order.
+func (r *catalogRepository) PublishedIn(ids []uint64) ([]models.Product, error) {
+	var products []models.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.published().Preload("Categories").Where("id IN (?)", ids).Find(&products).Error
+	return products, err
+}
+
+func (r *catalogRepository) Facets(filter ProductFilter) (Facets, error) {
+
+	var facets Facets
+
+	err := r.db.Table("categories").
+		Select("categories.id, categories.name, COUNT(*) AS total").
+		Joins("INNER JOIN products_categories ON products_categories.category_id = categories.id").
+		Where("products_categories.product_id IN (?)", r.matching(filter, "categories").Select("products.id").SubQuery()).
+		Group("categories.id, categories.name").
+		Order("categories.name asc").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return facets, err
+	}
+
+	err = r.db.Table("brands").
+		Select("brands.id, brands.name, COUNT(*) AS total").
+		Joins("INNER JOIN products ON products.brand_id = brands.id").
+		Where("products.id IN (?)", r.matching(filter, "brands").Select("products.id").SubQuery()).
+		Group("brands.id, brands.name").
+		Order("brands.name asc").
+		Scan(&facets.Brands).Error
+
+	return facets, err
+}
+
+// Indexable returns the products the search index covers, with the brand
+// and categories it indexes them under. Products scheduled for later are
+// included; Search only ever shows those already published.
+func (r *catalogRepository) Indexable() ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("status = 1").Preload("Brand").Preload("Categories").Order("id asc").Find(&products).Error
+	return products, err
+}
+
+func (r *catalogRepository) CountPublished() (int64, error) {
+	var total int64
+	err := r.published().Model(&models.Product{}).Count(&total).Error
+	return total, err
+}
+
+func (r *catalogRepository) PriceRange() (money.Money, money.Money, error) {
+
+	var lowest models.Product
+	if err := r.published().Order("price asc").First(&lowest).Error; err != nil {
+		return 0, 0, notFound(err)
+	}
+
+	var highest models.Product
+	if err := r.published().Order("price desc").First(&highest).Error; err != nil {
+		return 0, 0, notFound(err)
+	}
+
+	return lowest.Price, highest.Price, nil
+}
+
+func (r *catalogRepository) Categories(displayedOnly bool, limit int) ([]models.Category, error) {
+	db := r.db.Where("status = 1")
+	if displayedOnly {
+		db = db.Where("displayed = 1")
+	}
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var categories []models.Category
+	err := db.Order("name asc").Find(&categories).Error
+	return categories, err
+}
+
+func (r *catalogRepository) CategoryCounts() ([]NameCount, error) {
+	var counts []NameCount
+	err := r.db.Raw(`
+		SELECT 
+			categories.id, 
+			categories.name, 
+			COUNT(*) AS total
+		FROM categories
+		INNER JOIN products_categories ON products_categories.category_id = categories.id
+		GROUP BY categories.id, categories.name
+	`).Scan(&counts).Error
+	return counts, err
+}
+
+func (r *catalogRepository) BrandCounts() ([]NameCount, error) {
+	var counts []NameCount
+	err := r.db.Raw(`
+		SELECT
+			brands.id,
+			brands.name,
+			COUNT(*) AS total
+		FROM 
+			brands
+		INNER JOIN products ON products.brand_id = brands.id
+		GROUP BY
+			brands.id,
+			brands.name
+		ORDER BY brands.name ASC
+	`).Scan(&counts).Error
+	return counts, err
+}
+
+func (r *catalogRepository) Images(productId uint64) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	err := r.db.Where("product_id = ?", productId).Order("id desc").Find(&images).Error
+	return images, err
+}
+
+func (r *catalogRepository) Sizes() ([]models.Size, error) {
+	var sizes []models.Size
+	err := r.db.Where("status = 1").Order("name asc").Find(&sizes).Error
+	return sizes, err
+}
+
+func (r *catalogRepository) Colours() ([]models.Colour, error) {
+	var colours []models.Colour
+	err := r.db.Where("status = 1").Order("name asc").Find(&colours).Error
+	return colours, err
+}
+
+func (r *catalogRepository) Inventories(productId uint64) ([]models.ProductInventory, error) {
+	var inventories []models.ProductInventory
+	err := r.db.Where("product_id = ?", productId).Order("id asc").Find(&inventories).Error
+	return inventories, err
+}
+
+func (r *catalogRepository) FindInventory(productId uint64, sizeId uint64, colourId uint64) (models.ProductInventory, error) {
+	var inventory models.ProductInventory
+	err := r.db.Where("product_id = ? AND size_id = ? AND colour_id = ?", productId, sizeId, colourId).Order("id desc").First(&inventory).Error
+	return inventory, notFound(err)
+}
+
+// LockInventory reads an inventory row and its product, locking the row
+// until the surrounding transaction ends.
+func (r *catalogRepository) LockInventory(id uint64) (models.ProductInventory, error) {
+	var inventory models.ProductInventory
+	err := database.ForUpdate(r.db).Preload("Product").Where("id = ?", id).First(&inventory).Error
+	return inventory, notFound(err)
+}
+
+// TakeStock removes qty from an inventory row and reports false, without
+// changing anything, when fewer than qty items are left.
+func (r *catalogRepository) TakeStock(inventoryId uint64, qty uint16) (bool, error) {
+	result := r.db.Model(&models.ProductInventory{}).Where("id = ? AND stock >= ?", inventoryId, qty).UpdateColumn("stock", gorm.Expr("stock - ?", qty))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *catalogRepository) ReturnStock(inventoryId uint64, qty uint16) error {
+	return r.db.Model(&models.ProductInventory{}).Where("id = ?", inventoryId).UpdateColumn("stock", gorm.Expr("stock + ?", qty)).Error
+}
+
+func (r *catalogRepository) AddOrdered(productId uint64, qty uint16) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", productId).UpdateColumn("total_order", gorm.Expr("total_order + ?", qty)).Error
+}
+
+func (r *catalogRepository) RemoveOrdered(productId uint64, qty uint16) error {
+	return r.db.Model(&models.Product{}).Where("id = ? AND total_order >= ?", productId, qty).UpdateColumn("total_order", gorm.Expr("total_order - ?", qty)).Error
+}
+
+func (r *catalogRepository) ProductIds() ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&models.Product{}).Order("id asc").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// OrderedTotals sums, per product, the quantities on orders in one of
+// statuses.
+func (r *catalogRepository) OrderedTotals(statuses []uint8) (map[uint64]uint64, error) {
+	var rows []struct {
+		ProductId uint64
+		Total     uint64
+	}
+	err := r.db.Table("orders_details").
+		Select("products_inventories.product_id AS product_id, SUM(orders_details.qty) AS total").
+		Joins("INNER JOIN orders ON orders.id = orders_details.order_id").
+		Joins("INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id").
+		Where("orders.status IN (?)", statuses).
+		Group("products_inventories.product_id").
+		Scan(&rows).Error
+
+	totals := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		totals[row.ProductId] = row.Total
+	}
+	return totals, err
+}
+
+func (r *catalogRepository) SetOrdered(productId uint64, total uint16) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", productId).UpdateColumn("total_order", total).Error
+}
+
+// ProductPage lists every product, published or not, with its categories.
+func (r *catalogRepository) ProductPage(query ListQuery) ([]models.Product, int64, int64, error) {
+	var products []models.Product
+	total, filtered, err := search(r.db.Preload("Categories"), &models.Product{}, &products, query, "name", "sku", "description")
+	return products, total, filtered, err
+}
+
+// ProductComplete reads a product, published or not, with its categories,
+// images and inventories.
+func (r *catalogRepository) ProductComplete(id uint64) (models.Product, error) {
+	var product models.Product
+	err := r.db.Preload("Categories").Preload("Images").Preload("Inventories").Where("id = ?", id).First(&product).Error
+	return product, notFound(err)
+}
+
+// SortedImages returns the product's images in the order staff arranged them.
+func (r *catalogRepository) SortedImages(productId uint64) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	err := r.db.Where("product_id = ?", productId).Order("sort asc").Find(&images).Error
+	return images, err
+}
+
+func (r *catalogRepository) SkuTaken(sku string, exceptId uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.Product{}).Where("sku = ? AND id <> ?", sku, exceptId))
+}
+
+func (r *catalogRepository) CategoriesIn(ids []uint64) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Where("id IN (?)", ids).Find(&categories).Error
+	return categories, err
+}
+
+func (r *catalogRepository) ReplaceCategories(product *models.Product, categories []models.Category) error {
+	return r.db.Model(product).Association("Categories").Replace(categories).Error
+}
+
+func (r *catalogRepository) AttachCategory(product *models.Product, category models.Category) error {
+	return r.db.Model(product).Association("Categories").Append(category).Error
+}
+
+func (r *catalogRepository) DetachCategory(productId uint64, categoryId uint64) error {
+	return r.db.Exec("DELETE FROM products_categories WHERE product_id = ? AND category_id = ?", productId, categoryId).Error
+}
+
+// InventoryTaken reports whether another inventory of the product has the
+// same size and colour.
+func (r *catalogRepository) InventoryTaken(productId uint64, sizeId uint64, colourId uint64, exceptId uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.ProductInventory{}).
+		Where("product_id = ? AND size_id = ? AND colour_id = ? AND id <> ?", productId, sizeId, colourId, exceptId))
+}
+
+func (r *catalogRepository) BrandInUse(id uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.Product{}).Where("brand_id = ?", id))
+}
+
+func (r *catalogRepository) ColourInUse(id uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.ProductInventory{}).Where("colour_id = ?", id))
+}
+
+func (r *catalogRepository) SizeInUse(id uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.ProductInventory{}).Where("size_id = ?", id))
+}
+
+// ProductOrdered reports whether any order, carts included, has a line
+// of one of the product's inventories.
+func (r *catalogRepository) ProductOrdered(productId uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.OrderDetail{}).
+		Joins("INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id").
+		Where("products_inventories.product_id = ?", productId))
+}
+
+func (r *catalogRepository) InventoryOrdered(inventoryId uint64) (bool, error) {
+	return r.exists(r.db.Model(&models.OrderDetail{}).Where("inventory_id = ?", inventoryId))
+}
+
+// DeleteCategory deletes a category after taking it off its products.
+func (r *catalogRepository) DeleteCategory(id uint64) error {
+	if err := r.db.Exec("DELETE FROM products_categories WHERE category_id = ?", id).Error; err != nil {
+		return err
+	}
+	return r.db.Where("id = ?", id).Delete(&models.Category{}).Error
+}
+
+// DeleteProduct deletes a product with everything that hangs off it. It
+// is only meant for products nobody ordered; see ProductOrdered.
+func (r *catalogRepository) DeleteProduct(id uint64) error {
+	statements := []string{
+		"DELETE FROM products_categories WHERE product_id = ?",
+		"DELETE FROM products_wishlists WHERE product_id = ?",
+		"DELETE FROM orders_carts WHERE product_id = ?",
+		"DELETE FROM products_images WHERE product_id = ?",
+		"DELETE FROM products_inventories WHERE product_id = ?",
+		"DELETE FROM products_reviews WHERE product_id = ?",
+		"DELETE FROM products WHERE id = ?",
+	}
+	for _, statement := range statements {
+		if err := r.db.Exec(statement, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *catalogRepository) exists(query *gorm.DB) (bool, error) {
+	var total int64
+	err := query.Count(&total).Error
+	return total > 0, err
+}
+
+// SaveRatings writes the product's rating aggregates and nothing else.
+func (r *catalogRepository) SaveRatings(product models.Product) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", product.Id).UpdateColumns(map[string]interface{}{
+		"total_rating":   product.TotalRating,
+		"rating_average": product.RatingAverage,
+		"rating_count":   product.RatingCount,
+		"rating_1":       product.Rating1,
+		"rating_2":       product.Rating2,
+		"rating_3":       product.Rating3,
+		"rating_4":       product.Rating4,
+		"rating_5":       product.Rating5,
+	}).Error
+}
@@ -0,0 +1,181 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// coupon repository
+type CouponRepository interface {
+	Find(id uint64) (models.Coupon, error)
+	FindByCode(code string) (models.Coupon, error)
+	Lock(id uint64) (models.Coupon, error)
+	ForOrder(orderId uint64) (models.OrderCoupon, error)
+	Attach(orderCoupon *models.OrderCoupon) error
+	Detach(orderId uint64) error
+	SaveRedemption(orderCoupon *models.OrderCoupon) error
+	Redemptions(couponId uint64, userId uint64, exceptOrderId uint64) (int64, int64, error)
+	Search(query ListQuery) ([]models.Coupon, int64, int64, error)
+	CodeTaken(code string, exceptId uint64) (bool, error)
+	TargetExists(scope string, targetId uint64) (bool, error)
+	Redeemed(couponId uint64) (bool, error)
+	ReplaceTargets(coupon *models.Coupon, targets []models.CouponTarget) error
+	Delete(couponId uint64) error
+}
+
+// couponTargetTables maps a coupon target scope to the table its ids
+// point into.
+var couponTargetTables = map[string]string{
+	models.PriceScopeProduct:  "products",
+	models.PriceScopeCategory: "categories",
+	models.PriceScopeBrand:    "brands",
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func (r *couponRepository) Find(id uint64) (models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.Preload("Targets").Where("id = ?", id).First(&coupon).Error
+	return coupon, notFound(err)
+}
+
+// Search lists every coupon with its targets.
+func (r *couponRepository) Search(query ListQuery) ([]models.Coupon, int64, int64, error) {
+	var coupons []models.Coupon
+	total, filtered, err := search(r.db.Preload("Targets"), &models.Coupon{}, &coupons, query, "code", "name")
+	return coupons, total, filtered, err
+}
+
+func (r *couponRepository) CodeTaken(code string, exceptId uint64) (bool, error) {
+	var total int64
+	err := r.db.Model(&models.Coupon{}).Where("code = ? AND id <> ?", code, exceptId).Count(&total).Error
+	return total > 0, err
+}
+
+// TargetExists reports whether the product, category or brand a coupon
+// target names exists.
+func (r *couponRepository) TargetExists(scope string, targetId uint64) (bool, error) {
+	table, ok := couponTargetTables[scope]
+	if !ok {
+		return false, nil
+	}
+	var total int64
+	err := r.db.Table(table).Where("id = ?", targetId).Count(&total).Error
+	return total > 0, err
+}
+
+// Redeemed reports whether an order past the cart used the coupon.
+func (r *couponRepository) Redeemed(couponId uint64) (bool, error) {
+	var total int64
+	err := r.db.Model(&models.OrderCoupon{}).
+		Joins("INNER JOIN orders ON orders.id = orders_coupons.order_id").
+		Where("orders_coupons.coupon_id = ? AND orders.status <> ?", couponId, models.OrderStatusCart).
+		Count(&total).Error
+	return total > 0, err
+}
+
+// ReplaceTargets swaps the coupon's targets for targets.
+func (r *couponRepository) ReplaceTargets(coupon *models.Coupon, targets []models.CouponTarget) error {
+	if err := r.db.Where("coupon_id = ?", coupon.Id).Delete(&models.CouponTarget{}).Error; err != nil {
+		return err
+	}
+	for i := range targets {
+		targets[i].CouponId = coupon.Id
+		if err := r.db.Create(&targets[i]).Error; err != nil {
+			return err
+		}
+	}
+	coupon.Targets = targets
+	return nil
+}
+
+// Delete deletes a coupon with its targets and the carts it is on. It is
+// only meant for coupons nobody redeemed; see Redeemed.
+func (r *couponRepository) Delete(couponId uint64) error {
+	statements := []string{
+		"DELETE FROM orders_coupons WHERE coupon_id = ?",
+		"DELETE FROM coupons_targets WHERE coupon_id = ?",
+		"DELETE FROM coupons WHERE id = ?",
+	}
+	for _, statement := range statements {
+		if err := r.db.Exec(statement, couponId).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByCode looks a coupon up by its normalized code.
+func (r *couponRepository) FindByCode(code string) (models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.Preload("Targets").Where("code = ?", code).First(&coupon).Error
+	return coupon, notFound(err)
+}
+
+// Lock holds the coupon row until the transaction ends, so concurrent
+// checkouts cannot both take its last use.
+func (r *couponRepository) Lock(id uint64) (models.Coupon, error) {
+	var coupon models.Coupon
+	err := database.ForUpdate(r.db).Where("id = ?", id).First(&coupon).Error
+	return coupon, notFound(err)
+}
+
+func (r *couponRepository) ForOrder(orderId uint64) (models.OrderCoupon, error) {
+	var orderCoupon models.OrderCoupon
+	err := r.db.Where("order_id = ?", orderId).First(&orderCoupon).Error
+	return orderCoupon, notFound(err)
+}
+
+// Attach applies a coupon to an order, replacing the one it had.
+func (r *couponRepository) Attach(orderCoupon *models.OrderCoupon) error {
+	if err := r.Detach(orderCoupon.OrderId); err != nil {
+		return err
+	}
+	return r.db.Create(orderCoupon).Error
+}
+
+func (r *couponRepository) Detach(orderId uint64) error {
+	return r.db.Where("order_id = ?", orderId).Delete(&models.OrderCoupon{}).Error
+}
+
+func (r *couponRepository) SaveRedemption(orderCoupon *models.OrderCoupon) error {
+	return r.db.Save(orderCoupon).Error
+}
+
+// Redemptions counts the checked out orders that used the coupon, in
+// total and by userId. Carts and cancelled orders do not count, and
+// neither does exceptOrderId, the order being priced.
+func (r *couponRepository) Redemptions(couponId uint64, userId uint64, exceptOrderId uint64) (int64, int64, error) {
+
+	var counts struct {
+		Total  int64
+		ByUser int64
+	}
+	err := r.db.Raw(`
+		SELECT
+			COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN orders_coupons.user_id = ? THEN 1 ELSE 0 END), 0) AS by_user
+		FROM orders_coupons
+		INNER JOIN orders ON orders.id = orders_coupons.order_id
+		WHERE orders_coupons.coupon_id = ?
+		AND orders_coupons.order_id <> ?
+		AND orders.status NOT IN (?)
+	`, userId, couponId, exceptOrderId, []int{int(models.OrderStatusCart), int(models.OrderStatusCancelled)}).Scan(&counts).Error
+
+	return counts.Total, counts.ByUser, err
+}
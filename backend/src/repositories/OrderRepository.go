@@ -0,0 +1,324 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+	money "backend/src/money"
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
+
+// OrderLine is an order detail joined with the product it was bought as.
+// Id is the product's; DetailId is the one cart line edits are keyed by.
+type OrderLine struct {
+	Id       int64
+	DetailId int64
+	Name     string
+	Image    sql.NullString
+	Price    money.Money
+	Qty      uint16
+	Total    money.Money
+}
+
+// order repository
+type OrderRepository interface {
+	Find(id uint64) (models.Order, error)
+	Lock(id uint64) (models.Order, error)
+	FindOwned(userId uint64, id uint64) (models.Order, error)
+	LockOwned(userId uint64, id uint64) (models.Order, error)
+	HasDelivered(userId uint64, productId uint64) (bool, error)
+	OpenCart(userId uint64) (models.Order, error)
+	LockOpenCart(userId uint64) (models.Order, error)
+	OpenGuestCart(tokenHash string) (models.Order, error)
+	LockGuestCart(tokenHash string) (models.Order, error)
+	List(userId uint64, query ListQuery) ([]models.Order, int64, int64, error)
+	Search(status *uint8, query ListQuery) ([]models.Order, int64, int64, error)
+	FindComplete(id uint64) (models.Order, error)
+	Create(order *models.Order) error
+	Save(order *models.Order) error
+	SetStatus(order *models.Order, status uint8) error
+	Delete(order *models.Order) error
+	Details(orderId uint64) ([]models.OrderDetail, error)
+	Detail(orderId uint64, id uint64) (models.OrderDetail, error)
+	FindDetail(orderId uint64, inventoryId uint64) (models.OrderDetail, error)
+	SaveDetail(detail *models.OrderDetail) error
+	DeleteDetail(detail *models.OrderDetail) error
+	DeleteDetails(orderId uint64) error
+	Lines(orderId uint64) ([]OrderLine, error)
+	CartLines(userId uint64) ([]OrderLine, error)
+	AttachProduct(orderId uint64, productId uint64) error
+	DetachProducts(orderId uint64) error
+	PruneProducts(orderId uint64) error
+	Billings(orderId uint64) ([]models.OrderBilling, error)
+	CreateBilling(billing *models.OrderBilling) error
+	Histories(orderId uint64) ([]models.OrderStatusHistory, error)
+	AddHistory(history *models.OrderStatusHistory) error
+	Payments() ([]models.Payment, error)
+	Payment(id uint64) (models.Payment, error)
+	DefaultPayment() (models.Payment, error)
+}
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+const orderLineQuery = `
+		SELECT 
+			products.id,
+			orders_details.id AS detail_id,
+			products.image,
+			products.name,
+			products.price,
+			orders_details.qty,
+			orders_details.total
+		FROM orders_details
+		INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
+		INNER JOIN orders ON orders.id = orders_details.order_id
+		INNER JOIN products ON products.id = products_inventories.product_id
+	`
+
+func (r *orderRepository) Find(id uint64) (models.Order, error) {
+	var order models.Order
+	err := r.db.Where("id = ?", id).First(&order).Error
+	return order, notFound(err)
+}
+
+func (r *orderRepository) Lock(id uint64) (models.Order, error) {
+	var order models.Order
+	err := database.ForUpdate(r.db).Where("id = ?", id).First(&order).Error
+	return order, notFound(err)
+}
+
+// FindOwned finds an order of the given user. Other users' orders are
+// reported as ErrNotFound, so their ids cannot be told apart from ids
+// that do not exist.
+func (r *orderRepository) FindOwned(userId uint64, id uint64) (models.Order, error) {
+	var order models.Order
+	err := r.db.Where("id = ? AND user_id = ? AND user_id <> 0", id, userId).First(&order).Error
+	return order, notFound(err)
+}
+
+// LockOwned is FindOwned holding the row until the transaction ends.
+func (r *orderRepository) LockOwned(userId uint64, id uint64) (models.Order, error) {
+	var order models.Order
+	err := database.ForUpdate(r.db).Where("id = ? AND user_id = ? AND user_id <> 0", id, userId).First(&order).Error
+	return order, notFound(err)
+}
+
+// HasDelivered reports whether the user has received the product in an
+// order that was delivered.
+func (r *orderRepository) HasDelivered(userId uint64, productId uint64) (bool, error) {
+	var total int
+	err := r.db.Table("orders_details").
+		Joins("INNER JOIN orders ON orders.id = orders_details.order_id").
+		Joins("INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id").
+		Where("orders.user_id = ? AND orders.status = ? AND products_inventories.product_id = ?", userId, models.OrderStatusDelivered, productId).
+		Count(&total).Error
+	return total > 0, err
+}
+
+// OpenCart returns the user's newest order that has not been checked out.
+func (r *orderRepository) OpenCart(userId uint64) (models.Order, error) {
+	var order models.Order
+	err := r.db.Where("status = ? AND user_id = ?", models.OrderStatusCart, userId).Order("id desc").First(&order).Error
+	return order, notFound(err)
+}
+
+func (r *orderRepository) LockOpenCart(userId uint64) (models.Order, error) {
+	var order models.Order
+	err := database.ForUpdate(r.db).Where("status = ? AND user_id = ?", models.OrderStatusCart, userId).Order("id desc").First(&order).Error
+	return order, notFound(err)
+}
+
+// OpenGuestCart returns the guest cart whose cart token hashes to tokenHash.
+func (r *orderRepository) OpenGuestCart(tokenHash string) (models.Order, error) {
+	var order models.Order
+	err := r.db.Where("status = ? AND cart_token = ?", models.OrderStatusCart, tokenHash).First(&order).Error
+	return order, notFound(err)
+}
+
+func (r *orderRepository) LockGuestCart(tokenHash string) (models.Order, error) {
+	var order models.Order
+	err := database.ForUpdate(r.db).Where("status = ? AND cart_token = ?", models.OrderStatusCart, tokenHash).First(&order).Error
+	return order, notFound(err)
+}
+
+// List returns one page of the user's orders, the number of orders the
+// user has and the number that match the search.
+func (r *orderRepository) List(userId uint64, query ListQuery) ([]models.Order, int64, int64, error) {
+
+	db := r.db.Model(&models.Order{}).Where("user_id = ?", userId)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	filtered := total
+	if len(query.Search) > 0 {
+		db = db.Where("invoice_number LIKE ?", query.Search)
+		if err := db.Count(&filtered).Error; err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	var orders []models.Order
+	err := db.Limit(query.Limit).Offset(query.Offset()).Order(query.Sort()).Find(&orders).Error
+	return orders, total, filtered, err
+}
+
+// Search lists every user's orders, only those in status when it is set.
+func (r *orderRepository) Search(status *uint8, query ListQuery) ([]models.Order, int64, int64, error) {
+	db := r.db
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	var orders []models.Order
+	total, filtered, err := search(db, &models.Order{}, &orders, query, "invoice_number")
+	return orders, total, filtered, err
+}
+
+// FindComplete reads an order with its lines, billings, status history,
+// payment attempts and returns.
+func (r *orderRepository) FindComplete(id uint64) (models.Order, error) {
+	var order models.Order
+	err := r.db.Preload("Details").Preload("Billings").Preload("Histories").Preload("Transactions").Preload("Returns.Lines").
+		Where("id = ?", id).First(&order).Error
+	return order, notFound(err)
+}
+
+func (r *orderRepository) Create(order *models.Order) error {
+	return r.db.Create(order).Error
+}
+
+func (r *orderRepository) Save(order *models.Order) error {
+	return r.db.Save(order).Error
+}
+
+func (r *orderRepository) SetStatus(order *models.Order, status uint8) error {
+	return r.db.Model(order).UpdateColumn("status", status).Error
+}
+
+func (r *orderRepository) Delete(order *models.Order) error {
+	return r.db.Delete(order).Error
+}
+
+func (r *orderRepository) Details(orderId uint64) ([]models.OrderDetail, error) {
+	var details []models.OrderDetail
+	err := r.db.Where("order_id = ?", orderId).Order("inventory_id asc").Find(&details).Error
+	return details, err
+}
+
+// Detail returns the detail with id when it belongs to the order.
+func (r *orderRepository) Detail(orderId uint64, id uint64) (models.OrderDetail, error) {
+	var detail models.OrderDetail
+	err := r.db.Where("id = ? AND order_id = ?", id, orderId).First(&detail).Error
+	return detail, notFound(err)
+}
+
+func (r *orderRepository) FindDetail(orderId uint64, inventoryId uint64) (models.OrderDetail, error) {
+	var detail models.OrderDetail
+	err := r.db.Where("inventory_id = ? AND order_id = ?", inventoryId, orderId).First(&detail).Error
+	return detail, notFound(err)
+}
+
+// SaveDetail inserts detail when it has no id yet and updates it otherwise.
+func (r *orderRepository) SaveDetail(detail *models.OrderDetail) error {
+	return r.db.Save(detail).Error
+}
+
+func (r *orderRepository) DeleteDetail(detail *models.OrderDetail) error {
+	return r.db.Delete(detail).Error
+}
+
+func (r *orderRepository) DeleteDetails(orderId uint64) error {
+	return r.db.Where("order_id = ?", orderId).Delete(&models.OrderDetail{}).Error
+}
+
+func (r *orderRepository) Lines(orderId uint64) ([]OrderLine, error) {
+	var lines []OrderLine
+	err := r.db.Raw(orderLineQuery+"WHERE orders_details.order_id = ?", orderId).Scan(&lines).Error
+	return lines, err
+}
+
+// CartLines returns the lines of every order the user has not checked out yet.
+func (r *orderRepository) CartLines(userId uint64) ([]OrderLine, error) {
+	var lines []OrderLine
+	err := r.db.Raw(orderLineQuery+"WHERE orders.status = ? AND orders.user_id = ?", models.OrderStatusCart, userId).Scan(&lines).Error
+	return lines, err
+}
+
+func (r *orderRepository) AttachProduct(orderId uint64, productId uint64) error {
+	if err := r.db.Exec("DELETE FROM orders_carts WHERE order_id = ? AND product_id = ?", orderId, productId).Error; err != nil {
+		return err
+	}
+	return r.db.Exec("INSERT INTO orders_carts(order_id, product_id) VALUES (?,?)", orderId, productId).Error
+}
+
+func (r *orderRepository) DetachProducts(orderId uint64) error {
+	return r.db.Exec("DELETE FROM orders_carts WHERE order_id = ?", orderId).Error
+}
+
+// PruneProducts drops the products no detail of the order is for any more
+// from orders_carts.
+func (r *orderRepository) PruneProducts(orderId uint64) error {
+	return r.db.Exec(`
+		DELETE FROM orders_carts
+		WHERE order_id = ? AND product_id NOT IN (
+			SELECT products_inventories.product_id
+			FROM orders_details
+			INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
+			WHERE orders_details.order_id = ?
+		)
+	`, orderId, orderId).Error
+}
+
+func (r *orderRepository) Billings(orderId uint64) ([]models.OrderBilling, error) {
+	var billings []models.OrderBilling
+	err := r.db.Where("order_id = ?", orderId).Order("name asc").Find(&billings).Error
+	return billings, err
+}
+
+func (r *orderRepository) CreateBilling(billing *models.OrderBilling) error {
+	return r.db.Create(billing).Error
+}
+
+func (r *orderRepository) Histories(orderId uint64) ([]models.OrderStatusHistory, error) {
+	var histories []models.OrderStatusHistory
+	err := r.db.Where("order_id = ?", orderId).Order("id asc").Find(&histories).Error
+	return histories, err
+}
+
+func (r *orderRepository) AddHistory(history *models.OrderStatusHistory) error {
+	return r.db.Create(history).Error
+}
+
+func (r *orderRepository) Payments() ([]models.Payment, error) {
+	var payments []models.Payment
+	err := r.db.Where("status = 1").Order("name asc").Find(&payments).Error
+	return payments, err
+}
+
+func (r *orderRepository) Payment(id uint64) (models.Payment, error) {
+	var payment models.Payment
+	err := r.db.Where("id = ?", id).First(&payment).Error
+	return payment, notFound(err)
+}
+
+func (r *orderRepository) DefaultPayment() (models.Payment, error) {
+	var payment models.Payment
+	err := r.db.Where("status = 1").First(&payment).Error
+	return payment, notFound(err)
+}
@@ -0,0 +1,31 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	mailer "backend/src/mailer"
+
+	"github.com/jinzhu/gorm"
+)
+
+// outbox repository
+type OutboxRepository interface {
+	Enqueue(to string, template string, data map[string]interface{}) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func (r *outboxRepository) Enqueue(to string, template string, data map[string]interface{}) error {
+	return mailer.Enqueue(r.db, to, template, data)
+}
@@ -0,0 +1,83 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// payment repository
+type PaymentRepository interface {
+	CreateTransaction(transaction *models.PaymentTransaction) error
+	SaveTransaction(transaction *models.PaymentTransaction) error
+	Transactions(orderId uint64) ([]models.PaymentTransaction, error)
+	PendingTransaction(orderId uint64) (models.PaymentTransaction, error)
+	LockTransaction(provider string, reference string) (models.PaymentTransaction, error)
+	PaidTransaction(orderId uint64) (models.PaymentTransaction, error)
+	EventSeen(provider string, eventId string) (bool, error)
+	CreateEvent(event *models.PaymentEvent) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func (r *paymentRepository) CreateTransaction(transaction *models.PaymentTransaction) error {
+	return r.db.Create(transaction).Error
+}
+
+func (r *paymentRepository) SaveTransaction(transaction *models.PaymentTransaction) error {
+	return r.db.Save(transaction).Error
+}
+
+func (r *paymentRepository) Transactions(orderId uint64) ([]models.PaymentTransaction, error) {
+	var transactions []models.PaymentTransaction
+	err := r.db.Where("order_id = ?", orderId).Order("id desc").Find(&transactions).Error
+	return transactions, err
+}
+
+// PendingTransaction returns the order's latest attempt that is still
+// waiting on the provider.
+func (r *paymentRepository) PendingTransaction(orderId uint64) (models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	err := r.db.Where("order_id = ? AND status = ?", orderId, "pending").Order("id desc").First(&transaction).Error
+	return transaction, notFound(err)
+}
+
+// LockTransaction holds the attempt a provider reference names until the
+// transaction ends, so two deliveries of its webhooks are applied one
+// after the other.
+func (r *paymentRepository) LockTransaction(provider string, reference string) (models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	err := database.ForUpdate(r.db).Where("provider = ? AND reference = ?", provider, reference).Order("id desc").First(&transaction).Error
+	return transaction, notFound(err)
+}
+
+// PaidTransaction returns the attempt that collected the order's total.
+func (r *paymentRepository) PaidTransaction(orderId uint64) (models.PaymentTransaction, error) {
+	var transaction models.PaymentTransaction
+	err := r.db.Where("order_id = ? AND status = ?", orderId, "succeeded").Order("id desc").First(&transaction).Error
+	return transaction, notFound(err)
+}
+
+func (r *paymentRepository) EventSeen(provider string, eventId string) (bool, error) {
+	var total int
+	err := r.db.Model(&models.PaymentEvent{}).Where("provider = ? AND event_id = ?", provider, eventId).Count(&total).Error
+	return total > 0, err
+}
+
+func (r *paymentRepository) CreateEvent(event *models.PaymentEvent) error {
+	return r.db.Create(event).Error
+}
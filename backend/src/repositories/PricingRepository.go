@@ -0,0 +1,102 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+
+	"github.com/jinzhu/gorm"
+)
+
+// PriceItem is an order detail with the product fields price rules match on.
+type PriceItem struct {
+	DetailId    uint64
+	InventoryId uint64
+	ProductId   uint64
+	BrandId     uint64
+	CategoryIds []uint64 `gorm:"-"`
+	Name        string
+	Price       money.Money
+	Qty         uint16
+}
+
+// pricing repository
+type PricingRepository interface {
+	Rules() ([]models.PriceRule, error)
+	TaxRates() ([]models.TaxRate, error)
+	Items(orderId uint64) ([]PriceItem, error)
+}
+
+type pricingRepository struct {
+	db *gorm.DB
+}
+
+// Rules returns the enabled price rules in the order they apply. Time
+// windows are left to the caller, which knows the moment being priced.
+func (r *pricingRepository) Rules() ([]models.PriceRule, error) {
+	var rules []models.PriceRule
+	err := r.db.Where("status = 1").Order("priority asc, id asc").Find(&rules).Error
+	return rules, err
+}
+
+func (r *pricingRepository) TaxRates() ([]models.TaxRate, error) {
+	var rates []models.TaxRate
+	err := r.db.Where("status = 1").Order("id asc").Find(&rates).Error
+	return rates, err
+}
+
+func (r *pricingRepository) Items(orderId uint64) ([]PriceItem, error) {
+
+	var items []PriceItem
+	err := r.db.Raw(`
+		SELECT
+			orders_details.id AS detail_id,
+			orders_details.inventory_id,
+			products.id AS product_id,
+			products.brand_id,
+			products.name,
+			orders_details.price,
+			orders_details.qty
+		FROM orders_details
+		INNER JOIN products_inventories ON products_inventories.id = orders_details.inventory_id
+		INNER JOIN products ON products.id = products_inventories.product_id
+		WHERE orders_details.order_id = ?
+		ORDER BY orders_details.id ASC
+	`, orderId).Scan(&items).Error
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+
+	productIds := make([]uint64, len(items))
+	for i, item := range items {
+		productIds[i] = item.ProductId
+	}
+
+	var links []struct {
+		ProductId  uint64
+		CategoryId uint64
+	}
+	if err := r.db.Raw("SELECT product_id, category_id FROM products_categories WHERE product_id IN (?)", productIds).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uint64][]uint64)
+	for _, link := range links {
+		categories[link.ProductId] = append(categories[link.ProductId], link.CategoryId)
+	}
+	for i := range items {
+		items[i].CategoryIds = categories[items[i].ProductId]
+	}
+
+	return items, nil
+}
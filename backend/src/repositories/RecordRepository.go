@@ -0,0 +1,55 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// record repository
+//
+// Plain reads and writes of the rows the back office edits one at a time,
+// such as brands, categories, colours, sizes and product images. What may
+// be changed or deleted is up to the services.
+type RecordRepository interface {
+	List(list interface{}, query ListQuery, columns ...string) (int64, int64, error)
+	Find(record interface{}, id uint64) error
+	Create(record interface{}) error
+	Update(record interface{}, changes map[string]interface{}) error
+	Delete(record interface{}) error
+}
+
+type recordRepository struct {
+	db *gorm.DB
+}
+
+// List fills list, a pointer to a slice of models, with one page of their
+// table; see search.
+func (r *recordRepository) List(list interface{}, query ListQuery, columns ...string) (int64, int64, error) {
+	return search(r.db, list, list, query, columns...)
+}
+
+func (r *recordRepository) Find(record interface{}, id uint64) error {
+	return notFound(r.db.Where("id = ?", id).First(record).Error)
+}
+
+func (r *recordRepository) Create(record interface{}) error {
+	return r.db.Create(record).Error
+}
+
+func (r *recordRepository) Update(record interface{}, changes map[string]interface{}) error {
+	return r.db.Model(record).Updates(changes).Error
+}
+
+func (r *recordRepository) Delete(record interface{}) error {
+	return r.db.Delete(record).Error
+}
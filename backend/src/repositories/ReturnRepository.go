@@ -0,0 +1,111 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// return repository
+type ReturnRepository interface {
+	Find(id uint64) (models.OrderReturn, error)
+	Lock(id uint64) (models.OrderReturn, error)
+	ForOrder(orderId uint64) ([]models.OrderReturn, error)
+	Search(status string, query ListQuery) ([]models.OrderReturn, int64, int64, error)
+	FindComplete(id uint64) (models.OrderReturn, error)
+	Returned(orderId uint64) (map[uint64]uint16, error)
+	Create(orderReturn *models.OrderReturn) error
+	Save(orderReturn *models.OrderReturn) error
+	AddHistory(history *models.OrderReturnHistory) error
+}
+
+type returnRepository struct {
+	db *gorm.DB
+}
+
+func (r *returnRepository) Find(id uint64) (models.OrderReturn, error) {
+	var orderReturn models.OrderReturn
+	err := r.db.Preload("Lines").Where("id = ?", id).First(&orderReturn).Error
+	return orderReturn, notFound(err)
+}
+
+// Lock holds the return row until the transaction ends, so two staff
+// members cannot move it at once.
+func (r *returnRepository) Lock(id uint64) (models.OrderReturn, error) {
+	var orderReturn models.OrderReturn
+	err := database.ForUpdate(r.db).Preload("Lines").Where("id = ?", id).First(&orderReturn).Error
+	return orderReturn, notFound(err)
+}
+
+// Search lists every order's returns, only those in status when it is set.
+func (r *returnRepository) Search(status string, query ListQuery) ([]models.OrderReturn, int64, int64, error) {
+	db := r.db.Preload("Lines")
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	var orderReturns []models.OrderReturn
+	total, filtered, err := search(db, &models.OrderReturn{}, &orderReturns, query, "reason")
+	return orderReturns, total, filtered, err
+}
+
+// FindComplete reads a return with its lines and the history of its steps.
+func (r *returnRepository) FindComplete(id uint64) (models.OrderReturn, error) {
+	var orderReturn models.OrderReturn
+	err := r.db.Preload("Lines").Preload("Histories").Where("id = ?", id).First(&orderReturn).Error
+	return orderReturn, notFound(err)
+}
+
+func (r *returnRepository) ForOrder(orderId uint64) ([]models.OrderReturn, error) {
+	var orderReturns []models.OrderReturn
+	err := r.db.Preload("Lines").Where("order_id = ?", orderId).Order("id desc").Find(&orderReturns).Error
+	return orderReturns, err
+}
+
+// Returned sums, per order detail, the quantity already asked back by
+// returns that were not rejected.
+func (r *returnRepository) Returned(orderId uint64) (map[uint64]uint16, error) {
+
+	var rows []struct {
+		DetailId uint64
+		Qty      uint16
+	}
+
+	err := r.db.Table("orders_returns_lines").
+		Select("orders_returns_lines.detail_id, SUM(orders_returns_lines.qty) AS qty").
+		Joins("INNER JOIN orders_returns ON orders_returns.id = orders_returns_lines.return_id").
+		Where("orders_returns.order_id = ? AND orders_returns.status <> ?", orderId, models.ReturnStatusRejected).
+		Group("orders_returns_lines.detail_id").
+		Scan(&rows).Error
+
+	returned := make(map[uint64]uint16, len(rows))
+	for _, row := range rows {
+		returned[row.DetailId] = row.Qty
+	}
+	return returned, err
+}
+
+// Create inserts the return together with its lines.
+func (r *returnRepository) Create(orderReturn *models.OrderReturn) error {
+	return r.db.Create(orderReturn).Error
+}
+
+// Save updates the return's own columns; its lines never change.
+func (r *returnRepository) Save(orderReturn *models.OrderReturn) error {
+	return r.db.Set("gorm:save_associations", false).Save(orderReturn).Error
+}
+
+func (r *returnRepository) AddHistory(history *models.OrderReturnHistory) error {
+	return r.db.Create(history).Error
+}
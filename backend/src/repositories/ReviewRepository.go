@@ -0,0 +1,180 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	database "backend/src/database"
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// review repository
+type ReviewRepository interface {
+	Find(id uint64) (models.ProductReview, error)
+	Lock(id uint64) (models.ProductReview, error)
+	Owned(userId uint64, productId uint64) (models.ProductReview, error)
+	LockOwned(userId uint64, productId uint64) (models.ProductReview, error)
+	Approved(productId uint64) ([]models.ProductReview, error)
+	Search(status *uint8, productId uint64, query ListQuery) ([]models.ProductReview, int64, int64, error)
+	FindComplete(id uint64) (models.ProductReview, error)
+	Stars(productId uint64) ([5]uint32, error)
+	AllStars() (map[uint64][5]uint32, error)
+	Create(review *models.ProductReview) error
+	Save(review *models.ProductReview) error
+	Vote(reviewId uint64, userId uint64) (bool, error)
+	Unvote(reviewId uint64, userId uint64) (bool, error)
+	Votes(reviewId uint64) (uint32, error)
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func (r *reviewRepository) Find(id uint64) (models.ProductReview, error) {
+	var review models.ProductReview
+	err := r.db.Where("id = ?", id).First(&review).Error
+	return review, notFound(err)
+}
+
+// Search lists the reviews with their author and product, only those in
+// status and of productId when they are set.
+func (r *reviewRepository) Search(status *uint8, productId uint64, query ListQuery) ([]models.ProductReview, int64, int64, error) {
+	db := r.db.Preload("User").Preload("Product")
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+	if productId != 0 {
+		db = db.Where("product_id = ?", productId)
+	}
+	var reviews []models.ProductReview
+	total, filtered, err := search(db, &models.ProductReview{}, &reviews, query, "review")
+	return reviews, total, filtered, err
+}
+
+// FindComplete reads a review with its author and product.
+func (r *reviewRepository) FindComplete(id uint64) (models.ProductReview, error) {
+	var review models.ProductReview
+	err := r.db.Preload("User").Preload("Product").Where("id = ?", id).First(&review).Error
+	return review, notFound(err)
+}
+
+// Lock holds the review row until the transaction ends, so moderation and
+// votes on the same review are applied one at a time.
+func (r *reviewRepository) Lock(id uint64) (models.ProductReview, error) {
+	var review models.ProductReview
+	err := database.ForUpdate(r.db).Where("id = ?", id).First(&review).Error
+	return review, notFound(err)
+}
+
+// Owned returns the user's review of the product.
+func (r *reviewRepository) Owned(userId uint64, productId uint64) (models.ProductReview, error) {
+	var review models.ProductReview
+	err := r.db.Where("user_id = ? AND product_id = ?", userId, productId).First(&review).Error
+	return review, notFound(err)
+}
+
+func (r *reviewRepository) LockOwned(userId uint64, productId uint64) (models.ProductReview, error) {
+	var review models.ProductReview
+	err := database.ForUpdate(r.db).Where("user_id = ? AND product_id = ?", userId, productId).First(&review).Error
+	return review, notFound(err)
+}
+
+// Approved lists the product's approved reviews, the most helpful first.
+func (r *reviewRepository) Approved(productId uint64) ([]models.ProductReview, error) {
+	var reviews []models.ProductReview
+	err := r.db.Preload("User").
+		Where("product_id = ? AND status = ?", productId, models.ReviewStatusApproved).
+		Order("helpful_count desc").
+		Order("id desc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// Stars counts the product's approved reviews by their number of stars.
+func (r *reviewRepository) Stars(productId uint64) ([5]uint32, error) {
+
+	var stars [5]uint32
+	var rows []struct {
+		Rating uint16
+		Total  uint32
+	}
+
+	err := r.db.Model(&models.ProductReview{}).
+		Select("rating, COUNT(*) AS total").
+		Where("product_id = ? AND status = ?", productId, models.ReviewStatusApproved).
+		Group("rating").
+		Scan(&rows).Error
+
+	for _, row := range rows {
+		if row.Rating >= models.ReviewRatingMin && row.Rating <= models.ReviewRatingMax {
+			stars[row.Rating-1] = row.Total
+		}
+	}
+	return stars, err
+}
+
+// AllStars counts the approved reviews per product and rating, for every
+// product with at least one.
+func (r *reviewRepository) AllStars() (map[uint64][5]uint32, error) {
+
+	var rows []struct {
+		ProductId uint64
+		Rating    uint16
+		Total     uint32
+	}
+
+	err := r.db.Model(&models.ProductReview{}).
+		Select("product_id, rating, COUNT(*) AS total").
+		Where("status = ?", models.ReviewStatusApproved).
+		Group("product_id, rating").
+		Scan(&rows).Error
+
+	stars := map[uint64][5]uint32{}
+	for _, row := range rows {
+		if row.Rating >= models.ReviewRatingMin && row.Rating <= models.ReviewRatingMax {
+			counts := stars[row.ProductId]
+			counts[row.Rating-1] = row.Total
+			stars[row.ProductId] = counts
+		}
+	}
+	return stars, err
+}
+
+func (r *reviewRepository) Create(review *models.ProductReview) error {
+	return r.db.Create(review).Error
+}
+
+func (r *reviewRepository) Save(review *models.ProductReview) error {
+	return r.db.Set("gorm:save_associations", false).Save(review).Error
+}
+
+// Vote marks the review as helpful to the user, reporting false when the
+// user had already done so.
+func (r *reviewRepository) Vote(reviewId uint64, userId uint64) (bool, error) {
+	var vote models.ProductReviewVote
+	result := r.db.Where(models.ProductReviewVote{ReviewId: reviewId, UserId: userId}).FirstOrCreate(&vote)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unvote takes the user's helpful vote back, reporting false when there
+// was none.
+func (r *reviewRepository) Unvote(reviewId uint64, userId uint64) (bool, error) {
+	result := r.db.Where("review_id = ? AND user_id = ?", reviewId, userId).Delete(&models.ProductReviewVote{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *reviewRepository) Votes(reviewId uint64) (uint32, error) {
+	var total uint32
+	err := r.db.Model(&models.ProductReviewVote{}).Where("review_id = ?", reviewId).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,63 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// role repository
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	ForUser(userId uint64) ([]string, error)
+	Permissions(roles []string) ([]string, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// List returns every role with the permissions it grants.
+func (r *roleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("name asc").Find(&roles).Error
+	return roles, err
+}
+
+// ForUser returns the names of the user's active roles.
+func (r *roleRepository) ForUser(userId uint64) ([]string, error) {
+	roles := []string{}
+	err := r.db.Table("roles").
+		Joins("INNER JOIN users_roles ON users_roles.role_id = roles.id").
+		Where("users_roles.user_id = ? AND roles.status = 1", userId).
+		Order("roles.name asc").
+		Pluck("roles.name", &roles).Error
+	return roles, err
+}
+
+// Permissions returns the names of the permissions the active roles among
+// roles grant between them.
+func (r *roleRepository) Permissions(roles []string) ([]string, error) {
+	permissions := []string{}
+	if len(roles) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Table("permissions").
+		Joins("INNER JOIN roles_permissions ON roles_permissions.permission_id = permissions.id").
+		Joins("INNER JOIN roles ON roles.id = roles_permissions.role_id").
+		Where("roles.name IN (?) AND roles.status = 1", roles).
+		Group("permissions.name").
+		Pluck("permissions.name", &permissions).Error
+	return permissions, err
+}
@@ -0,0 +1,47 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	models "backend/src/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+// setting repository
+type SettingRepository interface {
+	All() (map[string]string, error)
+	Get(key string) (string, error)
+}
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func (r *settingRepository) All() (map[string]string, error) {
+	var settings []models.Setting
+	if err := r.db.Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, row := range settings {
+		values[row.KeyName] = row.KeyValue
+	}
+	return values, nil
+}
+
+// Get returns the newest value stored under key.
+func (r *settingRepository) Get(key string) (string, error) {
+	var setting models.Setting
+	err := r.db.Where("key_name = ?", key).Order("id desc").First(&setting).Error
+	return setting.KeyValue, notFound(err)
+}
@@ -0,0 +1,188 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrNotFound is returned by every repository when the requested row does not exist.
+var ErrNotFound = errors.New("record not found")
+
+// Store gives the services access to every repository over one database
+// handle. Repositories returned by a store inside Transaction share its
+// transaction.
+type Store interface {
+	Users() UserRepository
+	Catalog() CatalogRepository
+	Orders() OrderRepository
+	Settings() SettingRepository
+	Activities() ActivityRepository
+	Outbox() OutboxRepository
+	Pricing() PricingRepository
+	Coupons() CouponRepository
+	Payments() PaymentRepository
+	Returns() ReturnRepository
+	Reviews() ReviewRepository
+	Authentications() AuthenticationRepository
+	Roles() RoleRepository
+	Records() RecordRepository
+	Transaction(fn func(tx Store) error) error
+}
+
+type store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) Store {
+	return &store{db: db}
+}
+
+func (s *store) Users() UserRepository {
+	return &userRepository{db: s.db}
+}
+
+func (s *store) Catalog() CatalogRepository {
+	return &catalogRepository{db: s.db}
+}
+
+func (s *store) Orders() OrderRepository {
+	return &orderRepository{db: s.db}
+}
+
+func (s *store) Settings() SettingRepository {
+	return &settingRepository{db: s.db}
+}
+
+func (s *store) Activities() ActivityRepository {
+	return &activityRepository{db: s.db}
+}
+
+func (s *store) Outbox() OutboxRepository {
+	return &outboxRepository{db: s.db}
+}
+
+func (s *store) Pricing() PricingRepository {
+	return &pricingRepository{db: s.db}
+}
+
+func (s *store) Coupons() CouponRepository {
+	return &couponRepository{db: s.db}
+}
+
+func (s *store) Payments() PaymentRepository {
+	return &paymentRepository{db: s.db}
+}
+
+func (s *store) Returns() ReturnRepository {
+	return &returnRepository{db: s.db}
+}
+
+func (s *store) Reviews() ReviewRepository {
+	return &reviewRepository{db: s.db}
+}
+
+func (s *store) Authentications() AuthenticationRepository {
+	return &authenticationRepository{db: s.db}
+}
+
+func (s *store) Roles() RoleRepository {
+	return &roleRepository{db: s.db}
+}
+
+func (s *store) Records() RecordRepository {
+	return &recordRepository{db: s.db}
+}
+
+// Transaction runs fn inside a database transaction, committing when fn
+// returns nil and rolling back on an error or a panic.
+func (s *store) Transaction(fn func(tx Store) error) (err error) {
+
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(&store{db: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ListQuery holds the paging, sorting and search parameters shared by the
+// list endpoints.
+type ListQuery struct {
+	Page     int
+	Limit    int
+	OrderBy  string
+	OrderDir string
+	Search   string
+}
+
+func (query ListQuery) Offset() int {
+	if query.Page < 1 {
+		return 0
+	}
+	return (query.Page - 1) * query.Limit
+}
+
+func (query ListQuery) Sort() string {
+	return query.OrderBy + " " + query.OrderDir
+}
+
+// search pages through the rows db selects for the back-office lists,
+// counting them all and those whose columns contain the search text.
+func search(db *gorm.DB, model interface{}, out interface{}, query ListQuery, columns ...string) (int64, int64, error) {
+
+	var total int64
+	if err := db.Model(model).Count(&total).Error; err != nil {
+		return 0, 0, err
+	}
+
+	filtered := db.Model(model)
+	if len(query.Search) > 0 && len(columns) > 0 {
+		clauses := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for i, column := range columns {
+			clauses[i] = column + " LIKE ?"
+			args[i] = "%" + query.Search + "%"
+		}
+		filtered = filtered.Where("("+strings.Join(clauses, " OR ")+")", args...)
+	}
+
+	var totalFiltered int64
+	if err := filtered.Count(&totalFiltered).Error; err != nil {
+		return 0, 0, err
+	}
+
+	err := filtered.Limit(query.Limit).Offset(query.Offset()).Order(query.Sort()).Find(out).Error
+	return total, totalFiltered, err
+}
+
+func notFound(err error) error {
+	if gorm.IsRecordNotFoundError(err) {
+		return ErrNotFound
+	}
+	return err
+}
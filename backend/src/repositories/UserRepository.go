@@ -0,0 +1,140 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package repositories
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrUnknownRole is returned when a role to assign does not exist.
+var ErrUnknownRole = errors.New("unknown role")
+
+// WishlistItem is a product on a user's wishlist.
+type WishlistItem struct {
+	Id    int64
+	Name  string
+	Image sql.NullString
+	Price money.Money
+}
+
+// user repository
+type UserRepository interface {
+	Find(id uint64) (models.User, error)
+	FindByEmail(email string) (models.User, error)
+	Create(user *models.User) error
+	AssignRoles(userId uint64, roles ...string) error
+	EmailTaken(email string, exceptId uint64) (bool, error)
+	PhoneTaken(phone string, exceptId uint64) (bool, error)
+	Update(user *models.User, changes models.User) error
+	UpdatePassword(user *models.User, hashed string) error
+	Wishlist(userId uint64) ([]WishlistItem, error)
+	AddToWishlist(userId uint64, productId uint64) error
+	RemoveFromWishlist(userId uint64, productId uint64) error
+	Subscribe(newsletter *models.NewsLetter) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func (r *userRepository) Find(id uint64) (models.User, error) {
+	var user models.User
+	err := r.db.Where("id = ?", id).First(&user).Error
+	return user, notFound(err)
+}
+
+func (r *userRepository) FindByEmail(email string) (models.User, error) {
+	var user models.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	return user, notFound(err)
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+// AssignRoles replaces the user's roles with the named ones. It fails with
+// ErrUnknownRole, changing nothing, when one of them does not exist.
+func (r *userRepository) AssignRoles(userId uint64, roles ...string) error {
+	var found []models.Role
+	if len(roles) > 0 {
+		if err := r.db.Where("name IN (?)", roles).Find(&found).Error; err != nil {
+			return err
+		}
+	}
+	for _, role := range roles {
+		if !slices.ContainsFunc(found, func(f models.Role) bool { return f.Name == role }) {
+			return fmt.Errorf("%w: %s", ErrUnknownRole, role)
+		}
+	}
+	user := models.User{Id: userId}
+	return r.db.Model(&user).Association("Roles").Replace(found).Error
+}
+
+func (r *userRepository) EmailTaken(email string, exceptId uint64) (bool, error) {
+	var total int64
+	err := r.db.Model(&models.User{}).Where("email = ? AND id != ?", email, exceptId).Count(&total).Error
+	return total > 0, err
+}
+
+func (r *userRepository) PhoneTaken(phone string, exceptId uint64) (bool, error) {
+	var total int64
+	err := r.db.Model(&models.User{}).Where("phone = ? AND id != ?", phone, exceptId).Count(&total).Error
+	return total > 0, err
+}
+
+// Update writes the non-zero fields of changes to user.
+func (r *userRepository) Update(user *models.User, changes models.User) error {
+	return r.db.Model(user).Updates(changes).Error
+}
+
+func (r *userRepository) UpdatePassword(user *models.User, hashed string) error {
+	return r.db.Model(user).Updates(map[string]interface{}{"password": hashed, "salt": ""}).Error
+}
+
+func (r *userRepository) Wishlist(userId uint64) ([]WishlistItem, error) {
+	var items []WishlistItem
+	err := r.db.Raw(`
+		SELECT 
+			products.id,
+			products.image,
+			products.name,
+			products.price
+		FROM
+			products
+		INNER JOIN products_wishlists ON products_wishlists.product_id = products.id
+		WHERE products_wishlists.user_id = ?
+	`, userId).Scan(&items).Error
+	return items, err
+}
+
+func (r *userRepository) AddToWishlist(userId uint64, productId uint64) error {
+	if err := r.RemoveFromWishlist(userId, productId); err != nil {
+		return err
+	}
+	return r.db.Exec("INSERT INTO products_wishlists(product_id, user_id) VALUES(?,?) ", productId, userId).Error
+}
+
+func (r *userRepository) RemoveFromWishlist(userId uint64, productId uint64) error {
+	return r.db.Exec("DELETE FROM products_wishlists WHERE product_id = ? AND user_id = ?", productId, userId).Error
+}
+
+func (r *userRepository) Subscribe(newsletter *models.NewsLetter) error {
+	return r.db.Create(newsletter).Error
+}
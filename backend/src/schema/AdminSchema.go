@@ -0,0 +1,118 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package schema
+
+import (
+	money "backend/src/money"
+	"time"
+)
+
+type BrandSchema struct {
+	Image       string `json:"image" binding:"max=191"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Status      uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type CategorySchema struct {
+	Image       string `json:"image" binding:"max=191"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Displayed   uint8  `json:"displayed" binding:"oneof=0 1"`
+	Status      uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type ColourSchema struct {
+	Code        string `json:"code" binding:"required,max=100"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Status      uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type SizeSchema struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Status      uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type ProductSchema struct {
+	BrandId     uint64      `json:"brand_id" binding:"required"`
+	Image       string      `json:"image" binding:"max=191"`
+	Sku         string      `json:"sku" binding:"required,max=100"`
+	Name        string      `json:"name" binding:"required,max=255"`
+	Price       money.Money `json:"price" binding:"gte=0"`
+	Description string      `json:"description"`
+	Details     string      `json:"details"`
+	CategoryIds []uint64    `json:"category_ids"`
+}
+
+type ProductPublishSchema struct {
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+type ProductCategoriesSchema struct {
+	CategoryIds []uint64 `json:"category_ids" binding:"required"`
+}
+
+type ProductImageSchema struct {
+	Path   string `json:"path" binding:"required,max=255"`
+	Sort   uint16 `json:"sort"`
+	Status uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type ProductInventorySchema struct {
+	SizeId   uint64 `json:"size_id" binding:"required"`
+	ColourId uint64 `json:"colour_id" binding:"required"`
+	Stock    uint16 `json:"stock"`
+	Status   uint8  `json:"status" binding:"oneof=0 1"`
+}
+
+type CouponSchema struct {
+	Code              string               `json:"code" binding:"required,max=64"`
+	Name              string               `json:"name" binding:"required,max=255"`
+	Calculation       string               `json:"calculation" binding:"oneof=percent fixed"`
+	Value             money.Money          `json:"value" binding:"gt=0"`
+	MinSubtotal       money.Money          `json:"min_subtotal" binding:"gte=0"`
+	UsageLimit        uint32               `json:"usage_limit"`
+	UsageLimitPerUser uint32               `json:"usage_limit_per_user"`
+	StartsAt          *time.Time           `json:"starts_at"`
+	EndsAt            *time.Time           `json:"ends_at"`
+	Status            uint8                `json:"status" binding:"oneof=0 1"`
+	Targets           []CouponTargetSchema `json:"targets" binding:"dive"`
+}
+
+type CouponTargetSchema struct {
+	Scope    string `json:"scope" binding:"oneof=product category brand"`
+	TargetId uint64 `json:"target_id" binding:"required"`
+}
+
+type OrderStatusSchema struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type ReturnDecisionSchema struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type ReviewModerationSchema struct {
+	Note string `json:"note" binding:"max=2000"`
+}
+
+type ReturnRefundSchema struct {
+	Amount *money.Money `json:"amount"`
+	Note   string       `json:"note" binding:"max=2000"`
+}
+
+type UserRolesSchema struct {
+	Roles []string `json:"roles" binding:"required"`
+}
@@ -27,8 +27,16 @@ type UserForgotSchema struct {
 	Email string `json:"email"`
 }
 
+type ConfirmResendSchema struct {
+	Email string `json:"email"`
+}
+
 type UserResetSchema struct {
 	Email           string `json:"email"`
 	Password        string `json:"password"`
 	ConfirmPassword string `json:"password_confirm"`
 }
+
+type RefreshTokenSchema struct {
+	RefreshToken string `json:"refresh_token"`
+}
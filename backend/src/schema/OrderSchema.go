@@ -12,14 +12,22 @@
 package schema
 
 type ReviewSchema struct {
-	Review string `json:"review"`
-	Rating int32  `json:"rating"`
+	Review string `json:"review" binding:"max=5000"`
+	Rating uint16 `json:"rating" binding:"required,min=1,max=5"`
 }
 
 type CreateCartSchema struct {
 	SizeId   uint64 `json:"size_id"`
 	ColourId uint64 `json:"colour_id"`
-	Qty      uint32 `json:"qty"`
+	Qty      uint32 `json:"qty" binding:"required,min=1,max=65535"`
+}
+
+type UpdateCartLineSchema struct {
+	Qty uint32 `json:"qty" binding:"required,min=1,max=65535"`
+}
+
+type ApplyCouponSchema struct {
+	Code string `json:"code" binding:"required,max=64"`
 }
 
 type CheckoutSchema struct {
@@ -35,3 +43,13 @@ type CheckoutSchema struct {
 	Address   string `json:"address"`
 	Notes     string `json:"notes"`
 }
+
+type ReturnRequestSchema struct {
+	Reason string             `json:"reason" binding:"required,max=2000"`
+	Lines  []ReturnLineSchema `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReturnLineSchema struct {
+	DetailId uint64 `json:"detail_id" binding:"required"`
+	Qty      uint32 `json:"qty" binding:"required,min=1,max=65535"`
+}
@@ -0,0 +1,61 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package search
+
+import (
+	"sync"
+	"time"
+)
+
+// Engine holds the index the storefront searches and rebuilds it from
+// load once it is older than maxAge or has been invalidated. A zero
+// maxAge rebuilds it for every search.
+type Engine struct {
+	load   func() ([]Document, error)
+	maxAge time.Duration
+
+	mu    sync.Mutex
+	index *Index
+	built time.Time
+}
+
+func NewEngine(load func() ([]Document, error), maxAge time.Duration) *Engine {
+	return &Engine{load: load, maxAge: maxAge}
+}
+
+// Index returns the current index, building it first when needed.
+func (engine *Engine) Index() (*Index, error) {
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	if engine.index != nil && engine.maxAge > 0 && time.Since(engine.built) < engine.maxAge {
+		return engine.index, nil
+	}
+
+	documents, err := engine.load()
+	if err != nil {
+		return nil, err
+	}
+
+	engine.index = Build(documents)
+	engine.built = time.Now()
+	return engine.index, nil
+}
+
+// Invalidate makes the next search rebuild the index, after the catalogue
+// has changed.
+func (engine *Engine) Invalidate() {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.index = nil
+}
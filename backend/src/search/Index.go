@@ -0,0 +1,375 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is a product as the index sees it.
+type Document struct {
+	Id          uint64
+	Name        string
+	Sku         string
+	Brand       string
+	Categories  []string
+	Description string
+	Details     string
+}
+
+// Hit is a document matching a query, with its relevance score.
+type Hit struct {
+	Id    uint64
+	Score float64
+}
+
+// How much a term found in each field counts towards a document's score.
+const (
+	weightName        = 3
+	weightSku         = 3
+	weightBrand       = 2
+	weightCategory    = 2
+	weightDescription = 1
+	weightDetails     = 0.5
+)
+
+// BM25 tuning: k1 caps how much repeating a term helps, b how much long
+// documents are penalised.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// A term completing the last word of a query, or within a typo of a word,
+// scores less than the word typed in full.
+const (
+	prefixFactor  = 0.8
+	maxExpansions = 50
+)
+
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// Index is an immutable inverted index over product documents.
+type Index struct {
+	ids      []uint64
+	lengths  []float64
+	average  float64
+	postings map[string][]posting
+	terms    []string
+}
+
+// Build indexes the documents.
+func Build(documents []Document) *Index {
+
+	index := &Index{
+		ids:      make([]uint64, len(documents)),
+		lengths:  make([]float64, len(documents)),
+		postings: map[string][]posting{},
+	}
+
+	var total float64
+	for i, document := range documents {
+
+		fields := []struct {
+			text   string
+			weight float64
+		}{
+			{document.Name, weightName},
+			{document.Sku, weightSku},
+			{document.Brand, weightBrand},
+			{strings.Join(document.Categories, " "), weightCategory},
+			{document.Description, weightDescription},
+			{document.Details, weightDetails},
+		}
+
+		weights := map[string]float64{}
+		for _, field := range fields {
+			for _, term := range Tokenize(field.text) {
+				weights[term] += field.weight
+				index.lengths[i]++
+			}
+		}
+
+		// "SKU-001" is also found by typing it without the dash.
+		if parts := Tokenize(document.Sku); len(parts) > 1 {
+			weights[strings.Join(parts, "")] += weightSku
+		}
+
+		for term, weight := range weights {
+			index.postings[term] = append(index.postings[term], posting{doc: i, weight: weight})
+		}
+		index.ids[i] = document.Id
+		total += index.lengths[i]
+	}
+
+	if len(documents) > 0 {
+		index.average = total / float64(len(documents))
+	}
+
+	index.terms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+
+	return index
+}
+
+// Len is the number of documents in the index.
+func (index *Index) Len() int {
+	return len(index.ids)
+}
+
+// Tokenize lower-cases text and splits it into words of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Search returns the documents matching every word of the query, the most
+// relevant first. The last word also matches the words it begins, so
+// results follow the customer as they type, and a word the index does not
+// know matches those within a typo or two of it.
+func (index *Index) Search(query string) []Hit {
+
+	scores := index.match(Tokenize(query), true)
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		hits = append(hits, Hit{Id: index.ids[doc], Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Id < hits[j].Id
+	})
+	return hits
+}
+
+// match scores the documents containing every word, keyed by their
+// position in the index. With prefix set the last word may be unfinished.
+func (index *Index) match(words []string, prefix bool) map[int]float64 {
+
+	var scores map[int]float64
+	for i, word := range words {
+
+		matched := map[int]float64{}
+		for term, factor := range index.expand(word, prefix && i == len(words)-1) {
+			idf := index.idf(term)
+			for _, p := range index.postings[term] {
+				// A document counts once per word, through its best term.
+				if score := factor * idf * index.saturate(p); score > matched[p.doc] {
+					matched[p.doc] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = matched
+			continue
+		}
+		for doc := range scores {
+			if score, ok := matched[doc]; ok {
+				scores[doc] += score
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+	return scores
+}
+
+// Suggest completes the last word of the query with the terms most of the
+// matching products contain, and returns up to limit whole queries.
+func (index *Index) Suggest(query string, limit int) []string {
+
+	words := Tokenize(query)
+	if len(words) == 0 || limit <= 0 {
+		return nil
+	}
+	head, last := words[:len(words)-1], words[len(words)-1]
+
+	// Completions only count in products that match the earlier words.
+	var within map[int]float64
+	if len(head) > 0 {
+		within = index.match(head, false)
+	}
+
+	candidates := index.prefixed(last)
+	if len(candidates) == 0 {
+		for term := range index.fuzzy(last) {
+			candidates = append(candidates, term)
+		}
+	}
+
+	type completion struct {
+		term  string
+		total int
+	}
+	var completions []completion
+	for _, term := range candidates {
+		total := 0
+		for _, p := range index.postings[term] {
+			if _, ok := within[p.doc]; within == nil || ok {
+				total++
+			}
+		}
+		if total > 0 {
+			completions = append(completions, completion{term, total})
+		}
+	}
+	sort.Slice(completions, func(i, j int) bool {
+		if completions[i].total != completions[j].total {
+			return completions[i].total > completions[j].total
+		}
+		return completions[i].term < completions[j].term
+	})
+
+	prefix := strings.Join(head, " ")
+	if prefix != "" {
+		prefix += " "
+	}
+
+	var suggestions []string
+	for _, completion := range completions {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, prefix+completion.term)
+	}
+	return suggestions
+}
+
+// expand maps a query word to the indexed terms it matches and how much
+// each of them counts.
+func (index *Index) expand(word string, prefix bool) map[string]float64 {
+
+	terms := map[string]float64{}
+	if _, ok := index.postings[word]; ok {
+		terms[word] = 1
+	}
+
+	if prefix {
+		for _, term := range index.prefixed(word) {
+			if term != word {
+				terms[term] = prefixFactor
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return index.fuzzy(word)
+	}
+	return terms
+}
+
+// prefixed returns the terms that begin with prefix, in order.
+func (index *Index) prefixed(prefix string) []string {
+	var terms []string
+	for i := sort.SearchStrings(index.terms, prefix); i < len(index.terms) && len(terms) < maxExpansions; i++ {
+		if !strings.HasPrefix(index.terms[i], prefix) {
+			break
+		}
+		terms = append(terms, index.terms[i])
+	}
+	return terms
+}
+
+// fuzzy returns the terms within the typos allowed for word, each counting
+// less the more edits it takes.
+func (index *Index) fuzzy(word string) map[string]float64 {
+
+	terms := map[string]float64{}
+	allowed := allowedEdits(word)
+	if allowed == 0 {
+		return terms
+	}
+
+	runes := []rune(word)
+	for _, term := range index.terms {
+		candidate := []rune(term)
+		if diff := len(candidate) - len(runes); diff > allowed || -diff > allowed {
+			continue
+		}
+		if edits := distance(runes, candidate); edits <= allowed {
+			terms[term] = 1 / float64(1+edits)
+		}
+		if len(terms) == maxExpansions {
+			break
+		}
+	}
+	return terms
+}
+
+// allowedEdits is how many typos a word may have: none in short words,
+// where one edit makes another word, and two in long ones.
+func allowedEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b: the
+// insertions, deletions, substitutions and swaps of neighbouring letters
+// that turn one into the other.
+func distance(a []rune, b []rune) int {
+
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func (index *Index) idf(term string) float64 {
+	n := float64(len(index.ids))
+	df := float64(len(index.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+func (index *Index) saturate(p posting) float64 {
+	norm := 1.0
+	if index.average > 0 {
+		norm = 1 - bm25B + bm25B*index.lengths[p.doc]/index.average
+	}
+	return p.weight * (bm25K1 + 1) / (p.weight + bm25K1*norm)
+}
@@ -0,0 +1,177 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var catalogue = []Document{
+	{Id: 1, Name: "Oxford Shirt", Sku: "SKU-001", Brand: "Acme", Categories: []string{"Shirts"}, Description: "A cotton shirt for the office."},
+	{Id: 2, Name: "Running Shoes", Sku: "SKU-002", Brand: "Swift", Categories: []string{"Shoes"}, Description: "Light shoes with a cotton lining."},
+	{Id: 3, Name: "Denim Jacket", Sku: "SKU-003", Brand: "Acme", Categories: []string{"Jackets"}, Description: "Goes well over a shirt."},
+	{Id: 4, Name: "Wool Hat", Sku: "HAT-4", Brand: "Acme", Categories: []string{"Accessories"}, Details: "Hand wash only."},
+}
+
+func ids(hits []Hit) string {
+	var out []uint64
+	for _, hit := range hits {
+		out = append(out, hit.Id)
+	}
+	return fmt.Sprint(out)
+}
+
+func TestTokenize(t *testing.T) {
+	if got := fmt.Sprint(Tokenize("  Men's T-Shirt, size XL! ")); got != "[men s t shirt size xl]" {
+		t.Errorf("Tokenize = %s", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+
+	index := Build(catalogue)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// A shirt named so outranks one only described so.
+		{"shirt", "[1 3]"},
+		{"SHIRT", "[1 3]"},
+		{"acme shirt", "[1 3]"},
+		{"cotton shoes", "[2]"},
+		// Equal matches favour the shorter document.
+		{"acme", "[4 3 1]"},
+		{"accessories", "[4]"},
+		{"wash", "[4]"},
+		{"sku-002", "[2]"},
+		{"sku002", "[2]"},
+		// The last word may be unfinished; earlier words may not.
+		{"run", "[2]"},
+		{"run shoes", "[]"},
+		{"oxford sh", "[1]"},
+		// Typos.
+		{"shrit", "[1 3]"},
+		{"jakcet", "[3]"},
+		{"denium jacket", "[3]"},
+		{"runing", "[2]"},
+		// Short words must be typed right.
+		{"hta", "[]"},
+		{"socks", "[]"},
+		{"", "[]"},
+		{"!!", "[]"},
+	}
+
+	for _, test := range tests {
+		if got := ids(index.Search(test.query)); got != test.want {
+			t.Errorf("Search(%q) = %s, want %s", test.query, got, test.want)
+		}
+	}
+}
+
+func TestSearchScoresExactWordsHigher(t *testing.T) {
+
+	index := Build([]Document{
+		{Id: 1, Name: "Shirts"},
+		{Id: 2, Name: "Shirt"},
+	})
+
+	hits := index.Search("shirt")
+	if ids(hits) != "[2 1]" || hits[0].Score <= hits[1].Score {
+		t.Errorf("hits = %+v, want the exact word ahead of its completion", hits)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+
+	index := Build(catalogue)
+
+	tests := []struct {
+		query string
+		limit int
+		want  string
+	}{
+		{"sh", 5, "[shirt shirts shoes]"},
+		{"sh", 1, "[shirt]"},
+		{"acme sh", 5, "[acme shirt acme shirts]"},
+		{"swift sh", 5, "[swift shoes]"},
+		{"jakcet", 5, "[jacket]"},
+		{"zz", 5, "[]"},
+		{"", 5, "[]"},
+	}
+
+	for _, test := range tests {
+		if got := fmt.Sprint(index.Suggest(test.query, test.limit)); got != test.want {
+			t.Errorf("Suggest(%q, %d) = %s, want %s", test.query, test.limit, got, test.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"shirt", "shirt", 0},
+		{"shirt", "shrit", 1},
+		{"shirt", "shirts", 1},
+		{"shirt", "skirt", 1},
+		{"jacket", "jakcet", 1},
+		{"", "hat", 3},
+	}
+
+	for _, test := range tests {
+		if got := distance([]rune(test.a), []rune(test.b)); got != test.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestEngine(t *testing.T) {
+
+	loads := 0
+	documents := catalogue[:1]
+	var failure error
+	engine := NewEngine(func() ([]Document, error) {
+		loads++
+		return documents, failure
+	}, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		index, err := engine.Index()
+		if err != nil {
+			t.Fatalf("Index: %v", err)
+		}
+		if index.Len() != 1 {
+			t.Errorf("index holds %d documents, want 1", index.Len())
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loaded %d times, want the index kept between searches", loads)
+	}
+
+	documents = catalogue
+	engine.Invalidate()
+	if index, _ := engine.Index(); index.Len() != len(catalogue) || loads != 2 {
+		t.Errorf("index holds %d documents after %d loads, want it rebuilt", index.Len(), loads)
+	}
+
+	failure = errors.New("database is down")
+	engine.Invalidate()
+	if _, err := engine.Index(); !errors.Is(err, failure) {
+		t.Errorf("Index = %v, want the load error", err)
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// RolePermissions is the built-in role catalogue. Migration 0011 creates it
+// and the seeder keeps it in sync, so changing it needs a new migration.
+var RolePermissions = map[string][]string{
+	models.RoleCustomer: {},
+	models.RoleSupport: {
+		models.PermissionOrdersViewAny,
+		models.PermissionOrdersManage,
+		models.PermissionUsersView,
+		models.PermissionReviewsManage,
+	},
+	models.RoleCatalogManager: {
+		models.PermissionCatalogManage,
+	},
+	models.RoleAdmin: {
+		models.PermissionCatalogManage,
+		models.PermissionOrdersViewAny,
+		models.PermissionOrdersManage,
+		models.PermissionOrdersRefund,
+		models.PermissionUsersView,
+		models.PermissionUsersManage,
+		models.PermissionRolesManage,
+		models.PermissionCouponsManage,
+		models.PermissionReviewsManage,
+	},
+}
+
+// access control service
+type AccessControlService interface {
+	Roles() ([]models.Role, error)
+	UserRoles(userId uint64) []string
+	Permissions(roles []string) []string
+	HasPermission(roles []string, permission string) bool
+	AssignRoles(userId uint64, roles ...string) error
+}
+
+type accessControlServices struct {
+	store repositories.Store
+}
+
+func AccessControl(store repositories.Store) AccessControlService {
+	return &accessControlServices{store: store}
+}
+
+func (service *accessControlServices) Roles() ([]models.Role, error) {
+	return service.store.Roles().List()
+}
+
+// UserRoles returns the names of the user's active roles, or none when
+// they cannot be read.
+func (service *accessControlServices) UserRoles(userId uint64) []string {
+	roles, err := service.store.Roles().ForUser(userId)
+	if err != nil {
+		return []string{}
+	}
+	return roles
+}
+
+// Permissions returns what roles grant between them, or nothing when it
+// cannot be read.
+func (service *accessControlServices) Permissions(roles []string) []string {
+	permissions, err := service.store.Roles().Permissions(roles)
+	if err != nil {
+		return []string{}
+	}
+	return permissions
+}
+
+func (service *accessControlServices) HasPermission(roles []string, permission string) bool {
+	for _, granted := range service.Permissions(roles) {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignRoles replaces the user's roles; see UserRepository.AssignRoles.
+func (service *accessControlServices) AssignRoles(userId uint64, roles ...string) error {
+	return service.store.Users().AssignRoles(userId, roles...)
+}
+
+// ClaimRoles reads the role names embedded in a validated access token.
+func ClaimRoles(claims jwt.MapClaims) []string {
+	roles := []string{}
+	values, ok := claims["roles"].([]interface{})
+	if !ok {
+		return roles
+	}
+	for _, value := range values {
+		if role, ok := value.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
@@ -0,0 +1,265 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	helpers "backend/src/helpers"
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownEmail        = errors.New("no user has this e-mail address")
+	ErrAccountNotConfirmed = errors.New("the account has not been confirmed yet")
+	ErrAccountConfirmed    = errors.New("the account has already been confirmed")
+)
+
+// auth service
+//
+// Signs users in and out and runs the e-mail driven steps of an account:
+// registration and its confirmation, and password reset.
+type AuthService interface {
+	Login(email string, password string) (models.User, *TokenPair, error)
+	Logout(userId uint64, familyId string) error
+	LogoutAll(userId uint64) error
+	Register(name string, email string, password string) (models.User, error)
+	Confirm(token string) error
+	ResendConfirmation(email string) error
+	Forgot(email string) error
+	Reset(token string, email string, password string) error
+}
+
+type authServices struct {
+	config *appconfig.Config
+	store  repositories.Store
+}
+
+func Auth(config *appconfig.Config, store repositories.Store) AuthService {
+	return &authServices{config: config, store: store}
+}
+
+// Login checks the user's password, upgrading its hash when the hasher
+// asks for it, and opens a new session.
+func (service *authServices) Login(email string, password string) (models.User, *TokenPair, error) {
+
+	user, err := service.findByEmail(email)
+	if err != nil {
+		return user, nil, err
+	}
+
+	if user.Status == 0 {
+		return user, nil, ErrAccountNotConfirmed
+	}
+
+	passwords := PasswordHashService(service.config)
+	valid, rehash := passwords.Verify(password, user.Password, user.Salt)
+	if !valid {
+		return user, nil, ErrIncorrectPassword
+	}
+
+	if rehash {
+		if hashed, err := passwords.Hash(password); err == nil {
+			service.store.Users().UpdatePassword(&user, hashed)
+		}
+	}
+
+	if err := logActivity(service.store, user.Id, "User Login", "Sign In", "Sign in to application"); err != nil {
+		return user, nil, err
+	}
+
+	pair, err := Sessions(service.config, service.store).Issue(user)
+	return user, pair, err
+}
+
+func (service *authServices) Logout(userId uint64, familyId string) error {
+	if err := Sessions(service.config, service.store).Revoke(familyId); err != nil {
+		return err
+	}
+	return logActivity(service.store, userId, "User Logout", "Sign Out", "Sign out from application")
+}
+
+func (service *authServices) LogoutAll(userId uint64) error {
+	if err := Sessions(service.config, service.store).RevokeAll(userId); err != nil {
+		return err
+	}
+	return logActivity(service.store, userId, "User Logout", "Sign Out Everywhere", "Sign out from all sessions")
+}
+
+// Register creates an unconfirmed customer account. Without its
+// confirmation e-mail the account could never be used, so it is only kept
+// once the e-mail is queued.
+func (service *authServices) Register(name string, email string, password string) (models.User, error) {
+
+	taken, err := service.store.Users().EmailTaken(email, 0)
+	if err != nil {
+		return models.User{}, err
+	}
+	if taken {
+		return models.User{}, ErrEmailTaken
+	}
+
+	hashed, err := PasswordHashService(service.config).Hash(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	names := strings.Split(name, " ")
+	user := models.User{
+		FirstName: helpers.NewNullString(names[0]),
+		LastName:  helpers.NewNullString(strings.Join(names[1:], " ")),
+		Email:     email,
+		Password:  hashed,
+		Status:    0,
+	}
+
+	err = service.store.Transaction(func(tx repositories.Store) error {
+		if err := tx.Users().Create(&user); err != nil {
+			return err
+		}
+		if err := tx.Users().AssignRoles(user.Id, models.RoleCustomer); err != nil {
+			return err
+		}
+		if err := service.sendVerification(tx, user, models.AuthTypeEmailConfirm); err != nil {
+			return err
+		}
+		return logActivity(tx, user.Id, "User Register", "Sign Up", "Register new user account")
+	})
+
+	return user, err
+}
+
+// Confirm activates the account a confirmation token was issued for.
+func (service *authServices) Confirm(token string) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		verification, err := Verifications(service.config, tx).Consume(models.AuthTypeEmailConfirm, token, "")
+		if err != nil {
+			return err
+		}
+
+		user, err := tx.Users().Find(uint64(verification.UserId))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Users().Update(&user, models.User{Status: 1}); err != nil {
+			return err
+		}
+
+		return logActivity(tx, user.Id, "User Verification", "Email Confirmation", "Confirm new member registration account")
+	})
+}
+
+func (service *authServices) ResendConfirmation(email string) error {
+
+	user, err := service.findByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if user.Status == 1 {
+		return ErrAccountConfirmed
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+		if err := service.sendVerification(tx, user, models.AuthTypeEmailConfirm); err != nil {
+			return err
+		}
+		return logActivity(tx, user.Id, "User Verification", "Resend Confirmation", "Request a new account confirmation link")
+	})
+}
+
+func (service *authServices) Forgot(email string) error {
+
+	user, err := service.findByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+		if err := service.sendVerification(tx, user, models.AuthTypeResetPassword); err != nil {
+			return err
+		}
+		return logActivity(tx, user.Id, "Request Forgot Password", "Forgot Password", "Request reset password link")
+	})
+}
+
+// Reset sets a new password with a reset token issued to email. The
+// account counts as confirmed from then on, and every session it had is
+// signed out.
+func (service *authServices) Reset(token string, email string, password string) error {
+
+	user, err := service.findByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := PasswordHashService(service.config).Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		if _, err := Verifications(service.config, tx).Consume(models.AuthTypeResetPassword, token, email); err != nil {
+			return err
+		}
+
+		if err := tx.Users().UpdatePassword(&user, hashed); err != nil {
+			return err
+		}
+		if err := tx.Users().Update(&user, models.User{Status: 1}); err != nil {
+			return err
+		}
+
+		if err := Sessions(service.config, tx).RevokeAll(user.Id); err != nil {
+			return err
+		}
+
+		return logActivity(tx, user.Id, "User Recovery", "Reset Password", "Reset account password")
+	})
+}
+
+func (service *authServices) findByEmail(email string) (models.User, error) {
+	user, err := service.store.Users().FindByEmail(email)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return user, ErrUnknownEmail
+	}
+	return user, err
+}
+
+// sendVerification issues a confirmation or reset token for user inside
+// tx and queues the e-mail that carries it.
+func (service *authServices) sendVerification(tx repositories.Store, user models.User, authType string) error {
+
+	verifications := Verifications(service.config, tx)
+	token, err := verifications.Issue(user, authType)
+	if err != nil {
+		return err
+	}
+
+	template, path := mailer.TemplateConfirm, "auth/register/confirm/"
+	if authType == models.AuthTypeResetPassword {
+		template, path = mailer.TemplateReset, "auth/email/reset/"
+	}
+
+	return tx.Outbox().Enqueue(user.Email, template, map[string]interface{}{
+		"Name":      user.FirstName.String,
+		"Link":      mailer.Link(service.config, path+token),
+		"ExpiresIn": fmt.Sprintf("%d minutes", int(verifications.Lifespan().Minutes())),
+	})
+}
@@ -0,0 +1,117 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuthRegister(t *testing.T) {
+
+	store := newShopStore()
+	auth := Auth(testConfig(), store)
+
+	if _, err := auth.Register("Someone Else", "other@example.com", "password"); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("Register with a taken e-mail = %v, want ErrEmailTaken", err)
+	}
+
+	user, err := auth.Register("Jane van Dam", "jane@example.com", "password")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if got := store.users[user.Id]; got.Status != 0 || got.FirstName.String != "Jane" || got.LastName.String != "van Dam" {
+		t.Errorf("user = %+v, want an unconfirmed Jane van Dam", got)
+	}
+	if roles := store.userRoles[user.Id]; len(roles) != 1 || roles[0] != models.RoleCustomer {
+		t.Errorf("roles = %v, want customer", roles)
+	}
+	if len(store.outbox) != 1 || store.outbox[0].To != "jane@example.com" {
+		t.Errorf("outbox = %+v, want the confirmation e-mail", store.outbox)
+	}
+
+	if _, _, err := auth.Login("jane@example.com", "password"); !errors.Is(err, ErrAccountNotConfirmed) {
+		t.Errorf("Login before confirming = %v, want ErrAccountNotConfirmed", err)
+	}
+}
+
+func TestAuthLogin(t *testing.T) {
+
+	store := newShopStore()
+	config := testConfig()
+	auth := Auth(config, store)
+
+	hashed, err := PasswordHashService(config).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := store.users[1]
+	user.Password, user.Status = hashed, 1
+	store.users[1] = user
+
+	if _, _, err := auth.Login("nobody@example.com", "password"); !errors.Is(err, ErrUnknownEmail) {
+		t.Errorf("Login with an unknown e-mail = %v, want ErrUnknownEmail", err)
+	}
+	if _, _, err := auth.Login("buyer@example.com", "wrong-password"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("Login with a wrong password = %v, want ErrIncorrectPassword", err)
+	}
+
+	_, pair, err := auth.Login("buyer@example.com", "password")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Errorf("pair = %+v, want both tokens", pair)
+	}
+	if len(store.activities) != 1 {
+		t.Errorf("got %d activities, want 1", len(store.activities))
+	}
+}
+
+func TestAuthReset(t *testing.T) {
+
+	store := newShopStore()
+	config := testConfig()
+	config.Auth.VerificationTokenLifespan = time.Hour
+	auth := Auth(config, store)
+
+	if _, err := Sessions(config, store).Issue(store.users[1]); err != nil {
+		t.Fatal(err)
+	}
+	token, err := Verifications(config, store).Issue(store.users[1], models.AuthTypeResetPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := auth.Reset(token, "other@example.com", "new-password"); !errors.Is(err, ErrVerificationTokenInvalid) {
+		t.Fatalf("Reset with another user's e-mail = %v, want ErrVerificationTokenInvalid", err)
+	}
+	if err := auth.Reset(token, "buyer@example.com", "new-password"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+
+	user := store.users[1]
+	if valid, _ := PasswordHashService(config).Verify("new-password", user.Password, ""); !valid || user.Status != 1 {
+		t.Errorf("user = %+v, want the new password and a confirmed account", user)
+	}
+	for _, row := range store.authentications {
+		if row.AuthType == models.AuthTypeRefreshToken && row.Status != models.AuthStatusRevoked {
+			t.Errorf("refresh token %+v survived the reset", row)
+		}
+	}
+
+	if err := auth.Reset(token, "buyer@example.com", "other-password"); !errors.Is(err, ErrVerificationTokenInvalid) {
+		t.Errorf("second Reset = %v, want ErrVerificationTokenInvalid", err)
+	}
+}
@@ -0,0 +1,463 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrCartQuantity refuses a cart line more items than its quantity column
+// holds. Stock is only checked at checkout.
+var ErrCartQuantity = fmt.Errorf("a cart line cannot hold more than %d items", math.MaxUint16)
+
+// CartSession is what the storefront header shows for a signed-in user or
+// a guest. Guests have no wishlist.
+type CartSession struct {
+	Order    models.Order
+	Lines    []repositories.OrderLine
+	Wishlist []repositories.WishlistItem
+}
+
+// CartItem identifies the inventory a customer wants to buy by product, size and colour.
+type CartItem struct {
+	ProductId uint64
+	SizeId    uint64
+	ColourId  uint64
+	Qty       uint16
+}
+
+// CartOwner is whose cart a request works on: a signed-in user, or a guest
+// holding the cart token their cart was opened with. Only the token's hash
+// is stored on the order.
+type CartOwner struct {
+	UserId uint64
+	Token  string
+}
+
+func (owner CartOwner) IsGuest() bool {
+	return owner.UserId == 0
+}
+
+// cart service
+type CartService interface {
+	Session(owner CartOwner) (CartSession, error)
+	Add(owner CartOwner, item CartItem) (string, error)
+	UpdateLine(owner CartOwner, detailId uint64, qty uint16) error
+	RemoveLine(owner CartOwner, detailId uint64) error
+	Clear(owner CartOwner) error
+	Merge(userId uint64, token string) error
+	AddToWishlist(userId uint64, productId uint64) error
+}
+
+type cartServices struct {
+	store repositories.Store
+}
+
+func Cart(store repositories.Store) CartService {
+	return &cartServices{store: store}
+}
+
+func newCartToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// openCart finds the owner's open cart without locking it.
+func openCart(store repositories.Store, owner CartOwner) (models.Order, error) {
+	if !owner.IsGuest() {
+		return store.Orders().OpenCart(owner.UserId)
+	}
+	if owner.Token == "" {
+		return models.Order{}, repositories.ErrNotFound
+	}
+	return store.Orders().OpenGuestCart(hashToken(owner.Token))
+}
+
+func lockCart(tx repositories.Store, owner CartOwner) (models.Order, error) {
+	if !owner.IsGuest() {
+		return tx.Orders().LockOpenCart(owner.UserId)
+	}
+	if owner.Token == "" {
+		return models.Order{}, repositories.ErrNotFound
+	}
+	return tx.Orders().LockGuestCart(hashToken(owner.Token))
+}
+
+func (service *cartServices) Session(owner CartOwner) (CartSession, error) {
+
+	var session CartSession
+
+	order, err := openCart(service.store, owner)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return session, err
+	}
+	session.Order = order
+
+	if owner.IsGuest() {
+		if order.Id == 0 {
+			return session, nil
+		}
+		session.Lines, err = service.store.Orders().Lines(order.Id)
+		return session, err
+	}
+
+	if session.Lines, err = service.store.Orders().CartLines(owner.UserId); err != nil {
+		return session, err
+	}
+
+	if session.Wishlist, err = service.store.Users().Wishlist(owner.UserId); err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+// Add puts item in the owner's open cart, opening a new cart order when
+// they have none. An item with no inventory for its size and colour is
+// refused with repositories.ErrNotFound. For a guest Add returns the cart
+// token to send from now on: theirs, or a new one when it named no open
+// cart, so a guest never chooses their own token.
+func (service *cartServices) Add(owner CartOwner, item CartItem) (string, error) {
+
+	token := ""
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		product, err := tx.Catalog().Product(item.ProductId)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := tx.Catalog().FindInventory(item.ProductId, item.SizeId, item.ColourId)
+		if err != nil {
+			return err
+		}
+
+		order, err := lockCart(tx, owner)
+		if errors.Is(err, repositories.ErrNotFound) {
+			payment, err := tx.Orders().DefaultPayment()
+			if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return err
+			}
+			order = models.Order{
+				UserId:        owner.UserId,
+				PaymentId:     payment.Id,
+				InvoiceNumber: strconv.FormatInt(helpers.NowTicks(), 10),
+				Status:        models.OrderStatusCart,
+			}
+			if owner.IsGuest() {
+				if owner.Token, err = newCartToken(); err != nil {
+					return err
+				}
+				order.CartToken = sql.NullString{String: hashToken(owner.Token), Valid: true}
+			}
+			if err := tx.Orders().Create(&order); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		detail, err := tx.Orders().FindDetail(order.Id, inventory.Id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			detail = models.OrderDetail{
+				OrderId:     order.Id,
+				InventoryId: inventory.Id,
+				Price:       product.Price,
+				Status:      1,
+			}
+		} else if err != nil {
+			return err
+		}
+		// Summed wider than the column so a large request cannot wrap
+		// around to a small quantity.
+		qty := uint32(detail.Qty) + uint32(item.Qty)
+		if qty > math.MaxUint16 {
+			return ErrCartQuantity
+		}
+		detail.Qty = uint16(qty)
+		detail.Total = detail.Price.Times(int64(detail.Qty))
+		if err := tx.Orders().SaveDetail(&detail); err != nil {
+			return err
+		}
+
+		if err := tx.Orders().AttachProduct(order.Id, product.Id); err != nil {
+			return err
+		}
+
+		if err := recalculateCart(tx, &order); err != nil {
+			return err
+		}
+
+		return logCartActivity(tx, owner, "Add Cart", "Add product to cart with name "+product.Name, "Your has been added product to cart. with name "+product.Name)
+	})
+
+	if err == nil && owner.IsGuest() {
+		token = owner.Token
+	}
+	return token, err
+}
+
+// UpdateLine sets the quantity of a line in the owner's open cart. A line
+// that is not in that cart is repositories.ErrNotFound.
+func (service *cartServices) UpdateLine(owner CartOwner, detailId uint64, qty uint16) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		order, detail, err := cartDetail(tx, owner, detailId)
+		if err != nil {
+			return err
+		}
+
+		detail.Qty = qty
+		detail.Total = detail.Price.Times(int64(qty))
+		if err := tx.Orders().SaveDetail(&detail); err != nil {
+			return err
+		}
+
+		if err := recalculateCart(tx, &order); err != nil {
+			return err
+		}
+
+		return logCartActivity(tx, owner, "Update Cart", "Update cart line quantity", "Your cart has been updated.")
+	})
+}
+
+// RemoveLine takes a line out of the owner's open cart.
+func (service *cartServices) RemoveLine(owner CartOwner, detailId uint64) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		order, detail, err := cartDetail(tx, owner, detailId)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Orders().DeleteDetail(&detail); err != nil {
+			return err
+		}
+
+		if err := recalculateCart(tx, &order); err != nil {
+			return err
+		}
+
+		return logCartActivity(tx, owner, "Remove Cart", "Remove line from cart", "Your cart has been updated.")
+	})
+}
+
+// Clear empties the owner's open cart, coupon included, and keeps the
+// order to fill again. Clearing without a cart does nothing.
+func (service *cartServices) Clear(owner CartOwner) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := lockCart(tx, owner)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := tx.Orders().DeleteDetails(order.Id); err != nil {
+			return err
+		}
+
+		if err := tx.Coupons().Detach(order.Id); err != nil {
+			return err
+		}
+
+		if err := recalculateCart(tx, &order); err != nil {
+			return err
+		}
+
+		return logCartActivity(tx, owner, "Clear Cart", "Remove every line from cart", "Your cart has been emptied.")
+	})
+}
+
+// Merge moves the guest cart named by token into the user's open cart
+// when the guest signs in. Quantities of the same inventory are added up
+// but capped at the stock left, though never below what the user already
+// had; a user without an open cart simply takes the guest cart over. An
+// unknown token merges nothing.
+func (service *cartServices) Merge(userId uint64, token string) error {
+
+	if token == "" {
+		return nil
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		guest, err := tx.Orders().LockGuestCart(hashToken(token))
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		lines, err := tx.Orders().Details(guest.Id)
+		if err != nil {
+			return err
+		}
+
+		order, err := tx.Orders().LockOpenCart(userId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			order = guest
+		} else if err != nil {
+			return err
+		}
+		order.UserId = userId
+		order.CartToken = sql.NullString{}
+
+		for _, line := range lines {
+
+			inventory, err := tx.Catalog().LockInventory(line.InventoryId)
+			if err != nil {
+				return err
+			}
+
+			detail := line
+			qty := uint32(line.Qty)
+			if order.Id != guest.Id {
+				detail, err = tx.Orders().FindDetail(order.Id, line.InventoryId)
+				if errors.Is(err, repositories.ErrNotFound) {
+					detail = models.OrderDetail{OrderId: order.Id, InventoryId: line.InventoryId, Price: line.Price, Status: 1}
+				} else if err != nil {
+					return err
+				}
+				qty += uint32(detail.Qty)
+			}
+
+			// The merged line is cut down to the stock, but never below what
+			// the user's own cart already held.
+			if qty > uint32(inventory.Stock) {
+				qty = uint32(inventory.Stock)
+				if order.Id != guest.Id && uint32(detail.Qty) > qty {
+					qty = uint32(detail.Qty)
+				}
+			}
+			detail.Qty = uint16(qty)
+
+			if detail.Qty == 0 {
+				if detail.Id > 0 {
+					if err := tx.Orders().DeleteDetail(&detail); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			detail.Total = detail.Price.Times(int64(detail.Qty))
+			if err := tx.Orders().SaveDetail(&detail); err != nil {
+				return err
+			}
+			if err := tx.Orders().AttachProduct(order.Id, inventory.ProductId); err != nil {
+				return err
+			}
+		}
+
+		if order.Id != guest.Id {
+			if err := tx.Orders().DeleteDetails(guest.Id); err != nil {
+				return err
+			}
+			if err := tx.Orders().DetachProducts(guest.Id); err != nil {
+				return err
+			}
+			if err := tx.Orders().Delete(&guest); err != nil {
+				return err
+			}
+		}
+
+		if err := recalculateCart(tx, &order); err != nil {
+			return err
+		}
+
+		return logActivity(tx, userId, "Merge Cart", "Merge guest cart into cart", "The cart you filled before signing in has been added to your cart.")
+	})
+}
+
+// cartDetail locks the owner's open cart and finds one of its lines.
+func cartDetail(tx repositories.Store, owner CartOwner, detailId uint64) (models.Order, models.OrderDetail, error) {
+
+	order, err := lockCart(tx, owner)
+	if err != nil {
+		return order, models.OrderDetail{}, err
+	}
+
+	detail, err := tx.Orders().Detail(order.Id, detailId)
+	return order, detail, err
+}
+
+// recalculateCart sets the item count and totals of a cart order from its
+// details, and drops the products it no longer holds from orders_carts.
+// Until checkout prices the order, what it costs is its subtotal.
+func recalculateCart(tx repositories.Store, order *models.Order) error {
+
+	details, err := tx.Orders().Details(order.Id)
+	if err != nil {
+		return err
+	}
+
+	var items uint32
+	order.Subtotal = 0
+	for _, detail := range details {
+		items += uint32(detail.Qty)
+		order.Subtotal += detail.Total
+	}
+	order.TotalItem = uint16(min(items, math.MaxUint16))
+	order.TotalPaid = order.Subtotal
+
+	if err := tx.Orders().PruneProducts(order.Id); err != nil {
+		return err
+	}
+
+	return tx.Orders().Save(order)
+}
+
+func (service *cartServices) AddToWishlist(userId uint64, productId uint64) error {
+
+	if _, err := service.store.Users().Find(userId); err != nil {
+		return err
+	}
+
+	if err := service.store.Users().AddToWishlist(userId, productId); err != nil {
+		return err
+	}
+
+	return logActivity(service.store, userId, "Add Wishlist", "Add Product To Wishlist", "Your has been added product to your wishlist.")
+}
+
+// logCartActivity records cart changes of signed-in users; guests have no
+// activity log.
+func logCartActivity(tx repositories.Store, owner CartOwner, subject string, event string, description string) error {
+	if owner.IsGuest() {
+		return nil
+	}
+	return logActivity(tx, owner.UserId, subject, event, description)
+}
+
+func logActivity(store repositories.Store, userId uint64, subject string, event string, description string) error {
+	return store.Activities().Create(&models.Activity{
+		UserId:      int64(userId),
+		Subject:     subject,
+		Event:       event,
+		Description: description,
+	})
+}
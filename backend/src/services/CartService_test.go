@@ -0,0 +1,443 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func testConfig() *appconfig.Config {
+	return &appconfig.Config{
+		FrontendURL: "http://shop.test",
+		Auth:        appconfig.AuthConfig{PasswordHasher: "argon2id"},
+	}
+}
+
+// newShopStore returns a store with one customer, two published products
+// with one inventory row each, a payment method and the price rules.
+func newShopStore() fakeStore {
+
+	store := newFakeStore()
+	published := time.Now().Add(-time.Hour)
+
+	store.users[1] = models.User{Id: 1, Email: "buyer@example.com"}
+	store.users[2] = models.User{Id: 2, Email: "other@example.com", Phone: "555-0100"}
+
+	store.products[10] = models.Product{Id: 10, Name: "Shirt", Price: money.FromInt(20), TotalRating: 9, RatingAverage: 4.5, RatingCount: 2, Rating4: 1, Rating5: 1, Status: 1, PublishedAt: &published}
+	store.products[11] = models.Product{Id: 11, Name: "Shoes", Price: money.FromInt(50), TotalRating: 2, RatingAverage: 2, RatingCount: 1, Rating2: 1, Status: 1, PublishedAt: &published}
+	store.inventories[100] = models.ProductInventory{Id: 100, ProductId: 10, SizeId: 1, ColourId: 1, Stock: 5}
+	store.inventories[110] = models.ProductInventory{Id: 110, ProductId: 11, SizeId: 1, ColourId: 1, Stock: 1}
+
+	store.payments = []models.Payment{{Id: 7, Name: "Direct Bank Transfer", Status: 1}}
+	store.rules = []models.PriceRule{
+		{Id: 1, Name: "Store discount", Kind: models.PriceRuleDiscount, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationPercent, Value: money.FromInt(5), Status: 1},
+		{Id: 2, Name: "Flat shipment", Kind: models.PriceRuleShipment, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationFixed, Value: money.FromInt(15), Status: 1},
+	}
+	store.taxRates = []models.TaxRate{{Id: 1, Name: "Default tax", Rate: money.MustParseRate("10"), Status: 1}}
+
+	store.lastId = 1000
+	return store
+}
+
+func TestCartAddOpensOrder(t *testing.T) {
+
+	store := newShopStore()
+
+	if _, err := Cart(store).Add(CartOwner{UserId: 1}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	order, err := store.Orders().OpenCart(1)
+	if err != nil {
+		t.Fatalf("OpenCart: %v", err)
+	}
+	if order.TotalItem != 2 || order.Subtotal != money.FromInt(40) || order.PaymentId != 7 {
+		t.Errorf("order = %+v, want 2 items, subtotal 40 and payment 7", order)
+	}
+
+	details, _ := store.Orders().Details(order.Id)
+	if len(details) != 1 || details[0].InventoryId != 100 || details[0].Qty != 2 || details[0].Total != money.FromInt(40) {
+		t.Errorf("details = %+v, want one line of 2 x inventory 100", details)
+	}
+
+	if !store.carts[[2]uint64{order.Id, 10}] {
+		t.Error("product 10 is not attached to the cart order")
+	}
+	if len(store.activities) != 1 || store.activities[0].Subject != "Add Cart" {
+		t.Errorf("activities = %+v, want one Add Cart entry", store.activities)
+	}
+}
+
+func TestCartAddMergesLinesForTheSameInventory(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cart.Add(CartOwner{UserId: 1}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1}); err != nil {
+			t.Fatalf("Add #%d: %v", i+1, err)
+		}
+	}
+
+	if len(store.orders) != 1 {
+		t.Fatalf("got %d orders, want the second add to reuse the open cart", len(store.orders))
+	}
+
+	order, _ := store.Orders().OpenCart(1)
+	details, _ := store.Orders().Details(order.Id)
+	if len(details) != 1 || details[0].Qty != 2 || details[0].Total != money.FromInt(40) {
+		t.Errorf("details = %+v, want a single line of 2 items", details)
+	}
+	if order.TotalItem != 2 || order.Subtotal != money.FromInt(40) {
+		t.Errorf("order = %+v, want 2 items and subtotal 40", order)
+	}
+}
+
+func TestCartAddRejectsQuantityThatWouldWrap(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+	user := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: math.MaxUint16})
+
+	_, err := cart.Add(CartOwner{UserId: 1}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+	if !errors.Is(err, ErrCartQuantity) {
+		t.Fatalf("Add = %v, want ErrCartQuantity", err)
+	}
+
+	details, _ := store.Orders().Details(user.Id)
+	if len(details) != 1 || details[0].Qty != math.MaxUint16 {
+		t.Errorf("details = %+v, want the line left at %d", details, math.MaxUint16)
+	}
+}
+
+func TestCartAddUnknownProduct(t *testing.T) {
+
+	store := newShopStore()
+
+	_, err := Cart(store).Add(CartOwner{UserId: 1}, CartItem{ProductId: 99, SizeId: 1, ColourId: 1, Qty: 1})
+	if !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("Add = %v, want ErrNotFound", err)
+	}
+	if len(store.orders) != 0 || len(store.activities) != 0 {
+		t.Errorf("a failed add left %d orders and %d activities behind", len(store.orders), len(store.activities))
+	}
+}
+
+func TestCartAddUnknownInventory(t *testing.T) {
+
+	store := newShopStore()
+
+	_, err := Cart(store).Add(CartOwner{UserId: 1}, CartItem{ProductId: 10, SizeId: 9, ColourId: 1, Qty: 1})
+	if !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("Add = %v, want ErrNotFound", err)
+	}
+	if len(store.orders) != 0 || len(store.details) != 0 {
+		t.Errorf("a size that is not stocked left %d orders and %d details behind", len(store.orders), len(store.details))
+	}
+}
+
+func TestCartUpdateLine(t *testing.T) {
+
+	store := newShopStore()
+	order := fillCart(t, store,
+		CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1},
+		CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1},
+	)
+	details, _ := store.Orders().Details(order.Id)
+
+	if err := Cart(store).UpdateLine(CartOwner{UserId: 1}, details[0].Id, 3); err != nil {
+		t.Fatalf("UpdateLine: %v", err)
+	}
+
+	order, _ = store.Orders().OpenCart(1)
+	if order.TotalItem != 4 || order.Subtotal != money.FromInt(110) || order.TotalPaid != money.FromInt(110) {
+		t.Errorf("order = %d items, subtotal %v and total %v, want 4, 110 and 110", order.TotalItem, order.Subtotal, order.TotalPaid)
+	}
+	if detail := store.details[details[0].Id]; detail.Qty != 3 || detail.Total != money.FromInt(60) {
+		t.Errorf("detail = %+v, want 3 shirts for 60", detail)
+	}
+
+	if err := Cart(store).UpdateLine(CartOwner{UserId: 2}, details[0].Id, 1); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UpdateLine of another user's line = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCartRemoveLine(t *testing.T) {
+
+	store := newShopStore()
+	order := fillCart(t, store,
+		CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2},
+		CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1},
+	)
+	details, _ := store.Orders().Details(order.Id)
+
+	if err := Cart(store).RemoveLine(CartOwner{UserId: 1}, details[0].Id); err != nil {
+		t.Fatalf("RemoveLine: %v", err)
+	}
+
+	order, _ = store.Orders().OpenCart(1)
+	if order.TotalItem != 1 || order.Subtotal != money.FromInt(50) || order.TotalPaid != money.FromInt(50) {
+		t.Errorf("order = %d items and subtotal %v, want the shoes alone", order.TotalItem, order.Subtotal)
+	}
+	if store.carts[[2]uint64{order.Id, 10}] || !store.carts[[2]uint64{order.Id, 11}] {
+		t.Errorf("orders_carts = %v, want only product 11", store.carts)
+	}
+
+	if err := Cart(store).RemoveLine(CartOwner{UserId: 1}, details[0].Id); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("removing the line again = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCartClear(t *testing.T) {
+
+	store := newShopStore()
+	store.coupons[1] = models.Coupon{Id: 1, Code: "SAVE10", Calculation: models.PriceCalculationPercent, Value: money.FromInt(10), Status: 1}
+	order := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+	if _, err := Coupons(store).Apply(1, "SAVE10"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if err := Cart(store).Clear(CartOwner{UserId: 1}); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	cleared, err := store.Orders().OpenCart(1)
+	if err != nil || cleared.Id != order.Id {
+		t.Fatalf("OpenCart = %d, %v, want the same order kept open", cleared.Id, err)
+	}
+	if cleared.TotalItem != 0 || cleared.Subtotal != 0 || cleared.TotalPaid != 0 {
+		t.Errorf("cleared order = %+v, want no items and nothing to pay", cleared)
+	}
+	if len(store.details) != 0 || len(store.carts) != 0 || len(store.redemptions) != 0 {
+		t.Errorf("clear left %d details, %d products and %d coupons", len(store.details), len(store.carts), len(store.redemptions))
+	}
+
+	if err := Cart(store).Clear(CartOwner{UserId: 2}); err != nil {
+		t.Errorf("Clear without a cart = %v, want nil", err)
+	}
+}
+
+func TestCartSession(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+
+	if _, err := cart.Add(CartOwner{UserId: 1}, CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := cart.AddToWishlist(1, 10); err != nil {
+		t.Fatalf("AddToWishlist: %v", err)
+	}
+
+	session, err := cart.Session(CartOwner{UserId: 1})
+	if err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if session.Order.Id == 0 {
+		t.Error("session has no open order")
+	}
+	if len(session.Lines) != 1 || session.Lines[0].Name != "Shoes" {
+		t.Errorf("lines = %+v, want the shoes", session.Lines)
+	}
+	if len(session.Wishlist) != 1 || session.Wishlist[0].Id != 10 {
+		t.Errorf("wishlist = %+v, want product 10", session.Wishlist)
+	}
+
+	empty, err := cart.Session(CartOwner{UserId: 2})
+	if err != nil {
+		t.Fatalf("Session without a cart: %v", err)
+	}
+	if empty.Order.Id != 0 || len(empty.Lines) != 0 {
+		t.Errorf("session for a user without a cart = %+v, want it empty", empty)
+	}
+}
+
+func TestCartGuest(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+
+	token, err := cart.Add(CartOwner{Token: "made-up"}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if token == "" || token == "made-up" {
+		t.Fatalf("cart token = %q, want a new token for a token that names no cart", token)
+	}
+
+	guest := CartOwner{Token: token}
+	if again, err := cart.Add(guest, CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1}); err != nil || again != token {
+		t.Fatalf("second Add = %q, %v, want the same token", again, err)
+	}
+
+	if len(store.orders) != 1 {
+		t.Fatalf("got %d orders, want one guest cart", len(store.orders))
+	}
+	for _, order := range store.orders {
+		if order.UserId != 0 || order.CartToken.String != hashToken(token) || order.Subtotal != money.FromInt(70) {
+			t.Errorf("guest cart = %+v, want no user, the hashed token and subtotal 70", order)
+		}
+	}
+	if len(store.activities) != 0 {
+		t.Errorf("guest cart wrote %d activities", len(store.activities))
+	}
+
+	session, err := cart.Session(guest)
+	if err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if len(session.Lines) != 2 || session.Wishlist != nil {
+		t.Errorf("guest session = %+v, want two lines and no wishlist", session)
+	}
+
+	if _, err := cart.Session(CartOwner{UserId: 1}); err != nil {
+		t.Fatalf("Session of a user: %v", err)
+	}
+	if err := cart.Clear(CartOwner{Token: "someone-else"}); err != nil {
+		t.Errorf("Clear with an unknown token = %v, want nil", err)
+	}
+	if err := cart.RemoveLine(CartOwner{}, uint64(session.Lines[0].DetailId)); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("RemoveLine without a token = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCartMergeTakesGuestCartOver(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+
+	token, err := cart.Add(CartOwner{}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	guest, _ := store.Orders().OpenGuestCart(hashToken(token))
+
+	if err := cart.Merge(1, token); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	order, err := store.Orders().OpenCart(1)
+	if err != nil || order.Id != guest.Id {
+		t.Fatalf("OpenCart = %d, %v, want the guest cart %d", order.Id, err, guest.Id)
+	}
+	if order.CartToken.Valid || order.TotalItem != 2 {
+		t.Errorf("merged cart = %+v, want no token and 2 items", order)
+	}
+	if _, err := store.Orders().OpenGuestCart(hashToken(token)); !errors.Is(err, repositories.ErrNotFound) {
+		t.Error("the cart token still opens the merged cart")
+	}
+	if len(store.activities) != 1 || store.activities[0].Subject != "Merge Cart" {
+		t.Errorf("activities = %+v, want one Merge Cart entry", store.activities)
+	}
+
+	if err := cart.Merge(1, token); err != nil {
+		t.Errorf("merging a used token again = %v, want nil", err)
+	}
+}
+
+func TestCartMergeCombinesLines(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+	user := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+
+	token, err := cart.Add(CartOwner{}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 4})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := cart.Add(CartOwner{Token: token}, CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	guest, _ := store.Orders().OpenGuestCart(hashToken(token))
+
+	// The shoes sold out while the guest was shopping.
+	shoes := store.inventories[110]
+	shoes.Stock = 0
+	store.inventories[110] = shoes
+
+	if err := cart.Merge(1, token); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	details, _ := store.Orders().Details(user.Id)
+	if len(details) != 1 || details[0].InventoryId != 100 || details[0].Qty != 5 || details[0].Total != money.FromInt(100) {
+		t.Errorf("details = %+v, want 2 + 4 shirts capped at the 5 in stock", details)
+	}
+	order, _ := store.Orders().OpenCart(1)
+	if order.TotalItem != 5 || order.Subtotal != money.FromInt(100) {
+		t.Errorf("merged cart has %d items for %v, want 5 for 100", order.TotalItem, order.Subtotal)
+	}
+	if _, ok := store.orders[guest.Id]; ok {
+		t.Error("the guest cart was not deleted")
+	}
+	if left, _ := store.Orders().Details(guest.Id); len(left) != 0 {
+		t.Errorf("the guest cart left %d details behind", len(left))
+	}
+	if !store.carts[[2]uint64{user.Id, 10}] || store.carts[[2]uint64{user.Id, 11}] || store.carts[[2]uint64{guest.Id, 10}] {
+		t.Errorf("orders_carts = %v, want only product 10 on the user's cart", store.carts)
+	}
+}
+
+func TestCartMergeKeepsUserQuantity(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+	user := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 4})
+
+	token, err := cart.Add(CartOwner{}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	shirts := store.inventories[100]
+	shirts.Stock = 2
+	store.inventories[100] = shirts
+
+	if err := cart.Merge(1, token); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	details, _ := store.Orders().Details(user.Id)
+	if len(details) != 1 || details[0].Qty != 4 {
+		t.Errorf("details = %+v, want the user's 4 shirts kept", details)
+	}
+}
+
+func TestCartMergeDoesNotWrapQuantity(t *testing.T) {
+
+	store := newShopStore()
+	cart := Cart(store)
+	user := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 65000})
+
+	token, err := cart.Add(CartOwner{}, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1000})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := cart.Merge(1, token); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	// 66000 would wrap to 464; the 5 in stock cap it, the user's own line
+	// stays as it was.
+	details, _ := store.Orders().Details(user.Id)
+	if len(details) != 1 || details[0].Qty != 65000 {
+		t.Errorf("details = %+v, want the user's 65000 kept", details)
+	}
+}
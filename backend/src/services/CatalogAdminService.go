@@ -0,0 +1,520 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"time"
+)
+
+var (
+	ErrBrandInUse       = errors.New("the brand still has products")
+	ErrColourInUse      = errors.New("the colour is still used by product inventories")
+	ErrSizeInUse        = errors.New("the size is still used by product inventories")
+	ErrProductOrdered   = errors.New("the product has been ordered and can only be unpublished")
+	ErrInventoryOrdered = errors.New("the inventory has been ordered and can only be disabled")
+)
+
+// InvalidInputError carries the field errors of back office input that
+// binds but refers to missing or clashing records.
+type InvalidInputError struct {
+	Errors []helpers.ValidationError
+}
+
+func (e *InvalidInputError) Error() string {
+	return "The given data was invalid."
+}
+
+func invalidInput(errs []helpers.ValidationError) error {
+	if len(errs) > 0 {
+		return &InvalidInputError{Errors: errs}
+	}
+	return nil
+}
+
+// RecordPage is one page of a back office list. List holds the slice the
+// caller passed in.
+type RecordPage struct {
+	List          interface{}
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+}
+
+// catalog admin service
+//
+// The back office side of the catalogue: brands, categories, colours and
+// sizes, and products with their categories, images and inventories.
+// Records still referenced elsewhere cannot be deleted; products and
+// inventories that were ordered are unpublished or disabled instead.
+type CatalogAdminService interface {
+	List(list interface{}, query repositories.ListQuery) (RecordPage, error)
+	Find(record interface{}, id uint64) error
+	Delete(record interface{}) error
+	CreateBrand(input schema.BrandSchema) (models.Brand, error)
+	UpdateBrand(brand *models.Brand, input schema.BrandSchema) error
+	CreateCategory(input schema.CategorySchema) (models.Category, error)
+	UpdateCategory(category *models.Category, input schema.CategorySchema) error
+	CreateColour(input schema.ColourSchema) (models.Colour, error)
+	UpdateColour(colour *models.Colour, input schema.ColourSchema) error
+	CreateSize(input schema.SizeSchema) (models.Size, error)
+	UpdateSize(size *models.Size, input schema.SizeSchema) error
+	Products(query repositories.ListQuery) (RecordPage, error)
+	ProductDetail(id uint64) (models.Product, error)
+	CreateProduct(input schema.ProductSchema) (models.Product, error)
+	UpdateProduct(product *models.Product, input schema.ProductSchema) error
+	Publish(product *models.Product, at *time.Time) error
+	Unpublish(product *models.Product) error
+	SyncCategories(product *models.Product, ids []uint64) ([]models.Category, error)
+	AttachCategory(product *models.Product, category models.Category) error
+	DetachCategory(productId uint64, categoryId uint64) error
+	Images(productId uint64) ([]models.ProductImage, error)
+	CreateImage(productId uint64, input schema.ProductImageSchema) (models.ProductImage, error)
+	UpdateImage(image *models.ProductImage, input schema.ProductImageSchema) error
+	Inventories(productId uint64) ([]models.ProductInventory, error)
+	CreateInventory(productId uint64, input schema.ProductInventorySchema) (models.ProductInventory, error)
+	UpdateInventory(inventory *models.ProductInventory, input schema.ProductInventorySchema) error
+}
+
+type catalogAdminServices struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func CatalogAdmin(store repositories.Store) CatalogAdminService {
+	return &catalogAdminServices{store: store, now: time.Now}
+}
+
+// List fills list, a pointer to a slice of brands, categories, colours or
+// sizes, with one page of them.
+func (service *catalogAdminServices) List(list interface{}, query repositories.ListQuery) (RecordPage, error) {
+
+	columns := []string{"name", "description"}
+	if _, ok := list.(*[]models.Colour); ok {
+		columns = []string{"name", "code"}
+	}
+
+	total, filtered, err := service.store.Records().List(list, query, columns...)
+	return RecordPage{
+		List:          list,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+func (service *catalogAdminServices) Find(record interface{}, id uint64) error {
+	return service.store.Records().Find(record, id)
+}
+
+// Delete deletes a brand, category, colour, size, product, image or
+// inventory, refusing with one of the Err...InUse or Err...Ordered errors
+// while it is still referenced.
+func (service *catalogAdminServices) Delete(record interface{}) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		var inUse bool
+		var refusal error
+		var err error
+
+		switch record := record.(type) {
+		case *models.Brand:
+			inUse, err = tx.Catalog().BrandInUse(record.Id)
+			refusal = ErrBrandInUse
+		case *models.Colour:
+			inUse, err = tx.Catalog().ColourInUse(record.Id)
+			refusal = ErrColourInUse
+		case *models.Size:
+			inUse, err = tx.Catalog().SizeInUse(record.Id)
+			refusal = ErrSizeInUse
+		case *models.ProductInventory:
+			inUse, err = tx.Catalog().InventoryOrdered(record.Id)
+			refusal = ErrInventoryOrdered
+		case *models.Category:
+			return tx.Catalog().DeleteCategory(record.Id)
+		case *models.Product:
+			ordered, err := tx.Catalog().ProductOrdered(record.Id)
+			if err != nil {
+				return err
+			}
+			if ordered {
+				return ErrProductOrdered
+			}
+			return tx.Catalog().DeleteProduct(record.Id)
+		}
+
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return refusal
+		}
+		return tx.Records().Delete(record)
+	})
+}
+
+// brands
+
+func (service *catalogAdminServices) CreateBrand(input schema.BrandSchema) (models.Brand, error) {
+	brand := models.Brand{
+		Image:       helpers.NewNullString(input.Image),
+		Name:        input.Name,
+		Description: input.Description,
+		Status:      input.Status,
+	}
+	err := service.store.Records().Create(&brand)
+	return brand, err
+}
+
+func (service *catalogAdminServices) UpdateBrand(brand *models.Brand, input schema.BrandSchema) error {
+	return service.store.Records().Update(brand, map[string]interface{}{
+		"image":       helpers.NewNullString(input.Image),
+		"name":        input.Name,
+		"description": input.Description,
+		"status":      input.Status,
+	})
+}
+
+// categories
+
+func (service *catalogAdminServices) CreateCategory(input schema.CategorySchema) (models.Category, error) {
+	category := models.Category{
+		Image:       helpers.NewNullString(input.Image),
+		Name:        input.Name,
+		Description: input.Description,
+		Displayed:   input.Displayed,
+		Status:      input.Status,
+	}
+	err := service.store.Records().Create(&category)
+	return category, err
+}
+
+func (service *catalogAdminServices) UpdateCategory(category *models.Category, input schema.CategorySchema) error {
+	return service.store.Records().Update(category, map[string]interface{}{
+		"image":       helpers.NewNullString(input.Image),
+		"name":        input.Name,
+		"description": input.Description,
+		"displayed":   input.Displayed,
+		"status":      input.Status,
+	})
+}
+
+// colours
+
+func (service *catalogAdminServices) CreateColour(input schema.ColourSchema) (models.Colour, error) {
+	colour := models.Colour{
+		Code:        input.Code,
+		Name:        input.Name,
+		Description: input.Description,
+		Status:      input.Status,
+	}
+	err := service.store.Records().Create(&colour)
+	return colour, err
+}
+
+func (service *catalogAdminServices) UpdateColour(colour *models.Colour, input schema.ColourSchema) error {
+	return service.store.Records().Update(colour, map[string]interface{}{
+		"code":        input.Code,
+		"name":        input.Name,
+		"description": input.Description,
+		"status":      input.Status,
+	})
+}
+
+// sizes
+
+func (service *catalogAdminServices) CreateSize(input schema.SizeSchema) (models.Size, error) {
+	size := models.Size{
+		Name:        input.Name,
+		Description: input.Description,
+		Status:      input.Status,
+	}
+	err := service.store.Records().Create(&size)
+	return size, err
+}
+
+func (service *catalogAdminServices) UpdateSize(size *models.Size, input schema.SizeSchema) error {
+	return service.store.Records().Update(size, map[string]interface{}{
+		"name":        input.Name,
+		"description": input.Description,
+		"status":      input.Status,
+	})
+}
+
+// products
+
+func (service *catalogAdminServices) Products(query repositories.ListQuery) (RecordPage, error) {
+	products, total, filtered, err := service.store.Catalog().ProductPage(query)
+	return RecordPage{
+		List:          products,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+func (service *catalogAdminServices) ProductDetail(id uint64) (models.Product, error) {
+	return service.store.Catalog().ProductComplete(id)
+}
+
+// validateProduct checks the brand, sku and categories of input and
+// returns the categories it names.
+func validateProduct(store repositories.Store, input schema.ProductSchema, productId uint64) ([]models.Category, error) {
+
+	var errs []helpers.ValidationError
+
+	var brand models.Brand
+	if err := store.Records().Find(&brand, input.BrandId); errors.Is(err, repositories.ErrNotFound) {
+		errs = append(errs, helpers.ValidationError{Field: "brand_id", Rule: "exists", Message: "The selected brand_id is invalid."})
+	} else if err != nil {
+		return nil, err
+	}
+
+	taken, err := store.Catalog().SkuTaken(input.Sku, productId)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		errs = append(errs, helpers.ValidationError{Field: "sku", Rule: "unique", Message: "The sku has already been taken."})
+	}
+
+	categories, err := findCategories(store, input.CategoryIds)
+	var invalid *InvalidInputError
+	if errors.As(err, &invalid) {
+		errs = append(errs, invalid.Errors...)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return categories, invalidInput(errs)
+}
+
+// findCategories reads the categories ids names, refusing ids that do
+// not exist.
+func findCategories(store repositories.Store, ids []uint64) ([]models.Category, error) {
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	categories, err := store.Catalog().CategoriesIn(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != len(uniqueIds(ids)) {
+		return nil, invalidInput([]helpers.ValidationError{{Field: "category_ids", Rule: "exists", Message: "The selected category_ids are invalid."}})
+	}
+	return categories, nil
+}
+
+func uniqueIds(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	var result []uint64
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// CreateProduct creates an unpublished product.
+func (service *catalogAdminServices) CreateProduct(input schema.ProductSchema) (models.Product, error) {
+
+	var product models.Product
+
+	categories, err := validateProduct(service.store, input, 0)
+	if err != nil {
+		return product, err
+	}
+
+	product = models.Product{
+		BrandId:     input.BrandId,
+		Image:       helpers.NewNullString(input.Image),
+		Sku:         input.Sku,
+		Name:        input.Name,
+		Price:       input.Price.Round(money.Default()), // Prices are charged in whole minor units of the store currency.
+		Description: input.Description,
+		Details:     input.Details,
+		Status:      0,
+		Categories:  categories,
+	}
+	err = service.store.Records().Create(&product)
+	return product, err
+}
+
+// UpdateProduct updates the product, and replaces its categories when
+// input names them.
+func (service *catalogAdminServices) UpdateProduct(product *models.Product, input schema.ProductSchema) error {
+
+	categories, err := validateProduct(service.store, input, product.Id)
+	if err != nil {
+		return err
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		if err := tx.Records().Update(product, map[string]interface{}{
+			"brand_id":    input.BrandId,
+			"image":       helpers.NewNullString(input.Image),
+			"sku":         input.Sku,
+			"name":        input.Name,
+			"price":       input.Price.Round(money.Default()),
+			"description": input.Description,
+			"details":     input.Details,
+		}); err != nil {
+			return err
+		}
+
+		if input.CategoryIds != nil {
+			return tx.Catalog().ReplaceCategories(product, categories)
+		}
+		return nil
+	})
+}
+
+// Publish publishes the product from at, or from now when at is nil.
+func (service *catalogAdminServices) Publish(product *models.Product, at *time.Time) error {
+	publishedAt := service.now()
+	if at != nil {
+		publishedAt = *at
+	}
+	return service.store.Records().Update(product, map[string]interface{}{"status": 1, "published_at": publishedAt})
+}
+
+func (service *catalogAdminServices) Unpublish(product *models.Product) error {
+	if err := service.store.Records().Update(product, map[string]interface{}{"status": 0, "published_at": nil}); err != nil {
+		return err
+	}
+	product.PublishedAt = nil
+	return nil
+}
+
+// SyncCategories replaces the product's categories with those ids names.
+func (service *catalogAdminServices) SyncCategories(product *models.Product, ids []uint64) ([]models.Category, error) {
+	categories, err := findCategories(service.store, ids)
+	if err != nil {
+		return nil, err
+	}
+	return categories, service.store.Catalog().ReplaceCategories(product, categories)
+}
+
+func (service *catalogAdminServices) AttachCategory(product *models.Product, category models.Category) error {
+	return service.store.Catalog().AttachCategory(product, category)
+}
+
+func (service *catalogAdminServices) DetachCategory(productId uint64, categoryId uint64) error {
+	return service.store.Catalog().DetachCategory(productId, categoryId)
+}
+
+// product images
+
+func (service *catalogAdminServices) Images(productId uint64) ([]models.ProductImage, error) {
+	return service.store.Catalog().SortedImages(productId)
+}
+
+func (service *catalogAdminServices) CreateImage(productId uint64, input schema.ProductImageSchema) (models.ProductImage, error) {
+	image := models.ProductImage{
+		ProductId: productId,
+		Path:      input.Path,
+		Sort:      input.Sort,
+		Status:    input.Status,
+	}
+	err := service.store.Records().Create(&image)
+	return image, err
+}
+
+func (service *catalogAdminServices) UpdateImage(image *models.ProductImage, input schema.ProductImageSchema) error {
+	return service.store.Records().Update(image, map[string]interface{}{
+		"path":   input.Path,
+		"sort":   input.Sort,
+		"status": input.Status,
+	})
+}
+
+// product inventories
+
+// validateInventory checks that the size and colour of input exist and
+// that the product has no other inventory of them.
+func validateInventory(store repositories.Store, input schema.ProductInventorySchema, productId uint64, inventoryId uint64) error {
+
+	var errs []helpers.ValidationError
+
+	var size models.Size
+	if err := store.Records().Find(&size, input.SizeId); errors.Is(err, repositories.ErrNotFound) {
+		errs = append(errs, helpers.ValidationError{Field: "size_id", Rule: "exists", Message: "The selected size_id is invalid."})
+	} else if err != nil {
+		return err
+	}
+
+	var colour models.Colour
+	if err := store.Records().Find(&colour, input.ColourId); errors.Is(err, repositories.ErrNotFound) {
+		errs = append(errs, helpers.ValidationError{Field: "colour_id", Rule: "exists", Message: "The selected colour_id is invalid."})
+	} else if err != nil {
+		return err
+	}
+
+	taken, err := store.Catalog().InventoryTaken(productId, input.SizeId, input.ColourId, inventoryId)
+	if err != nil {
+		return err
+	}
+	if taken {
+		errs = append(errs, helpers.ValidationError{Field: "size_id", Rule: "unique", Message: "An inventory for this size and colour already exists."})
+	}
+
+	return invalidInput(errs)
+}
+
+func (service *catalogAdminServices) Inventories(productId uint64) ([]models.ProductInventory, error) {
+	return service.store.Catalog().Inventories(productId)
+}
+
+func (service *catalogAdminServices) CreateInventory(productId uint64, input schema.ProductInventorySchema) (models.ProductInventory, error) {
+
+	var inventory models.ProductInventory
+	if err := validateInventory(service.store, input, productId, 0); err != nil {
+		return inventory, err
+	}
+
+	inventory = models.ProductInventory{
+		ProductId: productId,
+		SizeId:    input.SizeId,
+		ColourId:  input.ColourId,
+		Stock:     input.Stock,
+		Status:    input.Status,
+	}
+	err := service.store.Records().Create(&inventory)
+	return inventory, err
+}
+
+func (service *catalogAdminServices) UpdateInventory(inventory *models.ProductInventory, input schema.ProductInventorySchema) error {
+
+	if err := validateInventory(service.store, input, inventory.ProductId, inventory.Id); err != nil {
+		return err
+	}
+
+	return service.store.Records().Update(inventory, map[string]interface{}{
+		"size_id":   input.SizeId,
+		"colour_id": input.ColourId,
+		"stock":     input.Stock,
+		"status":    input.Status,
+	})
+}
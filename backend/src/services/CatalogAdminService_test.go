@@ -0,0 +1,102 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	schema "backend/src/schema"
+	"errors"
+	"testing"
+)
+
+func TestCatalogAdminCreateProductInvalid(t *testing.T) {
+
+	store := newShopStore()
+	store.products[10] = models.Product{Id: 10, Sku: "SHIRT-1"}
+
+	_, err := CatalogAdmin(store).CreateProduct(schema.ProductSchema{BrandId: 99, Sku: "SHIRT-1", Name: "Another shirt"})
+
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("CreateProduct = %v, want *InvalidInputError", err)
+	}
+	fields := map[string]bool{}
+	for _, field := range invalid.Errors {
+		fields[field.Field] = true
+	}
+	if len(invalid.Errors) != 2 || !fields["brand_id"] || !fields["sku"] {
+		t.Errorf("errors = %+v, want brand_id and sku", invalid.Errors)
+	}
+	if len(store.products) != 2 {
+		t.Errorf("%d products, want the invalid one not created", len(store.products))
+	}
+}
+
+func TestCatalogAdminDeleteProduct(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1})
+	admin := CatalogAdmin(store)
+
+	ordered := store.products[10]
+	if err := admin.Delete(&ordered); !errors.Is(err, ErrProductOrdered) {
+		t.Fatalf("Delete of an ordered product = %v, want ErrProductOrdered", err)
+	}
+	if _, ok := store.products[10]; !ok {
+		t.Error("the ordered product was deleted")
+	}
+
+	unordered := store.products[11]
+	if err := admin.Delete(&unordered); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := store.products[11]; ok {
+		t.Error("the product was not deleted")
+	}
+	if _, ok := store.inventories[110]; ok {
+		t.Error("the product's inventory was not deleted")
+	}
+}
+
+// inventoryClash reports whether err refuses the size and colour as
+// already taken.
+func inventoryClash(t *testing.T, err error) bool {
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v, want *InvalidInputError", err)
+	}
+	for _, field := range invalid.Errors {
+		if field.Rule == "unique" {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCatalogAdminInventoryTaken(t *testing.T) {
+
+	store := newShopStore()
+	admin := CatalogAdmin(store)
+	input := schema.ProductInventorySchema{SizeId: 1, ColourId: 1, Stock: 3}
+
+	// The fake has no sizes or colours, so both are always refused as
+	// missing; only the clash differs.
+	_, err := admin.CreateInventory(10, input)
+	if !inventoryClash(t, err) {
+		t.Error("CreateInventory accepted a second size 1, colour 1 inventory")
+	}
+
+	inventory := store.inventories[100]
+	if inventoryClash(t, admin.UpdateInventory(&inventory, input)) {
+		t.Error("UpdateInventory clashed with the inventory itself")
+	}
+}
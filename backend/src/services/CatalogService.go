@@ -0,0 +1,239 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// ProductCard is a product as the storefront lists it.
+type ProductCard struct {
+	Id           int64
+	Name         string
+	Image        sql.NullString
+	Description  string
+	Details      string
+	Price        money.Money
+	PriceOld     money.Money
+	CategoryName string
+	IsNewest     bool
+	IsDiscount   bool
+	TotalRating  float64
+	RatingCount  uint32
+}
+
+type HomePage struct {
+	Categories  []models.Category
+	Products    []ProductCard
+	BestSellers []ProductCard
+	TopSellings []ProductCard
+}
+
+type ShopFilter struct {
+	Categories []repositories.NameCount
+	Brands     []repositories.NameCount
+	Tops       []ProductCard
+	MinPrice   money.Money
+	MaxPrice   money.Money
+}
+
+type ProductPage struct {
+	List          []ProductCard
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+	Facets        repositories.Facets
+}
+
+type ProductView struct {
+	Product     ProductCard
+	Related     []ProductCard
+	Images      []models.ProductImage
+	Sizes       []models.Size
+	Colours     []models.Colour
+	Inventories []models.ProductInventory
+	Stars       [5]uint32
+}
+
+// catalog service
+type CatalogService interface {
+	Component() ([]models.Category, map[string]string, error)
+	Home() (HomePage, error)
+	Filter() (ShopFilter, error)
+	Product(id uint64) (ProductView, error)
+}
+
+type catalogServices struct {
+	store repositories.Store
+}
+
+func Catalog(store repositories.Store) CatalogService {
+	return &catalogServices{store: store}
+}
+
+func (service *catalogServices) Component() ([]models.Category, map[string]string, error) {
+
+	categories, err := service.store.Catalog().Categories(false, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings, err := service.store.Settings().All()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return categories, settings, nil
+}
+
+func (service *catalogServices) Home() (HomePage, error) {
+
+	var page HomePage
+	catalog := service.store.Catalog()
+
+	var err error
+
+	if page.Categories, err = catalog.Categories(true, 3); err != nil {
+		return page, err
+	}
+
+	lists := []struct {
+		orderBy string
+		limit   int
+		out     *[]ProductCard
+	}{
+		{"id desc", 4, &page.Products},
+		{"rating_average desc, rating_count desc", 3, &page.BestSellers},
+		{"total_order desc", 3, &page.TopSellings},
+	}
+
+	for _, list := range lists {
+		products, err := catalog.Published(repositories.ProductQuery{OrderBy: list.orderBy, Limit: list.limit})
+		if err != nil {
+			return page, err
+		}
+		*list.out = productCards(products)
+	}
+
+	return page, nil
+}
+
+func (service *catalogServices) Filter() (ShopFilter, error) {
+
+	var filter ShopFilter
+	catalog := service.store.Catalog()
+
+	var err error
+
+	filter.MinPrice, filter.MaxPrice, err = catalog.PriceRange()
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return filter, err
+	}
+
+	tops, err := catalog.Published(repositories.ProductQuery{OrderBy: "total_order desc", Limit: 3})
+	if err != nil {
+		return filter, err
+	}
+	filter.Tops = productCards(tops)
+
+	if filter.Categories, err = catalog.CategoryCounts(); err != nil {
+		return filter, err
+	}
+
+	if filter.Brands, err = catalog.BrandCounts(); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// Product returns a published product together with everything the
+// product page needs to put it in the cart.
+func (service *catalogServices) Product(id uint64) (ProductView, error) {
+
+	var view ProductView
+	catalog := service.store.Catalog()
+
+	product, err := catalog.PublishedProduct(id)
+	if err != nil {
+		return view, err
+	}
+	view.Product = productCard(product)
+	view.Stars = product.Stars()
+
+	related, err := catalog.Published(repositories.ProductQuery{OrderBy: "total_order desc", Limit: 3, ExcludeId: id})
+	if err != nil {
+		return view, err
+	}
+	view.Related = productCards(related)
+
+	if view.Images, err = catalog.Images(id); err != nil {
+		return view, err
+	}
+
+	if view.Sizes, err = catalog.Sizes(); err != nil {
+		return view, err
+	}
+
+	if view.Colours, err = catalog.Colours(); err != nil {
+		return view, err
+	}
+
+	if view.Inventories, err = catalog.Inventories(id); err != nil {
+		return view, err
+	}
+
+	return view, nil
+}
+
+func productCards(products []models.Product) []ProductCard {
+	var cards []ProductCard
+	for _, product := range products {
+		cards = append(cards, productCard(product))
+	}
+	return cards
+}
+
+// oldPriceMarkup is how much higher than its price a product's struck
+// out price is shown.
+var oldPriceMarkup = money.MustParseRate("5")
+
+func productCard(product models.Product) ProductCard {
+
+	var categoryNames []string
+	for _, category := range product.Categories {
+		categoryNames = append(categoryNames, category.Name)
+	}
+
+	numRandom := uint16(helpers.RandomInt(0, 1))
+	return ProductCard{
+		Id:           int64(product.Id),
+		Name:         product.Name,
+		Image:        product.Image,
+		Description:  product.Description,
+		Details:      product.Details,
+		Price:        product.Price,
+		PriceOld:     product.Price + product.Price.Percent(oldPriceMarkup).Round(money.Default()),
+		CategoryName: strings.Join(categoryNames, ", "),
+		IsNewest:     numRandom == 1,
+		IsDiscount:   numRandom == 0,
+		TotalRating:  product.RatingAverage,
+		RatingCount:  product.RatingCount,
+	}
+}
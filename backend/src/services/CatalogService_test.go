@@ -0,0 +1,51 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"errors"
+	"testing"
+)
+
+func TestProduct(t *testing.T) {
+
+	store := newShopStore()
+
+	view, err := Catalog(store).Product(11)
+	if err != nil {
+		t.Fatalf("Product: %v", err)
+	}
+	if view.Product.Name != "Shoes" || view.Product.TotalRating != 2 || view.Product.RatingCount != 1 {
+		t.Errorf("product = %+v, want the shoes rated 2 of 5 stars once", view.Product)
+	}
+	if view.Stars != [5]uint32{0, 1, 0, 0, 0} {
+		t.Errorf("stars = %v, want one two-star rating", view.Stars)
+	}
+	if view.Product.Price != money.FromInt(50) || view.Product.PriceOld != money.MustParse("52.5") {
+		t.Errorf("prices = %v and %v, want 50 struck out from 52.50", view.Product.Price, view.Product.PriceOld)
+	}
+	if len(view.Related) != 1 || view.Related[0].Id != 10 {
+		t.Errorf("related = %+v, want only the shirt", view.Related)
+	}
+	if len(view.Inventories) != 1 {
+		t.Errorf("got %d inventories, want 1", len(view.Inventories))
+	}
+
+	draft := store.products[10]
+	draft.Status = 0
+	store.products[10] = draft
+	if _, err := Catalog(store).Product(10); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Product of a draft = %v, want ErrNotFound", err)
+	}
+}
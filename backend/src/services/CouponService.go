@@ -0,0 +1,324 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	helpers "backend/src/helpers"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CouponError explains why a coupon cannot be used on an order.
+type CouponError struct {
+	Code   string
+	Reason string
+}
+
+func (e *CouponError) Error() string {
+	return e.Reason
+}
+
+// ErrCouponRedeemed refuses deleting a coupon an order was placed with.
+var ErrCouponRedeemed = errors.New("the coupon has been redeemed and can only be disabled")
+
+// CouponPage is one page of the back office coupon list.
+type CouponPage struct {
+	List          []models.Coupon
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+}
+
+// coupon service
+type CouponService interface {
+	Apply(userId uint64, code string) (models.Coupon, error)
+	Remove(userId uint64) error
+	AdminList(query repositories.ListQuery) (CouponPage, error)
+	AdminDetail(id uint64) (models.Coupon, error)
+	Create(input schema.CouponSchema) (models.Coupon, error)
+	Update(coupon *models.Coupon, input schema.CouponSchema) error
+	Delete(coupon models.Coupon) error
+}
+
+type couponServices struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func Coupons(store repositories.Store) CouponService {
+	return &couponServices{store: store, now: time.Now}
+}
+
+// NormalizeCouponCode is the form coupon codes are stored and looked up
+// in, so customers can type them in any case.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Apply puts the coupon with code on the user's open cart, replacing any
+// coupon already there. A coupon the cart does not qualify for is
+// refused with a *CouponError.
+func (service *couponServices) Apply(userId uint64, code string) (models.Coupon, error) {
+
+	var coupon models.Coupon
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := tx.Orders().LockOpenCart(userId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrCartEmpty
+		} else if err != nil {
+			return err
+		}
+
+		items, err := tx.Pricing().Items(order.Id)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrCartEmpty
+		}
+
+		coupon, err = tx.Coupons().FindByCode(NormalizeCouponCode(code))
+		if errors.Is(err, repositories.ErrNotFound) {
+			return &CouponError{Code: code, Reason: "This coupon code is not valid."}
+		} else if err != nil {
+			return err
+		}
+
+		if err := checkCoupon(tx, coupon, order, items, service.now()); err != nil {
+			return err
+		}
+
+		if err := tx.Coupons().Attach(&models.OrderCoupon{
+			OrderId:  order.Id,
+			CouponId: coupon.Id,
+			UserId:   userId,
+			Code:     coupon.Code,
+		}); err != nil {
+			return err
+		}
+
+		return logActivity(tx, userId, "Apply Coupon", "Apply coupon "+coupon.Code+" to cart", "Your coupon "+coupon.Code+" has been applied to your cart.")
+	})
+
+	return coupon, err
+}
+
+// Remove takes the coupon off the user's open cart.
+func (service *couponServices) Remove(userId uint64) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := tx.Orders().LockOpenCart(userId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrCartEmpty
+		} else if err != nil {
+			return err
+		}
+
+		return tx.Coupons().Detach(order.Id)
+	})
+}
+
+func (service *couponServices) AdminList(query repositories.ListQuery) (CouponPage, error) {
+	coupons, total, filtered, err := service.store.Coupons().Search(query)
+	return CouponPage{
+		List:          coupons,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+func (service *couponServices) AdminDetail(id uint64) (models.Coupon, error) {
+	return service.store.Coupons().Find(id)
+}
+
+// validateCoupon normalizes the code and rounds the amounts of input,
+// then checks it. Problems are returned as an *InvalidInputError.
+func validateCoupon(store repositories.Store, input *schema.CouponSchema, couponId uint64) error {
+
+	var errs []helpers.ValidationError
+
+	input.Code = NormalizeCouponCode(input.Code)
+	input.Value = input.Value.Round(money.Default())
+	input.MinSubtotal = input.MinSubtotal.Round(money.Default())
+
+	taken, err := store.Coupons().CodeTaken(input.Code, couponId)
+	if err != nil {
+		return err
+	}
+	if taken {
+		errs = append(errs, helpers.ValidationError{Field: "code", Rule: "unique", Message: "The code has already been taken."})
+	}
+
+	if input.Calculation == models.PriceCalculationPercent && input.Value > money.FromInt(100) {
+		errs = append(errs, helpers.ValidationError{Field: "value", Rule: "max", Message: "A percent coupon cannot take off more than 100%."})
+	}
+
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		errs = append(errs, helpers.ValidationError{Field: "ends_at", Rule: "after", Message: "The ends_at must be a date after starts_at."})
+	}
+
+	for _, target := range input.Targets {
+		exists, err := store.Coupons().TargetExists(target.Scope, target.TargetId)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			errs = append(errs, helpers.ValidationError{Field: "targets", Rule: "exists", Message: "The selected " + target.Scope + " target is invalid."})
+			break
+		}
+	}
+
+	return invalidInput(errs)
+}
+
+// couponTargets returns the distinct targets of input.
+func couponTargets(input schema.CouponSchema) []models.CouponTarget {
+	seen := make(map[models.CouponTarget]bool)
+	targets := []models.CouponTarget{}
+	for _, target := range input.Targets {
+		row := models.CouponTarget{Scope: target.Scope, TargetId: target.TargetId}
+		if !seen[row] {
+			seen[row] = true
+			targets = append(targets, row)
+		}
+	}
+	return targets
+}
+
+func (service *couponServices) Create(input schema.CouponSchema) (models.Coupon, error) {
+
+	var coupon models.Coupon
+	if err := validateCoupon(service.store, &input, 0); err != nil {
+		return coupon, err
+	}
+
+	coupon = models.Coupon{
+		Code:              input.Code,
+		Name:              input.Name,
+		Calculation:       input.Calculation,
+		Value:             input.Value,
+		MinSubtotal:       input.MinSubtotal,
+		UsageLimit:        input.UsageLimit,
+		UsageLimitPerUser: input.UsageLimitPerUser,
+		StartsAt:          input.StartsAt,
+		EndsAt:            input.EndsAt,
+		Status:            input.Status,
+		Targets:           couponTargets(input),
+	}
+	err := service.store.Records().Create(&coupon)
+	return coupon, err
+}
+
+// Update updates the coupon and replaces its targets.
+func (service *couponServices) Update(coupon *models.Coupon, input schema.CouponSchema) error {
+
+	if err := validateCoupon(service.store, &input, coupon.Id); err != nil {
+		return err
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		if err := tx.Records().Update(coupon, map[string]interface{}{
+			"code":                 input.Code,
+			"name":                 input.Name,
+			"calculation":          input.Calculation,
+			"value":                input.Value,
+			"min_subtotal":         input.MinSubtotal,
+			"usage_limit":          input.UsageLimit,
+			"usage_limit_per_user": input.UsageLimitPerUser,
+			"starts_at":            input.StartsAt,
+			"ends_at":              input.EndsAt,
+			"status":               input.Status,
+		}); err != nil {
+			return err
+		}
+
+		return tx.Coupons().ReplaceTargets(coupon, couponTargets(input))
+	})
+}
+
+// Delete deletes a coupon no order was placed with, taking it off any
+// carts it is on. Redeemed coupons are refused with ErrCouponRedeemed.
+func (service *couponServices) Delete(coupon models.Coupon) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		redeemed, err := tx.Coupons().Redeemed(coupon.Id)
+		if err != nil {
+			return err
+		}
+		if redeemed {
+			return ErrCouponRedeemed
+		}
+
+		return tx.Coupons().Delete(coupon.Id)
+	})
+}
+
+// checkCoupon returns a *CouponError when order, holding items, cannot
+// use coupon at the given moment.
+func checkCoupon(store repositories.Store, coupon models.Coupon, order models.Order, items []repositories.PriceItem, at time.Time) error {
+
+	invalid := func(reason string) error {
+		return &CouponError{Code: coupon.Code, Reason: reason}
+	}
+
+	if coupon.Status != 1 {
+		return invalid("This coupon code is not valid.")
+	}
+	if coupon.StartsAt != nil && at.Before(*coupon.StartsAt) {
+		return invalid("This coupon is not valid yet.")
+	}
+	if coupon.EndsAt != nil && !at.Before(*coupon.EndsAt) {
+		return invalid("This coupon has expired.")
+	}
+
+	var subtotal money.Money
+	applies := false
+	for _, item := range items {
+		subtotal += item.Price.Times(int64(item.Qty))
+		applies = applies || couponApplies(coupon, item)
+	}
+	if subtotal < coupon.MinSubtotal {
+		return invalid(fmt.Sprintf("This coupon needs a subtotal of at least %s %s.", money.Default(), coupon.MinSubtotal))
+	}
+	if !applies {
+		return invalid("This coupon does not apply to any item in your cart.")
+	}
+
+	if coupon.UsageLimit == 0 && coupon.UsageLimitPerUser == 0 {
+		return nil
+	}
+
+	total, byUser, err := store.Coupons().Redemptions(coupon.Id, order.UserId, order.Id)
+	if err != nil {
+		return err
+	}
+	if coupon.UsageLimit > 0 && total >= int64(coupon.UsageLimit) {
+		return invalid("This coupon has been used up.")
+	}
+	if coupon.UsageLimitPerUser > 0 && byUser >= int64(coupon.UsageLimitPerUser) {
+		return invalid("You have already used this coupon.")
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	schema "backend/src/schema"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newCouponStore returns the shop store with a SAVE10 coupon taking 10%
+// off, usable once per customer.
+func newCouponStore() fakeStore {
+	store := newShopStore()
+	store.coupons[1] = models.Coupon{Id: 1, Code: "SAVE10", Name: "Ten off", Calculation: models.PriceCalculationPercent, Value: money.FromInt(10), UsageLimitPerUser: 1, Status: 1}
+	return store
+}
+
+func TestCouponApply(t *testing.T) {
+
+	store := newCouponStore()
+	cart := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+
+	coupon, err := Coupons(store).Apply(1, " save10 ")
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if coupon.Id != 1 {
+		t.Errorf("applied coupon %d, want 1", coupon.Id)
+	}
+	if applied, ok := store.redemptions[cart.Id]; !ok || applied.Code != "SAVE10" || applied.UserId != 1 {
+		t.Errorf("cart coupon = %+v, want SAVE10 for user 1", applied)
+	}
+
+	quote, err := Orders(testConfig(), store).Quote(1, PriceAddress{})
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if quote.Breakdown.Coupon != "SAVE10" || quote.Breakdown.CouponDiscount != money.MustParse("3.8") {
+		t.Errorf("coupon %q takes %v off, want SAVE10 taking 10%% of 40 - 2 = 3.8", quote.Breakdown.Coupon, quote.Breakdown.CouponDiscount)
+	}
+
+	if err := Coupons(store).Remove(1); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, ok := store.redemptions[cart.Id]; ok {
+		t.Error("Remove left the coupon on the cart")
+	}
+}
+
+func TestCouponApplyEmptyCart(t *testing.T) {
+
+	store := newCouponStore()
+
+	if _, err := Coupons(store).Apply(1, "SAVE10"); !errors.Is(err, ErrCartEmpty) {
+		t.Fatalf("Apply without a cart = %v, want ErrCartEmpty", err)
+	}
+}
+
+func TestCouponApplyRefused(t *testing.T) {
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		code   string
+		change func(store fakeStore, coupon *models.Coupon)
+		reason string
+	}{
+		{
+			name:   "unknown code",
+			code:   "NOPE",
+			reason: "This coupon code is not valid.",
+		},
+		{
+			name:   "disabled",
+			change: func(store fakeStore, coupon *models.Coupon) { coupon.Status = 0 },
+			reason: "This coupon code is not valid.",
+		},
+		{
+			name:   "not started",
+			change: func(store fakeStore, coupon *models.Coupon) { coupon.StartsAt = &future },
+			reason: "This coupon is not valid yet.",
+		},
+		{
+			name:   "expired",
+			change: func(store fakeStore, coupon *models.Coupon) { coupon.EndsAt = &past },
+			reason: "This coupon has expired.",
+		},
+		{
+			name:   "below the minimum subtotal",
+			change: func(store fakeStore, coupon *models.Coupon) { coupon.MinSubtotal = money.FromInt(50) },
+			reason: "This coupon needs a subtotal of at least USD 50.00.",
+		},
+		{
+			name: "no matching item",
+			change: func(store fakeStore, coupon *models.Coupon) {
+				coupon.Targets = []models.CouponTarget{{CouponId: 1, Scope: models.PriceScopeProduct, TargetId: 11}}
+			},
+			reason: "This coupon does not apply to any item in your cart.",
+		},
+		{
+			name: "used up",
+			change: func(store fakeStore, coupon *models.Coupon) {
+				coupon.UsageLimit = 1
+				store.orders[900] = models.Order{Id: 900, UserId: 2, Status: models.OrderStatusPendingPayment}
+				store.redemptions[900] = models.OrderCoupon{Id: 1, OrderId: 900, CouponId: 1, UserId: 2, Code: "SAVE10"}
+			},
+			reason: "This coupon has been used up.",
+		},
+		{
+			name: "already used",
+			change: func(store fakeStore, coupon *models.Coupon) {
+				store.orders[900] = models.Order{Id: 900, UserId: 1, Status: models.OrderStatusDelivered}
+				store.redemptions[900] = models.OrderCoupon{Id: 1, OrderId: 900, CouponId: 1, UserId: 1, Code: "SAVE10"}
+			},
+			reason: "You have already used this coupon.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			store := newCouponStore()
+			cart := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+			if test.change != nil {
+				coupon := store.coupons[1]
+				test.change(store, &coupon)
+				store.coupons[1] = coupon
+			}
+			code := test.code
+			if code == "" {
+				code = "SAVE10"
+			}
+
+			_, err := Coupons(store).Apply(1, code)
+			var invalid *CouponError
+			if !errors.As(err, &invalid) || invalid.Reason != test.reason {
+				t.Fatalf("Apply = %v, want %q", err, test.reason)
+			}
+			if _, ok := store.redemptions[cart.Id]; ok {
+				t.Error("refused coupon was put on the cart")
+			}
+		})
+	}
+}
+
+func TestCheckoutRedeemsCoupon(t *testing.T) {
+
+	store := newCouponStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+	if _, err := Coupons(store).Apply(1, "SAVE10"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	order, breakdown, err := Orders(testConfig(), store).Checkout(1, checkoutInput())
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if order.TotalDiscount != money.MustParse("5.8") || breakdown.CouponDiscount != money.MustParse("3.8") {
+		t.Errorf("order discount %v with coupon %v, want 2 + 3.8 and 3.8", order.TotalDiscount, breakdown.CouponDiscount)
+	}
+	if redeemed := store.redemptions[order.Id]; redeemed.Amount != money.MustParse("3.8") {
+		t.Errorf("redemption amount = %v, want 3.8", redeemed.Amount)
+	}
+
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1})
+	var invalid *CouponError
+	if _, err := Coupons(store).Apply(1, "SAVE10"); !errors.As(err, &invalid) {
+		t.Errorf("second Apply = %v, want the per-user limit to refuse it", err)
+	}
+}
+
+func TestCheckoutCouponNoLongerValid(t *testing.T) {
+
+	store := newCouponStore()
+	cart := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+	if _, err := Coupons(store).Apply(1, "SAVE10"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+	coupon := store.coupons[1]
+	coupon.EndsAt = &past
+	store.coupons[1] = coupon
+
+	_, _, err := Orders(testConfig(), store).Checkout(1, checkoutInput())
+	var invalid *CouponError
+	if !errors.As(err, &invalid) || invalid.Code != "SAVE10" || invalid.Reason != "This coupon has expired." {
+		t.Fatalf("Checkout = %v, want the expired coupon refused", err)
+	}
+	if order := store.orders[cart.Id]; order.Status != models.OrderStatusCart {
+		t.Errorf("order is %s, want it left in the cart", order.StatusName())
+	}
+	if stock := store.inventories[100].Stock; stock != 5 {
+		t.Errorf("inventory 100 stock = %d, want 5", stock)
+	}
+}
+
+func TestCouponCreateNormalizesCode(t *testing.T) {
+
+	store := newCouponStore()
+	coupons := Coupons(store)
+
+	input := schema.CouponSchema{Code: " welcome ", Name: "Welcome", Calculation: models.PriceCalculationFixed, Value: money.MustParse("5.004"), Status: 1}
+	coupon, err := coupons.Create(input)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if coupon.Code != "WELCOME" || coupon.Value != money.FromInt(5) {
+		t.Errorf("coupon %q of %v, want WELCOME of 5", coupon.Code, coupon.Value)
+	}
+
+	input.Code = "save10"
+	var invalid *InvalidInputError
+	if _, err := coupons.Create(input); !errors.As(err, &invalid) || invalid.Errors[0].Field != "code" {
+		t.Fatalf("Create with a taken code = %v, want a code error", err)
+	}
+}
+
+func TestCouponDeleteRedeemed(t *testing.T) {
+
+	store := newCouponStore()
+	cart := fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 1})
+	if _, err := Coupons(store).Apply(1, "SAVE10"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	order := store.orders[cart.Id]
+	order.Status = models.OrderStatusPendingPayment
+	store.orders[cart.Id] = order
+
+	if err := Coupons(store).Delete(store.coupons[1]); !errors.Is(err, ErrCouponRedeemed) {
+		t.Fatalf("Delete of a redeemed coupon = %v, want ErrCouponRedeemed", err)
+	}
+
+	order.Status = models.OrderStatusCart
+	store.orders[cart.Id] = order
+
+	if err := Coupons(store).Delete(store.coupons[1]); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := store.coupons[1]; ok {
+		t.Error("the coupon was not deleted")
+	}
+	if _, ok := store.redemptions[cart.Id]; ok {
+		t.Error("the coupon was left on the cart")
+	}
+}
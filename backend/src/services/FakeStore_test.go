@@ -0,0 +1,1667 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// fakeStore is an in-memory repositories.Store for unit tests. Transaction
+// snapshots the data and restores it when fn fails, so tests can check
+// that a failed unit of work leaves nothing behind.
+type fakeStore struct {
+	*fakeData
+}
+
+type fakeMail struct {
+	To       string
+	Template string
+	Data     map[string]interface{}
+}
+
+type fakeData struct {
+	lastId          uint64
+	users           map[uint64]models.User
+	products        map[uint64]models.Product
+	inventories     map[uint64]models.ProductInventory
+	orders          map[uint64]models.Order
+	details         map[uint64]models.OrderDetail
+	billings        []models.OrderBilling
+	histories       []models.OrderStatusHistory
+	payments        []models.Payment
+	reviews         map[uint64]models.ProductReview
+	votes           map[[2]uint64]bool
+	settings        map[string]string
+	activities      []models.Activity
+	outbox          []fakeMail
+	wishlists       map[[2]uint64]bool
+	carts           map[[2]uint64]bool
+	newsletters     []models.NewsLetter
+	rules           []models.PriceRule
+	taxRates        []models.TaxRate
+	categories      map[uint64][]uint64
+	coupons         map[uint64]models.Coupon
+	redemptions     map[uint64]models.OrderCoupon
+	attempts        map[uint64]models.PaymentTransaction
+	events          []models.PaymentEvent
+	returns         map[uint64]models.OrderReturn
+	returnHistories []models.OrderReturnHistory
+	userRoles       map[uint64][]string
+	authentications []models.Authentication
+}
+
+func newFakeStore() fakeStore {
+	return fakeStore{&fakeData{
+		users:       map[uint64]models.User{},
+		products:    map[uint64]models.Product{},
+		inventories: map[uint64]models.ProductInventory{},
+		orders:      map[uint64]models.Order{},
+		details:     map[uint64]models.OrderDetail{},
+		settings:    map[string]string{},
+		wishlists:   map[[2]uint64]bool{},
+		carts:       map[[2]uint64]bool{},
+		categories:  map[uint64][]uint64{},
+		coupons:     map[uint64]models.Coupon{},
+		redemptions: map[uint64]models.OrderCoupon{},
+		attempts:    map[uint64]models.PaymentTransaction{},
+		returns:     map[uint64]models.OrderReturn{},
+		reviews:     map[uint64]models.ProductReview{},
+		votes:       map[[2]uint64]bool{},
+		userRoles:   map[uint64][]string{},
+	}}
+}
+
+func (s fakeStore) nextId() uint64 {
+	s.lastId++
+	return s.lastId
+}
+
+func (s fakeStore) snapshot() fakeData {
+	data := *s.fakeData
+	data.users = cloneMap(s.users)
+	data.products = cloneMap(s.products)
+	data.inventories = cloneMap(s.inventories)
+	data.orders = cloneMap(s.orders)
+	data.details = cloneMap(s.details)
+	data.settings = cloneMap(s.settings)
+	data.wishlists = cloneMap(s.wishlists)
+	data.carts = cloneMap(s.carts)
+	data.categories = cloneMap(s.categories)
+	data.coupons = cloneMap(s.coupons)
+	data.redemptions = cloneMap(s.redemptions)
+	data.attempts = cloneMap(s.attempts)
+	data.returns = cloneMap(s.returns)
+	data.reviews = cloneMap(s.reviews)
+	data.votes = cloneMap(s.votes)
+	data.userRoles = cloneMap(s.userRoles)
+	data.authentications = append([]models.Authentication(nil), s.authentications...)
+	data.events = append([]models.PaymentEvent(nil), s.events...)
+	data.billings = append([]models.OrderBilling(nil), s.billings...)
+	data.histories = append([]models.OrderStatusHistory(nil), s.histories...)
+	data.returnHistories = append([]models.OrderReturnHistory(nil), s.returnHistories...)
+	data.activities = append([]models.Activity(nil), s.activities...)
+	data.outbox = append([]fakeMail(nil), s.outbox...)
+	data.newsletters = append([]models.NewsLetter(nil), s.newsletters...)
+	return data
+}
+
+func cloneMap[K comparable, V any](in map[K]V) map[K]V {
+	out := make(map[K]V, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
+
+func (s fakeStore) Users() repositories.UserRepository          { return fakeUsers{s} }
+func (s fakeStore) Catalog() repositories.CatalogRepository     { return fakeCatalog{s} }
+func (s fakeStore) Orders() repositories.OrderRepository        { return fakeOrders{s} }
+func (s fakeStore) Settings() repositories.SettingRepository    { return fakeSettings{s} }
+func (s fakeStore) Activities() repositories.ActivityRepository { return fakeActivities{s} }
+func (s fakeStore) Outbox() repositories.OutboxRepository       { return fakeOutbox{s} }
+func (s fakeStore) Pricing() repositories.PricingRepository     { return fakePricing{s} }
+func (s fakeStore) Coupons() repositories.CouponRepository      { return fakeCoupons{s} }
+func (s fakeStore) Payments() repositories.PaymentRepository    { return fakePayments{s} }
+func (s fakeStore) Returns() repositories.ReturnRepository      { return fakeReturns{s} }
+func (s fakeStore) Reviews() repositories.ReviewRepository      { return fakeReviews{s} }
+func (s fakeStore) Roles() repositories.RoleRepository          { return fakeRoles{s} }
+func (s fakeStore) Records() repositories.RecordRepository      { return fakeRecords{s} }
+func (s fakeStore) Authentications() repositories.AuthenticationRepository {
+	return fakeAuthentications{s}
+}
+
+func (s fakeStore) Transaction(fn func(tx repositories.Store) error) error {
+	snapshot := s.snapshot()
+	if err := fn(s); err != nil {
+		*s.fakeData = snapshot
+		return err
+	}
+	return nil
+}
+
+// users
+
+type fakeUsers struct{ fakeStore }
+
+func (r fakeUsers) Find(id uint64) (models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return user, repositories.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r fakeUsers) FindByEmail(email string) (models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, repositories.ErrNotFound
+}
+
+func (r fakeUsers) Create(user *models.User) error {
+	user.Id = r.nextId()
+	r.users[user.Id] = *user
+	return nil
+}
+
+// AssignRoles knows the built-in roles only.
+func (r fakeUsers) AssignRoles(userId uint64, roles ...string) error {
+	for _, role := range roles {
+		if _, ok := RolePermissions[role]; !ok {
+			return fmt.Errorf("%w: %s", repositories.ErrUnknownRole, role)
+		}
+	}
+	r.userRoles[userId] = roles
+	return nil
+}
+
+func (r fakeUsers) EmailTaken(email string, exceptId uint64) (bool, error) {
+	for _, user := range r.users {
+		if user.Email == email && user.Id != exceptId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeUsers) PhoneTaken(phone string, exceptId uint64) (bool, error) {
+	for _, user := range r.users {
+		if user.Phone == phone && user.Id != exceptId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeUsers) Update(user *models.User, changes models.User) error {
+	stored := r.users[user.Id]
+	if changes.Email != "" {
+		stored.Email = changes.Email
+	}
+	if changes.Phone != "" {
+		stored.Phone = changes.Phone
+	}
+	if changes.Image.Valid {
+		stored.Image = changes.Image
+	}
+	if changes.FirstName.Valid {
+		stored.FirstName = changes.FirstName
+	}
+	if changes.LastName.Valid {
+		stored.LastName = changes.LastName
+	}
+	if changes.Gender.Valid {
+		stored.Gender = changes.Gender
+	}
+	if changes.Country.Valid {
+		stored.Country = changes.Country
+	}
+	if changes.City.Valid {
+		stored.City = changes.City
+	}
+	if changes.ZipCode.Valid {
+		stored.ZipCode = changes.ZipCode
+	}
+	if changes.Address.Valid {
+		stored.Address = changes.Address
+	}
+	if changes.Status != 0 {
+		stored.Status = changes.Status
+	}
+	r.users[user.Id] = stored
+	*user = stored
+	return nil
+}
+
+func (r fakeUsers) UpdatePassword(user *models.User, hashed string) error {
+	stored := r.users[user.Id]
+	stored.Password = hashed
+	stored.Salt = ""
+	r.users[user.Id] = stored
+	*user = stored
+	return nil
+}
+
+func (r fakeUsers) Wishlist(userId uint64) ([]repositories.WishlistItem, error) {
+	var items []repositories.WishlistItem
+	for key := range r.wishlists {
+		if key[0] == userId {
+			product := r.products[key[1]]
+			items = append(items, repositories.WishlistItem{Id: int64(product.Id), Name: product.Name, Image: product.Image, Price: product.Price})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Id < items[j].Id })
+	return items, nil
+}
+
+func (r fakeUsers) AddToWishlist(userId uint64, productId uint64) error {
+	r.wishlists[[2]uint64{userId, productId}] = true
+	return nil
+}
+
+func (r fakeUsers) RemoveFromWishlist(userId uint64, productId uint64) error {
+	delete(r.wishlists, [2]uint64{userId, productId})
+	return nil
+}
+
+func (r fakeUsers) Subscribe(newsletter *models.NewsLetter) error {
+	newsletter.Id = r.nextId()
+	r.newsletters = append(r.newsletters, *newsletter)
+	return nil
+}
+
+// catalog
+
+type fakeCatalog struct{ fakeStore }
+
+func (r fakeCatalog) Product(id uint64) (models.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return product, repositories.ErrNotFound
+	}
+	return product, nil
+}
+
+func (r fakeCatalog) PublishedProduct(id uint64) (models.Product, error) {
+	product, ok := r.products[id]
+	if !ok || product.Status != 1 {
+		return product, repositories.ErrNotFound
+	}
+	return product, nil
+}
+
+func (r fakeCatalog) published() []models.Product {
+	var products []models.Product
+	for _, product := range r.products {
+		if product.Status == 1 {
+			products = append(products, product)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Id > products[j].Id })
+	return products
+}
+
+func (r fakeCatalog) Published(query repositories.ProductQuery) ([]models.Product, error) {
+	var products []models.Product
+	for _, product := range r.published() {
+		if product.Id == query.ExcludeId {
+			continue
+		}
+		if query.Limit > 0 && len(products) == query.Limit {
+			break
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// matching applies the brand and search-id parts of filter; categories
+// are not modelled.
+func (r fakeCatalog) matching(filter repositories.ProductFilter) []models.Product {
+	var products []models.Product
+	for _, product := range r.published() {
+		if len(filter.Brands) > 0 && !slices.Contains(filter.Brands, strconv.FormatUint(product.BrandId, 10)) {
+			continue
+		}
+		if filter.Ids != nil && !slices.Contains(filter.Ids, product.Id) {
+			continue
+		}
+		products = append(products, product)
+	}
+	return products
+}
+
+func (r fakeCatalog) Search(filter repositories.ProductFilter) ([]models.Product, int64, error) {
+	products := r.matching(filter)
+	total := int64(len(products))
+	start := filter.Offset()
+	if start > len(products) {
+		start = len(products)
+	}
+	end := start + filter.Limit
+	if end > len(products) {
+		end = len(products)
+	}
+	return products[start:end], total, nil
+}
+
+func (r fakeCatalog) SearchIds(filter repositories.ProductFilter) ([]uint64, error) {
+	var ids []uint64
+	for _, product := range r.matching(filter) {
+		ids = append(ids, product.Id)
+	}
+	return ids, nil
+}
+
+func (r fakeCatalog) PublishedIn(ids []uint64) ([]models.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.matching(repositories.ProductFilter{Ids: ids}), nil
+}
+
+func (r fakeCatalog) Facets(filter repositories.ProductFilter) (repositories.Facets, error) {
+	var facets repositories.Facets
+	totals := map[uint64]int64{}
+	for _, product := range r.matching(repositories.ProductFilter{Ids: filter.Ids}) {
+		totals[product.BrandId]++
+	}
+	for brandId, total := range totals {
+		facets.Brands = append(facets.Brands, repositories.NameCount{Id: uint(brandId), Total: total})
+	}
+	sort.Slice(facets.Brands, func(i, j int) bool { return facets.Brands[i].Id < facets.Brands[j].Id })
+	return facets, nil
+}
+
+func (r fakeCatalog) Indexable() ([]models.Product, error) {
+	return r.published(), nil
+}
+
+func (r fakeCatalog) CountPublished() (int64, error) {
+	return int64(len(r.published())), nil
+}
+
+func (r fakeCatalog) PriceRange() (money.Money, money.Money, error) {
+	products := r.published()
+	if len(products) == 0 {
+		return 0, 0, repositories.ErrNotFound
+	}
+	lowest, highest := products[0].Price, products[0].Price
+	for _, product := range products {
+		if product.Price < lowest {
+			lowest = product.Price
+		}
+		if product.Price > highest {
+			highest = product.Price
+		}
+	}
+	return lowest, highest, nil
+}
+
+func (r fakeCatalog) Categories(displayedOnly bool, limit int) ([]models.Category, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) CategoryCounts() ([]repositories.NameCount, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) BrandCounts() ([]repositories.NameCount, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) Images(productId uint64) ([]models.ProductImage, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) Sizes() ([]models.Size, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) Colours() ([]models.Colour, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) Inventories(productId uint64) ([]models.ProductInventory, error) {
+	var inventories []models.ProductInventory
+	for _, inventory := range r.inventories {
+		if inventory.ProductId == productId {
+			inventories = append(inventories, inventory)
+		}
+	}
+	return inventories, nil
+}
+
+func (r fakeCatalog) FindInventory(productId uint64, sizeId uint64, colourId uint64) (models.ProductInventory, error) {
+	for _, inventory := range r.inventories {
+		if inventory.ProductId == productId && inventory.SizeId == sizeId && inventory.ColourId == colourId {
+			return inventory, nil
+		}
+	}
+	return models.ProductInventory{}, repositories.ErrNotFound
+}
+
+func (r fakeCatalog) LockInventory(id uint64) (models.ProductInventory, error) {
+	inventory, ok := r.inventories[id]
+	if !ok {
+		return inventory, repositories.ErrNotFound
+	}
+	inventory.Product = r.products[inventory.ProductId]
+	return inventory, nil
+}
+
+func (r fakeCatalog) TakeStock(inventoryId uint64, qty uint16) (bool, error) {
+	inventory := r.inventories[inventoryId]
+	if inventory.Stock < qty {
+		return false, nil
+	}
+	inventory.Stock -= qty
+	r.inventories[inventoryId] = inventory
+	return true, nil
+}
+
+func (r fakeCatalog) ReturnStock(inventoryId uint64, qty uint16) error {
+	inventory := r.inventories[inventoryId]
+	inventory.Stock += qty
+	r.inventories[inventoryId] = inventory
+	return nil
+}
+
+func (r fakeCatalog) AddOrdered(productId uint64, qty uint16) error {
+	product := r.products[productId]
+	product.TotalOrder += qty
+	r.products[productId] = product
+	return nil
+}
+
+func (r fakeCatalog) RemoveOrdered(productId uint64, qty uint16) error {
+	product := r.products[productId]
+	if product.TotalOrder >= qty {
+		product.TotalOrder -= qty
+	}
+	r.products[productId] = product
+	return nil
+}
+
+func (r fakeCatalog) SaveRatings(product models.Product) error {
+	saved := r.products[product.Id]
+	saved.TotalRating = product.TotalRating
+	saved.RatingAverage = product.RatingAverage
+	saved.RatingCount = product.RatingCount
+	saved.Rating1, saved.Rating2, saved.Rating3, saved.Rating4, saved.Rating5 = product.Rating1, product.Rating2, product.Rating3, product.Rating4, product.Rating5
+	r.products[product.Id] = saved
+	return nil
+}
+
+func (r fakeCatalog) ProductIds() ([]uint64, error) {
+	return slices.Sorted(maps.Keys(r.products)), nil
+}
+
+func (r fakeCatalog) OrderedTotals(statuses []uint8) (map[uint64]uint64, error) {
+	totals := map[uint64]uint64{}
+	for _, detail := range r.details {
+		if slices.Contains(statuses, r.orders[detail.OrderId].Status) {
+			totals[r.inventories[detail.InventoryId].ProductId] += uint64(detail.Qty)
+		}
+	}
+	return totals, nil
+}
+
+func (r fakeCatalog) SetOrdered(productId uint64, total uint16) error {
+	product := r.products[productId]
+	product.TotalOrder = total
+	r.products[productId] = product
+	return nil
+}
+
+func (r fakeCatalog) ProductPage(query repositories.ListQuery) ([]models.Product, int64, int64, error) {
+	var products []models.Product
+	for _, id := range slices.Sorted(maps.Keys(r.products)) {
+		products = append(products, r.products[id])
+	}
+	page, total := fakePage(products, query)
+	return page, total, total, nil
+}
+
+func (r fakeCatalog) ProductComplete(id uint64) (models.Product, error) {
+	return r.Product(id)
+}
+
+func (r fakeCatalog) SortedImages(productId uint64) ([]models.ProductImage, error) {
+	return nil, nil
+}
+
+func (r fakeCatalog) SkuTaken(sku string, exceptId uint64) (bool, error) {
+	for _, product := range r.products {
+		if product.Sku == sku && product.Id != exceptId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) CategoriesIn(ids []uint64) ([]models.Category, error) {
+	var categories []models.Category
+	for _, id := range uniqueIds(ids) {
+		categories = append(categories, models.Category{Id: id})
+	}
+	return categories, nil
+}
+
+func (r fakeCatalog) ReplaceCategories(product *models.Product, categories []models.Category) error {
+	r.categories[product.Id] = nil
+	for _, category := range categories {
+		r.categories[product.Id] = append(r.categories[product.Id], category.Id)
+	}
+	return nil
+}
+
+func (r fakeCatalog) AttachCategory(product *models.Product, category models.Category) error {
+	r.categories[product.Id] = append(r.categories[product.Id], category.Id)
+	return nil
+}
+
+func (r fakeCatalog) DetachCategory(productId uint64, categoryId uint64) error {
+	r.categories[productId] = slices.DeleteFunc(r.categories[productId], func(id uint64) bool { return id == categoryId })
+	return nil
+}
+
+func (r fakeCatalog) InventoryTaken(productId uint64, sizeId uint64, colourId uint64, exceptId uint64) (bool, error) {
+	for _, inventory := range r.inventories {
+		if inventory.ProductId == productId && inventory.SizeId == sizeId && inventory.ColourId == colourId && inventory.Id != exceptId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) BrandInUse(id uint64) (bool, error) {
+	for _, product := range r.products {
+		if product.BrandId == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) ColourInUse(id uint64) (bool, error) {
+	for _, inventory := range r.inventories {
+		if inventory.ColourId == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) SizeInUse(id uint64) (bool, error) {
+	for _, inventory := range r.inventories {
+		if inventory.SizeId == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) ProductOrdered(productId uint64) (bool, error) {
+	for _, detail := range r.details {
+		if r.inventories[detail.InventoryId].ProductId == productId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) InventoryOrdered(inventoryId uint64) (bool, error) {
+	for _, detail := range r.details {
+		if detail.InventoryId == inventoryId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCatalog) DeleteCategory(id uint64) error {
+	for productId := range r.categories {
+		r.DetachCategory(productId, id)
+	}
+	return nil
+}
+
+func (r fakeCatalog) DeleteProduct(id uint64) error {
+	for inventoryId, inventory := range r.inventories {
+		if inventory.ProductId == id {
+			delete(r.inventories, inventoryId)
+		}
+	}
+	delete(r.categories, id)
+	delete(r.products, id)
+	return nil
+}
+
+// records
+//
+// Only products, inventories and coupons are kept; everything else
+// behaves as an empty table that accepts writes.
+
+type fakeRecords struct{ fakeStore }
+
+func (r fakeRecords) List(list interface{}, query repositories.ListQuery, columns ...string) (int64, int64, error) {
+	return 0, 0, nil
+}
+
+func (r fakeRecords) Find(record interface{}, id uint64) error {
+	var ok bool
+	switch record := record.(type) {
+	case *models.Product:
+		*record, ok = r.products[id]
+	case *models.ProductInventory:
+		*record, ok = r.inventories[id]
+	case *models.Coupon:
+		*record, ok = r.coupons[id]
+	}
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r fakeRecords) Create(record interface{}) error {
+	switch record := record.(type) {
+	case *models.Product:
+		record.Id = r.nextId()
+		r.products[record.Id] = *record
+	case *models.ProductInventory:
+		record.Id = r.nextId()
+		r.inventories[record.Id] = *record
+	case *models.Coupon:
+		record.Id = r.nextId()
+		r.coupons[record.Id] = *record
+	}
+	return nil
+}
+
+func (r fakeRecords) Update(record interface{}, changes map[string]interface{}) error {
+	return nil
+}
+
+func (r fakeRecords) Delete(record interface{}) error {
+	switch record := record.(type) {
+	case *models.Product:
+		delete(r.products, record.Id)
+	case *models.ProductInventory:
+		delete(r.inventories, record.Id)
+	case *models.Coupon:
+		delete(r.coupons, record.Id)
+	}
+	return nil
+}
+
+// orders
+
+type fakeOrders struct{ fakeStore }
+
+func (r fakeOrders) Find(id uint64) (models.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return order, repositories.ErrNotFound
+	}
+	return order, nil
+}
+
+func (r fakeOrders) Lock(id uint64) (models.Order, error) {
+	return r.Find(id)
+}
+
+func (r fakeOrders) FindOwned(userId uint64, id uint64) (models.Order, error) {
+	order, ok := r.orders[id]
+	if !ok || userId == 0 || order.UserId != userId {
+		return models.Order{}, repositories.ErrNotFound
+	}
+	return order, nil
+}
+
+func (r fakeOrders) LockOwned(userId uint64, id uint64) (models.Order, error) {
+	return r.FindOwned(userId, id)
+}
+
+func (r fakeOrders) HasDelivered(userId uint64, productId uint64) (bool, error) {
+	for _, detail := range r.details {
+		order := r.orders[detail.OrderId]
+		if order.UserId == userId && order.Status == models.OrderStatusDelivered && r.inventories[detail.InventoryId].ProductId == productId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeOrders) OpenCart(userId uint64) (models.Order, error) {
+	var cart models.Order
+	for _, order := range r.orders {
+		if order.UserId == userId && order.Status == models.OrderStatusCart && order.Id > cart.Id {
+			cart = order
+		}
+	}
+	if cart.Id == 0 {
+		return cart, repositories.ErrNotFound
+	}
+	return cart, nil
+}
+
+func (r fakeOrders) LockOpenCart(userId uint64) (models.Order, error) {
+	return r.OpenCart(userId)
+}
+
+func (r fakeOrders) OpenGuestCart(tokenHash string) (models.Order, error) {
+	for _, order := range r.orders {
+		if order.Status == models.OrderStatusCart && order.CartToken.Valid && order.CartToken.String == tokenHash {
+			return order, nil
+		}
+	}
+	return models.Order{}, repositories.ErrNotFound
+}
+
+func (r fakeOrders) LockGuestCart(tokenHash string) (models.Order, error) {
+	return r.OpenGuestCart(tokenHash)
+}
+
+func (r fakeOrders) List(userId uint64, query repositories.ListQuery) ([]models.Order, int64, int64, error) {
+	var orders []models.Order
+	for _, order := range r.orders {
+		if order.UserId == userId {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Id > orders[j].Id })
+	page, total := fakePage(orders, query)
+	return page, total, total, nil
+}
+
+// Search ignores the search text, which the fixtures never set.
+func (r fakeOrders) Search(status *uint8, query repositories.ListQuery) ([]models.Order, int64, int64, error) {
+	var orders []models.Order
+	for _, order := range r.orders {
+		if status == nil || order.Status == *status {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Id > orders[j].Id })
+	page, total := fakePage(orders, query)
+	return page, total, total, nil
+}
+
+func (r fakeOrders) FindComplete(id uint64) (models.Order, error) {
+	return r.Find(id)
+}
+
+// fakePage cuts one page out of rows, returning it with the number of rows.
+func fakePage[T any](rows []T, query repositories.ListQuery) ([]T, int64) {
+	start := min(query.Offset(), len(rows))
+	end := min(start+query.Limit, len(rows))
+	return rows[start:end], int64(len(rows))
+}
+
+func (r fakeOrders) Create(order *models.Order) error {
+	order.Id = r.nextId()
+	r.orders[order.Id] = *order
+	return nil
+}
+
+func (r fakeOrders) Save(order *models.Order) error {
+	if order.Id == 0 {
+		return r.Create(order)
+	}
+	r.orders[order.Id] = *order
+	return nil
+}
+
+func (r fakeOrders) SetStatus(order *models.Order, status uint8) error {
+	stored := r.orders[order.Id]
+	stored.Status = status
+	r.orders[order.Id] = stored
+	return nil
+}
+
+func (r fakeOrders) Delete(order *models.Order) error {
+	delete(r.orders, order.Id)
+	return nil
+}
+
+func (r fakeOrders) Details(orderId uint64) ([]models.OrderDetail, error) {
+	var details []models.OrderDetail
+	for _, detail := range r.details {
+		if detail.OrderId == orderId {
+			details = append(details, detail)
+		}
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].InventoryId < details[j].InventoryId })
+	return details, nil
+}
+
+func (r fakeOrders) Detail(orderId uint64, id uint64) (models.OrderDetail, error) {
+	detail, ok := r.details[id]
+	if !ok || detail.OrderId != orderId {
+		return models.OrderDetail{}, repositories.ErrNotFound
+	}
+	return detail, nil
+}
+
+func (r fakeOrders) FindDetail(orderId uint64, inventoryId uint64) (models.OrderDetail, error) {
+	for _, detail := range r.details {
+		if detail.OrderId == orderId && detail.InventoryId == inventoryId {
+			return detail, nil
+		}
+	}
+	return models.OrderDetail{}, repositories.ErrNotFound
+}
+
+func (r fakeOrders) SaveDetail(detail *models.OrderDetail) error {
+	if detail.Id == 0 {
+		detail.Id = r.nextId()
+	}
+	r.details[detail.Id] = *detail
+	return nil
+}
+
+func (r fakeOrders) DeleteDetail(detail *models.OrderDetail) error {
+	delete(r.details, detail.Id)
+	return nil
+}
+
+func (r fakeOrders) DeleteDetails(orderId uint64) error {
+	for id, detail := range r.details {
+		if detail.OrderId == orderId {
+			delete(r.details, id)
+		}
+	}
+	return nil
+}
+
+func (r fakeOrders) lines(match func(order models.Order) bool) []repositories.OrderLine {
+	details := make([]models.OrderDetail, 0, len(r.details))
+	for _, detail := range r.details {
+		details = append(details, detail)
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].Id < details[j].Id })
+
+	var lines []repositories.OrderLine
+	for _, detail := range details {
+		if !match(r.orders[detail.OrderId]) {
+			continue
+		}
+		product := r.products[r.inventories[detail.InventoryId].ProductId]
+		lines = append(lines, repositories.OrderLine{
+			Id:       int64(product.Id),
+			DetailId: int64(detail.Id),
+			Name:     product.Name,
+			Image:    product.Image,
+			Price:    product.Price,
+			Qty:      detail.Qty,
+			Total:    detail.Total,
+		})
+	}
+	return lines
+}
+
+func (r fakeOrders) Lines(orderId uint64) ([]repositories.OrderLine, error) {
+	return r.lines(func(order models.Order) bool { return order.Id == orderId }), nil
+}
+
+func (r fakeOrders) CartLines(userId uint64) ([]repositories.OrderLine, error) {
+	return r.lines(func(order models.Order) bool {
+		return order.UserId == userId && order.Status == models.OrderStatusCart
+	}), nil
+}
+
+func (r fakeOrders) AttachProduct(orderId uint64, productId uint64) error {
+	r.carts[[2]uint64{orderId, productId}] = true
+	return nil
+}
+
+func (r fakeOrders) DetachProducts(orderId uint64) error {
+	for key := range r.carts {
+		if key[0] == orderId {
+			delete(r.carts, key)
+		}
+	}
+	return nil
+}
+
+func (r fakeOrders) PruneProducts(orderId uint64) error {
+	held := map[uint64]bool{}
+	for _, detail := range r.details {
+		if detail.OrderId == orderId {
+			held[r.inventories[detail.InventoryId].ProductId] = true
+		}
+	}
+	for key := range r.carts {
+		if key[0] == orderId && !held[key[1]] {
+			delete(r.carts, key)
+		}
+	}
+	return nil
+}
+
+func (r fakeOrders) Billings(orderId uint64) ([]models.OrderBilling, error) {
+	var billings []models.OrderBilling
+	for _, billing := range r.billings {
+		if billing.OrderId == orderId {
+			billings = append(billings, billing)
+		}
+	}
+	return billings, nil
+}
+
+func (r fakeOrders) CreateBilling(billing *models.OrderBilling) error {
+	billing.Id = r.nextId()
+	r.billings = append(r.billings, *billing)
+	return nil
+}
+
+func (r fakeOrders) Histories(orderId uint64) ([]models.OrderStatusHistory, error) {
+	var histories []models.OrderStatusHistory
+	for _, history := range r.histories {
+		if history.OrderId == orderId {
+			histories = append(histories, history)
+		}
+	}
+	return histories, nil
+}
+
+func (r fakeOrders) AddHistory(history *models.OrderStatusHistory) error {
+	history.Id = r.nextId()
+	r.histories = append(r.histories, *history)
+	return nil
+}
+
+func (r fakeOrders) Payments() ([]models.Payment, error) {
+	return r.payments, nil
+}
+
+func (r fakeOrders) Payment(id uint64) (models.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.Id == id {
+			return payment, nil
+		}
+	}
+	return models.Payment{}, repositories.ErrNotFound
+}
+
+func (r fakeOrders) DefaultPayment() (models.Payment, error) {
+	if len(r.payments) == 0 {
+		return models.Payment{}, repositories.ErrNotFound
+	}
+	return r.payments[0], nil
+}
+
+// settings, activities and outbox
+
+type fakeSettings struct{ fakeStore }
+
+func (r fakeSettings) All() (map[string]string, error) {
+	return cloneMap(r.settings), nil
+}
+
+func (r fakeSettings) Get(key string) (string, error) {
+	value, ok := r.settings[key]
+	if !ok {
+		return "", repositories.ErrNotFound
+	}
+	return value, nil
+}
+
+type fakeActivities struct{ fakeStore }
+
+func (r fakeActivities) Create(activity *models.Activity) error {
+	activity.Id = r.nextId()
+	r.activities = append(r.activities, *activity)
+	return nil
+}
+
+func (r fakeActivities) List(userId uint64, query repositories.ListQuery) ([]models.Activity, error) {
+	var activities []models.Activity
+	for _, activity := range r.activities {
+		if uint64(activity.UserId) == userId {
+			activities = append(activities, activity)
+		}
+	}
+	return activities, nil
+}
+
+type fakeOutbox struct{ fakeStore }
+
+func (r fakeOutbox) Enqueue(to string, template string, data map[string]interface{}) error {
+	r.outbox = append(r.outbox, fakeMail{To: to, Template: template, Data: data})
+	return nil
+}
+
+// authentications
+
+type fakeAuthentications struct{ fakeStore }
+
+func (r fakeAuthentications) Latest(userId uint64, authType string) (models.Authentication, error) {
+	for i := len(r.authentications) - 1; i >= 0; i-- {
+		if row := r.authentications[i]; row.UserId == int64(userId) && row.AuthType == authType {
+			return row, nil
+		}
+	}
+	return models.Authentication{}, repositories.ErrNotFound
+}
+
+func (r fakeAuthentications) CountSince(userId uint64, authType string, since time.Time) (int64, error) {
+	var total int64
+	for _, row := range r.authentications {
+		if row.UserId == int64(userId) && row.AuthType == authType && row.CreatedAt.After(since) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (r fakeAuthentications) RevokePending(userId uint64, authType string) error {
+	for i, row := range r.authentications {
+		if row.UserId == int64(userId) && row.AuthType == authType && row.Status == models.AuthStatusPending {
+			r.authentications[i].Status = models.AuthStatusRevoked
+		}
+	}
+	return nil
+}
+
+func (r fakeAuthentications) Create(row *models.Authentication) error {
+	row.Id = r.nextId()
+	r.authentications = append(r.authentications, *row)
+	return nil
+}
+
+func (r fakeAuthentications) Lock(authType string, token string) (models.Authentication, error) {
+	for _, row := range r.authentications {
+		if row.AuthType == authType && row.Token == token {
+			return row, nil
+		}
+	}
+	return models.Authentication{}, repositories.ErrNotFound
+}
+
+func (r fakeAuthentications) FindPending(authType string, token string, credential string) (models.Authentication, error) {
+	for _, row := range r.authentications {
+		if row.AuthType == authType && row.Token == token && row.Status == models.AuthStatusPending &&
+			(credential == "" || row.Credential == credential) {
+			return row, nil
+		}
+	}
+	return models.Authentication{}, repositories.ErrNotFound
+}
+
+func (r fakeAuthentications) Use(id uint64, expiredAt *time.Time) (bool, error) {
+	for i, row := range r.authentications {
+		if row.Id == id && row.Status == models.AuthStatusPending {
+			r.authentications[i].Status = models.AuthStatusUsed
+			if expiredAt != nil {
+				r.authentications[i].ExpiredAt = expiredAt
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeAuthentications) RevokeFamily(familyId string) error {
+	for i, row := range r.authentications {
+		if row.AuthType == models.AuthTypeRefreshToken && row.FamilyId == familyId {
+			r.authentications[i].Status = models.AuthStatusRevoked
+		}
+	}
+	return nil
+}
+
+func (r fakeAuthentications) RevokeAll(userId uint64, authType string) error {
+	for i, row := range r.authentications {
+		if row.AuthType == authType && row.UserId == int64(userId) {
+			r.authentications[i].Status = models.AuthStatusRevoked
+		}
+	}
+	return nil
+}
+
+func (r fakeAuthentications) FamilyLive(familyId string, at time.Time) (bool, error) {
+	for _, row := range r.authentications {
+		if row.AuthType == models.AuthTypeRefreshToken && row.FamilyId == familyId &&
+			row.Status != models.AuthStatusRevoked && row.ExpiredAt != nil && row.ExpiredAt.After(at) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeAuthentications) DeleteRefresh(at time.Time) (int64, error) {
+	return r.delete(func(row models.Authentication) bool {
+		return row.AuthType == models.AuthTypeRefreshToken &&
+			(row.Status == models.AuthStatusRevoked || (row.ExpiredAt != nil && row.ExpiredAt.Before(at)))
+	}), nil
+}
+
+func (r fakeAuthentications) DeleteExpired(authTypes []string, before time.Time) (int64, error) {
+	return r.delete(func(row models.Authentication) bool {
+		return slices.Contains(authTypes, row.AuthType) && row.ExpiredAt != nil && row.ExpiredAt.Before(before)
+	}), nil
+}
+
+func (r fakeAuthentications) delete(match func(models.Authentication) bool) int64 {
+	kept := r.authentications[:0]
+	for _, row := range r.authentications {
+		if !match(row) {
+			kept = append(kept, row)
+		}
+	}
+	deleted := int64(len(r.authentications) - len(kept))
+	r.authentications = kept
+	return deleted
+}
+
+// roles
+
+// fakeRoles serves the built-in roles of RolePermissions, all active.
+type fakeRoles struct{ fakeStore }
+
+func (r fakeRoles) List() ([]models.Role, error) {
+	var roles []models.Role
+	for _, name := range slices.Sorted(maps.Keys(RolePermissions)) {
+		role := models.Role{Name: name, Status: 1}
+		for _, permission := range RolePermissions[name] {
+			role.Permissions = append(role.Permissions, models.Permission{Name: permission})
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (r fakeRoles) ForUser(userId uint64) ([]string, error) {
+	return slices.Sorted(slices.Values(r.userRoles[userId])), nil
+}
+
+func (r fakeRoles) Permissions(roles []string) ([]string, error) {
+	permissions := []string{}
+	for _, role := range roles {
+		for _, permission := range RolePermissions[role] {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// pricing
+
+type fakePricing struct{ fakeStore }
+
+func (r fakePricing) Rules() ([]models.PriceRule, error) {
+	var rules []models.PriceRule
+	for _, rule := range r.rules {
+		if rule.Status == 1 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r fakePricing) TaxRates() ([]models.TaxRate, error) {
+	var rates []models.TaxRate
+	for _, rate := range r.taxRates {
+		if rate.Status == 1 {
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+func (r fakePricing) Items(orderId uint64) ([]repositories.PriceItem, error) {
+	var items []repositories.PriceItem
+	for _, detail := range r.details {
+		if detail.OrderId != orderId {
+			continue
+		}
+		inventory := r.inventories[detail.InventoryId]
+		product := r.products[inventory.ProductId]
+		items = append(items, repositories.PriceItem{
+			DetailId:    detail.Id,
+			InventoryId: inventory.Id,
+			ProductId:   product.Id,
+			BrandId:     product.BrandId,
+			CategoryIds: r.categories[product.Id],
+			Name:        product.Name,
+			Price:       detail.Price,
+			Qty:         detail.Qty,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DetailId < items[j].DetailId })
+	return items, nil
+}
+
+// coupons
+
+type fakeCoupons struct{ fakeStore }
+
+func (r fakeCoupons) Find(id uint64) (models.Coupon, error) {
+	coupon, ok := r.coupons[id]
+	if !ok {
+		return coupon, repositories.ErrNotFound
+	}
+	return coupon, nil
+}
+
+func (r fakeCoupons) FindByCode(code string) (models.Coupon, error) {
+	for _, coupon := range r.coupons {
+		if coupon.Code == code {
+			return coupon, nil
+		}
+	}
+	return models.Coupon{}, repositories.ErrNotFound
+}
+
+func (r fakeCoupons) Lock(id uint64) (models.Coupon, error) {
+	return r.Find(id)
+}
+
+func (r fakeCoupons) ForOrder(orderId uint64) (models.OrderCoupon, error) {
+	orderCoupon, ok := r.redemptions[orderId]
+	if !ok {
+		return orderCoupon, repositories.ErrNotFound
+	}
+	return orderCoupon, nil
+}
+
+func (r fakeCoupons) Attach(orderCoupon *models.OrderCoupon) error {
+	orderCoupon.Id = r.nextId()
+	r.redemptions[orderCoupon.OrderId] = *orderCoupon
+	return nil
+}
+
+func (r fakeCoupons) Detach(orderId uint64) error {
+	delete(r.redemptions, orderId)
+	return nil
+}
+
+func (r fakeCoupons) SaveRedemption(orderCoupon *models.OrderCoupon) error {
+	r.redemptions[orderCoupon.OrderId] = *orderCoupon
+	return nil
+}
+
+func (r fakeCoupons) Redemptions(couponId uint64, userId uint64, exceptOrderId uint64) (int64, int64, error) {
+	var total, byUser int64
+	for orderId, orderCoupon := range r.redemptions {
+		status := r.orders[orderId].Status
+		if orderCoupon.CouponId != couponId || orderId == exceptOrderId || status == models.OrderStatusCart || status == models.OrderStatusCancelled {
+			continue
+		}
+		total++
+		if orderCoupon.UserId == userId {
+			byUser++
+		}
+	}
+	return total, byUser, nil
+}
+
+func (r fakeCoupons) Search(query repositories.ListQuery) ([]models.Coupon, int64, int64, error) {
+	var coupons []models.Coupon
+	for _, id := range slices.Sorted(maps.Keys(r.coupons)) {
+		coupons = append(coupons, r.coupons[id])
+	}
+	page, total := fakePage(coupons, query)
+	return page, total, total, nil
+}
+
+func (r fakeCoupons) CodeTaken(code string, exceptId uint64) (bool, error) {
+	for _, coupon := range r.coupons {
+		if coupon.Code == code && coupon.Id != exceptId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TargetExists only knows products; categories and brands always exist.
+func (r fakeCoupons) TargetExists(scope string, targetId uint64) (bool, error) {
+	if scope == models.PriceScopeProduct {
+		_, ok := r.products[targetId]
+		return ok, nil
+	}
+	return true, nil
+}
+
+func (r fakeCoupons) Redeemed(couponId uint64) (bool, error) {
+	for orderId, orderCoupon := range r.redemptions {
+		if orderCoupon.CouponId == couponId && r.orders[orderId].Status != models.OrderStatusCart {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeCoupons) ReplaceTargets(coupon *models.Coupon, targets []models.CouponTarget) error {
+	for i := range targets {
+		targets[i].CouponId = coupon.Id
+	}
+	coupon.Targets = targets
+	saved := r.coupons[coupon.Id]
+	saved.Targets = targets
+	r.coupons[coupon.Id] = saved
+	return nil
+}
+
+func (r fakeCoupons) Delete(couponId uint64) error {
+	for orderId, orderCoupon := range r.redemptions {
+		if orderCoupon.CouponId == couponId {
+			delete(r.redemptions, orderId)
+		}
+	}
+	delete(r.coupons, couponId)
+	return nil
+}
+
+// payments
+
+type fakePayments struct{ fakeStore }
+
+func (r fakePayments) CreateTransaction(transaction *models.PaymentTransaction) error {
+	transaction.Id = r.nextId()
+	r.attempts[transaction.Id] = *transaction
+	return nil
+}
+
+func (r fakePayments) SaveTransaction(transaction *models.PaymentTransaction) error {
+	r.attempts[transaction.Id] = *transaction
+	return nil
+}
+
+func (r fakePayments) Transactions(orderId uint64) ([]models.PaymentTransaction, error) {
+	var transactions []models.PaymentTransaction
+	for _, transaction := range r.attempts {
+		if transaction.OrderId == orderId {
+			transactions = append(transactions, transaction)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].Id > transactions[j].Id })
+	return transactions, nil
+}
+
+func (r fakePayments) PendingTransaction(orderId uint64) (models.PaymentTransaction, error) {
+	transactions, _ := r.Transactions(orderId)
+	for _, transaction := range transactions {
+		if transaction.Status == "pending" {
+			return transaction, nil
+		}
+	}
+	return models.PaymentTransaction{}, repositories.ErrNotFound
+}
+
+func (r fakePayments) LockTransaction(provider string, reference string) (models.PaymentTransaction, error) {
+	var found models.PaymentTransaction
+	for _, transaction := range r.attempts {
+		if transaction.Provider == provider && transaction.Reference == reference && transaction.Id > found.Id {
+			found = transaction
+		}
+	}
+	if found.Id == 0 {
+		return found, repositories.ErrNotFound
+	}
+	return found, nil
+}
+
+func (r fakePayments) PaidTransaction(orderId uint64) (models.PaymentTransaction, error) {
+	transactions, _ := r.Transactions(orderId)
+	for _, transaction := range transactions {
+		if transaction.Status == "succeeded" {
+			return transaction, nil
+		}
+	}
+	return models.PaymentTransaction{}, repositories.ErrNotFound
+}
+
+func (r fakePayments) EventSeen(provider string, eventId string) (bool, error) {
+	for _, event := range r.events {
+		if event.Provider == provider && event.EventId == eventId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakePayments) CreateEvent(event *models.PaymentEvent) error {
+	event.Id = r.nextId()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// returns
+
+type fakeReturns struct{ fakeStore }
+
+func (r fakeReturns) Find(id uint64) (models.OrderReturn, error) {
+	orderReturn, ok := r.returns[id]
+	if !ok {
+		return orderReturn, repositories.ErrNotFound
+	}
+	return orderReturn, nil
+}
+
+func (r fakeReturns) Lock(id uint64) (models.OrderReturn, error) {
+	return r.Find(id)
+}
+
+func (r fakeReturns) Search(status string, query repositories.ListQuery) ([]models.OrderReturn, int64, int64, error) {
+	var orderReturns []models.OrderReturn
+	for _, orderReturn := range r.returns {
+		if status == "" || orderReturn.Status == status {
+			orderReturns = append(orderReturns, orderReturn)
+		}
+	}
+	sort.Slice(orderReturns, func(i, j int) bool { return orderReturns[i].Id > orderReturns[j].Id })
+	page, total := fakePage(orderReturns, query)
+	return page, total, total, nil
+}
+
+func (r fakeReturns) FindComplete(id uint64) (models.OrderReturn, error) {
+	orderReturn, err := r.Find(id)
+	if err != nil {
+		return orderReturn, err
+	}
+	for _, history := range r.returnHistories {
+		if history.ReturnId == id {
+			orderReturn.Histories = append(orderReturn.Histories, history)
+		}
+	}
+	return orderReturn, nil
+}
+
+func (r fakeReturns) ForOrder(orderId uint64) ([]models.OrderReturn, error) {
+	var orderReturns []models.OrderReturn
+	for _, orderReturn := range r.returns {
+		if orderReturn.OrderId == orderId {
+			orderReturns = append(orderReturns, orderReturn)
+		}
+	}
+	sort.Slice(orderReturns, func(i, j int) bool { return orderReturns[i].Id > orderReturns[j].Id })
+	return orderReturns, nil
+}
+
+func (r fakeReturns) Returned(orderId uint64) (map[uint64]uint16, error) {
+	returned := map[uint64]uint16{}
+	for _, orderReturn := range r.returns {
+		if orderReturn.OrderId != orderId || orderReturn.Status == models.ReturnStatusRejected {
+			continue
+		}
+		for _, line := range orderReturn.Lines {
+			returned[line.DetailId] += line.Qty
+		}
+	}
+	return returned, nil
+}
+
+func (r fakeReturns) Create(orderReturn *models.OrderReturn) error {
+	orderReturn.Id = r.nextId()
+	for i := range orderReturn.Lines {
+		orderReturn.Lines[i].Id = r.nextId()
+		orderReturn.Lines[i].ReturnId = orderReturn.Id
+	}
+	r.returns[orderReturn.Id] = *orderReturn
+	return nil
+}
+
+func (r fakeReturns) Save(orderReturn *models.OrderReturn) error {
+	r.returns[orderReturn.Id] = *orderReturn
+	return nil
+}
+
+func (r fakeReturns) AddHistory(history *models.OrderReturnHistory) error {
+	history.Id = r.nextId()
+	r.returnHistories = append(r.returnHistories, *history)
+	return nil
+}
+
+// reviews
+
+type fakeReviews struct{ fakeStore }
+
+func (r fakeReviews) Find(id uint64) (models.ProductReview, error) {
+	review, ok := r.reviews[id]
+	if !ok {
+		return review, repositories.ErrNotFound
+	}
+	return review, nil
+}
+
+func (r fakeReviews) Search(status *uint8, productId uint64, query repositories.ListQuery) ([]models.ProductReview, int64, int64, error) {
+	var reviews []models.ProductReview
+	for _, review := range r.reviews {
+		if (status == nil || review.Status == *status) && (productId == 0 || review.ProductId == productId) {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].Id > reviews[j].Id })
+	page, total := fakePage(reviews, query)
+	return page, total, total, nil
+}
+
+func (r fakeReviews) FindComplete(id uint64) (models.ProductReview, error) {
+	review, err := r.Find(id)
+	if err != nil {
+		return review, err
+	}
+	review.User = r.users[uint64(review.UserId)]
+	review.Product = r.products[review.ProductId]
+	return review, nil
+}
+
+func (r fakeReviews) Lock(id uint64) (models.ProductReview, error) {
+	return r.Find(id)
+}
+
+func (r fakeReviews) Owned(userId uint64, productId uint64) (models.ProductReview, error) {
+	for _, review := range r.reviews {
+		if review.UserId == userId && review.ProductId == productId {
+			return review, nil
+		}
+	}
+	return models.ProductReview{}, repositories.ErrNotFound
+}
+
+func (r fakeReviews) LockOwned(userId uint64, productId uint64) (models.ProductReview, error) {
+	return r.Owned(userId, productId)
+}
+
+func (r fakeReviews) Approved(productId uint64) ([]models.ProductReview, error) {
+	var reviews []models.ProductReview
+	for _, review := range r.reviews {
+		if review.ProductId == productId && review.Status == models.ReviewStatusApproved {
+			review.User = r.users[review.UserId]
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if reviews[i].HelpfulCount != reviews[j].HelpfulCount {
+			return reviews[i].HelpfulCount > reviews[j].HelpfulCount
+		}
+		return reviews[i].Id > reviews[j].Id
+	})
+	return reviews, nil
+}
+
+func (r fakeReviews) Stars(productId uint64) ([5]uint32, error) {
+	var stars [5]uint32
+	for _, review := range r.reviews {
+		if review.ProductId == productId && review.Status == models.ReviewStatusApproved {
+			stars[review.Rating-1]++
+		}
+	}
+	return stars, nil
+}
+
+func (r fakeReviews) AllStars() (map[uint64][5]uint32, error) {
+	stars := map[uint64][5]uint32{}
+	for _, review := range r.reviews {
+		if review.Status == models.ReviewStatusApproved {
+			counts := stars[review.ProductId]
+			counts[review.Rating-1]++
+			stars[review.ProductId] = counts
+		}
+	}
+	return stars, nil
+}
+
+func (r fakeReviews) Create(review *models.ProductReview) error {
+	review.Id = r.nextId()
+	r.reviews[review.Id] = *review
+	return nil
+}
+
+func (r fakeReviews) Save(review *models.ProductReview) error {
+	r.reviews[review.Id] = *review
+	return nil
+}
+
+func (r fakeReviews) Vote(reviewId uint64, userId uint64) (bool, error) {
+	key := [2]uint64{reviewId, userId}
+	if r.votes[key] {
+		return false, nil
+	}
+	r.votes[key] = true
+	return true, nil
+}
+
+func (r fakeReviews) Unvote(reviewId uint64, userId uint64) (bool, error) {
+	key := [2]uint64{reviewId, userId}
+	if !r.votes[key] {
+		return false, nil
+	}
+	delete(r.votes, key)
+	return true, nil
+}
+
+func (r fakeReviews) Votes(reviewId uint64) (uint32, error) {
+	var total uint32
+	for key := range r.votes {
+		if key[0] == reviewId {
+			total++
+		}
+	}
+	return total, nil
+}
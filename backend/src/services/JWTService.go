@@ -12,55 +12,58 @@
 package services
 
 import (
+	appconfig "backend/src/appconfig"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"github.com/joho/godotenv"
+	"github.com/google/uuid"
 )
 
 // jwt service
 type JWTService interface {
-	GenerateToken(id int, email string, isUser bool) string
+	GenerateToken(id int, email string, isUser bool, roles []string, sessionId string) string
 	ValidateToken(token string) (*jwt.Token, error)
+	Lifespan() time.Duration
 }
 type authCustomClaims struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
-	User bool   `json:"user"`
+	Id    int      `json:"id"`
+	Name  string   `json:"name"`
+	User  bool     `json:"user"`
+	Roles []string `json:"roles"`
+	Sid   string   `json:"sid"`
 	jwt.StandardClaims
 }
 
 type jwtServices struct {
 	secretKey string
 	issure    string
+	lifespan  time.Duration
 }
 
 // auth-jwt
-func JWTAuthService() JWTService {
+func JWTAuthService(config *appconfig.Config) JWTService {
 	return &jwtServices{
-		secretKey: getSecretKey(),
+		secretKey: config.Auth.JWTSecret,
 		issure:    "Sandy Andryanto",
+		lifespan:  config.Auth.TokenLifespan,
 	}
 }
 
-func getSecretKey() string {
-	godotenv.Load(".env")
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "secret"
-	}
-	return secret
+func (service *jwtServices) Lifespan() time.Duration {
+	return service.lifespan
 }
 
-func (service *jwtServices) GenerateToken(id int, email string, isUser bool) string {
+func (service *jwtServices) GenerateToken(id int, email string, isUser bool, roles []string, sessionId string) string {
 	claims := &authCustomClaims{
 		id,
 		email,
 		isUser,
+		roles,
+		sessionId,
 		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 48).Unix(),
+			Id:        uuid.New().String(),
+			ExpiresAt: time.Now().Add(service.lifespan).Unix(),
 			Issuer:    service.issure,
 			IssuedAt:  time.Now().Unix(),
 		},
@@ -78,7 +81,7 @@ func (service *jwtServices) GenerateToken(id int, email string, isUser bool) str
 func (service *jwtServices) ValidateToken(encodedToken string) (*jwt.Token, error) {
 	return jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
 		if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
-			return nil, fmt.Errorf("Invalid token", token.Header["alg"])
+			return nil, fmt.Errorf("Invalid token %v", token.Header["alg"])
 
 		}
 		return []byte(service.secretKey), nil
@@ -0,0 +1,414 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"fmt"
+)
+
+var ErrCartEmpty = errors.New("the cart is empty")
+
+var ErrOrderNotCancellable = errors.New("this order has been paid for and can no longer be cancelled; request a return instead")
+
+// CheckoutLineError explains why one cart line cannot be checked out.
+type CheckoutLineError struct {
+	DetailId    uint64 `json:"detail_id"`
+	InventoryId uint64 `json:"inventory_id"`
+	ProductId   uint64 `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Requested   uint16 `json:"requested"`
+	Available   uint16 `json:"available"`
+	Error       string `json:"error"`
+}
+
+type OutOfStockError struct {
+	Lines []CheckoutLineError
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("%d cart lines are out of stock", len(e.Lines))
+}
+
+type CheckoutQuote struct {
+	Order     models.Order
+	Lines     []repositories.OrderLine
+	Payments  []models.Payment
+	Breakdown PriceBreakdown
+}
+
+type OrderPage struct {
+	List          []models.Order
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+}
+
+type OrderView struct {
+	Order     models.Order
+	Lines     []repositories.OrderLine
+	Billings  []models.OrderBilling
+	Payment   models.Payment
+	Histories []models.OrderStatusHistory
+	Discount  money.Rate
+	Taxes     money.Rate
+}
+
+// order service
+type OrderService interface {
+	Quote(userId uint64, address PriceAddress) (CheckoutQuote, error)
+	Checkout(userId uint64, input schema.CheckoutSchema) (models.Order, PriceBreakdown, error)
+	List(userId uint64, query repositories.ListQuery) (OrderPage, error)
+	AdminList(status *uint8, query repositories.ListQuery) (OrderPage, error)
+	AdminDetail(id uint64) (models.Order, error)
+	Detail(userId uint64, id uint64) (OrderView, error)
+	Cancel(userId uint64, id uint64) error
+	UpdateStatus(id uint64, to uint8, actorId uint64, actorType string, reason string) (models.Order, error)
+}
+
+type orderServices struct {
+	config *appconfig.Config
+	store  repositories.Store
+}
+
+func Orders(config *appconfig.Config, store repositories.Store) OrderService {
+	return &orderServices{config: config, store: store}
+}
+
+// Quote prices the user's open cart for a billing address without
+// changing anything.
+func (service *orderServices) Quote(userId uint64, address PriceAddress) (CheckoutQuote, error) {
+
+	var quote CheckoutQuote
+	var err error
+
+	if quote.Payments, err = service.store.Orders().Payments(); err != nil {
+		return quote, err
+	}
+
+	order, err := service.store.Orders().OpenCart(userId)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return quote, err
+	}
+
+	pricing := Pricing(service.store)
+	if order.Id > 0 {
+		quote.Breakdown, err = pricing.PriceOrder(order, address)
+	} else {
+		quote.Breakdown, err = pricing.Price(nil, address)
+	}
+	if err != nil {
+		return quote, err
+	}
+	quote.Breakdown.Apply(&order)
+	quote.Order = order
+
+	if quote.Lines, err = service.store.Orders().CartLines(userId); err != nil {
+		return quote, err
+	}
+
+	return quote, nil
+}
+
+// Checkout turns the user's open cart into an order awaiting payment. Stock
+// is taken for every line or for none: when any line cannot be filled the
+// whole checkout is rolled back and an *OutOfStockError lists the lines.
+// A coupon on the cart is redeemed with the order, or the checkout fails
+// with a *CouponError when the cart no longer qualifies for it.
+func (service *orderServices) Checkout(userId uint64, input schema.CheckoutSchema) (models.Order, PriceBreakdown, error) {
+
+	var order models.Order
+	var breakdown PriceBreakdown
+
+	user, err := service.store.Users().Find(userId)
+	if err != nil {
+		return order, breakdown, err
+	}
+
+	// Only payment methods offered at checkout can be chosen.
+	method, err := service.store.Orders().Payment(input.PaymentId)
+	if err != nil {
+		return order, breakdown, err
+	}
+	if method.Status != 1 {
+		return order, breakdown, repositories.ErrNotFound
+	}
+
+	err = service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err = tx.Orders().LockOpenCart(userId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrCartEmpty
+		} else if err != nil {
+			return err
+		}
+
+		details, err := tx.Orders().Details(order.Id)
+		if err != nil {
+			return err
+		}
+		if len(details) == 0 {
+			return ErrCartEmpty
+		}
+
+		// Lock every inventory row in a stable order before touching stock so
+		// concurrent checkouts of the same items serialize instead of deadlocking.
+		var lineErrors []CheckoutLineError
+		inventories := make([]models.ProductInventory, len(details))
+		for i, detail := range details {
+
+			inventory, err := tx.Catalog().LockInventory(detail.InventoryId)
+			if errors.Is(err, repositories.ErrNotFound) {
+				lineErrors = append(lineErrors, CheckoutLineError{
+					DetailId:    detail.Id,
+					InventoryId: detail.InventoryId,
+					Requested:   detail.Qty,
+					Error:       "This item is no longer available.",
+				})
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			if inventory.Stock < detail.Qty {
+				lineErrors = append(lineErrors, CheckoutLineError{
+					DetailId:    detail.Id,
+					InventoryId: inventory.Id,
+					ProductId:   inventory.ProductId,
+					ProductName: inventory.Product.Name,
+					Requested:   detail.Qty,
+					Available:   inventory.Stock,
+					Error:       "Insufficient stock for " + inventory.Product.Name + ".",
+				})
+				continue
+			}
+
+			inventories[i] = inventory
+		}
+
+		if len(lineErrors) > 0 {
+			return &OutOfStockError{Lines: lineErrors}
+		}
+
+		for i, detail := range details {
+
+			inventory := inventories[i]
+
+			taken, err := tx.Catalog().TakeStock(inventory.Id, detail.Qty)
+			if err != nil {
+				return err
+			}
+			if !taken {
+				return fmt.Errorf("inventory %d changed while it was locked", inventory.Id)
+			}
+
+			if err := tx.Catalog().AddOrdered(inventory.ProductId, detail.Qty); err != nil {
+				return err
+			}
+
+			if err := tx.Users().RemoveFromWishlist(userId, inventory.ProductId); err != nil {
+				return err
+			}
+		}
+
+		coupon, err := tx.Coupons().ForOrder(order.Id)
+		hasCoupon := err == nil
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		if hasCoupon {
+			if _, err := tx.Coupons().Lock(coupon.CouponId); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return err
+			}
+		}
+
+		breakdown, err = Pricing(tx).PriceOrder(order, PriceAddress{Country: input.Country, ZipCode: input.ZipCode})
+		if err != nil {
+			return err
+		}
+		if breakdown.CouponError != "" {
+			return &CouponError{Code: breakdown.Coupon, Reason: breakdown.CouponError}
+		}
+
+		order.PaymentId = input.PaymentId
+		breakdown.Apply(&order)
+		if err := tx.Orders().Save(&order); err != nil {
+			return err
+		}
+
+		if hasCoupon {
+			coupon.Amount = breakdown.CouponDiscount
+			if err := tx.Coupons().SaveRedemption(&coupon); err != nil {
+				return err
+			}
+		}
+
+		if err := OrderStateMachine(service.config).Transition(tx, &order, models.OrderStatusPendingPayment, user.Id, ActorCustomer, "Checkout submitted"); err != nil {
+			return err
+		}
+
+		billings := []struct {
+			Name  string
+			Value string
+		}{
+			{"email", input.Email},
+			{"phone", input.Phone},
+			{"first_name", input.FirstName},
+			{"last_name", input.LastName},
+			{"country", input.Country},
+			{"city", input.City},
+			{"zip_code", input.ZipCode},
+			{"address", input.Address},
+			{"notes", input.Notes},
+		}
+
+		for _, billing := range billings {
+			if err := tx.Orders().CreateBilling(&models.OrderBilling{
+				OrderId:     order.Id,
+				Name:        billing.Name,
+				Description: billing.Value,
+				Status:      1,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Orders().DetachProducts(order.Id); err != nil {
+			return err
+		}
+
+		if err := logActivity(tx, user.Id, "Checkout Order", "Completed Checkout Current Order", "Your order has been finished."); err != nil {
+			return err
+		}
+
+		return tx.Outbox().Enqueue(input.Email, mailer.TemplateOrderPlaced, map[string]interface{}{
+			"Name":          input.FirstName,
+			"InvoiceNumber": order.InvoiceNumber,
+			"Total":         fmt.Sprintf("%s %s", money.Default(), order.TotalPaid),
+			"Link":          mailer.Link(service.config, fmt.Sprintf("order/detail/%d", order.Id)),
+		})
+	})
+
+	return order, breakdown, err
+}
+
+func (service *orderServices) List(userId uint64, query repositories.ListQuery) (OrderPage, error) {
+	orders, total, filtered, err := service.store.Orders().List(userId, query)
+	return OrderPage{
+		List:          orders,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+// AdminList lists every user's orders for staff, only those in status
+// when it is set.
+func (service *orderServices) AdminList(status *uint8, query repositories.ListQuery) (OrderPage, error) {
+	orders, total, filtered, err := service.store.Orders().Search(status, query)
+	return OrderPage{
+		List:          orders,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+// AdminDetail shows staff an order with everything that happened to it.
+func (service *orderServices) AdminDetail(id uint64) (models.Order, error) {
+	return service.store.Orders().FindComplete(id)
+}
+
+// Detail shows one of the user's orders.
+func (service *orderServices) Detail(userId uint64, id uint64) (OrderView, error) {
+
+	var view OrderView
+	orders := service.store.Orders()
+
+	order, err := orders.FindOwned(userId, id)
+	if err != nil {
+		return view, err
+	}
+	view.Order = order
+
+	if view.Billings, err = orders.Billings(id); err != nil {
+		return view, err
+	}
+
+	view.Payment, err = orders.Payment(order.PaymentId)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return view, err
+	}
+
+	if view.Histories, err = orders.Histories(id); err != nil {
+		return view, err
+	}
+
+	if view.Lines, err = orders.Lines(id); err != nil {
+		return view, err
+	}
+
+	// Tax is charged on the discounted subtotal, so its rate is taken
+	// against that.
+	view.Discount = money.RateOf(order.TotalDiscount, order.Subtotal)
+	if net := order.Subtotal - order.TotalDiscount; net > 0 {
+		view.Taxes = money.RateOf(order.TotalTaxes, net)
+	}
+
+	return view, nil
+}
+
+// Cancel cancels one of the user's orders.
+func (service *orderServices) Cancel(userId uint64, id uint64) error {
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := tx.Orders().LockOwned(userId, id)
+		if err != nil {
+			return err
+		}
+
+		// A paid order may still be cancelled by staff, but the customer
+		// is sent down the return path so the refund is accounted for.
+		machine := OrderStateMachine(service.config)
+		if !models.CustomerCancellable[order.Status] && machine.CanTransition(order.Status, models.OrderStatusCancelled) {
+			return ErrOrderNotCancellable
+		}
+
+		if err := machine.Transition(tx, &order, models.OrderStatusCancelled, userId, ActorCustomer, "Cancelled by customer"); err != nil {
+			return err
+		}
+
+		return logActivity(tx, userId, "Cancel Order", "Canceling Current Order", "Your has been canceling current order.")
+	})
+}
+
+// UpdateStatus moves an order on behalf of staff.
+func (service *orderServices) UpdateStatus(id uint64, to uint8, actorId uint64, actorType string, reason string) (models.Order, error) {
+	var order models.Order
+	err := service.store.Transaction(func(tx repositories.Store) error {
+		var err error
+		if order, err = tx.Orders().Lock(id); err != nil {
+			return err
+		}
+		return OrderStateMachine(service.config).Transition(tx, &order, to, actorId, actorType, reason)
+	})
+	return order, err
+}
@@ -0,0 +1,297 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	money "backend/src/money"
+	payment "backend/src/payment"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"testing"
+)
+
+func checkoutInput() schema.CheckoutSchema {
+	return schema.CheckoutSchema{
+		PaymentId: 7,
+		Email:     "buyer@example.com",
+		FirstName: "Ada",
+		Address:   "1 Main Street",
+	}
+}
+
+func fillCart(t *testing.T, store fakeStore, items ...CartItem) models.Order {
+	t.Helper()
+	for _, item := range items {
+		if _, err := Cart(store).Add(CartOwner{UserId: 1}, item); err != nil {
+			t.Fatalf("Add %+v: %v", item, err)
+		}
+	}
+	order, err := store.Orders().OpenCart(1)
+	if err != nil {
+		t.Fatalf("OpenCart: %v", err)
+	}
+	return order
+}
+
+func TestQuote(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 5})
+
+	quote, err := Orders(testConfig(), store).Quote(1, PriceAddress{})
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if quote.Order.TotalPaid != money.MustParse("119.5") {
+		t.Errorf("TotalPaid = %v, want 100 - 5 + 9.5 + 15 = 119.5", quote.Order.TotalPaid)
+	}
+	if len(quote.Breakdown.Lines) != 1 || len(quote.Breakdown.Adjustments) != 3 {
+		t.Errorf("breakdown = %+v, want one line and three adjustments", quote.Breakdown)
+	}
+	if len(quote.Lines) != 1 || len(quote.Payments) != 1 {
+		t.Errorf("quote has %d lines and %d payments, want 1 and 1", len(quote.Lines), len(quote.Payments))
+	}
+
+	stored, _ := store.Orders().OpenCart(1)
+	if stored.TotalPaid == quote.Order.TotalPaid {
+		t.Error("Quote saved the priced order")
+	}
+}
+
+func TestCheckoutEmptyCart(t *testing.T) {
+
+	store := newShopStore()
+
+	if _, _, err := Orders(testConfig(), store).Checkout(1, checkoutInput()); !errors.Is(err, ErrCartEmpty) {
+		t.Fatalf("Checkout without a cart = %v, want ErrCartEmpty", err)
+	}
+}
+
+func TestCheckout(t *testing.T) {
+
+	store := newShopStore()
+	cart := fillCart(t, store,
+		CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2},
+		CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 1},
+	)
+	store.wishlists[[2]uint64{1, 11}] = true
+
+	order, breakdown, err := Orders(testConfig(), store).Checkout(1, checkoutInput())
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if order.Id != cart.Id || order.Status != models.OrderStatusPendingPayment {
+		t.Errorf("order %d is %s, want order %d pending payment", order.Id, order.StatusName(), cart.Id)
+	}
+	if order.Subtotal != money.FromInt(90) || order.TotalPaid != money.MustParse("109.05") {
+		t.Errorf("order subtotal %v and total %v, want 90 and 90 - 4.5 + 8.55 + 15", order.Subtotal, order.TotalPaid)
+	}
+	if breakdown.Total != order.TotalPaid || len(breakdown.Lines) != 2 {
+		t.Errorf("breakdown = %+v does not match the order", breakdown)
+	}
+
+	if stock := store.inventories[100].Stock; stock != 3 {
+		t.Errorf("inventory 100 stock = %d, want 3", stock)
+	}
+	if stock := store.inventories[110].Stock; stock != 0 {
+		t.Errorf("inventory 110 stock = %d, want 0", stock)
+	}
+	if ordered := store.products[10].TotalOrder; ordered != 2 {
+		t.Errorf("product 10 total_order = %d, want 2", ordered)
+	}
+
+	if store.wishlists[[2]uint64{1, 11}] {
+		t.Error("bought product is still on the wishlist")
+	}
+	if len(store.carts) != 0 {
+		t.Errorf("orders_carts still holds %d rows", len(store.carts))
+	}
+	if billings, _ := store.Orders().Billings(order.Id); len(billings) != 9 {
+		t.Errorf("got %d billing rows, want 9", len(billings))
+	}
+
+	histories, _ := store.Orders().Histories(order.Id)
+	if len(histories) != 1 || histories[0].ToStatus != models.OrderStatusPendingPayment || histories[0].ActorType != ActorCustomer {
+		t.Errorf("histories = %+v, want one customer move to pending payment", histories)
+	}
+
+	if len(store.outbox) != 1 || store.outbox[0].Template != mailer.TemplateOrderPlaced || store.outbox[0].To != "buyer@example.com" {
+		t.Errorf("outbox = %+v, want one order placed e-mail to the buyer", store.outbox)
+	}
+}
+
+func TestCheckoutOutOfStockChangesNothing(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store,
+		CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2},
+		CartItem{ProductId: 11, SizeId: 1, ColourId: 1, Qty: 3},
+	)
+
+	_, _, err := Orders(testConfig(), store).Checkout(1, checkoutInput())
+
+	var outOfStock *OutOfStockError
+	if !errors.As(err, &outOfStock) {
+		t.Fatalf("Checkout = %v, want an OutOfStockError", err)
+	}
+	if len(outOfStock.Lines) != 1 {
+		t.Fatalf("lines = %+v, want only the shoes", outOfStock.Lines)
+	}
+	line := outOfStock.Lines[0]
+	if line.InventoryId != 110 || line.Requested != 3 || line.Available != 1 || line.ProductName != "Shoes" {
+		t.Errorf("line = %+v", line)
+	}
+
+	if stock := store.inventories[100].Stock; stock != 5 {
+		t.Errorf("inventory 100 stock = %d, want it untouched at 5", stock)
+	}
+	if _, err := store.Orders().OpenCart(1); err != nil {
+		t.Errorf("the cart is gone after a failed checkout: %v", err)
+	}
+	if len(store.outbox) != 0 {
+		t.Error("a failed checkout queued an e-mail")
+	}
+}
+
+func TestCancelReturnsStock(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+
+	orders := Orders(testConfig(), store)
+	order, _, err := orders.Checkout(1, checkoutInput())
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if err := orders.Cancel(1, order.Id); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	if status := store.orders[order.Id].Status; status != models.OrderStatusCancelled {
+		t.Errorf("status = %d, want cancelled", status)
+	}
+	if stock := store.inventories[100].Stock; stock != 5 {
+		t.Errorf("stock = %d, want it back at 5", stock)
+	}
+	if ordered := store.products[10].TotalOrder; ordered != 0 {
+		t.Errorf("total_order = %d, want 0", ordered)
+	}
+
+	if err := orders.Cancel(2, order.Id); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Cancel of another user's order = %v, want ErrNotFound", err)
+	}
+
+	var invalid *InvalidTransitionError
+	if err := orders.Cancel(1, order.Id); !errors.As(err, &invalid) {
+		t.Errorf("second Cancel = %v, want an InvalidTransitionError", err)
+	}
+	if err := orders.Cancel(1, 424242); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Cancel of a missing order = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCancelRefusesPaidOrders(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+
+	orders := Orders(testConfig(), store)
+	order, _, err := orders.Checkout(1, checkoutInput())
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	for _, status := range []uint8{models.OrderStatusPaid, models.OrderStatusProcessing} {
+		paid := store.orders[order.Id]
+		paid.Status = status
+		store.orders[order.Id] = paid
+
+		if err := orders.Cancel(1, order.Id); !errors.Is(err, ErrOrderNotCancellable) {
+			t.Errorf("Cancel of a %s order = %v, want ErrOrderNotCancellable", models.OrderStatusNames[status], err)
+		}
+		if got := store.orders[order.Id].Status; got != status {
+			t.Errorf("status = %d, want it left at %d", got, status)
+		}
+	}
+	if stock := store.inventories[100].Stock; stock != 3 {
+		t.Errorf("stock = %d, want the two ordered still held", stock)
+	}
+}
+
+func TestStaffMarkPaidConfirmsManualPayment(t *testing.T) {
+
+	for _, started := range []bool{true, false} {
+
+		store := newShopStore()
+		store.payments[0].Provider = payment.ProviderManual
+		fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 2})
+
+		orders := Orders(testConfig(), store)
+		order, _, err := orders.Checkout(1, checkoutInput())
+		if err != nil {
+			t.Fatalf("Checkout: %v", err)
+		}
+		if started {
+			if _, err := Payments(testConfig(), store).Start(1, order.Id); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+		}
+
+		if _, err := orders.UpdateStatus(order.Id, models.OrderStatusPaid, 2, ActorStaff, "Transfer received"); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+
+		transactions, _ := store.Payments().Transactions(order.Id)
+		if len(transactions) != 1 || transactions[0].Status != payment.StatusSucceeded || transactions[0].Amount != order.TotalPaid {
+			t.Errorf("started %v: transactions = %+v, want one succeeded for %v", started, transactions, order.TotalPaid)
+		}
+	}
+}
+
+func TestDetail(t *testing.T) {
+
+	store := newShopStore()
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 5})
+
+	order, _, err := Orders(testConfig(), store).Checkout(1, checkoutInput())
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	view, err := Orders(testConfig(), store).Detail(1, order.Id)
+	if err != nil {
+		t.Fatalf("Detail: %v", err)
+	}
+	if _, err := Orders(testConfig(), store).Detail(2, order.Id); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Detail of another user's order = %v, want ErrNotFound", err)
+	}
+	if view.Discount != money.MustParseRate("5") || view.Taxes != money.MustParseRate("10") {
+		t.Errorf("discount %v%% and taxes %v%%, want 5%% and 10%%", view.Discount, view.Taxes)
+	}
+	if view.Payment.Id != 7 || len(view.Lines) != 1 || len(view.Histories) != 1 {
+		t.Errorf("view = %+v", view)
+	}
+
+	store.orders[999] = models.Order{Id: 999, UserId: 1}
+	view, err = Orders(testConfig(), store).Detail(1, 999)
+	if err != nil {
+		t.Fatalf("Detail of an empty order: %v", err)
+	}
+	if view.Discount != 0 || view.Taxes != 0 {
+		t.Errorf("empty order rates = %v and %v, want 0", view.Discount, view.Taxes)
+	}
+}
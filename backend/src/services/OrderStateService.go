@@ -0,0 +1,230 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	money "backend/src/money"
+	payment "backend/src/payment"
+	repositories "backend/src/repositories"
+	"errors"
+	"fmt"
+)
+
+const (
+	ActorCustomer = "customer"
+	ActorStaff    = "staff"
+	ActorSystem   = "system"
+)
+
+type InvalidTransitionError struct {
+	From uint8
+	To   uint8
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("an order cannot move from %s to %s", models.OrderStatusNames[e.From], models.OrderStatusNames[e.To])
+}
+
+// order state service
+type OrderStateService interface {
+	CanTransition(from uint8, to uint8) bool
+	Transition(tx repositories.Store, order *models.Order, to uint8, actorId uint64, actorType string, reason string) error
+}
+
+type orderStateServices struct {
+	config *appconfig.Config
+}
+
+func OrderStateMachine(config *appconfig.Config) OrderStateService {
+	return &orderStateServices{config: config}
+}
+
+func (service *orderStateServices) CanTransition(from uint8, to uint8) bool {
+	for _, next := range models.OrderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves order to the given status inside tx, applies the side
+// effects of that move and appends a row to order_status_history. The
+// caller owns tx and decides whether to commit.
+func (service *orderStateServices) Transition(tx repositories.Store, order *models.Order, to uint8, actorId uint64, actorType string, reason string) error {
+
+	from := order.Status
+	if !service.CanTransition(from, to) {
+		return &InvalidTransitionError{From: from, To: to}
+	}
+
+	// Staff may cancel an order that has already been paid for; everything
+	// it collected is paid back as part of the same move.
+	if to == models.OrderStatusCancelled && (from == models.OrderStatusPaid || from == models.OrderStatusProcessing) {
+		if err := bookRefund(tx, order, order.TotalPaid-order.TotalRefunded); err != nil {
+			return err
+		}
+	}
+
+	// Staff mark an order paid once an offline payment has come in.
+	if to == models.OrderStatusPaid && actorType == ActorStaff {
+		if err := confirmManualPayment(tx, order); err != nil {
+			return err
+		}
+	}
+
+	if to == models.OrderStatusCancelled && order.HoldsStock() {
+		if err := restockOrder(tx, order); err != nil {
+			return err
+		}
+	}
+
+	if to == models.OrderStatusCancelled {
+		if err := tx.Orders().DetachProducts(order.Id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Orders().SetStatus(order, to); err != nil {
+		return err
+	}
+	order.Status = to
+
+	if to == models.OrderStatusShipped {
+		if err := service.notifyShipped(tx, order); err != nil {
+			return err
+		}
+	}
+
+	history := models.OrderStatusHistory{
+		OrderId:    order.Id,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorId:    actorId,
+		ActorType:  actorType,
+		Reason:     reason,
+	}
+	return tx.Orders().AddHistory(&history)
+}
+
+// bookRefund records refund as paid back on the order and on the payment
+// transaction that collected it, if there is one.
+func bookRefund(tx repositories.Store, order *models.Order, refund money.Money) error {
+
+	if refund <= 0 {
+		return nil
+	}
+
+	order.TotalRefunded += refund
+	if err := tx.Orders().Save(order); err != nil {
+		return err
+	}
+
+	transaction, err := tx.Payments().PaidTransaction(order.Id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	transaction.Refunded += refund
+	return tx.Payments().SaveTransaction(&transaction)
+}
+
+// confirmManualPayment marks the order's pending offline payment attempt
+// as succeeded, recording one when the customer never started it, so that
+// refunds have a paid transaction to be booked against. Attempts with
+// other providers are left for their webhooks to settle.
+func confirmManualPayment(tx repositories.Store, order *models.Order) error {
+
+	transaction, err := tx.Payments().PendingTransaction(order.Id)
+	if err == nil {
+		if transaction.Provider != payment.ProviderManual {
+			return nil
+		}
+		transaction.Status = payment.StatusSucceeded
+		return tx.Payments().SaveTransaction(&transaction)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+
+	method, err := tx.Orders().Payment(order.PaymentId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if method.Provider != payment.ProviderManual {
+		return nil
+	}
+
+	currency := money.Default()
+	transaction = models.PaymentTransaction{
+		OrderId:   order.Id,
+		PaymentId: method.Id,
+		Provider:  payment.ProviderManual,
+		Reference: "manual-" + order.InvoiceNumber,
+		Amount:    order.TotalPaid.Round(currency),
+		Currency:  string(currency),
+		Status:    payment.StatusSucceeded,
+	}
+	return tx.Payments().CreateTransaction(&transaction)
+}
+
+func restockOrder(tx repositories.Store, order *models.Order) error {
+
+	details, err := tx.Orders().Details(order.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, detail := range details {
+
+		inventory, err := tx.Catalog().LockInventory(detail.InventoryId)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if err := tx.Catalog().ReturnStock(inventory.Id, detail.Qty); err != nil {
+			return err
+		}
+
+		if err := tx.Catalog().RemoveOrdered(inventory.ProductId, detail.Qty); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (service *orderStateServices) notifyShipped(tx repositories.Store, order *models.Order) error {
+
+	user, err := tx.Users().Find(order.UserId)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return tx.Outbox().Enqueue(user.Email, mailer.TemplateOrderShipped, map[string]interface{}{
+		"Name":          user.FirstName.String,
+		"InvoiceNumber": order.InvoiceNumber,
+		"Link":          mailer.Link(service.config, fmt.Sprintf("order/detail/%d", order.Id)),
+	})
+}
@@ -0,0 +1,110 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	helpers "backend/src/helpers"
+	"crypto/subtle"
+	"strings"
+	"sync"
+)
+
+// password service
+type PasswordService interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string, legacyKey string) (valid bool, rehash bool)
+}
+
+type passwordServices struct {
+	hasher PasswordHasher
+}
+
+var (
+	passwordHashersMu sync.RWMutex
+	passwordHashers   = map[string]PasswordHasher{}
+)
+
+func init() {
+	RegisterPasswordHasher(Argon2idHasher())
+	RegisterPasswordHasher(BcryptHasher())
+}
+
+// RegisterPasswordHasher makes a hasher available for verification and,
+// when named in the PASSWORD_HASHER setting, for hashing new passwords.
+func RegisterPasswordHasher(hasher PasswordHasher) {
+	passwordHashersMu.Lock()
+	defer passwordHashersMu.Unlock()
+	passwordHashers[hasher.Name()] = hasher
+}
+
+func PasswordHashService(config *appconfig.Config) PasswordService {
+	return &passwordServices{
+		hasher: getPasswordHasher(config.Auth.PasswordHasher),
+	}
+}
+
+func getPasswordHasher(name string) PasswordHasher {
+	passwordHashersMu.RLock()
+	defer passwordHashersMu.RUnlock()
+	if hasher, ok := passwordHashers[name]; ok {
+		return hasher
+	}
+	return passwordHashers["argon2id"]
+}
+
+func (service *passwordServices) Hash(password string) (string, error) {
+	return service.hasher.Hash(password)
+}
+
+// Verify checks password against the stored hash. Rows written before the
+// hashing subsystem hold an AES ciphertext keyed by users.salt; those are
+// still accepted once and always reported as needing a rehash.
+func (service *passwordServices) Verify(password string, encoded string, legacyKey string) (bool, bool) {
+
+	if !strings.HasPrefix(encoded, "$") {
+		if len(legacyKey) == 0 {
+			return false, false
+		}
+		decrypted, ok := legacyDecrypt(encoded, legacyKey)
+		if !ok || subtle.ConstantTimeCompare([]byte(decrypted), []byte(password)) != 1 {
+			return false, false
+		}
+		return true, true
+	}
+
+	passwordHashersMu.RLock()
+	defer passwordHashersMu.RUnlock()
+
+	for _, hasher := range passwordHashers {
+		if !hasher.Supports(encoded) {
+			continue
+		}
+		valid, err := hasher.Verify(password, encoded)
+		if err != nil || !valid {
+			return false, false
+		}
+		rehash := hasher.Name() != service.hasher.Name() || hasher.NeedsRehash(encoded)
+		return true, rehash
+	}
+
+	return false, false
+}
+
+func legacyDecrypt(encrypted string, key string) (decrypted string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			decrypted, ok = "", false
+		}
+	}()
+	return helpers.Decrypt(encrypted, key), true
+}
@@ -0,0 +1,255 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	mailer "backend/src/mailer"
+	models "backend/src/models"
+	money "backend/src/money"
+	payment "backend/src/payment"
+	repositories "backend/src/repositories"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrOrderNotPayable = errors.New("the order is not awaiting payment")
+
+// payment service
+type PaymentService interface {
+	Start(userId uint64, orderId uint64) (models.PaymentTransaction, error)
+	Transactions(orderId uint64) ([]models.PaymentTransaction, error)
+	HandleWebhook(provider string, payload []byte, signature string) error
+}
+
+type paymentServices struct {
+	config    *appconfig.Config
+	store     repositories.Store
+	providers func(name string) (payment.Provider, error)
+}
+
+func Payments(config *appconfig.Config, store repositories.Store) PaymentService {
+	return &paymentServices{config: config, store: store, providers: func(name string) (payment.Provider, error) {
+		return payment.NewProvider(config, name)
+	}}
+}
+
+// Start asks the provider of the order's payment method to collect the
+// order total. An attempt that is still pending is returned as it is, so
+// posting twice does not charge twice; after a failed attempt the order
+// goes back to pending_payment with a new one. Every attempt is sent to
+// the provider with its own idempotency key, so requests racing to start
+// the same attempt get the same intent, and only the first one records it.
+func (service *paymentServices) Start(userId uint64, orderId uint64) (models.PaymentTransaction, error) {
+
+	var transaction models.PaymentTransaction
+
+	order, err := service.store.Orders().FindOwned(userId, orderId)
+	if err != nil {
+		return transaction, err
+	}
+	if order.Status != models.OrderStatusPendingPayment && order.Status != models.OrderStatusPaymentFailed {
+		return transaction, ErrOrderNotPayable
+	}
+
+	transaction, err = service.store.Payments().PendingTransaction(order.Id)
+	if err == nil || !errors.Is(err, repositories.ErrNotFound) {
+		return transaction, err
+	}
+
+	previous, err := service.store.Payments().Transactions(order.Id)
+	if err != nil {
+		return transaction, err
+	}
+	attempt := len(previous) + 1
+
+	method, err := service.store.Orders().Payment(order.PaymentId)
+	if err != nil {
+		return transaction, err
+	}
+	provider, err := service.providers(method.Provider)
+	if err != nil {
+		return transaction, err
+	}
+
+	user, err := service.store.Users().Find(userId)
+	if err != nil {
+		return transaction, err
+	}
+
+	// The provider is called before the transaction is opened so a slow
+	// gateway does not hold the order row locked.
+	currency := money.Default()
+	intent, err := provider.CreateIntent(payment.Request{
+		OrderId:        order.Id,
+		InvoiceNumber:  order.InvoiceNumber,
+		Amount:         order.TotalPaid.Round(currency),
+		Currency:       currency,
+		Email:          user.Email,
+		ReturnURL:      mailer.Link(service.config, fmt.Sprintf("order/detail/%d", order.Id)),
+		IdempotencyKey: fmt.Sprintf("order-%d-attempt-%d", order.Id, attempt),
+	})
+	if err != nil {
+		return transaction, err
+	}
+
+	err = service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := tx.Orders().LockOwned(userId, orderId)
+		if err != nil {
+			return err
+		}
+
+		// Another request recorded this attempt while the provider was
+		// being called. It sent the same key, so its intent is ours too.
+		recorded, err := tx.Payments().Transactions(order.Id)
+		if err != nil {
+			return err
+		}
+		if len(recorded) >= attempt {
+			transaction = recorded[0]
+			return nil
+		}
+
+		switch order.Status {
+		case models.OrderStatusPendingPayment:
+		case models.OrderStatusPaymentFailed:
+			if err := OrderStateMachine(service.config).Transition(tx, &order, models.OrderStatusPendingPayment, userId, ActorCustomer, "Payment retried"); err != nil {
+				return err
+			}
+		default:
+			return ErrOrderNotPayable
+		}
+
+		transaction = models.PaymentTransaction{
+			OrderId:   order.Id,
+			PaymentId: method.Id,
+			Provider:  provider.Name(),
+			Reference: intent.Reference,
+			Amount:    order.TotalPaid.Round(currency),
+			Currency:  string(currency),
+			Status:    payment.StatusPending,
+		}
+		if intent.RedirectURL != "" {
+			transaction.RedirectURL = sql.NullString{String: intent.RedirectURL, Valid: true}
+		}
+		if err := tx.Payments().CreateTransaction(&transaction); err != nil {
+			return err
+		}
+
+		if intent.Status == payment.StatusPending {
+			return nil
+		}
+		return service.settle(tx, &transaction, intent.Status, "")
+	})
+
+	return transaction, err
+}
+
+func (service *paymentServices) Transactions(orderId uint64) ([]models.PaymentTransaction, error) {
+	return service.store.Payments().Transactions(orderId)
+}
+
+// HandleWebhook applies a provider event to the attempt it is about. Every
+// event is recorded, and one that was already recorded is acknowledged
+// without being applied again, since providers deliver at least once.
+// Events about attempts that are no longer pending change nothing.
+func (service *paymentServices) HandleWebhook(name string, payload []byte, signature string) error {
+
+	provider, err := service.providers(name)
+	if err != nil {
+		return err
+	}
+
+	event, err := provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	return service.store.Transaction(func(tx repositories.Store) error {
+
+		// Locking the attempt first makes concurrent deliveries of the same
+		// event wait here, so only one of them gets past EventSeen.
+		transaction, err := tx.Payments().LockTransaction(provider.Name(), event.Reference)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+
+		seen, err := tx.Payments().EventSeen(provider.Name(), event.Id)
+		if err != nil || seen {
+			return err
+		}
+
+		if err := tx.Payments().CreateEvent(&models.PaymentEvent{
+			Provider:  provider.Name(),
+			EventId:   event.Id,
+			Type:      event.Type,
+			Reference: sql.NullString{String: event.Reference, Valid: event.Reference != ""},
+		}); err != nil {
+			return err
+		}
+
+		if transaction.Id == 0 || transaction.Status != payment.StatusPending {
+			return nil
+		}
+
+		switch event.Type {
+		case payment.EventSucceeded:
+			currency := money.Currency(transaction.Currency)
+			if event.Currency != currency || event.Amount.Round(currency) != transaction.Amount.Round(currency) {
+				return service.settle(tx, &transaction, payment.StatusFailed, fmt.Sprintf("Paid %s %s, expected %s %s", event.Currency, event.Amount.Round(currency), currency, transaction.Amount.Round(currency)))
+			}
+			return service.settle(tx, &transaction, payment.StatusSucceeded, "")
+		case payment.EventFailed:
+			return service.settle(tx, &transaction, payment.StatusFailed, event.Reason)
+		}
+
+		return nil
+	})
+}
+
+// settle records the outcome of an attempt and moves its order to paid or
+// payment_failed. An order that has moved on in the meantime, say because
+// the customer cancelled it, keeps its status for staff to sort out.
+func (service *paymentServices) settle(tx repositories.Store, transaction *models.PaymentTransaction, status string, reason string) error {
+
+	transaction.Status = status
+	if reason != "" {
+		if len(reason) > 255 {
+			reason = reason[:255]
+		}
+		transaction.Error = sql.NullString{String: reason, Valid: true}
+	}
+	if err := tx.Payments().SaveTransaction(transaction); err != nil {
+		return err
+	}
+
+	order, err := tx.Orders().Lock(transaction.OrderId)
+	if err != nil {
+		return err
+	}
+
+	to, note := models.OrderStatusPaid, "Payment received"
+	if status == payment.StatusFailed {
+		to, note = models.OrderStatusPaymentFailed, "Payment failed"
+		if reason != "" {
+			note += ": " + reason
+		}
+	}
+
+	machine := OrderStateMachine(service.config)
+	if !machine.CanTransition(order.Status, to) {
+		return nil
+	}
+	return machine.Transition(tx, &order, to, 0, ActorSystem, note)
+}
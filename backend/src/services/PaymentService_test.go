@@ -0,0 +1,288 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	payment "backend/src/payment"
+	repositories "backend/src/repositories"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeProvider hands out numbered intents, the same one again for a
+// repeated idempotency key, and accepts any webhook whose signature is
+// "ok", returning the event queued in next. When set, during runs inside
+// CreateIntent, standing in for a request racing this one.
+type fakeProvider struct {
+	intents int
+	keys    map[string]payment.Intent
+	next    payment.Event
+	during  func()
+}
+
+func (p *fakeProvider) Name() string {
+	return payment.ProviderGateway
+}
+
+func (p *fakeProvider) CreateIntent(request payment.Request) (payment.Intent, error) {
+
+	if during := p.during; during != nil {
+		p.during = nil
+		during()
+	}
+
+	if intent, ok := p.keys[request.IdempotencyKey]; ok {
+		return intent, nil
+	}
+
+	p.intents++
+	intent := payment.Intent{
+		Reference:   fmt.Sprintf("pi_%d", p.intents),
+		Status:      payment.StatusPending,
+		RedirectURL: "https://pay.test/" + request.InvoiceNumber,
+	}
+	if p.keys == nil {
+		p.keys = map[string]payment.Intent{}
+	}
+	p.keys[request.IdempotencyKey] = intent
+	return intent, nil
+}
+
+func (p *fakeProvider) ParseWebhook(payload []byte, signature string) (payment.Event, error) {
+	if signature != "ok" {
+		return payment.Event{}, payment.ErrInvalidSignature
+	}
+	return p.next, nil
+}
+
+// newPaymentStore adds an order awaiting payment through the gateway to
+// the shop store.
+func newPaymentStore() fakeStore {
+	store := newShopStore()
+	store.payments = append(store.payments, models.Payment{Id: 8, Name: "Card", Provider: payment.ProviderGateway, Status: 1})
+	store.orders[50] = models.Order{Id: 50, UserId: 1, PaymentId: 8, InvoiceNumber: "INV-0050", TotalPaid: money.MustParse("119.5"), Status: models.OrderStatusPendingPayment}
+	return store
+}
+
+func newTestPayments(store fakeStore, provider *fakeProvider) PaymentService {
+	return &paymentServices{config: testConfig(), store: store, providers: func(name string) (payment.Provider, error) {
+		if name != provider.Name() {
+			return nil, payment.ErrUnknownProvider
+		}
+		return provider, nil
+	}}
+}
+
+func TestPaymentStart(t *testing.T) {
+
+	store := newPaymentStore()
+	provider := &fakeProvider{}
+	payments := newTestPayments(store, provider)
+
+	transaction, err := payments.Start(1, 50)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if transaction.Reference != "pi_1" || transaction.Status != payment.StatusPending || transaction.Amount != money.MustParse("119.5") || transaction.RedirectURL.String != "https://pay.test/INV-0050" {
+		t.Errorf("transaction = %+v", transaction)
+	}
+
+	again, err := payments.Start(1, 50)
+	if err != nil || again.Id != transaction.Id || provider.intents != 1 {
+		t.Errorf("second Start = %+v, %v after %d intents, want the pending attempt again", again, err, provider.intents)
+	}
+}
+
+func TestPaymentStartRace(t *testing.T) {
+
+	store := newPaymentStore()
+	provider := &fakeProvider{}
+	payments := newTestPayments(store, provider)
+
+	var raced models.PaymentTransaction
+	provider.during = func() {
+		var err error
+		if raced, err = payments.Start(1, 50); err != nil {
+			t.Errorf("racing Start: %v", err)
+		}
+	}
+
+	transaction, err := payments.Start(1, 50)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if transaction.Id != raced.Id || transaction.Reference != "pi_1" || provider.intents != 1 {
+		t.Errorf("Start = %+v after %d intents, want the racing attempt %+v", transaction, provider.intents, raced)
+	}
+	if transactions, _ := store.Payments().Transactions(50); len(transactions) != 1 {
+		t.Errorf("%d attempts recorded, want 1", len(transactions))
+	}
+}
+
+func TestPaymentStartRefused(t *testing.T) {
+
+	store := newPaymentStore()
+	payments := newTestPayments(store, &fakeProvider{})
+
+	if _, err := payments.Start(2, 50); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Start for another user = %v, want ErrNotFound", err)
+	}
+
+	order := store.orders[50]
+	order.Status = models.OrderStatusPaid
+	store.orders[50] = order
+
+	if _, err := payments.Start(1, 50); !errors.Is(err, ErrOrderNotPayable) {
+		t.Errorf("Start for a paid order = %v, want ErrOrderNotPayable", err)
+	}
+}
+
+func TestPaymentWebhook(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		event  payment.Event
+		status uint8
+		result string
+		reason string
+	}{
+		{
+			name:   "succeeded",
+			event:  payment.Event{Id: "evt_1", Type: payment.EventSucceeded, Reference: "pi_1", Amount: money.MustParse("119.5"), Currency: "USD"},
+			status: models.OrderStatusPaid,
+			result: payment.StatusSucceeded,
+		},
+		{
+			name:   "failed",
+			event:  payment.Event{Id: "evt_1", Type: payment.EventFailed, Reference: "pi_1", Reason: "card declined"},
+			status: models.OrderStatusPaymentFailed,
+			result: payment.StatusFailed,
+			reason: "card declined",
+		},
+		{
+			name:   "short payment",
+			event:  payment.Event{Id: "evt_1", Type: payment.EventSucceeded, Reference: "pi_1", Amount: money.FromInt(1), Currency: "USD"},
+			status: models.OrderStatusPaymentFailed,
+			result: payment.StatusFailed,
+			reason: "Paid USD 1.00, expected USD 119.50",
+		},
+		{
+			name:   "other event",
+			event:  payment.Event{Id: "evt_1", Type: "payment_intent.created", Reference: "pi_1"},
+			status: models.OrderStatusPendingPayment,
+			result: payment.StatusPending,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			store := newPaymentStore()
+			provider := &fakeProvider{}
+			payments := newTestPayments(store, provider)
+
+			transaction, err := payments.Start(1, 50)
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+
+			provider.next = test.event
+			if err := payments.HandleWebhook(payment.ProviderGateway, []byte("{}"), "ok"); err != nil {
+				t.Fatalf("HandleWebhook: %v", err)
+			}
+
+			if status := store.orders[50].Status; status != test.status {
+				t.Errorf("order status = %s, want %s", models.OrderStatusNames[status], models.OrderStatusNames[test.status])
+			}
+			stored := store.attempts[transaction.Id]
+			if stored.Status != test.result || stored.Error.String != test.reason {
+				t.Errorf("transaction = %s %q, want %s %q", stored.Status, stored.Error.String, test.result, test.reason)
+			}
+			if len(store.events) != 1 {
+				t.Errorf("%d events recorded, want 1", len(store.events))
+			}
+		})
+	}
+}
+
+func TestPaymentWebhookIsIdempotent(t *testing.T) {
+
+	store := newPaymentStore()
+	provider := &fakeProvider{}
+	payments := newTestPayments(store, provider)
+
+	if _, err := payments.Start(1, 50); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	provider.next = payment.Event{Id: "evt_1", Type: payment.EventSucceeded, Reference: "pi_1", Amount: money.MustParse("119.5"), Currency: "USD"}
+	for i := 0; i < 2; i++ {
+		if err := payments.HandleWebhook(payment.ProviderGateway, []byte("{}"), "ok"); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+	}
+
+	// A late failure for the same attempt must not undo the payment.
+	provider.next = payment.Event{Id: "evt_2", Type: payment.EventFailed, Reference: "pi_1"}
+	if err := payments.HandleWebhook(payment.ProviderGateway, []byte("{}"), "ok"); err != nil {
+		t.Fatalf("late failure: %v", err)
+	}
+
+	if status := store.orders[50].Status; status != models.OrderStatusPaid {
+		t.Errorf("order status = %s, want paid", models.OrderStatusNames[status])
+	}
+	if len(store.histories) != 1 || len(store.events) != 2 {
+		t.Errorf("%d histories and %d events, want 1 and 2", len(store.histories), len(store.events))
+	}
+}
+
+func TestPaymentWebhookRefused(t *testing.T) {
+
+	store := newPaymentStore()
+	payments := newTestPayments(store, &fakeProvider{})
+
+	if err := payments.HandleWebhook(payment.ProviderGateway, []byte("{}"), "forged"); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Errorf("forged webhook = %v, want ErrInvalidSignature", err)
+	}
+	if err := payments.HandleWebhook("unknown", []byte("{}"), "ok"); !errors.Is(err, payment.ErrUnknownProvider) {
+		t.Errorf("unknown provider = %v, want ErrUnknownProvider", err)
+	}
+	if len(store.events) != 0 {
+		t.Errorf("%d events recorded, want none", len(store.events))
+	}
+}
+
+func TestPaymentRetryAfterFailure(t *testing.T) {
+
+	store := newPaymentStore()
+	provider := &fakeProvider{}
+	payments := newTestPayments(store, provider)
+
+	if _, err := payments.Start(1, 50); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	provider.next = payment.Event{Id: "evt_1", Type: payment.EventFailed, Reference: "pi_1"}
+	if err := payments.HandleWebhook(payment.ProviderGateway, []byte("{}"), "ok"); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+
+	retry, err := payments.Start(1, 50)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if retry.Reference != "pi_2" || store.orders[50].Status != models.OrderStatusPendingPayment {
+		t.Errorf("retry = %s with the order %s, want pi_2 and pending_payment", retry.Reference, store.orders[50].StatusName())
+	}
+}
@@ -0,0 +1,406 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PriceAddress is the part of a billing address tax rates are chosen by.
+type PriceAddress struct {
+	Country string
+	ZipCode string
+}
+
+// PriceAdjustment is one discount, tax or shipment charge in a breakdown.
+// Discounts carry a negative Amount.
+type PriceAdjustment struct {
+	Kind   string      `json:"kind"`
+	Label  string      `json:"label"`
+	RuleId uint64      `json:"rule_id,omitempty"`
+	Rate   money.Rate  `json:"rate,omitempty"`
+	Amount money.Money `json:"amount"`
+}
+
+type PricedLine struct {
+	DetailId    uint64            `json:"detail_id"`
+	ProductId   uint64            `json:"product_id"`
+	Name        string            `json:"name"`
+	Price       money.Money       `json:"price"`
+	Qty         uint16            `json:"qty"`
+	Subtotal    money.Money       `json:"subtotal"`
+	Discount    money.Money       `json:"discount"`
+	Total       money.Money       `json:"total"`
+	Adjustments []PriceAdjustment `json:"adjustments"`
+}
+
+// PriceBreakdown is an itemized price for an order: every line with its
+// own discounts, then the order discounts, coupon, shipment and tax, all
+// in Currency. Discount includes the coupon. When the coupon applied to
+// the order can no longer be used, Coupon still names it and CouponError
+// says why it was left out.
+type PriceBreakdown struct {
+	Currency       money.Currency    `json:"currency"`
+	Lines          []PricedLine      `json:"lines"`
+	Adjustments    []PriceAdjustment `json:"adjustments"`
+	Subtotal       money.Money       `json:"subtotal"`
+	Discount       money.Money       `json:"discount"`
+	Coupon         string            `json:"coupon,omitempty"`
+	CouponDiscount money.Money       `json:"coupon_discount"`
+	CouponError    string            `json:"coupon_error,omitempty"`
+	TaxRate        money.Rate        `json:"tax_rate"`
+	Taxes          money.Money       `json:"taxes"`
+	Shipment       money.Money       `json:"shipment"`
+	Total          money.Money       `json:"total"`
+}
+
+// DiscountRate is the discount as a percentage of the subtotal.
+func (breakdown PriceBreakdown) DiscountRate() money.Rate {
+	return money.RateOf(breakdown.Discount, breakdown.Subtotal)
+}
+
+// Apply copies the totals onto order.
+func (breakdown PriceBreakdown) Apply(order *models.Order) {
+	order.Subtotal = breakdown.Subtotal
+	order.TotalDiscount = breakdown.Discount
+	order.TotalTaxes = breakdown.Taxes
+	order.TotalShipment = breakdown.Shipment
+	order.TotalPaid = breakdown.Total
+}
+
+// pricing service
+type PricingService interface {
+	PriceOrder(order models.Order, address PriceAddress) (PriceBreakdown, error)
+	Price(items []repositories.PriceItem, address PriceAddress) (PriceBreakdown, error)
+}
+
+type pricingServices struct {
+	store repositories.Store
+	now   func() time.Time
+}
+
+func Pricing(store repositories.Store) PricingService {
+	return &pricingServices{store: store, now: time.Now}
+}
+
+// PriceOrder prices the current lines of an order together with the
+// coupon applied to it, if the order still qualifies for the coupon.
+func (service *pricingServices) PriceOrder(order models.Order, address PriceAddress) (PriceBreakdown, error) {
+
+	items, err := service.store.Pricing().Items(order.Id)
+	if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	applied, err := service.store.Coupons().ForOrder(order.Id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return service.price(items, nil, address)
+	} else if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	coupon, err := service.store.Coupons().Find(applied.CouponId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		err = &CouponError{Code: applied.Code, Reason: "This coupon code is not valid."}
+	} else if err == nil {
+		err = checkCoupon(service.store, coupon, order, items, service.now())
+	}
+
+	var invalid *CouponError
+	if errors.As(err, &invalid) {
+		breakdown, err := service.price(items, nil, address)
+		breakdown.Coupon, breakdown.CouponError = invalid.Code, invalid.Reason
+		return breakdown, err
+	} else if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	return service.price(items, &coupon, address)
+}
+
+// Price applies the rules active right now and the tax rate of address.
+func (service *pricingServices) Price(items []repositories.PriceItem, address PriceAddress) (PriceBreakdown, error) {
+	return service.price(items, nil, address)
+}
+
+func (service *pricingServices) price(items []repositories.PriceItem, coupon *models.Coupon, address PriceAddress) (PriceBreakdown, error) {
+
+	rules, err := service.store.Pricing().Rules()
+	if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	rates, err := service.store.Pricing().TaxRates()
+	if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	return priceItems(items, rules, rates, coupon, address, money.Default(), service.now()), nil
+}
+
+// LoadCurrency makes the com_currency setting the currency amounts are
+// rounded and formatted in. Without the setting the default is kept.
+func LoadCurrency(store repositories.Store) error {
+	code, err := store.Settings().Get("com_currency")
+	if errors.Is(err, repositories.ErrNotFound) || err == nil && strings.TrimSpace(code) == "" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return money.SetDefault(code)
+}
+
+// priceItems is the pricing engine. Discounts are applied in rule order,
+// line rules before order rules, then the coupon, and never take a line or
+// the order below zero. Shipment comes from the first shipment rule the order qualifies
+// for. Tax is charged on the discounted subtotal, not on shipment. Every
+// percentage is rounded to the minor unit of currency as it is taken, so
+// the lines and adjustments always add up to the totals.
+func priceItems(items []repositories.PriceItem, rules []models.PriceRule, rates []models.TaxRate, coupon *models.Coupon, address PriceAddress, currency money.Currency, at time.Time) PriceBreakdown {
+
+	breakdown := PriceBreakdown{
+		Currency:    currency,
+		Lines:       make([]PricedLine, 0, len(items)),
+		Adjustments: []PriceAdjustment{},
+	}
+
+	var active []models.PriceRule
+	for _, rule := range rules {
+		if rule.ActiveAt(at) {
+			active = append(active, rule)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority < active[j].Priority
+		}
+		return active[i].Id < active[j].Id
+	})
+
+	for _, item := range items {
+		subtotal := item.Price.Times(int64(item.Qty))
+		breakdown.Subtotal += subtotal
+		breakdown.Lines = append(breakdown.Lines, PricedLine{
+			DetailId:    item.DetailId,
+			ProductId:   item.ProductId,
+			Name:        item.Name,
+			Price:       item.Price,
+			Qty:         item.Qty,
+			Subtotal:    subtotal,
+			Total:       subtotal,
+			Adjustments: []PriceAdjustment{},
+		})
+	}
+
+	if len(items) == 0 {
+		return breakdown
+	}
+
+	for _, rule := range active {
+		if rule.Kind != models.PriceRuleDiscount || rule.Scope == models.PriceScopeOrder || rule.MinSubtotal > breakdown.Subtotal {
+			continue
+		}
+		for i, item := range items {
+			if !ruleMatches(rule, item) {
+				continue
+			}
+			line := &breakdown.Lines[i]
+			amount := rule.Value.Times(int64(item.Qty))
+			if rule.Calculation == models.PriceCalculationPercent {
+				amount = line.Subtotal.Percent(money.Rate(rule.Value)).Round(currency)
+			}
+			amount = clamp(amount, line.Total)
+			if amount == 0 {
+				continue
+			}
+			line.Discount += amount
+			line.Total -= amount
+			line.Adjustments = append(line.Adjustments, discountAdjustment(rule, amount))
+			breakdown.Discount += amount
+		}
+	}
+
+	net := breakdown.Subtotal - breakdown.Discount
+	for _, rule := range active {
+		if rule.Kind != models.PriceRuleDiscount || rule.Scope != models.PriceScopeOrder || rule.MinSubtotal > breakdown.Subtotal {
+			continue
+		}
+		amount := rule.Value
+		if rule.Calculation == models.PriceCalculationPercent {
+			amount = (breakdown.Subtotal - lineDiscounts(breakdown)).Percent(money.Rate(rule.Value)).Round(currency)
+		}
+		amount = clamp(amount, net)
+		if amount == 0 {
+			continue
+		}
+		net -= amount
+		breakdown.Discount += amount
+		breakdown.Adjustments = append(breakdown.Adjustments, discountAdjustment(rule, amount))
+	}
+
+	if coupon != nil {
+		// A coupon with targets is taken off the lines it applies to,
+		// one without off the whole order.
+		base := net
+		if len(coupon.Targets) > 0 {
+			base = 0
+			for i, item := range items {
+				if couponApplies(*coupon, item) {
+					base += breakdown.Lines[i].Total
+				}
+			}
+		}
+		amount := coupon.Value
+		if coupon.Calculation == models.PriceCalculationPercent {
+			amount = base.Percent(money.Rate(coupon.Value)).Round(currency)
+		}
+		amount = clamp(clamp(amount, base), net)
+		breakdown.Coupon = coupon.Code
+		if amount > 0 {
+			net -= amount
+			breakdown.Discount += amount
+			breakdown.CouponDiscount = amount
+			adjustment := PriceAdjustment{Kind: "coupon", Label: coupon.Code, Amount: -amount}
+			if coupon.Calculation == models.PriceCalculationPercent {
+				adjustment.Rate = money.Rate(coupon.Value)
+			}
+			breakdown.Adjustments = append(breakdown.Adjustments, adjustment)
+		}
+	}
+
+	for _, rule := range active {
+		if rule.Kind != models.PriceRuleShipment || rule.MinSubtotal > breakdown.Subtotal {
+			continue
+		}
+		breakdown.Shipment = rule.Value
+		if rule.Calculation == models.PriceCalculationPercent {
+			breakdown.Shipment = net.Percent(money.Rate(rule.Value)).Round(currency)
+		}
+		adjustment := PriceAdjustment{Kind: models.PriceRuleShipment, Label: rule.Name, RuleId: rule.Id, Amount: breakdown.Shipment}
+		if rule.Calculation == models.PriceCalculationPercent {
+			adjustment.Rate = money.Rate(rule.Value)
+		}
+		breakdown.Adjustments = append(breakdown.Adjustments, adjustment)
+		break
+	}
+
+	if rate, ok := taxRateFor(rates, address); ok {
+		breakdown.TaxRate = rate.Rate
+		breakdown.Taxes = net.Percent(rate.Rate).Round(currency)
+		breakdown.Adjustments = append(breakdown.Adjustments, PriceAdjustment{
+			Kind:   "tax",
+			Label:  rate.Name,
+			Rate:   rate.Rate,
+			Amount: breakdown.Taxes,
+		})
+	}
+
+	breakdown.Total = net + breakdown.Taxes + breakdown.Shipment
+	return breakdown
+}
+
+func ruleMatches(rule models.PriceRule, item repositories.PriceItem) bool {
+	return rule.TargetId != nil && targetMatches(rule.Scope, *rule.TargetId, item)
+}
+
+// couponApplies reports whether a coupon discounts item: every item when
+// the coupon has no targets, otherwise the items any target matches.
+func couponApplies(coupon models.Coupon, item repositories.PriceItem) bool {
+	if len(coupon.Targets) == 0 {
+		return true
+	}
+	for _, target := range coupon.Targets {
+		if targetMatches(target.Scope, target.TargetId, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func targetMatches(scope string, target uint64, item repositories.PriceItem) bool {
+	switch scope {
+	case models.PriceScopeProduct:
+		return item.ProductId == target
+	case models.PriceScopeBrand:
+		return item.BrandId == target
+	case models.PriceScopeCategory:
+		for _, id := range item.CategoryIds {
+			if id == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taxRateFor picks the most specific enabled rate for address: a country
+// match outweighs any zip prefix, and a longer prefix beats a shorter one.
+func taxRateFor(rates []models.TaxRate, address PriceAddress) (models.TaxRate, bool) {
+
+	country := strings.TrimSpace(address.Country)
+	zipCode := strings.ReplaceAll(strings.TrimSpace(address.ZipCode), " ", "")
+
+	var best models.TaxRate
+	bestScore := -1
+	for _, rate := range rates {
+		if rate.Status != 1 {
+			continue
+		}
+		if rate.Country != "" && !strings.EqualFold(rate.Country, country) {
+			continue
+		}
+		if rate.ZipPrefix != "" && !strings.HasPrefix(strings.ToUpper(zipCode), strings.ToUpper(rate.ZipPrefix)) {
+			continue
+		}
+		score := len(rate.ZipPrefix)
+		if rate.Country != "" {
+			score += 1000
+		}
+		if score > bestScore {
+			best, bestScore = rate, score
+		}
+	}
+
+	return best, bestScore >= 0
+}
+
+func discountAdjustment(rule models.PriceRule, amount money.Money) PriceAdjustment {
+	adjustment := PriceAdjustment{Kind: models.PriceRuleDiscount, Label: rule.Name, RuleId: rule.Id, Amount: -amount}
+	if rule.Calculation == models.PriceCalculationPercent {
+		adjustment.Rate = money.Rate(rule.Value)
+	}
+	return adjustment
+}
+
+func lineDiscounts(breakdown PriceBreakdown) money.Money {
+	var total money.Money
+	for _, line := range breakdown.Lines {
+		total += line.Discount
+	}
+	return total
+}
+
+func clamp(amount money.Money, limit money.Money) money.Money {
+	if amount < 0 {
+		return 0
+	}
+	if amount > limit {
+		return limit
+	}
+	return amount
+}
@@ -0,0 +1,327 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	"testing"
+	"time"
+)
+
+func ruleTarget(id uint64) *uint64 {
+	return &id
+}
+
+func TestPriceItems(t *testing.T) {
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	yesterday, tomorrow := now.Add(-24*time.Hour), now.Add(24*time.Hour)
+
+	// A 100 shirt of brand 1 in category 7, and two 25 hats of brand 2.
+	items := []repositories.PriceItem{
+		{DetailId: 1, ProductId: 10, BrandId: 1, CategoryIds: []uint64{7}, Name: "Shirt", Price: money.FromInt(100), Qty: 1},
+		{DetailId: 2, ProductId: 11, BrandId: 2, Name: "Hat", Price: money.FromInt(25), Qty: 2},
+	}
+
+	percent := func(id uint64, scope string, target *uint64, value int64) models.PriceRule {
+		return models.PriceRule{Id: id, Name: "Sale", Kind: models.PriceRuleDiscount, Scope: scope, TargetId: target, Calculation: models.PriceCalculationPercent, Value: money.FromInt(value), Status: 1}
+	}
+	fixed := func(id uint64, scope string, target *uint64, value int64) models.PriceRule {
+		rule := percent(id, scope, target, value)
+		rule.Calculation = models.PriceCalculationFixed
+		return rule
+	}
+	shipment := func(id uint64, value int64, minSubtotal int64) models.PriceRule {
+		return models.PriceRule{Id: id, Name: "Shipping", Kind: models.PriceRuleShipment, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationFixed, Value: money.FromInt(value), MinSubtotal: money.FromInt(minSubtotal), Status: 1}
+	}
+	window := func(rule models.PriceRule, starts *time.Time, ends *time.Time) models.PriceRule {
+		rule.StartsAt, rule.EndsAt = starts, ends
+		return rule
+	}
+	inactive := percent(1, models.PriceScopeOrder, nil, 10)
+	inactive.Status = 0
+	coupon := func(calculation string, value int64, targets ...models.CouponTarget) *models.Coupon {
+		return &models.Coupon{Id: 1, Code: "SAVE", Calculation: calculation, Value: money.FromInt(value), Status: 1, Targets: targets}
+	}
+
+	tests := []struct {
+		name      string
+		rules     []models.PriceRule
+		rates     []models.TaxRate
+		coupon    *models.Coupon
+		address   PriceAddress
+		discount  money.Money
+		shipment  money.Money
+		taxes     money.Money
+		total     money.Money
+		lineTotal money.Money
+	}{
+		{
+			name:      "no rules",
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "order percent discount",
+			rules:     []models.PriceRule{percent(1, models.PriceScopeOrder, nil, 10)},
+			discount:  money.FromInt(15),
+			total:     money.FromInt(135),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "discount inside its window",
+			rules:     []models.PriceRule{window(percent(1, models.PriceScopeOrder, nil, 10), &yesterday, &tomorrow)},
+			discount:  money.FromInt(15),
+			total:     money.FromInt(135),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "discount not started yet",
+			rules:     []models.PriceRule{window(percent(1, models.PriceScopeOrder, nil, 10), &tomorrow, nil)},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "discount already ended",
+			rules:     []models.PriceRule{window(percent(1, models.PriceScopeOrder, nil, 10), nil, &yesterday)},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "disabled discount",
+			rules:     []models.PriceRule{inactive},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "product discount",
+			rules:     []models.PriceRule{percent(1, models.PriceScopeProduct, ruleTarget(10), 20)},
+			discount:  money.FromInt(20),
+			total:     money.FromInt(130),
+			lineTotal: money.FromInt(80),
+		},
+		{
+			name:      "category discount",
+			rules:     []models.PriceRule{fixed(1, models.PriceScopeCategory, ruleTarget(7), 30)},
+			discount:  money.FromInt(30),
+			total:     money.FromInt(120),
+			lineTotal: money.FromInt(70),
+		},
+		{
+			name:      "brand discount is per unit",
+			rules:     []models.PriceRule{fixed(1, models.PriceScopeBrand, ruleTarget(2), 5)},
+			discount:  money.FromInt(10),
+			total:     money.FromInt(140),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "line discount cannot go below zero",
+			rules:     []models.PriceRule{fixed(1, models.PriceScopeProduct, ruleTarget(10), 500)},
+			discount:  money.FromInt(100),
+			total:     money.FromInt(50),
+			lineTotal: money.FromInt(0),
+		},
+		{
+			name:      "order percent applies after line discounts",
+			rules:     []models.PriceRule{percent(1, models.PriceScopeOrder, nil, 10), percent(2, models.PriceScopeProduct, ruleTarget(10), 50)},
+			discount:  money.FromInt(60),
+			total:     money.FromInt(90),
+			lineTotal: money.FromInt(50),
+		},
+		{
+			name: "order discount below its minimum subtotal",
+			rules: []models.PriceRule{func() models.PriceRule {
+				r := fixed(1, models.PriceScopeOrder, nil, 20)
+				r.MinSubtotal = money.FromInt(200)
+				return r
+			}()},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "first qualifying shipment rule wins",
+			rules:     []models.PriceRule{shipment(1, 0, 100), shipment(2, 15, 0)},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "flat shipment under the free threshold",
+			rules:     []models.PriceRule{shipment(1, 0, 500), shipment(2, 15, 0)},
+			shipment:  money.FromInt(15),
+			total:     money.FromInt(165),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "tax on the discounted subtotal",
+			rules:     []models.PriceRule{percent(1, models.PriceScopeOrder, nil, 10), shipment(2, 15, 0)},
+			rates:     []models.TaxRate{{Id: 1, Name: "VAT", Rate: money.MustParseRate("10"), Status: 1}},
+			discount:  money.FromInt(15),
+			shipment:  money.FromInt(15),
+			taxes:     money.MustParse("13.5"),
+			total:     money.MustParse("163.5"),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name: "country rate beats the default",
+			rates: []models.TaxRate{
+				{Id: 1, Name: "Default", Rate: money.MustParseRate("10"), Status: 1},
+				{Id: 2, Name: "Indonesia", Country: "Indonesia", Rate: money.MustParseRate("11"), Status: 1},
+			},
+			address:   PriceAddress{Country: "indonesia"},
+			taxes:     money.MustParse("16.5"),
+			total:     money.MustParse("166.5"),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name: "longest zip prefix wins",
+			rates: []models.TaxRate{
+				{Id: 1, Name: "US", Country: "US", Rate: money.MustParseRate("5"), Status: 1},
+				{Id: 2, Name: "New York", Country: "US", ZipPrefix: "10", Rate: money.MustParseRate("8"), Status: 1},
+				{Id: 3, Name: "Manhattan", Country: "US", ZipPrefix: "100", Rate: money.MustParseRate("9"), Status: 1},
+				{Id: 4, Name: "Elsewhere", Country: "CA", ZipPrefix: "100", Rate: money.MustParseRate("20"), Status: 1},
+			},
+			address:   PriceAddress{Country: "US", ZipCode: "10001"},
+			taxes:     money.MustParse("13.5"),
+			total:     money.MustParse("163.5"),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "taxes round half away from zero to cents",
+			rates:     []models.TaxRate{{Id: 1, Name: "Sales tax", Rate: money.MustParseRate("8.55"), Status: 1}},
+			taxes:     money.MustParse("12.83"),
+			total:     money.MustParse("162.83"),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name: "percent discounts round to cents",
+			rules: []models.PriceRule{func() models.PriceRule {
+				r := percent(1, models.PriceScopeOrder, nil, 0)
+				r.Value = money.MustParse("3.333")
+				return r
+			}()},
+			discount:  money.FromInt(5),
+			total:     money.FromInt(145),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "order percent coupon",
+			coupon:    coupon(models.PriceCalculationPercent, 10),
+			discount:  money.FromInt(15),
+			total:     money.FromInt(135),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "coupon after order discount",
+			rules:     []models.PriceRule{fixed(1, models.PriceScopeOrder, nil, 50)},
+			coupon:    coupon(models.PriceCalculationPercent, 10),
+			discount:  money.FromInt(60),
+			total:     money.FromInt(90),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "targeted coupon discounts matching lines only",
+			coupon:    coupon(models.PriceCalculationPercent, 20, models.CouponTarget{Scope: models.PriceScopeBrand, TargetId: 2}),
+			discount:  money.FromInt(10),
+			total:     money.FromInt(140),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "fixed coupon clamped to targeted lines",
+			coupon:    coupon(models.PriceCalculationFixed, 80, models.CouponTarget{Scope: models.PriceScopeProduct, TargetId: 11}),
+			discount:  money.FromInt(50),
+			total:     money.FromInt(100),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "fixed coupon clamped to the order",
+			coupon:    coupon(models.PriceCalculationFixed, 500),
+			discount:  money.FromInt(150),
+			total:     money.FromInt(0),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "coupon not taken off shipment",
+			rules:     []models.PriceRule{shipment(2, 15, 0)},
+			coupon:    coupon(models.PriceCalculationFixed, 500),
+			discount:  money.FromInt(150),
+			shipment:  money.FromInt(15),
+			total:     money.FromInt(15),
+			lineTotal: money.FromInt(100),
+		},
+		{
+			name:      "no matching tax rate",
+			rates:     []models.TaxRate{{Id: 1, Name: "Indonesia", Country: "Indonesia", Rate: money.MustParseRate("11"), Status: 1}},
+			address:   PriceAddress{Country: "US"},
+			total:     money.FromInt(150),
+			lineTotal: money.FromInt(100),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			breakdown := priceItems(items, test.rules, test.rates, test.coupon, test.address, "USD", now)
+
+			got := []money.Money{breakdown.Subtotal, breakdown.Discount, breakdown.Shipment, breakdown.Taxes, breakdown.Total, breakdown.Lines[0].Total}
+			want := []money.Money{money.FromInt(150), test.discount, test.shipment, test.taxes, test.total, test.lineTotal}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("subtotal, discount, shipment, taxes, total, first line = %v, want %v", got, want)
+				}
+			}
+
+			var adjusted money.Money
+			for _, line := range breakdown.Lines {
+				for _, adjustment := range line.Adjustments {
+					adjusted += adjustment.Amount
+				}
+			}
+			for _, adjustment := range breakdown.Adjustments {
+				adjusted += adjustment.Amount
+			}
+			if breakdown.Subtotal+adjusted != breakdown.Total {
+				t.Errorf("adjustments add up to %v, want the total %v", breakdown.Subtotal+adjusted, breakdown.Total)
+			}
+		})
+	}
+}
+
+func TestPriceItemsEmptyCart(t *testing.T) {
+
+	rules := []models.PriceRule{{Id: 1, Name: "Shipping", Kind: models.PriceRuleShipment, Scope: models.PriceScopeOrder, Calculation: models.PriceCalculationFixed, Value: money.FromInt(15), Status: 1}}
+	rates := []models.TaxRate{{Id: 1, Name: "VAT", Rate: money.MustParseRate("10"), Status: 1}}
+
+	breakdown := priceItems(nil, rules, rates, nil, PriceAddress{}, "USD", time.Now())
+	if breakdown.Total != 0 || breakdown.Shipment != 0 || len(breakdown.Adjustments) != 0 {
+		t.Errorf("breakdown of an empty cart = %+v, want nothing to pay", breakdown)
+	}
+}
+
+func TestCheckoutUsesBillingAddress(t *testing.T) {
+
+	store := newShopStore()
+	store.taxRates = append(store.taxRates, models.TaxRate{Id: 2, Name: "Indonesia", Country: "Indonesia", Rate: money.MustParseRate("11"), Status: 1})
+	fillCart(t, store, CartItem{ProductId: 10, SizeId: 1, ColourId: 1, Qty: 5})
+
+	input := checkoutInput()
+	input.Country = "Indonesia"
+
+	order, breakdown, err := Orders(testConfig(), store).Checkout(1, input)
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if breakdown.TaxRate != money.MustParseRate("11") || order.TotalTaxes != money.MustParse("10.45") {
+		t.Errorf("tax rate %v and taxes %v, want 11%% of 95", breakdown.TaxRate, order.TotalTaxes)
+	}
+}
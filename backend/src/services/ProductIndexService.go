@@ -0,0 +1,90 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+)
+
+// product index service
+//
+// products.total_order and the products' rating aggregates are
+// denormalised counters that checkout, cancellation and review moderation
+// keep up to date as they go. Rebuild recomputes them from orders_details
+// and products_reviews.
+type ProductIndexService interface {
+	Rebuild() (int, error)
+}
+
+type productIndexServices struct {
+	store repositories.Store
+}
+
+func ProductIndex(store repositories.Store) ProductIndexService {
+	return &productIndexServices{store: store}
+}
+
+func (service *productIndexServices) Rebuild() (int, error) {
+
+	var holding []uint8
+	for status := range models.OrderStatusNames {
+		if (models.Order{Status: status}).HoldsStock() {
+			holding = append(holding, status)
+		}
+	}
+
+	var total int
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		catalog := tx.Catalog()
+
+		ordered, err := catalog.OrderedTotals(holding)
+		if err != nil {
+			return err
+		}
+
+		stars, err := tx.Reviews().AllStars()
+		if err != nil {
+			return err
+		}
+
+		ids, err := catalog.ProductIds()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := catalog.SetOrdered(id, clampCounter(ordered[id])); err != nil {
+				return err
+			}
+			product := models.Product{Id: id}
+			product.SetStars(stars[id])
+			if err := catalog.SaveRatings(product); err != nil {
+				return err
+			}
+		}
+
+		total = len(ids)
+		return nil
+	})
+
+	return total, err
+}
+
+// clampCounter keeps a counter within the uint16 product columns.
+func clampCounter(total uint64) uint16 {
+	if total > 65535 {
+		return 65535
+	}
+	return uint16(total)
+}
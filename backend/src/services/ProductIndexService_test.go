@@ -0,0 +1,47 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	"testing"
+)
+
+func TestProductIndexRebuild(t *testing.T) {
+
+	store := newShopStore()
+	store.orders[1] = models.Order{Id: 1, UserId: 1, Status: models.OrderStatusPaid}
+	store.orders[2] = models.Order{Id: 2, UserId: 1, Status: models.OrderStatusCancelled}
+	store.details[1] = models.OrderDetail{Id: 1, OrderId: 1, InventoryId: 100, Qty: 3}
+	store.details[2] = models.OrderDetail{Id: 2, OrderId: 2, InventoryId: 100, Qty: 5}
+	store.reviews[1] = models.ProductReview{Id: 1, ProductId: 10, UserId: 1, Rating: 5, Status: models.ReviewStatusApproved}
+	store.reviews[2] = models.ProductReview{Id: 2, ProductId: 10, UserId: 2, Rating: 1, Status: models.ReviewStatusRejected}
+
+	total, err := ProductIndex(store).Rebuild()
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Rebuild = %d products, want 2", total)
+	}
+
+	shirt, shoes := store.products[10], store.products[11]
+	if shirt.TotalOrder != 3 {
+		t.Errorf("shirt total_order = %d, want only the paid order's 3", shirt.TotalOrder)
+	}
+	if shirt.RatingCount != 1 || shirt.Rating5 != 1 || shirt.Rating4 != 0 {
+		t.Errorf("shirt ratings = %+v, want the one approved five-star review", shirt)
+	}
+	if shoes.TotalOrder != 0 || shoes.RatingCount != 0 || shoes.Rating2 != 0 {
+		t.Errorf("shoes = %+v, want its stale ratings cleared", shoes)
+	}
+}
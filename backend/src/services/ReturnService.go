@@ -0,0 +1,316 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrOrderNotReturnable = errors.New("only delivered orders can be returned")
+
+// ReturnError explains why a return or refund was refused.
+type ReturnError struct {
+	Reason string
+}
+
+func (e *ReturnError) Error() string {
+	return e.Reason
+}
+
+type InvalidReturnTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidReturnTransitionError) Error() string {
+	return fmt.Sprintf("a return cannot move from %s to %s", e.From, e.To)
+}
+
+type ReturnPage struct {
+	List          []models.OrderReturn
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+}
+
+// return service
+type ReturnService interface {
+	Request(userId uint64, orderId uint64, input schema.ReturnRequestSchema) (models.OrderReturn, error)
+	List(userId uint64, orderId uint64) ([]models.OrderReturn, error)
+	AdminList(status string, query repositories.ListQuery) (ReturnPage, error)
+	AdminDetail(id uint64) (models.OrderReturn, error)
+	Approve(id uint64, staffId uint64, note string) (models.OrderReturn, error)
+	Reject(id uint64, staffId uint64, note string) (models.OrderReturn, error)
+	Receive(id uint64, staffId uint64, note string) (models.OrderReturn, error)
+	Refund(id uint64, staffId uint64, amount *money.Money, note string) (models.OrderReturn, error)
+}
+
+type returnServices struct {
+	config *appconfig.Config
+	store  repositories.Store
+}
+
+func Returns(config *appconfig.Config, store repositories.Store) ReturnService {
+	return &returnServices{config: config, store: store}
+}
+
+// AdminList lists every order's returns for staff, only those in status
+// when it is set.
+func (service *returnServices) AdminList(status string, query repositories.ListQuery) (ReturnPage, error) {
+	orderReturns, total, filtered, err := service.store.Returns().Search(status, query)
+	return ReturnPage{
+		List:          orderReturns,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+// AdminDetail shows staff a return with the history of its steps.
+func (service *returnServices) AdminDetail(id uint64) (models.OrderReturn, error) {
+	return service.store.Returns().FindComplete(id)
+}
+
+// Request asks to send back quantities of a delivered order's lines. A
+// line cannot be returned more often than it was bought, counting every
+// earlier return that was not rejected.
+func (service *returnServices) Request(userId uint64, orderId uint64, input schema.ReturnRequestSchema) (models.OrderReturn, error) {
+
+	var orderReturn models.OrderReturn
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		order, err := tx.Orders().LockOwned(userId, orderId)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderStatusDelivered {
+			return ErrOrderNotReturnable
+		}
+
+		details, err := tx.Orders().Details(order.Id)
+		if err != nil {
+			return err
+		}
+		byId := make(map[uint64]models.OrderDetail, len(details))
+		for _, detail := range details {
+			byId[detail.Id] = detail
+		}
+
+		returned, err := tx.Returns().Returned(order.Id)
+		if err != nil {
+			return err
+		}
+
+		// The same line may be listed twice; its quantities add up.
+		orderReturn = models.OrderReturn{OrderId: order.Id, UserId: userId, Status: models.ReturnStatusRequested, Reason: input.Reason}
+		lines := make(map[uint64]int, len(input.Lines))
+		for _, item := range input.Lines {
+
+			detail, ok := byId[item.DetailId]
+			if !ok {
+				return &ReturnError{Reason: fmt.Sprintf("Line %d is not part of this order.", item.DetailId)}
+			}
+
+			i, seen := lines[detail.Id]
+			if !seen {
+				i = len(orderReturn.Lines)
+				lines[detail.Id] = i
+				orderReturn.Lines = append(orderReturn.Lines, models.OrderReturnLine{DetailId: detail.Id, InventoryId: detail.InventoryId, Price: detail.Price})
+			}
+
+			qty := uint32(orderReturn.Lines[i].Qty) + item.Qty
+			if left := uint32(detail.Qty) - uint32(returned[detail.Id]); qty > left {
+				return &ReturnError{Reason: fmt.Sprintf("Only %d of line %d can still be returned.", left, detail.Id)}
+			}
+			orderReturn.Lines[i].Qty = uint16(qty)
+		}
+
+		if err := tx.Returns().Create(&orderReturn); err != nil {
+			return err
+		}
+
+		history := models.OrderReturnHistory{ReturnId: orderReturn.Id, ToStatus: models.ReturnStatusRequested, ActorId: userId, ActorType: ActorCustomer, Note: input.Reason}
+		if err := tx.Returns().AddHistory(&history); err != nil {
+			return err
+		}
+
+		return logActivity(tx, userId, "Return Order", "Request Return", fmt.Sprintf("Your return for order %s has been requested.", order.InvoiceNumber))
+	})
+
+	return orderReturn, err
+}
+
+func (service *returnServices) List(userId uint64, orderId uint64) ([]models.OrderReturn, error) {
+
+	order, err := service.store.Orders().FindOwned(userId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.store.Returns().ForOrder(order.Id)
+}
+
+func (service *returnServices) Approve(id uint64, staffId uint64, note string) (models.OrderReturn, error) {
+	return service.move(id, models.ReturnStatusApproved, staffId, note, func(tx repositories.Store, orderReturn *models.OrderReturn, order *models.Order) error {
+		now := time.Now()
+		orderReturn.ApprovedAt = &now
+		return nil
+	})
+}
+
+func (service *returnServices) Reject(id uint64, staffId uint64, note string) (models.OrderReturn, error) {
+	return service.move(id, models.ReturnStatusRejected, staffId, note, nil)
+}
+
+// Receive puts the returned quantities back in stock.
+func (service *returnServices) Receive(id uint64, staffId uint64, note string) (models.OrderReturn, error) {
+	return service.move(id, models.ReturnStatusReceived, staffId, note, func(tx repositories.Store, orderReturn *models.OrderReturn, order *models.Order) error {
+
+		for _, line := range orderReturn.Lines {
+
+			inventory, err := tx.Catalog().LockInventory(line.InventoryId)
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			if err := tx.Catalog().ReturnStock(inventory.Id, line.Qty); err != nil {
+				return err
+			}
+			if err := tx.Catalog().RemoveOrdered(inventory.ProductId, line.Qty); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		orderReturn.ReceivedAt = &now
+		return nil
+	})
+}
+
+// Refund pays back amount, or the value of the returned lines when amount
+// is nil, and records it on the order and on the payment that collected
+// it. Once everything paid has been refunded the order moves to refunded.
+func (service *returnServices) Refund(id uint64, staffId uint64, amount *money.Money, note string) (models.OrderReturn, error) {
+	return service.move(id, models.ReturnStatusRefunded, staffId, note, func(tx repositories.Store, orderReturn *models.OrderReturn, order *models.Order) error {
+
+		currency := money.Default()
+		left := order.TotalPaid - order.TotalRefunded
+
+		var refund money.Money
+		if amount == nil {
+			refund = orderReturn.Total().Round(currency)
+			if refund > left {
+				refund = left
+			}
+		} else {
+			refund = amount.Round(currency)
+		}
+
+		if refund <= 0 {
+			return &ReturnError{Reason: "The refund amount must be greater than zero."}
+		}
+		if refund > left {
+			return &ReturnError{Reason: fmt.Sprintf("Only %s %s of this order is left to refund.", currency, left)}
+		}
+
+		if err := bookRefund(tx, order, refund); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		orderReturn.RefundAmount = refund
+		orderReturn.RefundedAt = &now
+
+		machine := OrderStateMachine(service.config)
+		if order.TotalRefunded >= order.TotalPaid && machine.CanTransition(order.Status, models.OrderStatusRefunded) {
+			return machine.Transition(tx, order, models.OrderStatusRefunded, staffId, ActorStaff, fmt.Sprintf("Refunded in full by return %d", orderReturn.Id))
+		}
+		return nil
+	})
+}
+
+var returnEvents = map[string]string{
+	models.ReturnStatusApproved: "Return Approved",
+	models.ReturnStatusRejected: "Return Rejected",
+	models.ReturnStatusReceived: "Return Received",
+	models.ReturnStatusRefunded: "Return Refunded",
+}
+
+// move takes a return to the given status inside one transaction, runs
+// apply for the side effects of the step, records which staff member took
+// it and tells the customer about it.
+func (service *returnServices) move(id uint64, to string, staffId uint64, note string, apply func(tx repositories.Store, orderReturn *models.OrderReturn, order *models.Order) error) (models.OrderReturn, error) {
+
+	var orderReturn models.OrderReturn
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		var err error
+		if orderReturn, err = tx.Returns().Lock(id); err != nil {
+			return err
+		}
+
+		if !canMoveReturn(orderReturn.Status, to) {
+			return &InvalidReturnTransitionError{From: orderReturn.Status, To: to}
+		}
+
+		order, err := tx.Orders().Lock(orderReturn.OrderId)
+		if err != nil {
+			return err
+		}
+
+		if apply != nil {
+			if err := apply(tx, &orderReturn, &order); err != nil {
+				return err
+			}
+		}
+
+		history := models.OrderReturnHistory{ReturnId: orderReturn.Id, FromStatus: orderReturn.Status, ToStatus: to, ActorId: staffId, ActorType: ActorStaff, Note: note}
+
+		orderReturn.Status = to
+		if note != "" {
+			orderReturn.Note = sql.NullString{String: note, Valid: true}
+		}
+		if err := tx.Returns().Save(&orderReturn); err != nil {
+			return err
+		}
+		if err := tx.Returns().AddHistory(&history); err != nil {
+			return err
+		}
+
+		return logActivity(tx, orderReturn.UserId, "Return Order", returnEvents[to], fmt.Sprintf("Your return for order %s has been %s.", order.InvoiceNumber, to))
+	})
+
+	return orderReturn, err
+}
+
+func canMoveReturn(from string, to string) bool {
+	for _, next := range models.ReturnTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
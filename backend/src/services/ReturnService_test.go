@@ -0,0 +1,208 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	money "backend/src/money"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// newReturnStore adds to the shop store a delivered order of three shirts
+// and a pair of shoes, paid through the gateway.
+func newReturnStore() fakeStore {
+	store := newShopStore()
+	store.orders[60] = models.Order{Id: 60, UserId: 1, PaymentId: 7, InvoiceNumber: "INV-0060", Subtotal: money.FromInt(110), TotalPaid: money.FromInt(110), Status: models.OrderStatusDelivered}
+	store.details[601] = models.OrderDetail{Id: 601, OrderId: 60, InventoryId: 100, Price: money.FromInt(20), Qty: 3, Total: money.FromInt(60)}
+	store.details[602] = models.OrderDetail{Id: 602, OrderId: 60, InventoryId: 110, Price: money.FromInt(50), Qty: 1, Total: money.FromInt(50)}
+	store.attempts[603] = models.PaymentTransaction{Id: 603, OrderId: 60, PaymentId: 7, Provider: "gateway", Reference: "pi_60", Amount: money.FromInt(110), Currency: "USD", Status: "succeeded"}
+	return store
+}
+
+func returnInput(lines ...schema.ReturnLineSchema) schema.ReturnRequestSchema {
+	return schema.ReturnRequestSchema{Reason: "Too small", Lines: lines}
+}
+
+func TestReturnRequest(t *testing.T) {
+
+	store := newReturnStore()
+	returns := Returns(testConfig(), store)
+
+	orderReturn, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 601, Qty: 1}, schema.ReturnLineSchema{DetailId: 601, Qty: 1}))
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if orderReturn.Status != models.ReturnStatusRequested || len(orderReturn.Lines) != 1 || orderReturn.Lines[0].Qty != 2 || orderReturn.Total() != money.FromInt(40) {
+		t.Errorf("return = %+v, want one line of two shirts worth 40", orderReturn)
+	}
+	if len(store.activities) != 1 || store.activities[0].Event != "Request Return" {
+		t.Errorf("activities = %+v, want the request logged", store.activities)
+	}
+
+	// Two of three shirts are already on their way back.
+	var refused *ReturnError
+	if _, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 601, Qty: 2})); !errors.As(err, &refused) {
+		t.Errorf("returning more than was bought = %v, want a *ReturnError", err)
+	}
+	if _, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 999, Qty: 1})); !errors.As(err, &refused) {
+		t.Errorf("returning a line of another order = %v, want a *ReturnError", err)
+	}
+
+	// A rejected return frees its quantities again.
+	if _, err := returns.Reject(orderReturn.Id, 2, "Worn"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if _, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 601, Qty: 3})); err != nil {
+		t.Errorf("Request after a rejection = %v, want the shirts returnable again", err)
+	}
+}
+
+func TestReturnRequestRefused(t *testing.T) {
+
+	store := newReturnStore()
+	returns := Returns(testConfig(), store)
+	input := returnInput(schema.ReturnLineSchema{DetailId: 601, Qty: 1})
+
+	if _, err := returns.Request(2, 60, input); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Request for another user's order = %v, want ErrNotFound", err)
+	}
+
+	order := store.orders[60]
+	order.Status = models.OrderStatusShipped
+	store.orders[60] = order
+
+	if _, err := returns.Request(1, 60, input); !errors.Is(err, ErrOrderNotReturnable) {
+		t.Errorf("Request for an order on its way = %v, want ErrOrderNotReturnable", err)
+	}
+	if len(store.returns) != 0 {
+		t.Errorf("%d returns stored, want none", len(store.returns))
+	}
+}
+
+func TestReturnWorkflow(t *testing.T) {
+
+	store := newReturnStore()
+	returns := Returns(testConfig(), store)
+
+	orderReturn, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 601, Qty: 2}))
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+
+	var invalid *InvalidReturnTransitionError
+	if _, err := returns.Receive(orderReturn.Id, 2, ""); !errors.As(err, &invalid) {
+		t.Errorf("Receive before approval = %v, want an *InvalidReturnTransitionError", err)
+	}
+
+	if _, err := returns.Approve(orderReturn.Id, 2, "Send it back"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if _, err := returns.Receive(orderReturn.Id, 2, ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if stock := store.inventories[100].Stock; stock != 7 {
+		t.Errorf("stock after receipt = %d, want 5 + 2", stock)
+	}
+
+	refunded, err := returns.Refund(orderReturn.Id, 2, nil, "")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if refunded.RefundAmount != money.FromInt(40) || refunded.RefundedAt == nil || refunded.Note.String != "Send it back" {
+		t.Errorf("return = %+v, want 40 refunded", refunded)
+	}
+	if order := store.orders[60]; order.TotalRefunded != money.FromInt(40) || order.Status != models.OrderStatusDelivered {
+		t.Errorf("order refunded %v and is %s, want 40 and still delivered", order.TotalRefunded, order.StatusName())
+	}
+	if transaction := store.attempts[603]; transaction.Refunded != money.FromInt(40) {
+		t.Errorf("transaction refunded %v, want 40", transaction.Refunded)
+	}
+
+	var events []string
+	for _, activity := range store.activities {
+		events = append(events, activity.Event)
+	}
+	if len(events) != 4 || events[3] != "Return Refunded" {
+		t.Errorf("activities = %v, want one for each step", events)
+	}
+
+	var steps []string
+	for _, history := range store.returnHistories {
+		if history.ReturnId != orderReturn.Id {
+			continue
+		}
+		steps = append(steps, fmt.Sprintf("%s>%s by %s %d", history.FromStatus, history.ToStatus, history.ActorType, history.ActorId))
+	}
+	want := []string{
+		">requested by customer 1",
+		"requested>approved by staff 2",
+		"approved>received by staff 2",
+		"received>refunded by staff 2",
+	}
+	if fmt.Sprint(steps) != fmt.Sprint(want) {
+		t.Errorf("history = %v, want %v", steps, want)
+	}
+}
+
+func TestReturnRefundAmount(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		amount *money.Money
+		want   money.Money
+		status uint8
+		fails  bool
+	}{
+		{name: "partial", amount: moneyPtr(money.FromInt(15)), want: money.FromInt(15), status: models.OrderStatusDelivered},
+		{name: "everything paid", amount: moneyPtr(money.FromInt(110)), want: money.FromInt(110), status: models.OrderStatusRefunded},
+		{name: "more than was paid", amount: moneyPtr(money.FromInt(111)), fails: true},
+		{name: "nothing", amount: moneyPtr(0), fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			store := newReturnStore()
+			returns := Returns(testConfig(), store)
+
+			orderReturn, err := returns.Request(1, 60, returnInput(schema.ReturnLineSchema{DetailId: 602, Qty: 1}))
+			if err != nil {
+				t.Fatalf("Request: %v", err)
+			}
+			returns.Approve(orderReturn.Id, 2, "")
+			returns.Receive(orderReturn.Id, 2, "")
+
+			refunded, err := returns.Refund(orderReturn.Id, 2, test.amount, "")
+			if test.fails {
+				var refused *ReturnError
+				if !errors.As(err, &refused) || store.orders[60].TotalRefunded != 0 {
+					t.Errorf("Refund = %v with %v refunded, want a *ReturnError and nothing refunded", err, store.orders[60].TotalRefunded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Refund: %v", err)
+			}
+			if refunded.RefundAmount != test.want || store.orders[60].Status != test.status {
+				t.Errorf("refunded %v and the order is %s, want %v and %s", refunded.RefundAmount, store.orders[60].StatusName(), test.want, models.OrderStatusNames[test.status])
+			}
+		})
+	}
+}
+
+func moneyPtr(m money.Money) *money.Money {
+	return &m
+}
@@ -0,0 +1,292 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrReviewNotAllowed = errors.New("only customers who received the product can review it")
+	ErrInvalidRating    = fmt.Errorf("a rating must be from %d to %d stars", models.ReviewRatingMin, models.ReviewRatingMax)
+	ErrOwnReviewVote    = errors.New("customers cannot vote on their own review")
+)
+
+// ReviewCard is a product review as the storefront lists it.
+type ReviewCard struct {
+	Id          int64
+	Name        string
+	Description string
+	Rating      uint16
+	Percentage  float64
+	Verified    bool
+	Helpful     uint32
+	CreatedAt   time.Time
+}
+
+type ReviewPage struct {
+	List          []models.ProductReview
+	TotalAll      int64
+	TotalFiltered int64
+	Limit         int
+	Page          int
+}
+
+// review service
+//
+// Every customer has at most one review per product. Writing or editing it
+// puts it in the moderation queue, and only approved reviews are listed
+// and counted in the product's rating aggregates.
+type ReviewService interface {
+	List(productId uint64) ([]ReviewCard, error)
+	AdminList(status *uint8, productId uint64, query repositories.ListQuery) (ReviewPage, error)
+	AdminDetail(id uint64) (models.ProductReview, error)
+	Mine(userId uint64, productId uint64) (models.ProductReview, error)
+	Submit(userId uint64, productId uint64, rating uint16, text string) (models.ProductReview, error)
+	Approve(id uint64, note string) (models.ProductReview, error)
+	Reject(id uint64, note string) (models.ProductReview, error)
+	Vote(userId uint64, id uint64) (models.ProductReview, error)
+	Unvote(userId uint64, id uint64) (models.ProductReview, error)
+}
+
+type reviewServices struct {
+	store repositories.Store
+}
+
+func Reviews(store repositories.Store) ReviewService {
+	return &reviewServices{store: store}
+}
+
+// AdminList is the moderation queue: the reviews in status and of
+// productId when they are set.
+func (service *reviewServices) AdminList(status *uint8, productId uint64, query repositories.ListQuery) (ReviewPage, error) {
+	reviews, total, filtered, err := service.store.Reviews().Search(status, productId, query)
+	return ReviewPage{
+		List:          reviews,
+		TotalAll:      total,
+		TotalFiltered: filtered,
+		Limit:         query.Limit,
+		Page:          query.Page,
+	}, err
+}
+
+func (service *reviewServices) AdminDetail(id uint64) (models.ProductReview, error) {
+	return service.store.Reviews().FindComplete(id)
+}
+
+// List returns the product's approved reviews, the most helpful first.
+func (service *reviewServices) List(productId uint64) ([]ReviewCard, error) {
+
+	reviews, err := service.store.Reviews().Approved(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []ReviewCard
+	for _, review := range reviews {
+		cards = append(cards, ReviewCard{
+			Id:          int64(review.Id),
+			Name:        review.User.FirstName.String + " " + review.User.LastName.String,
+			Description: review.Review,
+			Rating:      review.Rating,
+			Percentage:  float64(review.Rating) * 100 / models.ReviewRatingMax,
+			Verified:    review.Verified,
+			Helpful:     review.HelpfulCount,
+			CreatedAt:   review.CreatedAt,
+		})
+	}
+
+	return cards, nil
+}
+
+// Mine returns the user's own review of the product, whatever its status.
+func (service *reviewServices) Mine(userId uint64, productId uint64) (models.ProductReview, error) {
+	return service.store.Reviews().Owned(userId, productId)
+}
+
+// Submit writes the user's review of a product they have received, or
+// rewrites the one they wrote before. Either way it waits for moderation.
+func (service *reviewServices) Submit(userId uint64, productId uint64, rating uint16, text string) (models.ProductReview, error) {
+
+	var review models.ProductReview
+
+	if rating < models.ReviewRatingMin || rating > models.ReviewRatingMax {
+		return review, ErrInvalidRating
+	}
+
+	product, err := service.store.Catalog().Product(productId)
+	if err != nil {
+		return review, err
+	}
+
+	delivered, err := service.store.Orders().HasDelivered(userId, product.Id)
+	if err != nil {
+		return review, err
+	}
+	if !delivered {
+		return review, ErrReviewNotAllowed
+	}
+
+	err = service.store.Transaction(func(tx repositories.Store) error {
+
+		var err error
+		review, err = tx.Reviews().LockOwned(userId, product.Id)
+		exists := err == nil
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+
+		wasApproved := review.Status == models.ReviewStatusApproved
+		review.UserId = userId
+		review.ProductId = product.Id
+		review.Rating = rating
+		review.Review = text
+		review.Verified = true
+		review.Status = models.ReviewStatusPending
+		review.ModerationNote = sql.NullString{}
+		review.ModeratedAt = nil
+
+		if !exists {
+			if err := tx.Reviews().Create(&review); err != nil {
+				return err
+			}
+			return logActivity(tx, userId, "Create new review", "Add review to "+product.Name, "Your has been added new review to "+product.Name)
+		}
+
+		if err := tx.Reviews().Save(&review); err != nil {
+			return err
+		}
+
+		// An approved review leaves the product's rating until it is
+		// approved again.
+		if wasApproved {
+			if err := recountRatings(tx, product.Id); err != nil {
+				return err
+			}
+		}
+
+		return logActivity(tx, userId, "Update review", "Edit review of "+product.Name, "Your review of "+product.Name+" has been updated and is waiting for moderation.")
+	})
+
+	return review, err
+}
+
+func (service *reviewServices) Approve(id uint64, note string) (models.ProductReview, error) {
+	return service.moderate(id, models.ReviewStatusApproved, note)
+}
+
+func (service *reviewServices) Reject(id uint64, note string) (models.ProductReview, error) {
+	return service.moderate(id, models.ReviewStatusRejected, note)
+}
+
+// moderate settles a review, brings its product's rating up to date and
+// tells the customer about it.
+func (service *reviewServices) moderate(id uint64, status uint8, note string) (models.ProductReview, error) {
+
+	var review models.ProductReview
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		var err error
+		if review, err = tx.Reviews().Lock(id); err != nil {
+			return err
+		}
+
+		product, err := tx.Catalog().Product(review.ProductId)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		review.Status = status
+		review.ModeratedAt = &now
+		review.ModerationNote = sql.NullString{}
+		if note != "" {
+			review.ModerationNote = sql.NullString{String: note, Valid: true}
+		}
+		if err := tx.Reviews().Save(&review); err != nil {
+			return err
+		}
+
+		if err := recountRatings(tx, product.Id); err != nil {
+			return err
+		}
+
+		name := review.StatusName()
+		return logActivity(tx, review.UserId, "Review Moderation", "Review "+name, fmt.Sprintf("Your review of %s has been %s.", product.Name, name))
+	})
+
+	return review, err
+}
+
+// Vote marks an approved review as helpful to the user. Voting twice
+// counts once.
+func (service *reviewServices) Vote(userId uint64, id uint64) (models.ProductReview, error) {
+	return service.vote(userId, id, repositories.ReviewRepository.Vote)
+}
+
+// Unvote takes the user's helpful vote on a review back.
+func (service *reviewServices) Unvote(userId uint64, id uint64) (models.ProductReview, error) {
+	return service.vote(userId, id, repositories.ReviewRepository.Unvote)
+}
+
+func (service *reviewServices) vote(userId uint64, id uint64, apply func(reviews repositories.ReviewRepository, reviewId uint64, userId uint64) (bool, error)) (models.ProductReview, error) {
+
+	var review models.ProductReview
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		var err error
+		if review, err = tx.Reviews().Lock(id); err != nil {
+			return err
+		}
+
+		// Reviews still waiting for moderation are not public yet.
+		if review.Status != models.ReviewStatusApproved {
+			return repositories.ErrNotFound
+		}
+		if review.UserId == userId {
+			return ErrOwnReviewVote
+		}
+
+		changed, err := apply(tx.Reviews(), review.Id, userId)
+		if err != nil || !changed {
+			return err
+		}
+
+		if review.HelpfulCount, err = tx.Reviews().Votes(review.Id); err != nil {
+			return err
+		}
+		return tx.Reviews().Save(&review)
+	})
+
+	return review, err
+}
+
+// recountRatings rebuilds the product's rating aggregates from its
+// approved reviews.
+func recountRatings(tx repositories.Store, productId uint64) error {
+
+	stars, err := tx.Reviews().Stars(productId)
+	if err != nil {
+		return err
+	}
+
+	product := models.Product{Id: productId}
+	product.SetStars(stars)
+	return tx.Catalog().SaveRatings(product)
+}
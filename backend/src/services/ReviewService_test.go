@@ -0,0 +1,232 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// newReviewStore adds to the shop store a third customer and a delivered
+// order of a shirt for each of the first two.
+func newReviewStore() fakeStore {
+	store := newShopStore()
+	store.products[10] = models.Product{Id: 10, Name: "Shirt", Status: 1}
+	store.users[1] = models.User{Id: 1, FirstName: sql.NullString{String: "Ada", Valid: true}, LastName: sql.NullString{String: "Lovelace", Valid: true}}
+	store.users[3] = models.User{Id: 3, Email: "third@example.com"}
+	for _, userId := range []uint64{1, 2} {
+		orderId := 50 + userId
+		store.orders[orderId] = models.Order{Id: orderId, UserId: userId, Status: models.OrderStatusDelivered}
+		store.details[orderId*10] = models.OrderDetail{Id: orderId * 10, OrderId: orderId, InventoryId: 100, Qty: 1}
+	}
+	return store
+}
+
+func TestProductSetStars(t *testing.T) {
+
+	tests := []struct {
+		stars   [5]uint32
+		average float64
+		count   uint32
+		total   uint16
+	}{
+		{stars: [5]uint32{}, average: 0, count: 0, total: 0},
+		{stars: [5]uint32{0, 0, 0, 1, 1}, average: 4.5, count: 2, total: 9},
+		{stars: [5]uint32{1, 0, 0, 0, 2}, average: 3.67, count: 3, total: 11},
+		{stars: [5]uint32{0, 0, 0, 0, 20000}, average: 5, count: 20000, total: 65535},
+	}
+
+	for _, test := range tests {
+		var product models.Product
+		product.SetStars(test.stars)
+		if product.RatingAverage != test.average || product.RatingCount != test.count || product.TotalRating != test.total || product.Stars() != test.stars {
+			t.Errorf("SetStars(%v) = %v average of %d ratings totalling %d, want %v of %d totalling %d",
+				test.stars, product.RatingAverage, product.RatingCount, product.TotalRating, test.average, test.count, test.total)
+		}
+	}
+}
+
+func TestReviewSubmit(t *testing.T) {
+
+	store := newReviewStore()
+	reviews := Reviews(store)
+
+	if _, err := reviews.Submit(3, 10, 5, "Never got one"); !errors.Is(err, ErrReviewNotAllowed) {
+		t.Errorf("Submit without a delivery = %v, want ErrReviewNotAllowed", err)
+	}
+	for _, rating := range []uint16{0, 6} {
+		if _, err := reviews.Submit(1, 10, rating, "Off the scale"); !errors.Is(err, ErrInvalidRating) {
+			t.Errorf("Submit rated %d = %v, want ErrInvalidRating", rating, err)
+		}
+	}
+	if _, err := reviews.Submit(1, 99, 5, "Ghost"); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Submit of a missing product = %v, want ErrNotFound", err)
+	}
+
+	review, err := reviews.Submit(1, 10, 4, "Fits well")
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if review.Status != models.ReviewStatusPending || !review.Verified {
+		t.Errorf("review = %+v, want a verified review waiting for moderation", review)
+	}
+
+	list, _ := reviews.List(10)
+	if len(list) != 0 {
+		t.Errorf("listed %d reviews before moderation, want none", len(list))
+	}
+
+	if _, err := reviews.Approve(review.Id, ""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if product := store.products[10]; product.RatingCount != 1 || product.RatingAverage != 4 || product.Rating4 != 1 {
+		t.Errorf("product = %+v, want one four-star rating", product)
+	}
+
+	// Editing replaces the review rather than adding a second one, and
+	// takes it out of the rating until it is approved again.
+	edited, err := reviews.Submit(1, 10, 2, "Shrank in the wash")
+	if err != nil {
+		t.Fatalf("Submit again: %v", err)
+	}
+	if edited.Id != review.Id || edited.Rating != 2 || edited.Status != models.ReviewStatusPending || len(store.reviews) != 1 {
+		t.Errorf("edited = %+v with %d reviews stored, want review %d rewritten and pending", edited, len(store.reviews), review.Id)
+	}
+	if product := store.products[10]; product.RatingCount != 0 || product.RatingAverage != 0 {
+		t.Errorf("product = %+v, want no ratings while the edit waits", product)
+	}
+
+	if _, err := reviews.Approve(review.Id, ""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	list, _ = reviews.List(10)
+	if len(list) != 1 || list[0].Rating != 2 || list[0].Percentage != 40 || list[0].Name != "Ada Lovelace" || !list[0].Verified {
+		t.Errorf("list = %+v, want Ada's verified two-star review", list)
+	}
+
+	events := []string{}
+	for _, activity := range store.activities {
+		events = append(events, activity.Event)
+	}
+	if len(events) != 4 || events[0] != "Add review to Shirt" || events[1] != "Review approved" || events[2] != "Edit review of Shirt" {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestReviewModerate(t *testing.T) {
+
+	store := newReviewStore()
+	reviews := Reviews(store)
+
+	first, _ := reviews.Submit(1, 10, 5, "Great")
+	second, _ := reviews.Submit(2, 10, 2, "Meh")
+
+	if _, err := reviews.Approve(first.Id, ""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if _, err := reviews.Approve(second.Id, ""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if product := store.products[10]; product.RatingCount != 2 || product.RatingAverage != 3.5 || product.Stars() != [5]uint32{0, 1, 0, 0, 1} {
+		t.Errorf("product = %+v, want a 3.5 average over two ratings", product)
+	}
+
+	rejected, err := reviews.Reject(second.Id, "Off topic")
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if rejected.Status != models.ReviewStatusRejected || rejected.ModerationNote.String != "Off topic" || rejected.ModeratedAt == nil {
+		t.Errorf("rejected = %+v", rejected)
+	}
+	if product := store.products[10]; product.RatingCount != 1 || product.RatingAverage != 5 {
+		t.Errorf("product = %+v, want only the five-star rating left", product)
+	}
+
+	if _, err := reviews.Approve(999, ""); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Approve of a missing review = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReviewAdminList(t *testing.T) {
+
+	store := newReviewStore()
+	reviews := Reviews(store)
+
+	first, _ := reviews.Submit(1, 10, 5, "Great")
+	reviews.Submit(2, 10, 2, "Meh")
+	reviews.Approve(first.Id, "")
+
+	pending := models.ReviewStatusPending
+	page, err := reviews.AdminList(&pending, 10, repositories.ListQuery{Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("AdminList: %v", err)
+	}
+	if len(page.List) != 1 || page.List[0].UserId != 2 || page.TotalAll != 1 {
+		t.Errorf("pending reviews = %+v, want the second customer's", page)
+	}
+
+	page, _ = reviews.AdminList(nil, 0, repositories.ListQuery{Page: 2, Limit: 1})
+	if len(page.List) != 1 || page.List[0].Id != first.Id || page.TotalAll != 2 {
+		t.Errorf("second page = %+v, want the older review of two", page)
+	}
+
+	detail, err := reviews.AdminDetail(first.Id)
+	if err != nil || detail.User.Id != 1 || detail.Product.Id != 10 {
+		t.Errorf("AdminDetail = %+v, %v; want the review with its author and product", detail, err)
+	}
+}
+
+func TestReviewVote(t *testing.T) {
+
+	store := newReviewStore()
+	reviews := Reviews(store)
+
+	first, _ := reviews.Submit(1, 10, 5, "Great")
+	second, _ := reviews.Submit(2, 10, 4, "Good")
+
+	if _, err := reviews.Vote(2, first.Id); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Vote on a pending review = %v, want ErrNotFound", err)
+	}
+
+	reviews.Approve(first.Id, "")
+	reviews.Approve(second.Id, "")
+
+	if _, err := reviews.Vote(1, first.Id); !errors.Is(err, ErrOwnReviewVote) {
+		t.Errorf("Vote on one's own review = %v, want ErrOwnReviewVote", err)
+	}
+
+	for _, userId := range []uint64{1, 1, 3} {
+		if _, err := reviews.Vote(userId, second.Id); err != nil {
+			t.Fatalf("Vote: %v", err)
+		}
+	}
+	voted := store.reviews[second.Id]
+	if voted.HelpfulCount != 2 {
+		t.Errorf("helpful = %d, want 2 with the repeated vote counted once", voted.HelpfulCount)
+	}
+
+	list, _ := reviews.List(10)
+	if len(list) != 2 || list[0].Id != int64(second.Id) || list[0].Helpful != 2 {
+		t.Errorf("list = %+v, want the helpful review first", list)
+	}
+
+	unvoted, err := reviews.Unvote(3, second.Id)
+	if err != nil {
+		t.Fatalf("Unvote: %v", err)
+	}
+	if unvoted.HelpfulCount != 1 {
+		t.Errorf("helpful = %d after taking a vote back, want 1", unvoted.HelpfulCount)
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	repositories "backend/src/repositories"
+	search "backend/src/search"
+	"sort"
+	"strings"
+)
+
+// RelevanceOrder sorts the shop listing by how well products match the
+// search, the best match first.
+const RelevanceOrder = "relevance"
+
+// Suggestions completes what a customer is typing in the search box.
+type Suggestions struct {
+	Terms    []string
+	Products []ProductCard
+}
+
+// search service
+//
+// Searches run against an in-memory index of the catalogue; the database
+// still decides what is published and applies the other shop filters.
+type SearchService interface {
+	Shop(filter repositories.ProductFilter) (ProductPage, error)
+	Suggest(query string, limit int) (Suggestions, error)
+}
+
+type searchServices struct {
+	store  repositories.Store
+	engine *search.Engine
+}
+
+func Search(store repositories.Store, engine *search.Engine) SearchService {
+	return &searchServices{store: store, engine: engine}
+}
+
+// SearchIndexLoader reads the documents of the search index from store.
+func SearchIndexLoader(store repositories.Store) func() ([]search.Document, error) {
+	return func() ([]search.Document, error) {
+
+		products, err := store.Catalog().Indexable()
+		if err != nil {
+			return nil, err
+		}
+
+		documents := make([]search.Document, 0, len(products))
+		for _, product := range products {
+			document := search.Document{
+				Id:          product.Id,
+				Name:        product.Name,
+				Sku:         product.Sku,
+				Brand:       product.Brand.Name,
+				Description: product.Description,
+				Details:     product.Details,
+			}
+			for _, category := range product.Categories {
+				document.Categories = append(document.Categories, category.Name)
+			}
+			documents = append(documents, document)
+		}
+
+		return documents, nil
+	}
+}
+
+// Shop returns one page of the shop listing with its facet counts. A
+// search limits the listing to the products the index finds, and the
+// relevance order ranks them the way the index scored them.
+func (service *searchServices) Shop(filter repositories.ProductFilter) (ProductPage, error) {
+
+	page := ProductPage{Limit: filter.Limit, Page: filter.Page}
+	catalog := service.store.Catalog()
+
+	var err error
+
+	if page.TotalAll, err = catalog.CountPublished(); err != nil {
+		return page, err
+	}
+
+	rank := map[uint64]int{}
+	if query := strings.TrimSpace(filter.Search); len(query) > 0 {
+		index, err := service.engine.Index()
+		if err != nil {
+			return page, err
+		}
+		filter.Ids = []uint64{}
+		for i, hit := range index.Search(query) {
+			filter.Ids = append(filter.Ids, hit.Id)
+			rank[hit.Id] = i
+		}
+	} else if filter.OrderBy == RelevanceOrder {
+		// Without a search every product is as relevant as the next.
+		filter.OrderBy = "id"
+	}
+
+	if page.Facets, err = catalog.Facets(filter); err != nil {
+		return page, err
+	}
+
+	if filter.OrderBy != RelevanceOrder {
+		products, filtered, err := catalog.Search(filter)
+		if err != nil {
+			return page, err
+		}
+		page.List = productCards(products)
+		page.TotalFiltered = filtered
+		return page, nil
+	}
+
+	ids, err := catalog.SearchIds(filter)
+	if err != nil {
+		return page, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return rank[ids[i]] < rank[ids[j]] })
+
+	start := min(filter.Offset(), len(ids))
+	end := min(start+filter.Limit, len(ids))
+
+	products, err := catalog.PublishedIn(ids[start:end])
+	if err != nil {
+		return page, err
+	}
+	sort.Slice(products, func(i, j int) bool { return rank[products[i].Id] < rank[products[j].Id] })
+
+	page.List = productCards(products)
+	page.TotalFiltered = int64(len(ids))
+	return page, nil
+}
+
+// Suggest returns up to limit completions of query and the products that
+// best match it as typed so far.
+func (service *searchServices) Suggest(query string, limit int) (Suggestions, error) {
+
+	var suggestions Suggestions
+
+	if len(strings.TrimSpace(query)) == 0 || limit < 1 {
+		return suggestions, nil
+	}
+
+	index, err := service.engine.Index()
+	if err != nil {
+		return suggestions, err
+	}
+
+	suggestions.Terms = index.Suggest(query, limit)
+
+	rank := map[uint64]int{}
+	var ids []uint64
+	for i, hit := range index.Search(query) {
+		if i == limit {
+			break
+		}
+		ids = append(ids, hit.Id)
+		rank[hit.Id] = i
+	}
+
+	products, err := service.store.Catalog().PublishedIn(ids)
+	if err != nil {
+		return suggestions, err
+	}
+	sort.Slice(products, func(i, j int) bool { return rank[products[i].Id] < rank[products[j].Id] })
+
+	suggestions.Products = productCards(products)
+	return suggestions, nil
+}
@@ -0,0 +1,97 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	search "backend/src/search"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newSearchStore adds a dress described as going with the shirt and a
+// draft shirt to the shop store.
+func newSearchStore() fakeStore {
+
+	store := newShopStore()
+	published := time.Now().Add(-time.Hour)
+
+	store.products[12] = models.Product{Id: 12, BrandId: 1, Name: "Dress", Description: "Wear it over a shirt.", Status: 1, PublishedAt: &published}
+	store.products[13] = models.Product{Id: 13, Name: "Shirt Draft"}
+
+	return store
+}
+
+func cardIds(cards []ProductCard) string {
+	var ids []int64
+	for _, card := range cards {
+		ids = append(ids, card.Id)
+	}
+	return fmt.Sprint(ids)
+}
+
+func TestSearchShop(t *testing.T) {
+
+	store := newSearchStore()
+	service := Search(store, search.NewEngine(SearchIndexLoader(store), 0))
+
+	tests := []struct {
+		filter repositories.ProductFilter
+		want   string
+		total  int64
+	}{
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: RelevanceOrder, Search: "shirt"}}, "[10 12]", 2},
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 2, Limit: 1, OrderBy: RelevanceOrder, Search: "shirt"}}, "[12]", 2},
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: "id", Search: "shirt"}}, "[12 10]", 2},
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: RelevanceOrder, Search: "shirt"}, Brands: []string{"1"}}, "[12]", 1},
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: RelevanceOrder, Search: "sock"}}, "[]", 0},
+		{repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: RelevanceOrder}}, "[12 11 10]", 3},
+	}
+
+	for _, test := range tests {
+		page, err := service.Shop(test.filter)
+		if err != nil {
+			t.Fatalf("Shop(%+v): %v", test.filter, err)
+		}
+		if got := cardIds(page.List); got != test.want || page.TotalFiltered != test.total {
+			t.Errorf("Shop(%+v) = %s of %d, want %s of %d", test.filter, got, page.TotalFiltered, test.want, test.total)
+		}
+		if page.TotalAll != 3 {
+			t.Errorf("TotalAll = %d, want the 3 published products", page.TotalAll)
+		}
+	}
+
+	page, _ := service.Shop(repositories.ProductFilter{ListQuery: repositories.ListQuery{Page: 1, Limit: 9, OrderBy: RelevanceOrder, Search: "shirt"}, Brands: []string{"1"}})
+	if fmt.Sprint(page.Facets.Brands) != "[{0  1} {1  1}]" {
+		t.Errorf("brand facets = %v, want both brands of the matches", page.Facets.Brands)
+	}
+}
+
+func TestSearchSuggest(t *testing.T) {
+
+	store := newSearchStore()
+	service := Search(store, search.NewEngine(SearchIndexLoader(store), 0))
+
+	suggestions, err := service.Suggest("shi", 1)
+	if err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+	if fmt.Sprint(suggestions.Terms) != "[shirt]" || cardIds(suggestions.Products) != "[10]" {
+		t.Errorf("suggestions = %+v, want the shirt", suggestions)
+	}
+
+	if suggestions, _ := service.Suggest("  ", 5); len(suggestions.Terms) != 0 || len(suggestions.Products) != 0 {
+		t.Errorf("suggestions = %+v for a blank query, want none", suggestions)
+	}
+}
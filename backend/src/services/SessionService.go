@@ -0,0 +1,199 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("the refresh token is invalid or has expired")
+	ErrRefreshTokenReused  = errors.New("the refresh token has already been used; all sessions in this family were revoked")
+)
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// session service
+//
+// A session is a family of refresh tokens. Every refresh rotates the token
+// and marks the old one as used; presenting a used token again means it was
+// stolen, so the whole family is revoked. Access tokens carry the family id
+// in their sid claim so AuthorizeJWT can reject them once the family has no
+// refresh token left that is still pending or used and unexpired.
+type SessionService interface {
+	Issue(user models.User) (*TokenPair, error)
+	Rotate(refreshToken string) (*TokenPair, error)
+	AccessToken(user models.User, familyId string) *TokenPair
+	Revoke(familyId string) error
+	RevokeAll(userId uint64) error
+	IsRevoked(familyId string) bool
+	Cleanup() (int64, error)
+}
+
+type sessionServices struct {
+	store    repositories.Store
+	jwt      JWTService
+	lifespan time.Duration
+}
+
+func Sessions(config *appconfig.Config, store repositories.Store) SessionService {
+	return &sessionServices{
+		store:    store,
+		jwt:      JWTAuthService(config),
+		lifespan: config.Auth.RefreshTokenLifespan,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (service *sessionServices) Issue(user models.User) (*TokenPair, error) {
+	return service.issue(user, uuid.New().String())
+}
+
+func (service *sessionServices) issue(user models.User, familyId string) (*TokenPair, error) {
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(bytes)
+
+	expiredAt := time.Now().Add(service.lifespan)
+	row := models.Authentication{
+		UserId:     int64(user.Id),
+		AuthType:   models.AuthTypeRefreshToken,
+		Credential: user.Email,
+		Token:      hashToken(refreshToken),
+		FamilyId:   familyId,
+		Status:     models.AuthStatusPending,
+		ExpiredAt:  &expiredAt,
+	}
+	if err := service.store.Authentications().Create(&row); err != nil {
+		return nil, err
+	}
+
+	pair := service.AccessToken(user, familyId)
+	pair.RefreshToken = refreshToken
+	return pair, nil
+}
+
+func (service *sessionServices) AccessToken(user models.User, familyId string) *TokenPair {
+	roles := AccessControl(service.store).UserRoles(user.Id)
+	return &TokenPair{
+		AccessToken: service.jwt.GenerateToken(int(user.Id), user.Email, true, roles, familyId),
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(service.jwt.Lifespan().Seconds()),
+	}
+}
+
+// Rotate trades a pending refresh token for a new pair in the same family.
+// Presenting a used token revokes the family; that revocation is kept even
+// though Rotate fails with ErrRefreshTokenReused.
+func (service *sessionServices) Rotate(refreshToken string) (*TokenPair, error) {
+
+	var pair *TokenPair
+	reused := false
+
+	err := service.store.Transaction(func(tx repositories.Store) error {
+
+		row, err := tx.Authentications().Lock(models.AuthTypeRefreshToken, hashToken(refreshToken))
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrInvalidRefreshToken
+		} else if err != nil {
+			return err
+		}
+
+		switch {
+		case row.Status == models.AuthStatusUsed:
+			reused = true
+			return tx.Authentications().RevokeFamily(row.FamilyId)
+		case row.Status != models.AuthStatusPending, row.ExpiredAt == nil, row.ExpiredAt.Before(time.Now()):
+			return ErrInvalidRefreshToken
+		}
+
+		user, err := tx.Users().Find(uint64(row.UserId))
+		if errors.Is(err, repositories.ErrNotFound) || (err == nil && user.Status != 1) {
+			return ErrInvalidRefreshToken
+		} else if err != nil {
+			return err
+		}
+
+		if used, err := tx.Authentications().Use(row.Id, nil); err != nil {
+			return err
+		} else if !used {
+			return ErrInvalidRefreshToken
+		}
+
+		pair, err = service.within(tx).issue(user, row.FamilyId)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return pair, nil
+}
+
+// within returns a copy of the service that works inside tx.
+func (service *sessionServices) within(tx repositories.Store) *sessionServices {
+	scoped := *service
+	scoped.store = tx
+	return &scoped
+}
+
+func (service *sessionServices) Revoke(familyId string) error {
+	return service.store.Authentications().RevokeFamily(familyId)
+}
+
+func (service *sessionServices) RevokeAll(userId uint64) error {
+	return service.store.Authentications().RevokeAll(userId, models.AuthTypeRefreshToken)
+}
+
+// IsRevoked reports whether the family has ended: logged out, revoked for
+// reuse, or idle until its last refresh token expired. It looks for a live
+// row rather than a revoked one so Cleanup can delete revoked rows.
+func (service *sessionServices) IsRevoked(familyId string) bool {
+	if len(familyId) == 0 {
+		return true
+	}
+	live, err := service.store.Authentications().FamilyLive(familyId, time.Now())
+	return err != nil || !live
+}
+
+// Cleanup deletes the refresh tokens nothing needs any more. Revoked rows
+// go at once, since IsRevoked no longer looks at them. Used rows stay until
+// they expire so Rotate can still detect their reuse; after that Rotate
+// would reject them anyway.
+func (service *sessionServices) Cleanup() (int64, error) {
+	return service.store.Authentications().DeleteRefresh(time.Now())
+}
@@ -0,0 +1,154 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrEmailTaken        = errors.New("email address already exists")
+	ErrPhoneTaken        = errors.New("phone number already exists")
+	ErrIncorrectPassword = errors.New("incorrect current password")
+)
+
+// user service
+type UserService interface {
+	Find(id uint64) (models.User, error)
+	UpdateProfile(id uint64, input schema.UserProfileSchema) error
+	ChangePassword(id uint64, current string, password string) error
+	ChangeImage(id uint64, image string) (string, error)
+	Activities(id uint64, query repositories.ListQuery) ([]models.Activity, error)
+	Subscribe(email string, ipAddress string) error
+}
+
+type userServices struct {
+	config *appconfig.Config
+	store  repositories.Store
+}
+
+func Users(config *appconfig.Config, store repositories.Store) UserService {
+	return &userServices{config: config, store: store}
+}
+
+func (service *userServices) Find(id uint64) (models.User, error) {
+	return service.store.Users().Find(id)
+}
+
+func (service *userServices) UpdateProfile(id uint64, input schema.UserProfileSchema) error {
+
+	users := service.store.Users()
+
+	taken, err := users.EmailTaken(input.Email, id)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+
+	if len(strings.TrimSpace(input.Phone)) > 0 {
+		taken, err := users.PhoneTaken(input.Phone, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrPhoneTaken
+		}
+	}
+
+	user, err := users.Find(id)
+	if err != nil {
+		return err
+	}
+
+	if err := users.Update(&user, models.User{
+		Email:     input.Email,
+		Phone:     input.Phone,
+		FirstName: sql.NullString{String: input.FirstName, Valid: true},
+		LastName:  sql.NullString{String: input.LastName, Valid: true},
+		Gender:    sql.NullString{String: input.Gender, Valid: true},
+		Country:   sql.NullString{String: input.Country, Valid: true},
+		City:      sql.NullString{String: input.City, Valid: true},
+		ZipCode:   sql.NullString{String: input.ZipCode, Valid: true},
+		Address:   sql.NullString{String: input.Address, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	return logActivity(service.store, user.Id, "Update Current Profile", "Update Profile", "Edit user profile account")
+}
+
+func (service *userServices) ChangePassword(id uint64, current string, password string) error {
+
+	user, err := service.store.Users().Find(id)
+	if err != nil {
+		return err
+	}
+
+	passwords := PasswordHashService(service.config)
+
+	if valid, _ := passwords.Verify(current, user.Password, user.Salt); !valid {
+		return ErrIncorrectPassword
+	}
+
+	hashed, err := passwords.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	if err := service.store.Users().UpdatePassword(&user, hashed); err != nil {
+		return err
+	}
+
+	return logActivity(service.store, user.Id, "User Profile Password", "Change Password", "Change new password account")
+}
+
+// ChangeImage points the user's profile image at an uploaded file and
+// returns the path of the image it replaced, if any, so the caller can
+// remove the old file.
+func (service *userServices) ChangeImage(id uint64, image string) (string, error) {
+
+	user, err := service.store.Users().Find(id)
+	if err != nil {
+		return "", err
+	}
+
+	var previous string
+	if user.Image.Valid {
+		previous = user.Image.String
+	}
+
+	if err := service.store.Users().Update(&user, models.User{Image: sql.NullString{String: image, Valid: true}}); err != nil {
+		return "", err
+	}
+
+	return previous, logActivity(service.store, user.Id, "User Upload Image", "Upload Profile Image", "Upload new user profile image")
+}
+
+func (service *userServices) Activities(id uint64, query repositories.ListQuery) ([]models.Activity, error) {
+	return service.store.Activities().List(id, query)
+}
+
+func (service *userServices) Subscribe(email string, ipAddress string) error {
+	return service.store.Users().Subscribe(&models.NewsLetter{
+		Email:     email,
+		Status:    1,
+		IpAddress: ipAddress,
+	})
+}
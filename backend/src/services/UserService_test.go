@@ -0,0 +1,98 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	repositories "backend/src/repositories"
+	schema "backend/src/schema"
+	"errors"
+	"testing"
+)
+
+func TestUpdateProfile(t *testing.T) {
+
+	store := newShopStore()
+	users := Users(testConfig(), store)
+
+	tests := []struct {
+		name  string
+		input schema.UserProfileSchema
+		want  error
+	}{
+		{"email of another user", schema.UserProfileSchema{Email: "other@example.com"}, ErrEmailTaken},
+		{"phone of another user", schema.UserProfileSchema{Email: "buyer@example.com", Phone: "555-0100"}, ErrPhoneTaken},
+		{"own email", schema.UserProfileSchema{Email: "buyer@example.com", FirstName: "Ada"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := users.UpdateProfile(1, test.input); !errors.Is(err, test.want) {
+				t.Errorf("UpdateProfile = %v, want %v", err, test.want)
+			}
+		})
+	}
+
+	if name := store.users[1].FirstName.String; name != "Ada" {
+		t.Errorf("first name = %q, want Ada", name)
+	}
+	if err := users.UpdateProfile(404, schema.UserProfileSchema{Email: "ghost@example.com"}); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UpdateProfile of a missing user = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+
+	store := newShopStore()
+	config := testConfig()
+	users := Users(config, store)
+
+	hashed, err := PasswordHashService(config).Hash("old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := store.users[1]
+	user.Password = hashed
+	store.users[1] = user
+
+	if err := users.ChangePassword(1, "wrong-password", "new-password"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("ChangePassword with a wrong password = %v, want ErrIncorrectPassword", err)
+	}
+	if err := users.ChangePassword(1, "old-password", "new-password"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	if valid, _ := PasswordHashService(config).Verify("new-password", store.users[1].Password, ""); !valid {
+		t.Error("the new password does not verify")
+	}
+	if len(store.activities) != 1 {
+		t.Errorf("got %d activities, want 1", len(store.activities))
+	}
+}
+
+func TestChangeImage(t *testing.T) {
+
+	store := newShopStore()
+	users := Users(testConfig(), store)
+
+	previous, err := users.ChangeImage(1, "2025-01-01/first.png")
+	if err != nil || previous != "" {
+		t.Fatalf("first ChangeImage = %q, %v; want no previous image", previous, err)
+	}
+
+	previous, err = users.ChangeImage(1, "2025-01-02/second.png")
+	if err != nil || previous != "2025-01-01/first.png" {
+		t.Fatalf("second ChangeImage = %q, %v; want the first image back", previous, err)
+	}
+	if image := store.users[1].Image.String; image != "2025-01-02/second.png" {
+		t.Errorf("image = %q", image)
+	}
+}
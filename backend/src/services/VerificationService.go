@@ -0,0 +1,175 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	appconfig "backend/src/appconfig"
+	models "backend/src/models"
+	repositories "backend/src/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrVerificationTokenInvalid = errors.New("this token is invalid or has already been used")
+	ErrVerificationTokenExpired = errors.New("this token has expired, please request a new one")
+	ErrVerificationThrottled    = errors.New("too many requests, please wait before asking for another e-mail")
+)
+
+const (
+	verificationResendInterval = time.Minute
+	verificationHourlyLimit    = 5
+	verificationRetention      = 7 * 24 * time.Hour
+)
+
+// verification service
+//
+// Owns the one-time tokens behind account confirmation and password reset.
+// Only a sha256 of each token is stored, issuing a new token revokes the
+// pending ones of the same kind, and a token can be consumed exactly once
+// before it expires.
+type VerificationService interface {
+	Issue(user models.User, authType string) (string, error)
+	Consume(authType string, token string, credential string) (*models.Authentication, error)
+	Lifespan() time.Duration
+	Cleanup() (int64, error)
+}
+
+type verificationServices struct {
+	store    repositories.Store
+	lifespan time.Duration
+}
+
+func Verifications(config *appconfig.Config, store repositories.Store) VerificationService {
+	return &verificationServices{
+		store:    store,
+		lifespan: config.Auth.VerificationTokenLifespan,
+	}
+}
+
+func (service *verificationServices) Lifespan() time.Duration {
+	return service.lifespan
+}
+
+// Issue creates a new token of authType for user and returns it in plain
+// text; the caller is responsible for delivering it. Requests are limited
+// to one per minute and verificationHourlyLimit per hour.
+func (service *verificationServices) Issue(user models.User, authType string) (string, error) {
+
+	now := time.Now()
+	authentications := service.store.Authentications()
+
+	last, err := authentications.Latest(user.Id, authType)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return "", err
+	}
+	if err == nil && last.CreatedAt.After(now.Add(-verificationResendInterval)) {
+		return "", ErrVerificationThrottled
+	}
+
+	recent, err := authentications.CountSince(user.Id, authType, now.Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if recent >= verificationHourlyLimit {
+		return "", ErrVerificationThrottled
+	}
+
+	if err := authentications.RevokePending(user.Id, authType); err != nil {
+		return "", err
+	}
+
+	token := uuid.New().String()
+	expiredAt := now.Add(service.lifespan)
+	row := models.Authentication{
+		UserId:     int64(user.Id),
+		AuthType:   authType,
+		Credential: user.Email,
+		Token:      hashToken(token),
+		Status:     models.AuthStatusPending,
+		ExpiredAt:  &expiredAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := authentications.Create(&row); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Consume marks a pending, unexpired token as used. When credential is not
+// empty it must match the address the token was issued to. The update is
+// conditional on the row still being pending, so two concurrent requests
+// with the same token cannot both succeed.
+func (service *verificationServices) Consume(authType string, token string, credential string) (*models.Authentication, error) {
+
+	authentications := service.store.Authentications()
+
+	row, err := authentications.FindPending(authType, hashToken(token), credential)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrVerificationTokenInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if row.ExpiredAt == nil || row.ExpiredAt.Before(now) {
+		return nil, ErrVerificationTokenExpired
+	}
+
+	used, err := authentications.Use(row.Id, &now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrVerificationTokenInvalid
+	}
+
+	row.Status = models.AuthStatusUsed
+	row.ExpiredAt = &now
+	return &row, nil
+}
+
+// Cleanup deletes confirmation and reset tokens that expired more than
+// verificationRetention ago. Recent rows are kept so Issue can still
+// enforce its rate limit.
+func (service *verificationServices) Cleanup() (int64, error) {
+	return service.store.Authentications().DeleteExpired([]string{models.AuthTypeEmailConfirm, models.AuthTypeResetPassword}, time.Now().Add(-verificationRetention))
+}
+
+// StartTokenCleanup runs the verification and session Cleanup every
+// interval until ctx is done. The returned channel is closed once the loop
+// has stopped.
+func StartTokenCleanup(ctx context.Context, store repositories.Store, config *appconfig.Config, interval time.Duration) <-chan struct{} {
+	verifications := Verifications(config, store)
+	sessions := Sessions(config, store)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			verifications.Cleanup()
+			sessions.Cleanup()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
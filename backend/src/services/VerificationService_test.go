@@ -0,0 +1,47 @@
+/**
+ * This file is part of the Sandy Andryanto Online Store Website.
+ *
+ * @author     Sandy Andryanto <sandy.andryanto.official@gmail.com>
+ * @copyright  2025
+ *
+ * For the full copyright and license information,
+ * please view the LICENSE.md file that was distributed
+ * with this source code.
+ */
+
+package services
+
+import (
+	models "backend/src/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerificationIssue(t *testing.T) {
+
+	store := newShopStore()
+	verifications := Verifications(testConfig(), store)
+	user := store.users[1]
+
+	token, err := verifications.Issue(user, models.AuthTypeEmailConfirm)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if len(store.authentications) != 1 || store.authentications[0].Token != hashToken(token) {
+		t.Fatalf("authentications = %+v, want one row holding the token hash", store.authentications)
+	}
+
+	if _, err := verifications.Issue(user, models.AuthTypeEmailConfirm); !errors.Is(err, ErrVerificationThrottled) {
+		t.Errorf("second Issue = %v, want ErrVerificationThrottled", err)
+	}
+
+	// A minute later the new token replaces the pending one.
+	store.authentications[0].CreatedAt = time.Now().Add(-2 * time.Minute)
+	if _, err := verifications.Issue(user, models.AuthTypeEmailConfirm); err != nil {
+		t.Fatalf("Issue after a minute: %v", err)
+	}
+	if store.authentications[0].Status != models.AuthStatusRevoked || store.authentications[1].Status != models.AuthStatusPending {
+		t.Errorf("authentications = %+v, want the first revoked and the second pending", store.authentications)
+	}
+}